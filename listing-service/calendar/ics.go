@@ -0,0 +1,92 @@
+package calendar
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const timeFormat = "20060102T150405Z"
+
+// Event is one VEVENT. Sequence must grow whenever the event changes so
+// subscribed calendars replace their copy.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	Summary     string
+	Description string
+	Location    string
+	Cancelled   bool
+}
+
+// Encode renders an iCalendar (RFC 5545) feed named name.
+func Encode(name string, events []Event) []byte {
+	var buf bytes.Buffer
+	line := func(format string, args ...interface{}) {
+		buf.WriteString(fold(fmt.Sprintf(format, args...)))
+		buf.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//real-estate-system//viewings//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", escape(name))
+
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:%s", e.UID)
+		line("SEQUENCE:%d", e.Sequence)
+		line("DTSTAMP:%s", e.Stamp.UTC().Format(timeFormat))
+		line("DTSTART:%s", e.Start.UTC().Format(timeFormat))
+		line("DTEND:%s", e.End.UTC().Format(timeFormat))
+		line("SUMMARY:%s", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:%s", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION:%s", escape(e.Location))
+		}
+		if e.Cancelled {
+			line("STATUS:CANCELLED")
+		} else {
+			line("STATUS:CONFIRMED")
+		}
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// fold splits content lines longer than 75 octets, continuing them with a
+// leading space, without cutting UTF-8 sequences.
+func fold(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/calendar"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode_Event(t *testing.T) {
+	start := time.Date(2026, 3, 14, 9, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+	ics := string(calendar.Encode("Viewings", []calendar.Event{{
+		UID:         "viewing-1@real-estate-system",
+		Sequence:    2,
+		Start:       start,
+		End:         start.Add(30 * time.Minute),
+		Stamp:       start,
+		Summary:     "Viewing: listing #7",
+		Description: "Bring keys; gate code 1,2,3\nCall on arrival",
+		Cancelled:   true,
+	}}))
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "DTSTART:20260314T023000Z\r\n")
+	assert.Contains(t, ics, "DTEND:20260314T030000Z\r\n")
+	assert.Contains(t, ics, "SEQUENCE:2\r\n")
+	assert.Contains(t, ics, `DESCRIPTION:Bring keys\; gate code 1\,2\,3\nCall on arrival`+"\r\n")
+	assert.Contains(t, ics, "STATUS:CANCELLED\r\n")
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	ics := string(calendar.Encode("Viewings", []calendar.Event{{
+		UID:     "viewing-2@real-estate-system",
+		Summary: strings.Repeat("é", 60),
+	}}))
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+	assert.Contains(t, ics, "\r\n é")
+}
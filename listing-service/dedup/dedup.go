@@ -0,0 +1,249 @@
+package dedup
+
+import (
+	"math"
+	"real-estate-system/listing-service/media"
+	"real-estate-system/listing-service/models"
+	"strings"
+	"unicode"
+)
+
+const (
+	SignalGeo        = "geo"
+	SignalAddress    = "address"
+	SignalAttributes = "attributes"
+	SignalPhotos     = "photos"
+
+	// DefaultThreshold is the score from which a pair is reported.
+	DefaultThreshold = 0.75
+
+	// SearchRadius bounds the candidates by location, in metres.
+	SearchRadius = 500
+)
+
+// weights of each signal in the score. Signals a pair lacks, such as
+// coordinates on either listing, are left out of the mean.
+var weights = map[string]float64{
+	SignalGeo:        0.3,
+	SignalAddress:    0.3,
+	SignalAttributes: 0.2,
+	SignalPhotos:     0.2,
+}
+
+// Match is the likeness of two listings.
+type Match struct {
+	Score   float64
+	Signals map[string]float64
+}
+
+// Compare scores how likely a and b are the same property. Listings of
+// different types never match, and neither do pairs known only by their
+// attributes, which many different properties share.
+func Compare(a, b *models.Listing) Match {
+	signals := map[string]float64{}
+	if a.ListingType != b.ListingType {
+		return Match{Signals: signals}
+	}
+
+	if a.HasLocation() && b.HasLocation() {
+		signals[SignalGeo] = proximity(Distance(a.Latitude, a.Longitude, b.Latitude, b.Longitude))
+	}
+	if x, y := NormalizeAddress(a.Address), NormalizeAddress(b.Address); x != "" && y != "" {
+		signals[SignalAddress] = Similarity(x, y)
+	}
+	if score, ok := photoSimilarity(a.Photos, b.Photos); ok {
+		signals[SignalPhotos] = score
+	}
+	if len(signals) == 0 {
+		return Match{Signals: signals}
+	}
+	if score, ok := attributeSimilarity(a, b); ok {
+		signals[SignalAttributes] = score
+	}
+
+	var sum, total float64
+	for name, score := range signals {
+		sum += score * weights[name]
+		total += weights[name]
+	}
+	return Match{Score: math.Round(sum/total*1000) / 1000, Signals: signals}
+}
+
+// Pairs compares listing with each candidate and returns the pairs scoring
+// at least threshold.
+func Pairs(listing *models.Listing, candidates []models.Listing, threshold float64, now int64) []models.DuplicatePair {
+	var pairs []models.DuplicatePair
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.ID == listing.ID {
+			continue
+		}
+		match := Compare(listing, candidate)
+		if match.Score < threshold {
+			continue
+		}
+		pair := models.DuplicatePair{
+			ListingID:   min(listing.ID, candidate.ID),
+			DuplicateID: max(listing.ID, candidate.ID),
+			Score:       match.Score,
+			Signals:     match.Signals,
+			Status:      models.DuplicatePending,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// Distance is the great-circle distance between two points in metres.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// proximity is 1 within 25 m, where geocoding of the same address differs,
+// falling to 0 at 250 m.
+func proximity(metres float64) float64 {
+	switch {
+	case metres <= 25:
+		return 1
+	case metres >= 250:
+		return 0
+	}
+	return 1 - (metres-25)/225
+}
+
+// abbreviations are expanded so that "Jl. Kemang Raya No. 5" and "Jalan
+// Kemang Raya 5" normalize alike.
+var abbreviations = map[string]string{
+	"jl":   "jalan",
+	"jln":  "jalan",
+	"gg":   "gang",
+	"kec":  "kecamatan",
+	"kel":  "kelurahan",
+	"st":   "street",
+	"str":  "street",
+	"rd":   "road",
+	"ave":  "avenue",
+	"av":   "avenue",
+	"blvd": "boulevard",
+	"dr":   "drive",
+	"ln":   "lane",
+	"apt":  "apartment",
+	"blk":  "block",
+	"blok": "block",
+}
+
+// filler words carry no information about the place.
+var filler = map[string]bool{"no": true, "nomor": true, "number": true, "the": true}
+
+// NormalizeAddress lowercases the address, drops punctuation and filler
+// words and expands common abbreviations.
+func NormalizeAddress(address string) string {
+	words := strings.FieldsFunc(strings.ToLower(address), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := words[:0]
+	for _, word := range words {
+		if filler[word] {
+			continue
+		}
+		if full, ok := abbreviations[word]; ok {
+			word = full
+		}
+		out = append(out, word)
+	}
+	return strings.Join(out, " ")
+}
+
+// Similarity is the Dice coefficient of the character bigrams of a and b,
+// from 0 for nothing in common to 1 for the same text.
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	x, y := bigrams(a), bigrams(b)
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+	counts := map[string]int{}
+	for _, g := range x {
+		counts[g]++
+	}
+	shared := 0
+	for _, g := range y {
+		if counts[g] > 0 {
+			counts[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(x)+len(y))
+}
+
+func bigrams(s string) []string {
+	runes := []rune(s)
+	var out []string
+	for i := 0; i+1 < len(runes); i++ {
+		out = append(out, string(runes[i:i+2]))
+	}
+	return out
+}
+
+// attributeSimilarity is the share of the attributes known for both
+// listings that agree. Prices agree within 15% and floor areas within 5%,
+// as reposts of the same property often differ slightly.
+func attributeSimilarity(a, b *models.Listing) (float64, bool) {
+	var known, equal int
+	compare := func(ok, same bool) {
+		if ok {
+			known++
+			if same {
+				equal++
+			}
+		}
+	}
+
+	compare(a.Bedrooms > 0 && b.Bedrooms > 0, a.Bedrooms == b.Bedrooms)
+	compare(a.Bathrooms > 0 && b.Bathrooms > 0, a.Bathrooms == b.Bathrooms)
+	compare(a.FloorArea > 0 && b.FloorArea > 0, within(float64(a.FloorArea), float64(b.FloorArea), 0.05))
+	compare(a.District != "" && b.District != "", strings.EqualFold(strings.TrimSpace(a.District), strings.TrimSpace(b.District)))
+	compare(a.Price > 0 && b.Price > 0 && a.Currency == b.Currency && a.RentPeriod == b.RentPeriod,
+		within(float64(a.Price), float64(b.Price), 0.15))
+
+	if known == 0 {
+		return 0, false
+	}
+	return float64(equal) / float64(known), true
+}
+
+func within(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Max(a, b)
+}
+
+// photoSimilarity scores the closest pair of photos of the two listings: 1
+// for copies up to 4 bits apart, falling to 0 at 16 bits.
+func photoSimilarity(a, b []models.ListingMedia) (float64, bool) {
+	best, found := 64, false
+	for _, x := range a {
+		for _, y := range b {
+			if distance, ok := media.HashDistance(x.PerceptualHash, y.PerceptualHash); ok {
+				best, found = min(best, distance), true
+			}
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	switch {
+	case best <= 4:
+		return 1, true
+	case best >= 16:
+		return 0, true
+	}
+	return 1 - float64(best-4)/12, true
+}
@@ -0,0 +1,37 @@
+package dedup
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+// maxCandidates bounds the listings each listing is compared with.
+const maxCandidates = 200
+
+// Detector finds and records the likely duplicates of listings.
+type Detector struct {
+	Repo      interfaces.DuplicateRepository
+	Threshold float64
+}
+
+func NewDetector(repo interfaces.DuplicateRepository) *Detector {
+	return &Detector{Repo: repo, Threshold: DefaultThreshold}
+}
+
+// Check compares the listing with listings of the same type nearby or in
+// the same city and records the pairs that score at least Threshold.
+func (d *Detector) Check(listing *models.Listing) ([]models.DuplicatePair, error) {
+	if listing.City == "" && !listing.HasLocation() {
+		return nil, nil
+	}
+	candidates, err := d.Repo.GetCandidates(listing, SearchRadius, maxCandidates)
+	if err != nil {
+		return nil, err
+	}
+	pairs := Pairs(listing, candidates, d.Threshold, time.Now().UnixMicro())
+	if err := d.Repo.SaveDuplicatePairs(pairs); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
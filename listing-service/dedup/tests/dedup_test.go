@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/dedup"
+	"real-estate-system/listing-service/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	assert.Equal(t, "jalan kemang raya 12", dedup.NormalizeAddress("Jl. Kemang Raya No. 12"))
+	assert.Equal(t, "jalan kemang raya 12", dedup.NormalizeAddress("JALAN Kemang Raya, 12"))
+	assert.Equal(t, "12 baker street", dedup.NormalizeAddress("12 Baker St."))
+	assert.Equal(t, "", dedup.NormalizeAddress(" - "))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, dedup.Similarity("jalan kemang raya 12", "jalan kemang raya 12"))
+	assert.Greater(t, dedup.Similarity("jalan kemang raya 12", "jalan kemang raya 12a"), 0.9)
+	assert.Less(t, dedup.Similarity("jalan kemang raya 12", "jalan cikini raya 40"), 0.6)
+}
+
+func TestDistance(t *testing.T) {
+	// One thousandth of a degree of latitude is about 111 m.
+	assert.InDelta(t, 111.2, dedup.Distance(-6.2600, 106.8137, -6.2610, 106.8137), 0.5)
+}
+
+func TestCompare(t *testing.T) {
+	base := models.Listing{
+		ID: 1, ListingType: "sale", Price: 2000, Currency: "IDR", District: "Kemang",
+		Address: "Jl. Kemang Raya No. 12", Latitude: -6.2607, Longitude: 106.8137, Bedrooms: 3,
+		Photos: []models.ListingMedia{{PerceptualHash: "f0f0f0f0f0f0f0f0"}},
+	}
+
+	repost := base
+	repost.ID, repost.Price, repost.Address = 2, 1900, "Jalan Kemang Raya 12"
+	repost.Photos = []models.ListingMedia{{PerceptualHash: "f0f0f0f0f0f0f0f1"}}
+	match := dedup.Compare(&base, &repost)
+	assert.Greater(t, match.Score, 0.95)
+	assert.Equal(t, 1.0, match.Signals[dedup.SignalPhotos])
+	assert.Equal(t, 1.0, match.Signals[dedup.SignalAttributes])
+
+	neighbour := base
+	neighbour.ID, neighbour.Address, neighbour.Latitude, neighbour.Bedrooms = 3, "Jl. Kemang Raya No. 48", -6.2630, 4
+	neighbour.Photos = []models.ListingMedia{{PerceptualHash: "0f0f0f0f0f0f0f0f"}}
+	assert.Less(t, dedup.Compare(&base, &neighbour).Score, dedup.DefaultThreshold)
+
+	rental := repost
+	rental.ListingType = "rent"
+	assert.Zero(t, dedup.Compare(&base, &rental).Score)
+
+	// Matching attributes alone are not evidence of the same property.
+	bare := models.Listing{ListingType: "sale", District: "Kemang", Bedrooms: 3}
+	other := bare
+	assert.Zero(t, dedup.Compare(&bare, &other).Score)
+}
+
+func TestPairs_OrdersIDsAndSkipsWeakMatches(t *testing.T) {
+	listing := models.Listing{ID: 9, ListingType: "rent", Latitude: -6.2607, Longitude: 106.8137}
+	candidates := []models.Listing{
+		{ID: 4, ListingType: "rent", Latitude: -6.2607, Longitude: 106.8137},
+		{ID: 5, ListingType: "rent", Latitude: -6.2707, Longitude: 106.8137},
+		{ID: 9, ListingType: "rent", Latitude: -6.2607, Longitude: 106.8137},
+	}
+
+	pairs := dedup.Pairs(&listing, candidates, dedup.DefaultThreshold, 100)
+	assert.Len(t, pairs, 1)
+	assert.Equal(t, 4, pairs[0].ListingID)
+	assert.Equal(t, 9, pairs[0].DuplicateID)
+	assert.Equal(t, models.DuplicatePending, pairs[0].Status)
+}
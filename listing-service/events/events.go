@@ -0,0 +1,75 @@
+package events
+
+import (
+	"encoding/json"
+	"real-estate-system/listing-service/models"
+	"time"
+)
+
+const (
+	AggregateListing     = "listing"
+	AggregateInquiry     = "inquiry"
+	AggregateThread      = "thread"
+	AggregateViewing     = "viewing"
+	AggregateOffer       = "offer"
+	AggregateApplication = "application"
+	AggregateLease       = "lease"
+	AggregateModeration  = "moderation"
+
+	ListingCreated       = "listing.created"
+	ListingUpdated       = "listing.updated"
+	ListingStatusChanged = "listing.status_changed"
+	ListingPriceChanged  = "listing.price_changed"
+	ListingPriceDropped  = "listing.price_dropped"
+
+	InquiryCreated       = "inquiry.created"
+	InquiryStatusChanged = "inquiry.status_changed"
+
+	MessageCreated = "message.created"
+	ThreadRead     = "thread.read"
+	ThreadClosed   = "thread.closed"
+
+	ViewingBooked      = "viewing.booked"
+	ViewingRescheduled = "viewing.rescheduled"
+	ViewingCancelled   = "viewing.cancelled"
+	ViewingReminder    = "viewing.reminder"
+
+	// Offer events are "offer." followed by the models.OfferAction* value,
+	// e.g. offer.submitted or offer.countered.
+	OfferEventPrefix = "offer."
+
+	ApplicationSubmitted     = "application.submitted"
+	ApplicationStatusChanged = "application.status_changed"
+
+	LeaseCreated    = "lease.created"
+	LeaseRenewed    = "lease.renewed"
+	LeaseTerminated = "lease.terminated"
+	LeaseExpiring   = "lease.expiring"
+	LeaseEnded      = "lease.ended"
+
+	// Ledger events are published on the lease aggregate.
+	PaymentSucceeded = "payment.succeeded"
+	PaymentFailed    = "payment.failed"
+	LateFeeApplied   = "rent.late_fee_applied"
+
+	// Moderation events carry the review, with the lister as owner_id.
+	ModerationPendingReview = "moderation.pending_review"
+	ModerationApproved      = "moderation.approved"
+	ModerationRejected      = "moderation.rejected"
+)
+
+// NewOutboxEvent serializes payload into a pending outbox row.
+func NewOutboxEvent(eventType, aggregateType string, aggregateID int, payload interface{}) (*models.OutboxEvent, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(body),
+		CreatedAt:     time.Now().UnixMicro(),
+	}, nil
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultStream = "listing-events"
+
+	// MessageStream carries private events between buyers and owners, such
+	// as inquiries, messages, viewings, offers, rental applications and leases. It is
+	// kept apart from DefaultStream, which partners can subscribe to.
+	MessageStream = "message-events"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// RedisStreamPublisher appends events to a Redis Stream, Stream unless the
+// aggregate type is routed elsewhere by AggregateStreams. All events of an
+// aggregate go to one stream, which keeps the relay's ID ordering, so
+// consumers see them in the order they were written.
+//
+// With Rates, listing events also carry monthly_price: the listing's price
+// per month in whole units of money.DefaultCurrency, which consumers without
+// exchange rates filter on, as GetListings does.
+type RedisStreamPublisher struct {
+	Client           *redis.Client
+	Stream           string
+	AggregateStreams map[string]string
+	MaxLen           int64
+	Rates            *fx.Service
+}
+
+func NewRedisStreamPublisher(client *redis.Client, stream string) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		Client: client,
+		Stream: stream,
+		AggregateStreams: map[string]string{
+			AggregateInquiry:     MessageStream,
+			AggregateThread:      MessageStream,
+			AggregateViewing:     MessageStream,
+			AggregateOffer:       MessageStream,
+			AggregateApplication: MessageStream,
+			AggregateLease:       MessageStream,
+			AggregateModeration:  MessageStream,
+		},
+		MaxLen: 100000,
+	}
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	stream := p.Stream
+	if routed, ok := p.AggregateStreams[event.AggregateType]; ok {
+		stream = routed
+	}
+
+	values := map[string]interface{}{
+		"event_id":       strconv.FormatInt(event.ID, 10),
+		"event_type":     event.EventType,
+		"aggregate_type": event.AggregateType,
+		"aggregate_id":   strconv.Itoa(event.AggregateID),
+		"payload":        event.Payload,
+		"occurred_at":    strconv.FormatInt(event.CreatedAt, 10),
+	}
+	if event.AggregateType == AggregateListing && p.Rates != nil {
+		if price, ok := monthlyPrice(p.Rates.Rates(), event.Payload); ok {
+			values["monthly_price"] = strconv.FormatInt(price, 10)
+		}
+	}
+
+	return p.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: p.MaxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+// monthlyPrice converts the price of the listing in payload to
+// money.DefaultCurrency, per month for yearly rents, rounded to whole units.
+func monthlyPrice(rates *fx.Rates, payload string) (int64, bool) {
+	var listing models.Listing
+	if err := json.Unmarshal([]byte(payload), &listing); err != nil || listing.Price <= 0 {
+		return 0, false
+	}
+	converted, err := rates.Convert(listing.PriceAmount(), money.DefaultCurrency)
+	if err != nil {
+		return 0, false
+	}
+
+	unit := money.FromMajor(1, money.DefaultCurrency).Minor
+	if listing.RentPeriod == models.RentPerYear {
+		unit *= 12
+	}
+	return (converted.Minor + unit/2) / unit, true
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"context"
+	"log"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"time"
+)
+
+// Relay moves pending outbox rows to a Publisher. Delivery is at-least-once:
+// a row is only marked published after Publish succeeds, so a crash in
+// between re-sends it and consumers should dedupe on event_id.
+//
+// Run a single relay per database; ordering per aggregate relies on rows
+// being published sequentially in ID order.
+type Relay struct {
+	Repo      interfaces.OutboxRepository
+	Publisher Publisher
+	BatchSize int
+	Interval  time.Duration
+}
+
+func NewRelay(repo interfaces.OutboxRepository, publisher Publisher) *Relay {
+	return &Relay{
+		Repo:      repo,
+		Publisher: publisher,
+		BatchSize: 100,
+		Interval:  time.Second,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.PublishPending(ctx); err != nil {
+			log.Println("outbox relay:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes one batch and returns how many events went out.
+// When an event fails, later events of the same aggregate in the batch are
+// held back so they cannot overtake it.
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	pending, err := r.Repo.FetchPending(r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	blocked := make(map[string]bool)
+	published := 0
+
+	for _, event := range pending {
+		key := event.AggregateType + ":" + strconv.Itoa(event.AggregateID)
+		if blocked[key] {
+			continue
+		}
+
+		if err := r.Publisher.Publish(ctx, event); err != nil {
+			blocked[key] = true
+			if markErr := r.Repo.MarkFailed(event.ID, err.Error()); markErr != nil {
+				return published, markErr
+			}
+			continue
+		}
+
+		if err := r.Repo.MarkPublished(event.ID, time.Now().UnixMicro()); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type publisherMock struct {
+	mock.Mock
+}
+
+func (m *publisherMock) Publish(ctx context.Context, event models.OutboxEvent) error {
+	args := m.Called(event.ID)
+	return args.Error(0)
+}
+
+func TestPublishPending_Success(t *testing.T) {
+	repo := new(mocks.OutboxRepositoryMock)
+	pub := new(publisherMock)
+	relay := events.NewRelay(repo, pub)
+
+	repo.On("FetchPending", 100).Return([]models.OutboxEvent{
+		{ID: 1, AggregateType: "listing", AggregateID: 7},
+		{ID: 2, AggregateType: "listing", AggregateID: 8},
+	}, nil)
+	pub.On("Publish", int64(1)).Return(nil)
+	pub.On("Publish", int64(2)).Return(nil)
+	repo.On("MarkPublished", int64(1), mock.Anything).Return(nil)
+	repo.On("MarkPublished", int64(2), mock.Anything).Return(nil)
+
+	n, err := relay.PublishPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	repo.AssertExpectations(t)
+	pub.AssertExpectations(t)
+}
+
+func TestPublishPending_FailureHoldsBackSameAggregate(t *testing.T) {
+	repo := new(mocks.OutboxRepositoryMock)
+	pub := new(publisherMock)
+	relay := events.NewRelay(repo, pub)
+
+	repo.On("FetchPending", 100).Return([]models.OutboxEvent{
+		{ID: 1, AggregateType: "listing", AggregateID: 7},
+		{ID: 2, AggregateType: "listing", AggregateID: 8},
+		{ID: 3, AggregateType: "listing", AggregateID: 7},
+	}, nil)
+	pub.On("Publish", int64(1)).Return(errors.New("redis down"))
+	pub.On("Publish", int64(2)).Return(nil)
+	repo.On("MarkFailed", int64(1), "redis down").Return(nil)
+	repo.On("MarkPublished", int64(2), mock.Anything).Return(nil)
+
+	n, err := relay.PublishPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	pub.AssertNotCalled(t, "Publish", int64(3))
+	repo.AssertExpectations(t)
+}
+
+func TestPublishPending_FetchError(t *testing.T) {
+	repo := new(mocks.OutboxRepositoryMock)
+	relay := events.NewRelay(repo, new(publisherMock))
+
+	repo.On("FetchPending", 100).Return([]models.OutboxEvent{}, errors.New("db error"))
+
+	n, err := relay.PublishPending(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestRedisStreamPublisher_Publish(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	pub := events.NewRedisStreamPublisher(rdb, events.DefaultStream)
+
+	err := pub.Publish(context.Background(), models.OutboxEvent{
+		ID:            5,
+		AggregateType: "listing",
+		AggregateID:   7,
+		EventType:     events.ListingCreated,
+		Payload:       `{"id":7}`,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := rdb.XRange(context.Background(), events.DefaultStream, "-", "+").Result()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, "listing.created", msgs[0].Values["event_type"])
+	assert.Equal(t, "7", msgs[0].Values["aggregate_id"])
+	assert.Equal(t, "5", msgs[0].Values["event_id"])
+}
+
+func TestRedisStreamPublisher_RoutesThreadEvents(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	pub := events.NewRedisStreamPublisher(rdb, events.DefaultStream)
+
+	err := pub.Publish(context.Background(), models.OutboxEvent{
+		ID:            6,
+		AggregateType: events.AggregateThread,
+		AggregateID:   4,
+		EventType:     events.MessageCreated,
+		Payload:       `{"thread_id":4}`,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := rdb.XRange(context.Background(), events.MessageStream, "-", "+").Result()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.False(t, mr.Exists(events.DefaultStream))
+}
+
+func TestRedisStreamPublisher_AddsMonthlyPriceToListingEvents(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	rates, err := fx.Parse([]byte(`{"base": "USD", "rates": {"IDR": "16000"}}`))
+	assert.NoError(t, err)
+	pub := events.NewRedisStreamPublisher(rdb, events.DefaultStream)
+	pub.Rates = fx.NewStaticService(rates)
+
+	for i, payload := range []string{
+		`{"id":7,"price":1200,"currency":"USD","listing_type":"rent","rent_period":"year"}`,
+		`{"id":8,"price":5000000,"listing_type":"rent"}`,
+		`{"id":9,"price":100,"currency":"EUR","listing_type":"sale"}`,
+	} {
+		err := pub.Publish(context.Background(), models.OutboxEvent{
+			ID:            int64(i + 1),
+			AggregateType: events.AggregateListing,
+			AggregateID:   7 + i,
+			EventType:     events.ListingCreated,
+			Payload:       payload,
+		})
+		assert.NoError(t, err)
+	}
+
+	msgs, err := rdb.XRange(context.Background(), events.DefaultStream, "-", "+").Result()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, "1600000", msgs[0].Values["monthly_price"])
+	assert.Equal(t, "5000000", msgs[1].Values["monthly_price"])
+	assert.NotContains(t, msgs[2].Values, "monthly_price") // no EUR rate
+}
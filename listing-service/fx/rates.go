@@ -0,0 +1,146 @@
+package fx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"real-estate-system/listing-service/money"
+	"sort"
+)
+
+var ErrUnknownCurrency = errors.New("no exchange rate for currency")
+
+// Rates is a table of exchange rates against Base: one unit of Base buys
+// rates[code] units of code.
+type Rates struct {
+	Base  string
+	Date  string
+	rates map[string]*big.Rat
+}
+
+type ratesFile struct {
+	Base  string                 `json:"base"`
+	Date  string                 `json:"date"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// Parse reads a rates table such as
+//
+//	{"base": "USD", "date": "2026-10-01", "rates": {"IDR": 16250, "EUR": "0.92"}}
+//
+// Rates are kept as exact decimals.
+func Parse(data []byte) (*Rates, error) {
+	var file ratesFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	r := &Rates{Base: money.Normalize(file.Base), Date: file.Date, rates: map[string]*big.Rat{}}
+	if !money.Valid(r.Base) {
+		return nil, fmt.Errorf("unsupported base currency %q", file.Base)
+	}
+	r.rates[r.Base] = big.NewRat(1, 1)
+	for code, raw := range file.Rates {
+		code = money.Normalize(code)
+		rate, ok := new(big.Rat).SetString(raw.String())
+		if !money.Valid(code) || !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate for %q", code)
+		}
+		r.rates[code] = rate
+	}
+	return r, nil
+}
+
+func LoadFile(path string) (*Rates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Currencies returns the codes with a rate, sorted.
+func (r *Rates) Currencies() []string {
+	codes := make([]string, 0, len(r.rates))
+	for code := range r.rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func (r *Rates) Has(currency string) bool {
+	_, ok := r.rates[currency]
+	return ok
+}
+
+// factor is what one major unit of from is worth in major units of to.
+func (r *Rates) factor(from, to string) (*big.Rat, error) {
+	fromRate, ok := r.rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownCurrency, from)
+	}
+	toRate, ok := r.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownCurrency, to)
+	}
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+func pow10(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+// Convert converts a into to, rounding half away from zero to the minor
+// unit of to.
+func (r *Rates) Convert(a money.Amount, to string) (money.Amount, error) {
+	factor, err := r.factor(a.Currency, to)
+	if err != nil {
+		return money.Amount{}, err
+	}
+
+	v := new(big.Rat).SetInt64(a.Minor)
+	v.Quo(v, pow10(money.Exponent(a.Currency)))
+	v.Mul(v, factor)
+	v.Mul(v, pow10(money.Exponent(to)))
+	return money.Amount{Minor: round(v), Currency: to}, nil
+}
+
+// ConvertRange converts the bounds of a price range in whole units, widening
+// it to whole units of to so that no price inside the range is left out.
+// A zero bound stays zero, meaning unbounded.
+func (r *Rates) ConvertRange(min, max int, from, to string) (int, int, error) {
+	factor, err := r.factor(from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	convert := func(v int, ceil bool) int {
+		if v == 0 {
+			return 0
+		}
+		x := new(big.Rat).Mul(big.NewRat(int64(v), 1), factor)
+		q, m := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+		if ceil && m.Sign() > 0 {
+			q.Add(q, big.NewInt(1))
+		}
+		return int(q.Int64())
+	}
+	return convert(min, false), convert(max, true), nil
+}
+
+func round(v *big.Rat) int64 {
+	num := new(big.Int).Abs(v.Num())
+	twice := new(big.Int).Mul(num, big.NewInt(2))
+	twice.Add(twice, v.Denom())
+	q := twice.Quo(twice, new(big.Int).Mul(v.Denom(), big.NewInt(2)))
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
@@ -0,0 +1,40 @@
+package fx
+
+import "sync/atomic"
+
+// Service holds the current rates table and reloads it from its file, so
+// rates can be updated without a redeploy.
+type Service struct {
+	path    string
+	current atomic.Pointer[Rates]
+}
+
+func NewService(path string) (*Service, error) {
+	s := &Service{path: path}
+	return s, s.Reload()
+}
+
+// NewStaticService serves fixed rates, e.g. in tests.
+func NewStaticService(rates *Rates) *Service {
+	s := &Service{}
+	s.current.Store(rates)
+	return s
+}
+
+// Reload replaces the rates with the file's contents. On error the
+// previous rates are kept.
+func (s *Service) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+	rates, err := LoadFile(s.path)
+	if err != nil {
+		return err
+	}
+	s.current.Store(rates)
+	return nil
+}
+
+func (s *Service) Rates() *Rates {
+	return s.current.Load()
+}
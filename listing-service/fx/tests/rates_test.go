@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRates(t *testing.T) *fx.Rates {
+	rates, err := fx.Parse([]byte(`{"base": "USD", "date": "2026-10-01", "rates": {"IDR": 16250, "EUR": "0.92", "JPY": "150.2"}}`))
+	require.NoError(t, err)
+	return rates
+}
+
+func TestConvert_UsesMinorUnitsOfBothCurrencies(t *testing.T) {
+	rates := testRates(t)
+
+	// IDR 32,500,000.00 is USD 2,000.00
+	usd, err := rates.Convert(money.Amount{Minor: 3250000000, Currency: "IDR"}, "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount{Minor: 200000, Currency: "USD"}, usd)
+
+	// EUR 10.00 is USD 10.869..., rounded to JPY 1633
+	jpy, err := rates.Convert(money.Amount{Minor: 1000, Currency: "EUR"}, "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount{Minor: 1633, Currency: "JPY"}, jpy)
+}
+
+func TestConvert_UnknownCurrency(t *testing.T) {
+	_, err := testRates(t).Convert(money.Amount{Minor: 100, Currency: "GBP"}, "USD")
+	assert.ErrorIs(t, err, fx.ErrUnknownCurrency)
+}
+
+func TestConvertRange_WidensToWholeUnits(t *testing.T) {
+	min, max, err := testRates(t).ConvertRange(1000, 4000, "IDR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, min)
+	assert.Equal(t, 1, max)
+
+	min, max, err = testRates(t).ConvertRange(100, 0, "USD", "IDR")
+	assert.NoError(t, err)
+	assert.Equal(t, 1625000, min)
+	assert.Equal(t, 0, max)
+}
+
+func TestParse_RejectsInvalidRates(t *testing.T) {
+	_, err := fx.Parse([]byte(`{"base": "USD", "rates": {"IDR": "-1"}}`))
+	assert.Error(t, err)
+	_, err = fx.Parse([]byte(`{"base": "XXX", "rates": {}}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"real-estate-system/listing-service/screening"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	maxReferences         = 5
+	maxDocuments          = 10
+	maxApplicationMessage = 5000
+)
+
+type ApplicationHandler struct {
+	Repo     interfaces.ApplicationRepository
+	Listings interfaces.ListingRepository
+}
+
+func NewApplicationHandler(repo interfaces.ApplicationRepository, listings interfaces.ListingRepository) *ApplicationHandler {
+	return &ApplicationHandler{Repo: repo, Listings: listings}
+}
+
+// ScreenedApplication is an application as the landlord sees it.
+type ScreenedApplication struct {
+	models.Application
+	Screening screening.Result `json:"screening"`
+}
+
+func (h *ApplicationHandler) CreateApplication(c echo.Context) error {
+	listingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	applicantID, err := strconv.Atoi(c.FormValue("applicant_id"))
+	if err != nil || applicantID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid applicant_id")
+	}
+
+	timestamp := time.Now().UnixMicro()
+	app := models.Application{
+		ListingID:        listingID,
+		ApplicantID:      applicantID,
+		Status:           models.ApplicationStatusSubmitted,
+		EmploymentStatus: c.FormValue("employment_status"),
+		Employer:         strings.TrimSpace(c.FormValue("employer")),
+		Message:          strings.TrimSpace(c.FormValue("message")),
+		References:       []models.ApplicationReference{},
+		Documents:        []models.ApplicationDocument{},
+		CreatedAt:        timestamp,
+		UpdatedAt:        timestamp,
+	}
+	if err := parseApplication(c, &app); err != nil {
+		return err
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.ListingType != "rent" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Applications can only be made for rent listings")
+	}
+	if listing.Status != models.ListingStatusActive {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is no longer available")
+	}
+	if listing.UserID == applicantID {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot apply for your own listing")
+	}
+	app.LandlordID = listing.UserID
+
+	open, err := h.Repo.HasOpenApplication(listingID, applicantID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if open {
+		return echo.NewHTTPError(http.StatusConflict, "You already have an open application for this listing")
+	}
+
+	if err := h.Repo.CreateApplication(&app); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":      true,
+		"application": app,
+	})
+}
+
+// parseApplication reads and validates the applicant's income, employment,
+// references and documents.
+func parseApplication(c echo.Context, app *models.Application) error {
+	var err error
+	app.MonthlyIncome, err = strconv.Atoi(c.FormValue("monthly_income"))
+	if err != nil || app.MonthlyIncome < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid monthly_income")
+	}
+	if !models.ValidEmploymentStatus(app.EmploymentStatus) {
+		return echo.NewHTTPError(http.StatusBadRequest, "employment_status must be 'employed', 'self_employed', 'student', 'retired' or 'unemployed'")
+	}
+	if raw := c.FormValue("employment_months"); raw != "" {
+		app.EmploymentMonths, err = strconv.Atoi(raw)
+		if err != nil || app.EmploymentMonths < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid employment_months")
+		}
+	}
+	if len(app.Message) > maxApplicationMessage {
+		return echo.NewHTTPError(http.StatusBadRequest, "message must be at most 5000 characters")
+	}
+
+	if raw := c.FormValue("references"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &app.References); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "references must be a JSON array")
+		}
+	}
+	if len(app.References) > maxReferences {
+		return echo.NewHTTPError(http.StatusBadRequest, "at most 5 references are allowed")
+	}
+	for _, ref := range app.References {
+		if strings.TrimSpace(ref.Name) == "" || (ref.Email == "" && ref.Phone == "") {
+			return echo.NewHTTPError(http.StatusBadRequest, "each reference needs a name and an email or phone")
+		}
+	}
+
+	if raw := c.FormValue("documents"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &app.Documents); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "documents must be a JSON array")
+		}
+	}
+	if len(app.Documents) > maxDocuments {
+		return echo.NewHTTPError(http.StatusBadRequest, "at most 10 documents are allowed")
+	}
+	for _, doc := range app.Documents {
+		if !models.ValidDocumentKind(doc.Kind) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid document kind")
+		}
+		u, err := url.Parse(doc.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "document url must be an absolute http(s) URL")
+		}
+	}
+	return nil
+}
+
+// GetApplications lists the applications the user has made.
+func (h *ApplicationHandler) GetApplications(c echo.Context) error {
+	userID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	apps, err := h.Repo.GetApplications(models.ApplicationFilter{ApplicantID: userID, Status: c.QueryParam("status")})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":       true,
+		"applications": apps,
+	})
+}
+
+// GetReceivedApplications lists applications for the user's listings with
+// their screening score, newest first or best first with sort=score.
+func (h *ApplicationHandler) GetReceivedApplications(c echo.Context) error {
+	landlordID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	filter := models.ApplicationFilter{LandlordID: landlordID, Status: c.QueryParam("status")}
+	if raw := c.QueryParam("listing_id"); raw != "" {
+		if filter.ListingID, err = strconv.Atoi(raw); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing_id")
+		}
+	}
+
+	apps, err := h.Repo.GetApplications(filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	screened, err := h.screen(tenantListings(c, h.Listings), landlordID, apps)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if c.QueryParam("sort") == "score" {
+		sort.SliceStable(screened, func(i, j int) bool {
+			return screened[i].Screening.Score > screened[j].Screening.Score
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":       true,
+		"applications": screened,
+	})
+}
+
+// screen scores apps with the landlord's current rules against the monthly
+// rent of each listing.
+func (h *ApplicationHandler) screen(listings interfaces.ListingRepository, landlordID int, apps []models.Application) ([]ScreenedApplication, error) {
+	rules, err := h.Repo.GetScreeningRules(landlordID)
+	if err != nil {
+		return nil, err
+	}
+
+	rents := map[int]int{}
+	screened := make([]ScreenedApplication, len(apps))
+	for i, app := range apps {
+		rent, ok := rents[app.ListingID]
+		if !ok {
+			if listing, err := listings.GetListing(app.ListingID); err == nil && listing != nil {
+				rent = listing.MonthlyRent()
+			}
+			rents[app.ListingID] = rent
+		}
+		screened[i] = ScreenedApplication{Application: app, Screening: screening.Score(*rules, app, rent)}
+	}
+	return screened, nil
+}
+
+// GetApplication returns one application to its applicant or, with its
+// screening score, to the landlord.
+func (h *ApplicationHandler) GetApplication(c echo.Context) error {
+	app, userID, err := h.participantApplication(c)
+	if err != nil {
+		return err
+	}
+
+	if userID != app.LandlordID {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"result":      true,
+			"application": app,
+		})
+	}
+
+	screened, err := h.screen(tenantListings(c, h.Listings), userID, []models.Application{*app})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":      true,
+		"application": screened[0],
+	})
+}
+
+func (h *ApplicationHandler) participantApplication(c echo.Context) (*models.Application, int, error) {
+	userID, err := userParam(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	id, err := strconv.ParseInt(c.Param("application_id"), 10, 64)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid application ID")
+	}
+
+	app, err := h.Repo.GetApplication(id)
+	if err != nil || app == nil || (userID != app.ApplicantID && userID != app.LandlordID) {
+		return nil, 0, echo.NewHTTPError(http.StatusNotFound, "Application not found")
+	}
+	return app, userID, nil
+}
+
+// UpdateApplicationStatus lets the landlord move an open application to
+// reviewing, approved or rejected. Approving marks the listing rented and
+// rejects the other open applications.
+func (h *ApplicationHandler) UpdateApplicationStatus(c echo.Context) error {
+	app, userID, err := h.participantApplication(c)
+	if err != nil {
+		return err
+	}
+	if userID != app.LandlordID {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the landlord can decide on an application")
+	}
+
+	status := c.FormValue("status")
+	if !models.ValidApplicationStatus(status) || status == models.ApplicationStatusSubmitted {
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be 'reviewing', 'approved' or 'rejected'")
+	}
+	if !app.Open() {
+		return echo.NewHTTPError(http.StatusConflict, "Application is already "+app.Status)
+	}
+
+	note := strings.TrimSpace(c.FormValue("note"))
+	if status == models.ApplicationStatusApproved {
+		err = h.Repo.ApproveApplication(app, note)
+	} else if status != app.Status {
+		err = h.Repo.UpdateApplicationStatus(app, status, note)
+	}
+	if errors.Is(err, models.ErrListingUnavailable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":      true,
+		"application": app,
+	})
+}
+
+func (h *ApplicationHandler) GetScreeningRules(c echo.Context) error {
+	landlordID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	rules, err := h.Repo.GetScreeningRules(landlordID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"rules":  rules,
+	})
+}
+
+// UpdateScreeningRules changes the fields given and keeps the others.
+func (h *ApplicationHandler) UpdateScreeningRules(c echo.Context) error {
+	landlordID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	rules, err := h.Repo.GetScreeningRules(landlordID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if raw := c.FormValue("min_income_ratio"); raw != "" {
+		rules.MinIncomeRatio, err = strconv.ParseFloat(raw, 64)
+		if err != nil || rules.MinIncomeRatio < 0 || rules.MinIncomeRatio > 10 {
+			return echo.NewHTTPError(http.StatusBadRequest, "min_income_ratio must be between 0 and 10")
+		}
+	}
+
+	ints := []struct {
+		name     string
+		dst      *int
+		min, max int
+	}{
+		{"income_weight", &rules.IncomeWeight, 0, 100},
+		{"min_employment_months", &rules.MinEmploymentMonths, 0, 120},
+		{"employment_weight", &rules.EmploymentWeight, 0, 100},
+		{"min_references", &rules.MinReferences, 0, maxReferences},
+		{"references_weight", &rules.ReferencesWeight, 0, 100},
+		{"documents_weight", &rules.DocumentsWeight, 0, 100},
+	}
+	for _, field := range ints {
+		raw := c.FormValue(field.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < field.min || v > field.max {
+			return echo.NewHTTPError(http.StatusBadRequest, field.name+" must be between "+strconv.Itoa(field.min)+" and "+strconv.Itoa(field.max))
+		}
+		*field.dst = v
+	}
+
+	if _, ok := c.Request().Form["required_documents"]; ok {
+		rules.RequiredDocuments = []string{}
+		for _, kind := range strings.Split(c.FormValue("required_documents"), ",") {
+			kind = strings.TrimSpace(kind)
+			if kind == "" {
+				continue
+			}
+			if !models.ValidDocumentKind(kind) {
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid document kind "+kind)
+			}
+			rules.RequiredDocuments = append(rules.RequiredDocuments, kind)
+		}
+	}
+
+	if rules.IncomeWeight+rules.EmploymentWeight+rules.ReferencesWeight+rules.DocumentsWeight == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "At least one rule must have a weight")
+	}
+
+	rules.UpdatedAt = time.Now().UnixMicro()
+	if err := h.Repo.SaveScreeningRules(rules); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"rules":  rules,
+	})
+}
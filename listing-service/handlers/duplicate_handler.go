@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DuplicateHandler struct {
+	Repo interfaces.DuplicateRepository
+}
+
+func NewDuplicateHandler(repo interfaces.DuplicateRepository) *DuplicateHandler {
+	return &DuplicateHandler{Repo: repo}
+}
+
+// admin returns the administrator's user_id, rejecting other callers.
+func admin(c echo.Context) (int, error) {
+	if !isAdmin(c) {
+		return 0, echo.NewHTTPError(http.StatusForbidden, "Only administrators can do this")
+	}
+	userID, err := strconv.Atoi(c.FormValue("user_id"))
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	return userID, nil
+}
+
+// GetDuplicates lists suspected duplicate pairs, likeliest first, pending
+// ones unless status says otherwise, optionally of one listing_id.
+func (h *DuplicateHandler) GetDuplicates(c echo.Context) error {
+	if _, err := admin(c); err != nil {
+		return err
+	}
+
+	filter := models.DuplicateFilter{Status: c.QueryParam("status")}
+	switch filter.Status {
+	case "":
+		filter.Status = models.DuplicatePending
+	case "all":
+		filter.Status = ""
+	case models.DuplicatePending, models.DuplicateMerged, models.DuplicateDismissed:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be 'pending', 'merged', 'dismissed' or 'all'")
+	}
+	if raw := c.QueryParam("listing_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing_id")
+		}
+		filter.ListingID = id
+	}
+	pageNum, _ := strconv.Atoi(c.QueryParam("page_num"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	pairs, err := h.Repo.GetDuplicatePairs(filter, pageNum, pageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":     true,
+		"duplicates": pairs,
+	})
+}
+
+func (h *DuplicateHandler) pair(c echo.Context) (*models.DuplicatePair, error) {
+	id, err := strconv.ParseInt(c.Param("pair_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid pair ID")
+	}
+	pair, err := h.Repo.GetDuplicatePair(id)
+	if err != nil || pair == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Duplicate pair not found")
+	}
+	return pair, nil
+}
+
+func duplicateError(err error) error {
+	if errors.Is(err, models.ErrDuplicateResolved) || errors.Is(err, models.ErrListingUnavailable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// MergeDuplicate keeps keep_listing_id, one of the pair, and archives the
+// other listing.
+func (h *DuplicateHandler) MergeDuplicate(c echo.Context) error {
+	adminID, err := admin(c)
+	if err != nil {
+		return err
+	}
+	pair, err := h.pair(c)
+	if err != nil {
+		return err
+	}
+	keepID, err := strconv.Atoi(c.FormValue("keep_listing_id"))
+	if err != nil || (keepID != pair.ListingID && keepID != pair.DuplicateID) {
+		return echo.NewHTTPError(http.StatusBadRequest, "keep_listing_id must be one of the pair's listings")
+	}
+
+	if err := h.Repo.MergeDuplicatePair(pair, keepID, adminID); err != nil {
+		return duplicateError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"duplicate": pair,
+	})
+}
+
+// DismissDuplicate marks the pair as different properties.
+func (h *DuplicateHandler) DismissDuplicate(c echo.Context) error {
+	adminID, err := admin(c)
+	if err != nil {
+		return err
+	}
+	pair, err := h.pair(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.DismissDuplicatePair(pair, adminID); err != nil {
+		return duplicateError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"duplicate": pair,
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type FavoriteHandler struct {
+	Repo     interfaces.FavoriteRepository
+	Listings interfaces.ListingRepository
+}
+
+func NewFavoriteHandler(repo interfaces.FavoriteRepository, listings interfaces.ListingRepository) *FavoriteHandler {
+	return &FavoriteHandler{Repo: repo, Listings: listings}
+}
+
+func favoriteParams(c echo.Context) (int, int, error) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		return 0, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	listingID, err := strconv.Atoi(c.Param("listing_id"))
+	if err != nil {
+		return 0, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	return userID, listingID, nil
+}
+
+// AddFavorite bookmarks an active listing. Repeating it is a no-op.
+func (h *FavoriteHandler) AddFavorite(c echo.Context) error {
+	userID, listingID, err := favoriteParams(c)
+	if err != nil {
+		return err
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.Status != models.ListingStatusActive {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is no longer available")
+	}
+
+	favorite := models.Favorite{
+		UserID:     userID,
+		ListingID:  listingID,
+		SavedPrice: listing.Price,
+		CreatedAt:  time.Now().UnixMicro(),
+	}
+	created, err := h.Repo.AddFavorite(&favorite)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	return c.JSON(status, map[string]interface{}{
+		"result":   true,
+		"favorite": favorite,
+	})
+}
+
+func (h *FavoriteHandler) RemoveFavorite(c echo.Context) error {
+	userID, listingID, err := favoriteParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.RemoveFavorite(userID, listingID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+	})
+}
+
+func (h *FavoriteHandler) GetFavorites(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+
+	favorites, err := h.Repo.GetFavorites(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"favorites": favorites,
+	})
+}
+
+// GetFavoriteCounts returns how many users favorited each of the comma
+// separated listing ids.
+func (h *FavoriteHandler) GetFavoriteCounts(c echo.Context) error {
+	var ids []int
+	for _, raw := range strings.Split(c.QueryParam("ids"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "ids is required")
+	}
+
+	counts, err := h.Repo.CountFavorites(ids)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"counts": counts,
+	})
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+	"net/mail"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	maxInquiryMessage  = 2000
+	maxSchedulePrefLen = 200
+)
+
+type InquiryHandler struct {
+	Repo     interfaces.InquiryRepository
+	Listings interfaces.ListingRepository
+}
+
+func NewInquiryHandler(repo interfaces.InquiryRepository, listings interfaces.ListingRepository) *InquiryHandler {
+	return &InquiryHandler{Repo: repo, Listings: listings}
+}
+
+// pagination reads page_num and page_size, defaulting to the first page of 10.
+func pagination(c echo.Context) (int, int) {
+	pageNum, _ := strconv.Atoi(c.QueryParam("page_num"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	return pageNum, pageSize
+}
+
+// CreateInquiry records a lead on an active listing for its owner.
+func (h *InquiryHandler) CreateInquiry(c echo.Context) error {
+	listingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+
+	inquiry := models.Inquiry{
+		ListingID:          listingID,
+		Status:             models.InquiryStatusNew,
+		Name:               strings.TrimSpace(c.FormValue("name")),
+		Email:              strings.TrimSpace(c.FormValue("email")),
+		Phone:              strings.TrimSpace(c.FormValue("phone")),
+		Message:            strings.TrimSpace(c.FormValue("message")),
+		PreferredContact:   c.FormValue("preferred_contact"),
+		SchedulePreference: strings.TrimSpace(c.FormValue("schedule_preference")),
+		Source:             c.FormValue("source"),
+		SourceRef:          strings.TrimSpace(c.FormValue("source_ref")),
+	}
+
+	if raw := c.FormValue("buyer_id"); raw != "" {
+		inquiry.BuyerID, err = strconv.Atoi(raw)
+		if err != nil || inquiry.BuyerID <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid buyer_id")
+		}
+	}
+	if err := validateInquiry(&inquiry); err != nil {
+		return err
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.Status != models.ListingStatusActive {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is no longer available")
+	}
+	if inquiry.BuyerID == listing.UserID {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot send an inquiry on your own listing")
+	}
+	inquiry.OwnerID = listing.UserID
+	inquiry.TenantID = listing.TenantID
+
+	timestamp := time.Now().UnixMicro()
+	inquiry.CreatedAt = timestamp
+	inquiry.UpdatedAt = timestamp
+
+	if err := h.Repo.CreateInquiry(&inquiry); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":  true,
+		"inquiry": inquiry,
+	})
+}
+
+func validateInquiry(inquiry *models.Inquiry) error {
+	if inquiry.Message == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "message is required")
+	}
+	if len(inquiry.Message) > maxInquiryMessage {
+		return echo.NewHTTPError(http.StatusBadRequest, "message must be at most 2000 characters")
+	}
+	if len(inquiry.SchedulePreference) > maxSchedulePrefLen {
+		return echo.NewHTTPError(http.StatusBadRequest, "schedule_preference must be at most 200 characters")
+	}
+	if inquiry.Email != "" {
+		if _, err := mail.ParseAddress(inquiry.Email); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid email")
+		}
+	}
+
+	if inquiry.PreferredContact == "" {
+		inquiry.PreferredContact = models.ContactEmail
+		if inquiry.Email == "" {
+			inquiry.PreferredContact = models.ContactPhone
+		}
+	}
+	switch inquiry.PreferredContact {
+	case models.ContactEmail:
+		if inquiry.Email == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "email is required when preferred_contact is 'email'")
+		}
+	case models.ContactPhone:
+		if inquiry.Phone == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "phone is required when preferred_contact is 'phone'")
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "preferred_contact must be 'email' or 'phone'")
+	}
+
+	switch inquiry.Source {
+	case "":
+		inquiry.Source = models.InquirySourceWeb
+	case models.InquirySourceWeb, models.InquirySourcePartner:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "source must be 'web' or 'partner'")
+	}
+	if inquiry.Source == models.InquirySourcePartner && inquiry.SourceRef == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "source_ref is required for partner inquiries")
+	}
+	return nil
+}
+
+// GetInquiries is the owner's inbox, newest first, optionally by status.
+func (h *InquiryHandler) GetInquiries(c echo.Context) error {
+	ownerID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || ownerID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+
+	status := c.QueryParam("status")
+	if status != "" && !models.ValidInquiryStatus(status) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid status")
+	}
+
+	pageNum, pageSize := pagination(c)
+	inquiries, err := h.Repo.GetInquiries(models.InquiryFilter{OwnerID: ownerID, Status: status}, pageNum, pageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"inquiries": inquiries,
+	})
+}
+
+// UpdateInquiryStatus moves an inquiry in the owner's inbox to another status.
+func (h *InquiryHandler) UpdateInquiryStatus(c echo.Context) error {
+	ownerID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || ownerID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	id, err := strconv.ParseInt(c.Param("inquiry_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid inquiry ID")
+	}
+
+	status := c.FormValue("status")
+	if !models.ValidInquiryStatus(status) {
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be one of new, contacted, qualified, closed")
+	}
+
+	inquiry, err := h.Repo.GetInquiry(id)
+	if err != nil || inquiry == nil || inquiry.OwnerID != ownerID {
+		return echo.NewHTTPError(http.StatusNotFound, "Inquiry not found")
+	}
+
+	if inquiry.Status != status {
+		if err := h.Repo.UpdateInquiryStatus(inquiry, status); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"inquiry": inquiry,
+	})
+}
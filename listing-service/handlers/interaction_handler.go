@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InteractionHandler struct {
+	Repo interfaces.InteractionRepository
+}
+
+func NewInteractionHandler(repo interfaces.InteractionRepository) *InteractionHandler {
+	return &InteractionHandler{Repo: repo}
+}
+
+// GetInteractions lists the closed inquiries, past viewings and leases of
+// the user with the owner, agent or landlord given as with, latest first.
+// The gateway uses it to check who may review whom.
+func (h *InteractionHandler) GetInteractions(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	counterpartID, err := strconv.Atoi(c.QueryParam("with"))
+	if err != nil || counterpartID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid with")
+	}
+
+	interactions, err := h.Repo.GetCompletedInteractions(userID, counterpartID, time.Now().UnixMicro())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":       true,
+		"interactions": interactions,
+	})
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/rent"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	dateFormat    = "2006-01-02"
+	maxLeaseTerm  = 120
+	maxPaymentDay = 28
+)
+
+type LeaseHandler struct {
+	Repo         interfaces.LeaseRepository
+	Listings     interfaces.ListingRepository
+	Applications interfaces.ApplicationRepository
+}
+
+func NewLeaseHandler(repo interfaces.LeaseRepository, listings interfaces.ListingRepository, applications interfaces.ApplicationRepository) *LeaseHandler {
+	return &LeaseHandler{Repo: repo, Listings: listings, Applications: applications}
+}
+
+// formDate parses a YYYY-MM-DD form value as midnight UTC.
+func formDate(c echo.Context, name string) (time.Time, error) {
+	t, err := time.Parse(dateFormat, c.FormValue(name))
+	if err != nil {
+		return t, echo.NewHTTPError(http.StatusBadRequest, name+" must be a date (YYYY-MM-DD)")
+	}
+	return t, nil
+}
+
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// leaseTerms reads term_months, monthly_rent, billing_period and
+// payment_due_day into lease, keeping its current values for omitted fields.
+func leaseTerms(c echo.Context, lease *models.Lease) error {
+	var err error
+	if raw := c.FormValue("term_months"); raw != "" || lease.TermMonths == 0 {
+		lease.TermMonths, err = strconv.Atoi(raw)
+		if err != nil || lease.TermMonths < 1 || lease.TermMonths > maxLeaseTerm {
+			return echo.NewHTTPError(http.StatusBadRequest, "term_months must be between 1 and 120")
+		}
+	}
+	if raw := c.FormValue("monthly_rent"); raw != "" {
+		lease.MonthlyRent, err = strconv.Atoi(raw)
+		if err != nil || lease.MonthlyRent <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid monthly_rent")
+		}
+	}
+	if raw := c.FormValue("billing_period"); raw != "" {
+		if raw != models.BillingMonthly && raw != models.BillingYearly {
+			return echo.NewHTTPError(http.StatusBadRequest, "billing_period must be 'monthly' or 'yearly'")
+		}
+		lease.BillingPeriod = raw
+	}
+	if raw := c.FormValue("payment_due_day"); raw != "" {
+		lease.PaymentDueDay, err = strconv.Atoi(raw)
+		if err != nil || lease.PaymentDueDay < 1 || lease.PaymentDueDay > maxPaymentDay {
+			return echo.NewHTTPError(http.StatusBadRequest, "payment_due_day must be between 1 and 28")
+		}
+	}
+	lease.EndsAt = time.UnixMicro(lease.StartsAt).UTC().AddDate(0, lease.TermMonths, 0).UnixMicro()
+	return nil
+}
+
+func leaseError(err error) error {
+	if errors.Is(err, models.ErrLeaseOverlap) || errors.Is(err, models.ErrLeaseRenewed) || errors.Is(err, models.ErrListingUnavailable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// CreateLease records a tenancy of one of the landlord's rent listings. With
+// application_id the tenant is the approved applicant.
+func (h *LeaseHandler) CreateLease(c echo.Context) error {
+	landlordID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+	listingID, err := strconv.Atoi(c.FormValue("listing_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing_id")
+	}
+	startsOn, err := formDate(c, "starts_on")
+	if err != nil {
+		return err
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil || listing.UserID != landlordID {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.ListingType != "rent" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Leases can only be created for rent listings")
+	}
+
+	timestamp := time.Now().UnixMicro()
+	lease := models.Lease{
+		ListingID:     listingID,
+		LandlordID:    landlordID,
+		Status:        models.LeaseStatusActive,
+		StartsAt:      startsOn.UnixMicro(),
+		MonthlyRent:   listing.MonthlyRent(),
+		PaymentDueDay: min(startsOn.Day(), maxPaymentDay),
+		BillingPeriod: models.BillingMonthly,
+		Currency:      listing.PriceAmount().Currency,
+		CreatedAt:     timestamp,
+		UpdatedAt:     timestamp,
+	}
+
+	if raw := c.FormValue("application_id"); raw != "" {
+		if lease.ApplicationID, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid application_id")
+		}
+		app, err := h.Applications.GetApplication(lease.ApplicationID)
+		if err != nil || app == nil || app.ListingID != listingID {
+			return echo.NewHTTPError(http.StatusNotFound, "Application not found")
+		}
+		if app.Status != models.ApplicationStatusApproved {
+			return echo.NewHTTPError(http.StatusConflict, "Application is not approved")
+		}
+		lease.TenantID = app.ApplicantID
+	} else {
+		lease.TenantID, err = strconv.Atoi(c.FormValue("tenant_id"))
+		if err != nil || lease.TenantID <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid tenant_id")
+		}
+	}
+	if lease.TenantID == landlordID {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot lease your own listing to yourself")
+	}
+
+	if raw := c.FormValue("deposit"); raw != "" {
+		lease.Deposit, err = strconv.Atoi(raw)
+		if err != nil || lease.Deposit < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid deposit")
+		}
+	}
+	if raw := c.FormValue("currency"); raw != "" {
+		currency := money.Normalize(raw)
+		if !money.Valid(currency) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unsupported currency")
+		}
+		// The rent defaults to the listing's price, in its currency.
+		if currency != lease.Currency && (c.FormValue("monthly_rent") == "" || c.FormValue("deposit") == "") {
+			return echo.NewHTTPError(http.StatusBadRequest, "monthly_rent and deposit are required in a currency other than the listing's")
+		}
+		lease.Currency = currency
+	}
+	if err := leaseTerms(c, &lease); err != nil {
+		return err
+	}
+
+	if err := h.Repo.CreateLease(&lease, rent.Schedule(lease)); err != nil {
+		return leaseError(err)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result": true,
+		"lease":  lease,
+	})
+}
+
+// GetLeases lists the user's leases as landlord or tenant. With
+// expiring_within_days only active leases ending that soon are returned.
+func (h *LeaseHandler) GetLeases(c echo.Context) error {
+	userID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	filter := models.LeaseFilter{UserID: userID, Status: c.QueryParam("status")}
+	if raw := c.QueryParam("listing_id"); raw != "" {
+		if filter.ListingID, err = strconv.Atoi(raw); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing_id")
+		}
+	}
+	if raw := c.QueryParam("expiring_within_days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid expiring_within_days")
+		}
+		filter.Status = models.LeaseStatusActive
+		filter.EndingBefore = time.Now().AddDate(0, 0, days).UnixMicro()
+	}
+
+	leases, err := h.Repo.GetLeases(filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"leases": leases,
+	})
+}
+
+func (h *LeaseHandler) participantLease(c echo.Context) (*models.Lease, int, error) {
+	return participantLease(c, h.Repo)
+}
+
+// participantLease loads the :lease_id lease if :user_id is its landlord or
+// tenant.
+func participantLease(c echo.Context, leases interfaces.LeaseRepository) (*models.Lease, int, error) {
+	userID, err := userParam(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	id, err := strconv.ParseInt(c.Param("lease_id"), 10, 64)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid lease ID")
+	}
+
+	lease, err := leases.GetLease(id)
+	if err != nil || lease == nil || !lease.HasParticipant(userID) {
+		return nil, 0, echo.NewHTTPError(http.StatusNotFound, "Lease not found")
+	}
+	return lease, userID, nil
+}
+
+// GetLease returns the lease with its rent schedule.
+func (h *LeaseHandler) GetLease(c echo.Context) error {
+	lease, _, err := h.participantLease(c)
+	if err != nil {
+		return err
+	}
+
+	charges, err := h.Repo.GetRentCharges(lease.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":   true,
+		"lease":    lease,
+		"schedule": charges,
+	})
+}
+
+// RenewLease creates a follow-on lease starting when this one ends, on the
+// same terms unless new ones are given.
+func (h *LeaseHandler) RenewLease(c echo.Context) error {
+	lease, userID, err := h.participantLease(c)
+	if err != nil {
+		return err
+	}
+	if userID != lease.LandlordID {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the landlord can renew a lease")
+	}
+	if lease.Status != models.LeaseStatusActive || lease.TerminationReason != "" {
+		return echo.NewHTTPError(http.StatusConflict, "Only running leases can be renewed")
+	}
+	if lease.RenewedToID != 0 {
+		return echo.NewHTTPError(http.StatusConflict, models.ErrLeaseRenewed.Error())
+	}
+
+	timestamp := time.Now().UnixMicro()
+	renewal := *lease
+	renewal.ID = 0
+	renewal.ApplicationID = 0
+	renewal.StartsAt = lease.EndsAt
+	renewal.TermMonths = 0
+	renewal.RenewedFromID = lease.ID
+	renewal.RenewedToID = 0
+	renewal.ExpiryNotifiedAt = 0
+	renewal.CreatedAt = timestamp
+	renewal.UpdatedAt = timestamp
+	if err := leaseTerms(c, &renewal); err != nil {
+		return err
+	}
+
+	if err := h.Repo.RenewLease(lease, &renewal, rent.Schedule(renewal)); err != nil {
+		return leaseError(err)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result": true,
+		"lease":  renewal,
+	})
+}
+
+// TerminateLease ends the lease early on ends_on, the move-out date, which
+// defaults to today. Either party may terminate.
+func (h *LeaseHandler) TerminateLease(c echo.Context) error {
+	lease, _, err := h.participantLease(c)
+	if err != nil {
+		return err
+	}
+	if lease.Status != models.LeaseStatusActive || lease.TerminationReason != "" {
+		return echo.NewHTTPError(http.StatusConflict, "Lease is not running")
+	}
+
+	reason := strings.TrimSpace(c.FormValue("reason"))
+	if reason == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "reason is required")
+	}
+
+	endsOn := today()
+	if c.FormValue("ends_on") != "" {
+		if endsOn, err = formDate(c, "ends_on"); err != nil {
+			return err
+		}
+	}
+	endsAt := endsOn.UnixMicro()
+	if endsOn.Before(today()) || endsAt >= lease.EndsAt {
+		return echo.NewHTTPError(http.StatusBadRequest, "ends_on must be between today and the end of the lease")
+	}
+	if endsAt < lease.StartsAt {
+		endsAt = lease.StartsAt
+	}
+
+	if err := h.Repo.TerminateLease(lease, endsAt, reason); err != nil {
+		return leaseError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"lease":  lease,
+	})
+}
@@ -1,27 +1,68 @@
 package handlers
 
 import (
+	"log"
+	"math"
 	"net/http"
+	"real-estate-system/listing-service/dedup"
+	"real-estate-system/listing-service/fx"
 	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/moderation"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/mortgage"
 	"real-estate-system/listing-service/repository/interfaces"
+	"real-estate-system/listing-service/tenant"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	maxAddress     = 300
+	maxDescription = 5000
+)
+
 type ListingHandler struct {
-	Repo interfaces.ListingRepository
+	Repo       interfaces.ListingRepository
+	FX         *fx.Service
+	Duplicates *dedup.Detector
+	Moderator  *moderation.Moderator
+	Mortgage   mortgage.Terms
+}
+
+func NewListingHandler(repo interfaces.ListingRepository, rates *fx.Service, duplicates *dedup.Detector, moderator *moderation.Moderator, mortgageTerms mortgage.Terms) *ListingHandler {
+	return &ListingHandler{Repo: repo, FX: rates, Duplicates: duplicates, Moderator: moderator, Mortgage: mortgageTerms}
+}
+
+// tenantListings narrows repo to the listings of the request's tenant.
+func tenantListings(c echo.Context, repo interfaces.ListingRepository) interfaces.ListingRepository {
+	return repo.ForTenant(tenant.ID(c))
+}
+
+func (h *ListingHandler) listings(c echo.Context) interfaces.ListingRepository {
+	return tenantListings(c, h.Repo)
 }
 
-func NewListingHandler(repo interfaces.ListingRepository) *ListingHandler {
-	return &ListingHandler{Repo: repo}
+// PossibleDuplicate is an existing listing that may be the same property as
+// a new one.
+type PossibleDuplicate struct {
+	PairID    int64              `json:"pair_id"`
+	ListingID int                `json:"listing_id"`
+	Score     float64            `json:"score"`
+	Signals   map[string]float64 `json:"signals"`
 }
 
+// CreateListing creates a listing, live straight away unless it breaks a
+// moderation rule. Such listings wait in pending_review for an
+// administrator, and moderation_reasons says why.
 func (h *ListingHandler) CreateListing(c echo.Context) error {
 	userIDStr := c.FormValue("user_id")
 	listingType := c.FormValue("listing_type")
 	priceStr := c.FormValue("price")
+	city := strings.TrimSpace(c.FormValue("city"))
+	district := strings.TrimSpace(c.FormValue("district"))
 
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
@@ -37,26 +78,150 @@ func (h *ListingHandler) CreateListing(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "listing_type must be 'rent' or 'sale'")
 	}
 
+	currency := money.DefaultCurrency
+	if raw := c.FormValue("currency"); raw != "" {
+		currency = money.Normalize(raw)
+		if !money.Valid(currency) || !h.FX.Rates().Has(currency) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unsupported currency")
+		}
+	}
+
+	rentPeriod := c.FormValue("rent_period")
+	switch {
+	case listingType == "sale" && rentPeriod != "":
+		return echo.NewHTTPError(http.StatusBadRequest, "rent_period only applies to rent listings")
+	case listingType == "rent" && rentPeriod == "":
+		rentPeriod = models.RentPerMonth
+	case listingType == "rent" && rentPeriod != models.RentPerMonth && rentPeriod != models.RentPerYear:
+		return echo.NewHTTPError(http.StatusBadRequest, "rent_period must be 'month' or 'year'")
+	}
+
 	timestamp := time.Now().UnixMicro()
 
 	listing := models.Listing{
+		TenantID:    tenant.ID(c),
 		UserID:      userID,
 		Price:       price,
+		Currency:    currency,
+		RentPeriod:  rentPeriod,
 		ListingType: listingType,
+		City:        city,
+		District:    district,
+		Status:      models.ListingStatusActive,
+		ListedAt:    timestamp,
 		CreatedAt:   timestamp,
 		UpdatedAt:   timestamp,
 	}
+	if err := parseListingDetails(c, &listing); err != nil {
+		return err
+	}
 
-	if err := h.Repo.CreateListing(&listing); err != nil {
+	reasons, err := h.Moderator.Check(&listing)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if len(reasons) > 0 {
+		review := models.ListingReview{Reasons: reasons, CreatedAt: timestamp}
+		err = h.Moderator.Repo.SubmitListing(&listing, &review)
+	} else {
+		err = h.listings(c).CreateListing(&listing)
+	}
+	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.JSON(http.StatusCreated, map[string]interface{}{
-		"result":  true,
-		"listing": listing,
-	})
+	response := map[string]interface{}{
+		"result":              true,
+		"listing":             listing,
+		"possible_duplicates": h.possibleDuplicates(&listing),
+	}
+	if len(reasons) > 0 {
+		response["moderation_reasons"] = reasons
+	}
+	return c.JSON(http.StatusCreated, response)
+}
+
+// possibleDuplicates checks a new listing for duplicates. The listing is
+// created either way, so a failed check is only logged; the batch scan
+// catches up later.
+func (h *ListingHandler) possibleDuplicates(listing *models.Listing) []PossibleDuplicate {
+	found := []PossibleDuplicate{}
+	pairs, err := h.Duplicates.Check(listing)
+	if err != nil {
+		log.Printf("duplicates: checking listing %d: %v", listing.ID, err)
+		return found
+	}
+	for _, pair := range pairs {
+		found = append(found, PossibleDuplicate{
+			PairID:    pair.ID,
+			ListingID: pair.Other(listing.ID),
+			Score:     pair.Score,
+			Signals:   pair.Signals,
+		})
+	}
+	return found
 }
 
+// parseListingDetails reads the optional property type, description,
+// address, coordinates, room counts and floor area of a new listing.
+func parseListingDetails(c echo.Context, listing *models.Listing) error {
+	listing.PropertyType = c.FormValue("property_type")
+	if listing.PropertyType != "" && !models.ValidPropertyType(listing.PropertyType) {
+		return echo.NewHTTPError(http.StatusBadRequest, "property_type must be 'house', 'apartment', 'townhouse', 'land' or 'commercial'")
+	}
+
+	listing.Description = strings.TrimSpace(c.FormValue("description"))
+	if len(listing.Description) > maxDescription {
+		return echo.NewHTTPError(http.StatusBadRequest, "description is too long")
+	}
+
+	listing.Address = strings.TrimSpace(c.FormValue("address"))
+	if len(listing.Address) > maxAddress {
+		return echo.NewHTTPError(http.StatusBadRequest, "address is too long")
+	}
+
+	rawLat, rawLon := c.FormValue("latitude"), c.FormValue("longitude")
+	if rawLat != "" || rawLon != "" {
+		lat, errLat := strconv.ParseFloat(rawLat, 64)
+		lon, errLon := strconv.ParseFloat(rawLon, 64)
+		if errLat != nil || errLon != nil || !finiteDegrees(lat, lon) || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+			return echo.NewHTTPError(http.StatusBadRequest, "latitude and longitude must be given together as decimal degrees")
+		}
+		listing.Latitude, listing.Longitude = lat, lon
+	}
+
+	ints := map[string]*int{
+		"bedrooms":   &listing.Bedrooms,
+		"bathrooms":  &listing.Bathrooms,
+		"floor_area": &listing.FloorArea,
+	}
+	for name, dst := range ints {
+		raw := c.FormValue(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+		}
+		*dst = v
+	}
+	return nil
+}
+
+// finiteDegrees reports whether no coordinate is NaN or infinite. NaN gets
+// past the range checks and breaks distance and duplicate matching.
+func finiteDegrees(coords ...float64) bool {
+	for _, v := range coords {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetListings lists live listings. Owners see their own listings under
+// review too, when viewer_id is the user_id filtered on.
 func (h *ListingHandler) GetListings(c echo.Context) error {
 	pageNum, _ := strconv.Atoi(c.QueryParam("page_num"))
 	if pageNum < 1 {
@@ -67,13 +232,262 @@ func (h *ListingHandler) GetListings(c echo.Context) error {
 		pageSize = 10
 	}
 
-	listings, err := h.Repo.GetListings(pageNum, pageSize)
+	filter, err := parseListingFilter(c)
+	if err != nil {
+		return err
+	}
+	display, err := h.displayCurrency(c)
+	if err != nil {
+		return err
+	}
+	if err := h.normalizePriceFilter(&filter, display); err != nil {
+		return err
+	}
+	if viewerID, _ := strconv.Atoi(c.QueryParam("viewer_id")); viewerID > 0 && viewerID == filter.UserID {
+		filter.IncludeUnreviewed = true
+	}
+
+	listings, err := h.listings(c).GetListings(filter, pageNum, pageSize)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	for i := range listings {
+		h.setDisplayPrice(&listings[i], display)
+	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"result":   true,
 		"listings": listings,
 	})
 }
+
+// UpdateListingPrice lets the owner (user_id) change the asking price.
+func (h *ListingHandler) UpdateListingPrice(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	userID, err := strconv.Atoi(c.FormValue("user_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	price, err := strconv.Atoi(c.FormValue("price"))
+	if err != nil || price <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid price")
+	}
+
+	listing, err := h.listings(c).GetListing(id)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.UserID != userID {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the owner can change the price")
+	}
+	if listing.Status == models.ListingStatusArchived {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is archived")
+	}
+
+	if err := h.listings(c).UpdateListingPrice(listing, price); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"listing": listing,
+	})
+}
+
+// GetPriceHistory lists every price change of the listing, oldest first.
+// Like GetListing, listings that are not public are only shown to their
+// owner (user_id) and administrators.
+func (h *ListingHandler) GetPriceHistory(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+
+	listing, err := h.listings(c).GetListing(id)
+	if err != nil || listing == nil || !canView(c, listing) {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+
+	history, err := h.listings(c).GetPriceHistory(id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":        true,
+		"listing_id":    listing.ID,
+		"price":         listing.Price,
+		"currency":      listing.Currency,
+		"price_history": history,
+	})
+}
+
+// GetListing returns a listing. Sale listings come with the installments of
+// buying at their price on the default mortgage terms.
+func (h *ListingHandler) GetListing(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+
+	display, err := h.displayCurrency(c)
+	if err != nil {
+		return err
+	}
+
+	listing, err := h.listings(c).GetListing(id)
+	if err != nil || listing == nil || !canView(c, listing) {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	h.setDisplayPrice(listing, display)
+
+	response := map[string]interface{}{
+		"result":  true,
+		"listing": listing,
+	}
+	if estimate := mortgageEstimate(listing, h.Mortgage); estimate != nil {
+		response["mortgage_estimate"] = estimate
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// canView reports whether the caller may see the listing. Listings under
+// review, rejected or hidden are only shown to their owner (user_id) and
+// administrators.
+func canView(c echo.Context, listing *models.Listing) bool {
+	if listing.IsPublic() {
+		return true
+	}
+	userID, _ := strconv.Atoi(c.QueryParam("user_id"))
+	return isAdmin(c) || (userID > 0 && userID == listing.UserID)
+}
+
+// UpdateListingStatus archives or reactivates a listing. Listings under
+// review wait for the administrators, and rejected or hidden ones can only
+// be archived.
+func (h *ListingHandler) UpdateListingStatus(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+
+	status := c.FormValue("status")
+	if status != models.ListingStatusActive && status != models.ListingStatusArchived {
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be 'active' or 'archived'")
+	}
+
+	listing, err := h.listings(c).GetListing(id)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	switch {
+	case listing.Status == models.ListingStatusPendingReview:
+		return echo.NewHTTPError(http.StatusConflict, "Listing is waiting for review")
+	case listing.Status == models.ListingStatusRejected && status == models.ListingStatusActive:
+		return echo.NewHTTPError(http.StatusConflict, "Listing was rejected")
+	case listing.Status == models.ListingStatusHidden && status == models.ListingStatusActive:
+		return echo.NewHTTPError(http.StatusConflict, "Listing is hidden while reports about it are reviewed")
+	}
+
+	if listing.Status != status {
+		if err := h.listings(c).UpdateListingStatus(listing, status); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"listing": listing,
+	})
+}
+
+// displayCurrency reads the currency query parameter, the currency to show
+// prices in. It is empty if none was asked for.
+func (h *ListingHandler) displayCurrency(c echo.Context) (string, error) {
+	currency := money.Normalize(c.QueryParam("currency"))
+	if currency != "" && !h.FX.Rates().Has(currency) {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Unsupported currency")
+	}
+	return currency, nil
+}
+
+// normalizePriceFilter turns min_price and max_price, given in currency or
+// the default currency, into a range per listing currency. Rents compare
+// per month.
+func (h *ListingHandler) normalizePriceFilter(filter *models.ListingFilter, currency string) error {
+	if filter.MinPrice == 0 && filter.MaxPrice == 0 {
+		return nil
+	}
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	rates := h.FX.Rates()
+	for _, code := range rates.Currencies() {
+		min, max, err := rates.ConvertRange(filter.MinPrice, filter.MaxPrice, currency, code)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		filter.PriceRanges = append(filter.PriceRanges, models.PriceRange{Currency: code, Min: min, Max: max})
+	}
+	return nil
+}
+
+// setDisplayPrice converts the listing's price for display. Prices without
+// a rate are left as they are.
+func (h *ListingHandler) setDisplayPrice(listing *models.Listing, currency string) {
+	if currency == "" {
+		return
+	}
+	if converted, err := h.FX.Rates().Convert(listing.PriceAmount(), currency); err == nil {
+		listing.DisplayPrice = &converted
+	}
+}
+
+func parseListingFilter(c echo.Context) (models.ListingFilter, error) {
+	filter := models.ListingFilter{
+		ListingType: c.QueryParam("listing_type"),
+		Area:        strings.TrimSpace(c.QueryParam("area")),
+	}
+
+	if filter.ListingType != "" && filter.ListingType != "rent" && filter.ListingType != "sale" {
+		return filter, echo.NewHTTPError(http.StatusBadRequest, "listing_type must be 'rent' or 'sale'")
+	}
+
+	ints := map[string]*int{
+		"user_id":   &filter.UserID,
+		"min_price": &filter.MinPrice,
+		"max_price": &filter.MaxPrice,
+	}
+	for name, dst := range ints {
+		raw := c.QueryParam(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+		}
+		*dst = v
+	}
+
+	if raw := c.QueryParam("price_dropped_since"); raw != "" {
+		since, err := parseSince(raw)
+		if err != nil {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "price_dropped_since must be RFC 3339 or YYYY-MM-DD")
+		}
+		filter.PriceDroppedSince = since.UnixMicro()
+	}
+
+	return filter, nil
+}
+
+func parseSince(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(dateFormat, raw)
+}
@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/media"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// roleAdmin is the role value the gateway passes for administrators.
+	roleAdmin = "admin"
+
+	maxMediaTitle = 200
+	maxTourURL    = 2048
+)
+
+// isAdmin reports whether the gateway passed the administrator role.
+func isAdmin(c echo.Context) bool {
+	return c.FormValue("role") == roleAdmin
+}
+
+type MediaHandler struct {
+	Repo     interfaces.MediaRepository
+	Listings interfaces.ListingRepository
+	Storage  media.Storage
+	Signer   *media.Signer
+}
+
+func NewMediaHandler(repo interfaces.MediaRepository, listings interfaces.ListingRepository, storage media.Storage, signer *media.Signer) *MediaHandler {
+	return &MediaHandler{Repo: repo, Listings: listings, Storage: storage, Signer: signer}
+}
+
+// ownedListing loads the :id listing if the user_id form value is its owner.
+func (h *MediaHandler) ownedListing(c echo.Context) (*models.Listing, int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	userID, err := strconv.Atoi(c.FormValue("user_id"))
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(id)
+	if err != nil || listing == nil {
+		return nil, 0, echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.UserID != userID {
+		return nil, 0, echo.NewHTTPError(http.StatusForbidden, "Only the owner can change the listing's attachments")
+	}
+	return listing, userID, nil
+}
+
+// ownedMedia loads the attachment named by the param of the owner's :id
+// listing. With mediaType it must be of that type.
+func (h *MediaHandler) ownedMedia(c echo.Context, param, mediaType string) (*models.ListingMedia, error) {
+	listing, _, err := h.ownedListing(c)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid attachment ID")
+	}
+
+	item, err := h.Repo.GetMediaItem(id)
+	if err != nil || item == nil || item.ListingID != listing.ID || (mediaType != "" && item.Type != mediaType) {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Attachment not found")
+	}
+	return item, nil
+}
+
+func mediaError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrTooManyMedia), errors.Is(err, models.ErrListingUnavailable):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, models.ErrPhotoOrder):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// present replaces the URLs of private attachments with signed ones.
+func (h *MediaHandler) present(items []models.ListingMedia) {
+	now := time.Now()
+	for i := range items {
+		if items[i].Private {
+			link, expires := h.Signer.Sign(items[i].ID, now)
+			items[i].URL = link
+			items[i].URLExpiresAt = expires.UnixMicro()
+		}
+	}
+}
+
+// UploadPhoto adds the multipart file "photo" to the listing. The image is
+// stored without its metadata, together with its thumbnails.
+func (h *MediaHandler) UploadPhoto(c echo.Context) error {
+	photo, err := h.create(c, models.MediaPhoto, "photo")
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result": true,
+		"photo":  photo,
+	})
+}
+
+// CreateAttachment adds an attachment of the given type to the listing:
+// the multipart file "file", or for virtual tours the https url.
+func (h *MediaHandler) CreateAttachment(c echo.Context) error {
+	mediaType := c.FormValue("type")
+	if _, ok := models.MediaRules[mediaType]; !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "type must be 'photo', 'floor_plan', 'document' or 'virtual_tour_url'")
+	}
+
+	attachment, err := h.create(c, mediaType, "file")
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":     true,
+		"attachment": attachment,
+	})
+}
+
+// create stores the upload in field, or the link, as an attachment of the
+// owner's listing.
+func (h *MediaHandler) create(c echo.Context, mediaType, field string) (*models.ListingMedia, error) {
+	listing, userID, err := h.ownedListing(c)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status == models.ListingStatusArchived {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Listing is archived")
+	}
+
+	title := strings.TrimSpace(c.FormValue("title"))
+	if len(title) > maxMediaTitle {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "title is too long")
+	}
+
+	rule := models.MediaRules[mediaType]
+	item := models.ListingMedia{
+		ListingID:  listing.ID,
+		UploadedBy: userID,
+		Type:       mediaType,
+		Title:      title,
+		Private:    rule.Private,
+		CreatedAt:  time.Now().UnixMicro(),
+	}
+
+	if len(rule.ContentTypes) == 0 {
+		if item.URL, err = tourURL(c.FormValue("url")); err != nil {
+			return nil, err
+		}
+	} else if err := h.storeUpload(c, field, rule, &item); err != nil {
+		return nil, err
+	}
+
+	if err := h.Repo.AddMedia(&item); err != nil {
+		h.deleteFiles(item.Keys)
+		return nil, mediaError(err)
+	}
+
+	created := []models.ListingMedia{item}
+	h.present(created)
+	return &created[0], nil
+}
+
+// tourURL validates the link to a virtual tour.
+func tourURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if raw == "" || len(raw) > maxTourURL || err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "url must be an https link to the virtual tour")
+	}
+	return u.String(), nil
+}
+
+// storeUpload validates the multipart file against the rule and stores it
+// in item. Images are re-encoded without their metadata; public ones also
+// get thumbnails.
+func (h *MediaHandler) storeUpload(c echo.Context, field string, rule models.MediaRule, item *models.ListingMedia) error {
+	header, err := c.FormFile(field)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, field+" file is required")
+	}
+	if header.Size > media.MaxUploadBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, media.ErrTooLarge.Error())
+	}
+	file, err := header.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, field+" could not be read")
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, media.MaxUploadBytes+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, field+" could not be read")
+	}
+	if len(data) > media.MaxUploadBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, media.ErrTooLarge.Error())
+	}
+
+	contentType := media.DetectType(data)
+	if !rule.Allows(contentType) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, item.Type+" must be one of "+strings.Join(rule.ContentTypes, ", "))
+	}
+
+	var processed *media.Processed
+	if contentType == "application/pdf" {
+		processed = &media.Processed{
+			ContentType: contentType,
+			Extension:   ".pdf",
+			Original:    media.Rendition{Name: "original", Data: data},
+		}
+	} else {
+		processed, err = media.Process(data)
+		switch {
+		case errors.Is(err, media.ErrUnsupportedType):
+			return echo.NewHTTPError(http.StatusUnsupportedMediaType, err.Error())
+		case err != nil:
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if item.Private {
+			processed.Thumbnails = nil
+		} else {
+			item.PerceptualHash = media.FormatHash(processed.Hash)
+		}
+	}
+
+	prefix, err := media.KeyPrefix(item.ListingID, item.Private)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	item.ContentType = processed.ContentType
+	item.Width = processed.Original.Width
+	item.Height = processed.Original.Height
+	item.Size = len(processed.Original.Data)
+
+	ctx := c.Request().Context()
+	for _, rendition := range append([]media.Rendition{processed.Original}, processed.Thumbnails...) {
+		key := prefix + rendition.Name + processed.Extension
+		if err := h.Storage.Put(ctx, key, bytes.NewReader(rendition.Data), int64(len(rendition.Data)), processed.ContentType); err != nil {
+			h.deleteFiles(item.Keys)
+			return echo.NewHTTPError(http.StatusBadGateway, "Media storage unavailable")
+		}
+		item.Keys = append(item.Keys, key)
+		switch {
+		case item.Private:
+		case rendition.Name == processed.Original.Name:
+			item.URL = h.Storage.URL(key)
+		default:
+			if item.Thumbnails == nil {
+				item.Thumbnails = map[string]string{}
+			}
+			item.Thumbnails[rendition.Name] = h.Storage.URL(key)
+		}
+	}
+	return nil
+}
+
+// deleteFiles removes stored files that are no longer referenced. Failures
+// only leave orphaned files behind, so they are logged.
+func (h *MediaHandler) deleteFiles(keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, key := range keys {
+		if err := h.Storage.Delete(ctx, key); err != nil {
+			log.Printf("media: deleting %s: %v", key, err)
+		}
+	}
+}
+
+func (h *MediaHandler) GetPhotos(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+
+	photos, err := h.Repo.GetMedia(models.MediaFilter{ListingID: id, Type: models.MediaPhoto})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"photos": photos,
+	})
+}
+
+// GetAttachments lists the listing's attachments, optionally of one type.
+// Private ones are included, with signed links, when user_id is the owner
+// or role is admin.
+func (h *MediaHandler) GetAttachments(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	filter := models.MediaFilter{ListingID: id, Type: c.QueryParam("type")}
+	if _, ok := models.MediaRules[filter.Type]; filter.Type != "" && !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid type")
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(id)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	userID, _ := strconv.Atoi(c.QueryParam("user_id"))
+	filter.IncludePrivate = isAdmin(c) || (userID > 0 && userID == listing.UserID)
+
+	items, err := h.Repo.GetMedia(filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	h.present(items)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":      true,
+		"attachments": items,
+	})
+}
+
+// GetPrivateFile serves a private attachment through a link made by
+// GetAttachments.
+func (h *MediaHandler) GetPrivateFile(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid attachment ID")
+	}
+	if !h.Signer.Verify(id, c.QueryParam("expires"), c.QueryParam("signature"), time.Now()) {
+		return echo.NewHTTPError(http.StatusForbidden, "Link is invalid or has expired")
+	}
+
+	item, err := h.Repo.GetMediaItem(id)
+	if err != nil || item == nil || !item.Private || len(item.Keys) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "Attachment not found")
+	}
+	file, err := h.Storage.Get(c.Request().Context(), item.Keys[0])
+	if errors.Is(err, media.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Attachment not found")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "Media storage unavailable")
+	}
+	defer file.Close()
+
+	c.Response().Header().Set("Cache-Control", "private, no-store")
+	return c.Stream(http.StatusOK, item.ContentType, file)
+}
+
+func (h *MediaHandler) DeletePhoto(c echo.Context) error {
+	return h.delete(c, "photo_id", models.MediaPhoto)
+}
+
+func (h *MediaHandler) DeleteAttachment(c echo.Context) error {
+	return h.delete(c, "attachment_id", "")
+}
+
+func (h *MediaHandler) delete(c echo.Context, param, mediaType string) error {
+	item, err := h.ownedMedia(c, param, mediaType)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteMedia(item); err != nil {
+		return mediaError(err)
+	}
+	h.deleteFiles(item.Keys)
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+	})
+}
+
+// ReorderPhotos sets the display order to photo_ids, a comma-separated list
+// of every photo of the listing.
+func (h *MediaHandler) ReorderPhotos(c echo.Context) error {
+	listing, _, err := h.ownedListing(c)
+	if err != nil {
+		return err
+	}
+
+	var ids []int64
+	for _, raw := range strings.Split(c.FormValue("photo_ids"), ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "photo_ids must be a comma-separated list of photo IDs")
+		}
+		ids = append(ids, id)
+	}
+
+	photos, err := h.Repo.ReorderPhotos(listing.ID, ids)
+	if err != nil {
+		return mediaError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"photos": photos,
+	})
+}
+
+// SetCover makes the photo the one shown for the listing in results.
+func (h *MediaHandler) SetCover(c echo.Context) error {
+	photo, err := h.ownedMedia(c, "photo_id", models.MediaPhoto)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.SetCover(photo); err != nil {
+		return mediaError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"photo":  photo,
+	})
+}
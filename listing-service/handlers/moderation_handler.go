@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxReviewNote = 1000
+
+type ModerationHandler struct {
+	Repo interfaces.ModerationRepository
+}
+
+func NewModerationHandler(repo interfaces.ModerationRepository) *ModerationHandler {
+	return &ModerationHandler{Repo: repo}
+}
+
+// GetReviews lists the moderation queue, oldest first, pending reviews
+// unless status says otherwise.
+func (h *ModerationHandler) GetReviews(c echo.Context) error {
+	if _, err := admin(c); err != nil {
+		return err
+	}
+
+	filter := models.ReviewFilter{Status: c.QueryParam("status")}
+	switch filter.Status {
+	case "":
+		filter.Status = models.ReviewPending
+	case "all":
+		filter.Status = ""
+	case models.ReviewPending, models.ReviewApproved, models.ReviewRejected:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be 'pending', 'approved', 'rejected' or 'all'")
+	}
+	pageNum, _ := strconv.Atoi(c.QueryParam("page_num"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	reviews, err := h.Repo.GetReviews(filter, pageNum, pageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"reviews": reviews,
+	})
+}
+
+// decision reads the review and the administrator's note on it.
+func (h *ModerationHandler) decision(c echo.Context) (*models.ListingReview, int, string, error) {
+	adminID, err := admin(c)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	id, err := strconv.ParseInt(c.Param("review_id"), 10, 64)
+	if err != nil {
+		return nil, 0, "", echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+	note := strings.TrimSpace(c.FormValue("note"))
+	if len(note) > maxReviewNote {
+		return nil, 0, "", echo.NewHTTPError(http.StatusBadRequest, "note is too long")
+	}
+
+	review, err := h.Repo.GetReview(id)
+	if err != nil || review == nil {
+		return nil, 0, "", echo.NewHTTPError(http.StatusNotFound, "Review not found")
+	}
+	return review, adminID, note, nil
+}
+
+func reviewError(err error) error {
+	if errors.Is(err, models.ErrReviewClosed) || errors.Is(err, models.ErrListingUnavailable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// ApproveListing publishes the listing under review.
+func (h *ModerationHandler) ApproveListing(c echo.Context) error {
+	review, adminID, note, err := h.decision(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.ApproveListing(review, adminID, note); err != nil {
+		return reviewError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"review": review,
+	})
+}
+
+// RejectListing keeps the listing under review hidden. The note, which is
+// required, is sent to the lister.
+func (h *ModerationHandler) RejectListing(c echo.Context) error {
+	review, adminID, note, err := h.decision(c)
+	if err != nil {
+		return err
+	}
+	if note == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "note is required")
+	}
+
+	if err := h.Repo.RejectListing(review, adminID, note); err != nil {
+		return reviewError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"review": review,
+	})
+}
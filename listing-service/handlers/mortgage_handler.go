@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/mortgage"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MortgageHandler struct {
+	Defaults mortgage.Terms
+}
+
+func NewMortgageHandler(defaults mortgage.Terms) *MortgageHandler {
+	return &MortgageHandler{Defaults: defaults}
+}
+
+// GetSchedule computes the installments of buying at price (a decimal in
+// currency) with down_payment or down_payment_pct, over tenor_years, at
+// fixed_rate for fixed_years and floating_rate after. Missing terms take
+// the defaults. format=csv exports the amortization table.
+func (h *MortgageHandler) GetSchedule(c echo.Context) error {
+	currency, err := mortgageCurrency(c)
+	if err != nil {
+		return err
+	}
+	price, err := money.Parse(c.QueryParam("price"), currency)
+	if err != nil || price.Minor <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid price")
+	}
+	terms, err := h.terms(c)
+	if err != nil {
+		return err
+	}
+
+	downPayment := terms.DownPayment(price)
+	if raw := c.QueryParam("down_payment"); raw != "" {
+		downPayment, err = money.Parse(raw, currency)
+		if err != nil || downPayment.Minor >= price.Minor {
+			return echo.NewHTTPError(http.StatusBadRequest, "down payment must be less than the price")
+		}
+	}
+
+	schedule := mortgage.Amortize(price, downPayment, terms)
+
+	if c.QueryParam("format") == "csv" {
+		var buf bytes.Buffer
+		if err := mortgage.WriteCSV(&buf, schedule); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="mortgage-schedule.csv"`)
+		return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":   true,
+		"currency": currency,
+		"schedule": schedule,
+	})
+}
+
+// GetAffordability returns the highest price a buyer earning
+// monthly_income can borrow for, after monthly_debts, when installments may
+// take debt_ratio percent of income (30 by default). It takes the same
+// terms as GetSchedule, with down_payment_pct.
+func (h *MortgageHandler) GetAffordability(c echo.Context) error {
+	currency, err := mortgageCurrency(c)
+	if err != nil {
+		return err
+	}
+	income, err := money.Parse(c.QueryParam("monthly_income"), currency)
+	if err != nil || income.Minor <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid monthly_income")
+	}
+	debts := money.Amount{Currency: currency}
+	if raw := c.QueryParam("monthly_debts"); raw != "" {
+		debts, err = money.Parse(raw, currency)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid monthly_debts")
+		}
+	}
+	debtRatio := float64(mortgage.DefaultDebtRatio)
+	if raw := c.QueryParam("debt_ratio"); raw != "" {
+		debtRatio, err = strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(debtRatio) || debtRatio <= 0 || debtRatio > 100 {
+			return echo.NewHTTPError(http.StatusBadRequest, "debt_ratio must be above 0 and at most 100 percent")
+		}
+	}
+	terms, err := h.terms(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":        true,
+		"currency":      currency,
+		"affordability": mortgage.Afford(income, debts, debtRatio, terms),
+	})
+}
+
+// terms reads the loan terms, taking the defaults for those not given.
+func (h *MortgageHandler) terms(c echo.Context) (mortgage.Terms, error) {
+	terms := h.Defaults
+	floats := map[string]*float64{
+		"down_payment_pct": &terms.DownPaymentPct,
+		"fixed_rate":       &terms.FixedRate,
+		"floating_rate":    &terms.FloatingRate,
+	}
+	for name, dst := range floats {
+		if raw := c.QueryParam(name); raw != "" {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return terms, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+			}
+			*dst = v
+		}
+	}
+	months := map[string]*int{
+		"tenor_years": &terms.TenorMonths,
+		"fixed_years": &terms.FixedMonths,
+	}
+	for name, dst := range months {
+		if raw := c.QueryParam(name); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil {
+				return terms, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+			}
+			*dst = v * 12
+		}
+	}
+	if err := terms.Validate(); err != nil {
+		return terms, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return terms, nil
+}
+
+func mortgageCurrency(c echo.Context) (string, error) {
+	currency := money.DefaultCurrency
+	if raw := c.QueryParam("currency"); raw != "" {
+		currency = money.Normalize(raw)
+		if !money.Valid(currency) {
+			return "", echo.NewHTTPError(http.StatusBadRequest, "Unsupported currency")
+		}
+	}
+	return currency, nil
+}
+
+// mortgageEstimate is the installments of buying a sale listing at its price
+// on the default terms, without the monthly payments. Prices too large for
+// minor units get none.
+func mortgageEstimate(listing *models.Listing, terms mortgage.Terms) *mortgage.Schedule {
+	if listing.ListingType != "sale" || listing.Price <= 0 {
+		return nil
+	}
+	if !money.FitsMajor(int64(listing.Price), listing.PriceAmount().Currency) {
+		return nil
+	}
+	price := listing.PriceAmount()
+	estimate := mortgage.Amortize(price, terms.DownPayment(price), terms)
+	estimate.Payments = nil
+	return &estimate
+}
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultOfferExpiry = 72 * time.Hour
+	maxOfferExpiry     = 30 * 24 * time.Hour
+)
+
+type OfferHandler struct {
+	Repo     interfaces.OfferRepository
+	Listings interfaces.ListingRepository
+}
+
+func NewOfferHandler(repo interfaces.OfferRepository, listings interfaces.ListingRepository) *OfferHandler {
+	return &OfferHandler{Repo: repo, Listings: listings}
+}
+
+// offerTerms reads amount, conditions and expires_at. expires_at defaults to
+// 72 hours from now and may be at most 30 days away.
+func offerTerms(c echo.Context, now int64) (amount int, conditions string, expiresAt int64, err error) {
+	amount, err = strconv.Atoi(c.FormValue("amount"))
+	if err != nil || amount <= 0 {
+		return 0, "", 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid amount")
+	}
+	conditions = strings.TrimSpace(c.FormValue("conditions"))
+	if len(conditions) > 2000 {
+		return 0, "", 0, echo.NewHTTPError(http.StatusBadRequest, "conditions must be at most 2000 characters")
+	}
+
+	expiresAt = now + defaultOfferExpiry.Microseconds()
+	if c.FormValue("expires_at") != "" {
+		if expiresAt, err = formTime(c, "expires_at"); err != nil {
+			return 0, "", 0, err
+		}
+		if expiresAt <= now || expiresAt > now+maxOfferExpiry.Microseconds() {
+			return 0, "", 0, echo.NewHTTPError(http.StatusBadRequest, "expires_at must be within the next 30 days")
+		}
+	}
+	return amount, conditions, expiresAt, nil
+}
+
+func (h *OfferHandler) CreateOffer(c echo.Context) error {
+	listingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	buyerID, err := strconv.Atoi(c.FormValue("buyer_id"))
+	if err != nil || buyerID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid buyer_id")
+	}
+
+	now := time.Now().UnixMicro()
+	amount, conditions, expiresAt, err := offerTerms(c, now)
+	if err != nil {
+		return err
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.ListingType != "sale" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Offers can only be made on sale listings")
+	}
+	if listing.Status != models.ListingStatusActive {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is no longer available")
+	}
+	if listing.UserID == buyerID {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot make an offer on your own listing")
+	}
+
+	pending, err := h.Repo.HasPendingOffer(listingID, buyerID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if pending {
+		return echo.NewHTTPError(http.StatusConflict, "You already have a pending offer on this listing")
+	}
+
+	offer := models.Offer{
+		ListingID:  listingID,
+		BuyerID:    buyerID,
+		SellerID:   listing.UserID,
+		Amount:     amount,
+		Conditions: conditions,
+		Status:     models.OfferStatusPending,
+		AwaitingID: listing.UserID,
+		ExpiresAt:  expiresAt,
+		Version:    1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	event := models.OfferEvent{
+		ActorID:    buyerID,
+		Action:     models.OfferActionSubmit,
+		Amount:     amount,
+		Conditions: conditions,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	}
+
+	if err := h.Repo.CreateOffer(&offer, &event); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result": true,
+		"offer":  offer,
+	})
+}
+
+// GetOffers lists the offers the user made or received.
+func (h *OfferHandler) GetOffers(c echo.Context) error {
+	userID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	filter := models.OfferFilter{UserID: userID, Status: c.QueryParam("status")}
+	if raw := c.QueryParam("listing_id"); raw != "" {
+		if filter.ListingID, err = strconv.Atoi(raw); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing_id")
+		}
+	}
+
+	offers, err := h.Repo.GetOffers(filter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"offers": offers,
+	})
+}
+
+// participantOffer loads the offer in the URL if user_id is its buyer or
+// seller.
+func (h *OfferHandler) participantOffer(c echo.Context) (*models.Offer, int, error) {
+	userID, err := userParam(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	id, err := strconv.ParseInt(c.Param("offer_id"), 10, 64)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid offer ID")
+	}
+
+	offer, err := h.Repo.GetOffer(id)
+	if err != nil || offer == nil || !offer.HasParticipant(userID) {
+		return nil, 0, echo.NewHTTPError(http.StatusNotFound, "Offer not found")
+	}
+	return offer, userID, nil
+}
+
+// openOffer is participantOffer for offers that can still be acted on.
+// Offers past their deadline are treated as expired even before the expiry
+// job has run.
+func (h *OfferHandler) openOffer(c echo.Context) (*models.Offer, int, error) {
+	offer, userID, err := h.participantOffer(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offer.Status != models.OfferStatusPending {
+		return nil, 0, echo.NewHTTPError(http.StatusConflict, "Offer is "+offer.Status)
+	}
+	if offer.ExpiresAt <= time.Now().UnixMicro() {
+		return nil, 0, echo.NewHTTPError(http.StatusConflict, "Offer has expired")
+	}
+	return offer, userID, nil
+}
+
+// awaitedOffer is openOffer for the party whose response is awaited.
+func (h *OfferHandler) awaitedOffer(c echo.Context) (*models.Offer, int, error) {
+	offer, userID, err := h.openOffer(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offer.AwaitingID != userID {
+		return nil, 0, echo.NewHTTPError(http.StatusForbidden, "Waiting for the other party to respond")
+	}
+	return offer, userID, nil
+}
+
+func offerError(err error) error {
+	if errors.Is(err, models.ErrOfferChanged) || errors.Is(err, models.ErrListingUnavailable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// GetOffer returns the offer with its full negotiation history.
+func (h *OfferHandler) GetOffer(c echo.Context) error {
+	offer, _, err := h.participantOffer(c)
+	if err != nil {
+		return err
+	}
+
+	history, err := h.Repo.GetOfferEvents(offer.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"offer":   offer,
+		"history": history,
+	})
+}
+
+// CounterOffer replaces the terms and hands the response to the other party.
+func (h *OfferHandler) CounterOffer(c echo.Context) error {
+	offer, userID, err := h.awaitedOffer(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMicro()
+	amount, conditions, expiresAt, err := offerTerms(c, now)
+	if err != nil {
+		return err
+	}
+
+	offer.Amount = amount
+	offer.Conditions = conditions
+	offer.ExpiresAt = expiresAt
+	offer.AwaitingID = offer.Counterparty(userID)
+	offer.UpdatedAt = now
+	event := models.OfferEvent{
+		ActorID:    userID,
+		Action:     models.OfferActionCounter,
+		Amount:     amount,
+		Conditions: conditions,
+		ExpiresAt:  expiresAt,
+		Note:       strings.TrimSpace(c.FormValue("note")),
+		CreatedAt:  now,
+	}
+
+	if err := h.Repo.UpdateOffer(offer, &event); err != nil {
+		return offerError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"offer":  offer,
+	})
+}
+
+// AcceptOffer accepts the current terms. The listing goes under offer and
+// the other pending offers on it are declined.
+func (h *OfferHandler) AcceptOffer(c echo.Context) error {
+	offer, userID, err := h.awaitedOffer(c)
+	if err != nil {
+		return err
+	}
+
+	event := h.close(offer, userID, models.OfferStatusAccepted, models.OfferActionAccept, c.FormValue("note"))
+	if err := h.Repo.AcceptOffer(offer, &event); err != nil {
+		return offerError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"offer":  offer,
+	})
+}
+
+func (h *OfferHandler) RejectOffer(c echo.Context) error {
+	offer, userID, err := h.awaitedOffer(c)
+	if err != nil {
+		return err
+	}
+
+	event := h.close(offer, userID, models.OfferStatusRejected, models.OfferActionReject, c.FormValue("note"))
+	if err := h.Repo.UpdateOffer(offer, &event); err != nil {
+		return offerError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"offer":  offer,
+	})
+}
+
+// WithdrawOffer lets the buyer pull out at any point of the negotiation.
+func (h *OfferHandler) WithdrawOffer(c echo.Context) error {
+	offer, userID, err := h.openOffer(c)
+	if err != nil {
+		return err
+	}
+	if userID != offer.BuyerID {
+		return echo.NewHTTPError(http.StatusForbidden, "Only the buyer can withdraw an offer")
+	}
+
+	event := h.close(offer, userID, models.OfferStatusWithdrawn, models.OfferActionWithdraw, c.FormValue("note"))
+	if err := h.Repo.UpdateOffer(offer, &event); err != nil {
+		return offerError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"offer":  offer,
+	})
+}
+
+// close moves the offer to a final status on the current terms and returns
+// the history entry recording it.
+func (h *OfferHandler) close(offer *models.Offer, actorID int, status, action, note string) models.OfferEvent {
+	now := time.Now().UnixMicro()
+	offer.Status = status
+	offer.AwaitingID = 0
+	offer.UpdatedAt = now
+	return models.OfferEvent{
+		ActorID:    actorID,
+		Action:     action,
+		Amount:     offer.Amount,
+		Conditions: offer.Conditions,
+		ExpiresAt:  offer.ExpiresAt,
+		Note:       strings.TrimSpace(note),
+		CreatedAt:  now,
+	}
+}
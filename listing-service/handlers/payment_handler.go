@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"real-estate-system/listing-service/ledger"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/payments"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxGraceDays = 60
+
+type PaymentHandler struct {
+	Repo     interfaces.LedgerRepository
+	Leases   interfaces.LeaseRepository
+	Provider payments.Provider
+}
+
+func NewPaymentHandler(repo interfaces.LedgerRepository, leases interfaces.LeaseRepository, provider payments.Provider) *PaymentHandler {
+	return &PaymentHandler{Repo: repo, Leases: leases, Provider: provider}
+}
+
+// RentRollEntry is one lease in a landlord's rent roll. Balance and Overdue
+// are in minor units of Currency.
+type RentRollEntry struct {
+	Lease    models.Lease `json:"lease"`
+	Currency string       `json:"currency"`
+	Balance  int64        `json:"balance"`
+	Overdue  int64        `json:"overdue"`
+}
+
+func (h *PaymentHandler) participantLease(c echo.Context) (*models.Lease, int, error) {
+	return participantLease(c, h.Leases)
+}
+
+// idempotencyKey scopes the client's key to the lease, or makes one up so
+// that requests without a key are never deduplicated.
+func idempotencyKey(c echo.Context, leaseID int64) (string, error) {
+	key := strings.TrimSpace(c.FormValue("idempotency_key"))
+	if key == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		key = "auto:" + hex.EncodeToString(buf)
+	}
+	if len(key) > 100 {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "idempotency_key is too long")
+	}
+	return fmt.Sprintf("%d:%s", leaseID, key), nil
+}
+
+// CreatePayment pays amount, in minor units of the lease currency, towards
+// the lease. The tenant pays through the payment provider with
+// payment_method; the landlord records money received outside the system.
+// Retrying with the same idempotency_key returns the original payment.
+func (h *PaymentHandler) CreatePayment(c echo.Context) error {
+	lease, userID, err := h.participantLease(c)
+	if err != nil {
+		return err
+	}
+
+	amount, err := strconv.ParseInt(c.FormValue("amount"), 10, 64)
+	if err != nil || amount <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "amount must be a positive number of minor units")
+	}
+	method := strings.TrimSpace(c.FormValue("payment_method"))
+	if userID == lease.TenantID && method == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "payment_method is required")
+	}
+	key, err := idempotencyKey(c, lease.ID)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().UnixMicro()
+	payment := models.Payment{
+		LeaseID:        lease.ID,
+		TenantID:       lease.TenantID,
+		LandlordID:     lease.LandlordID,
+		RecordedBy:     userID,
+		Amount:         amount,
+		Currency:       ledger.Currency(*lease),
+		Status:         models.PaymentStatusPending,
+		Provider:       h.Provider.Name(),
+		IdempotencyKey: key,
+		CreatedAt:      timestamp,
+		UpdatedAt:      timestamp,
+	}
+	if userID == lease.LandlordID {
+		payment.Provider = models.PaymentProviderManual
+	}
+
+	created, err := h.Repo.CreatePayment(&payment)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if !created && payment.Status != models.PaymentStatusPending {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"result":  true,
+			"payment": payment,
+		})
+	}
+
+	payment.Status = models.PaymentStatusSucceeded
+	if payment.Provider != models.PaymentProviderManual {
+		// A pending payment found by key is retried; providers deduplicate
+		// charges by reference.
+		result, err := h.Provider.Charge(c.Request().Context(), payments.ChargeRequest{
+			Reference:   ledger.PaymentReference(payment.ID),
+			Amount:      money.Amount{Minor: payment.Amount, Currency: payment.Currency},
+			Method:      method,
+			Description: fmt.Sprintf("Rent for lease %d", lease.ID),
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadGateway, "Payment provider unavailable, retry with the same idempotency_key")
+		}
+		payment.ProviderRef = result.ProviderRef
+		if !result.Succeeded {
+			payment.Status = models.PaymentStatusFailed
+			payment.FailureReason = result.FailureReason
+		}
+	}
+
+	payment.UpdatedAt = time.Now().UnixMicro()
+	txn := ledger.Payment(payment)
+	if err := h.Repo.CompletePayment(&payment, &txn); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":  true,
+		"payment": payment,
+	})
+}
+
+func (h *PaymentHandler) GetPayments(c echo.Context) error {
+	lease, _, err := h.participantLease(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := h.Repo.GetPayments(lease.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":   true,
+		"payments": list,
+	})
+}
+
+// GetStatement returns the tenant's account for the lease between from and
+// to (YYYY-MM-DD, to inclusive), by default from the start of the lease to
+// today, together with the balance of each ledger account.
+func (h *PaymentHandler) GetStatement(c echo.Context) error {
+	lease, _, err := h.participantLease(c)
+	if err != nil {
+		return err
+	}
+
+	from := time.UnixMicro(lease.StartsAt).UTC()
+	if c.QueryParam("from") != "" {
+		if from, err = queryDate(c, "from"); err != nil {
+			return err
+		}
+	}
+	to := today()
+	if c.QueryParam("to") != "" {
+		if to, err = queryDate(c, "to"); err != nil {
+			return err
+		}
+	}
+	if to.Before(from) {
+		return echo.NewHTTPError(http.StatusBadRequest, "from must not be after to")
+	}
+
+	txns, err := h.Repo.GetTransactions(lease.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"statement": ledger.BuildStatement(*lease, txns, from.UnixMicro(), to.AddDate(0, 0, 1).UnixMicro(), time.Now().UnixMicro()),
+		"accounts":  ledger.Balances(txns),
+	})
+}
+
+func queryDate(c echo.Context, name string) (time.Time, error) {
+	t, err := time.Parse(dateFormat, c.QueryParam(name))
+	if err != nil {
+		return t, echo.NewHTTPError(http.StatusBadRequest, name+" must be a date (YYYY-MM-DD)")
+	}
+	return t, nil
+}
+
+// GetRentRoll lists the landlord's leases, active ones by default, with what
+// each tenant owes and how much of it is overdue.
+func (h *PaymentHandler) GetRentRoll(c echo.Context) error {
+	landlordID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+	status := c.QueryParam("status")
+	if status == "" {
+		status = models.LeaseStatusActive
+	}
+
+	leases, err := h.Leases.GetLeases(models.LeaseFilter{UserID: landlordID, Status: status})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	now := time.Now().UnixMicro()
+	roll := []RentRollEntry{}
+	for _, lease := range leases {
+		if lease.LandlordID != landlordID {
+			continue
+		}
+		txns, err := h.Repo.GetTransactions(lease.ID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		entry := RentRollEntry{Lease: lease, Currency: ledger.Currency(lease)}
+		entry.Balance = ledger.Balances(txns)[models.AccountReceivable]
+		for _, item := range ledger.Allocate(txns, now) {
+			if item.Status == ledger.ItemOverdue {
+				entry.Overdue += item.Outstanding
+			}
+		}
+		roll = append(roll, entry)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"rent_roll": roll,
+	})
+}
+
+func (h *PaymentHandler) GetLateFeeRule(c echo.Context) error {
+	landlordID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	rule, err := h.Repo.GetLateFeeRule(landlordID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"rule":   rule,
+	})
+}
+
+// UpdateLateFeeRule changes the given fields of the landlord's late fee
+// rule. flat_fee is in minor units of currency.
+func (h *PaymentHandler) UpdateLateFeeRule(c echo.Context) error {
+	landlordID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	rule, err := h.Repo.GetLateFeeRule(landlordID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if raw := c.FormValue("grace_days"); raw != "" {
+		rule.GraceDays, err = strconv.Atoi(raw)
+		if err != nil || rule.GraceDays < 0 || rule.GraceDays > maxGraceDays {
+			return echo.NewHTTPError(http.StatusBadRequest, "grace_days must be between 0 and 60")
+		}
+	}
+	if raw := c.FormValue("percent_bps"); raw != "" {
+		rule.PercentBps, err = strconv.Atoi(raw)
+		if err != nil || rule.PercentBps < 0 || rule.PercentBps > 10000 {
+			return echo.NewHTTPError(http.StatusBadRequest, "percent_bps must be between 0 and 10000")
+		}
+	}
+	if raw := c.FormValue("flat_fee"); raw != "" {
+		rule.FlatFee, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || rule.FlatFee < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid flat_fee")
+		}
+	}
+	if raw := c.FormValue("currency"); raw != "" {
+		rule.Currency = money.Normalize(raw)
+	}
+	if rule.FlatFee > 0 && rule.Currency == "" {
+		rule.Currency = money.DefaultCurrency
+	}
+	if rule.Currency != "" && !money.Valid(rule.Currency) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Unsupported currency")
+	}
+
+	rule.UpdatedAt = time.Now().UnixMicro()
+	if err := h.Repo.SaveLateFeeRule(rule); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"rule":   rule,
+	})
+}
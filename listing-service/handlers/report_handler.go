@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxReportDetails = 1000
+
+type ReportHandler struct {
+	Repo     interfaces.ReportRepository
+	Listings interfaces.ListingRepository
+	// HideAfter is how many users must report an active listing before it
+	// is hidden pending triage.
+	HideAfter int
+}
+
+func NewReportHandler(repo interfaces.ReportRepository, listings interfaces.ListingRepository, hideAfter int) *ReportHandler {
+	return &ReportHandler{Repo: repo, Listings: listings, HideAfter: hideAfter}
+}
+
+// newReport reads the reporter_id, reason and details of a report.
+func newReport(c echo.Context, targetType string, targetID int) (*models.Report, error) {
+	reporterID, err := strconv.Atoi(c.FormValue("reporter_id"))
+	if err != nil || reporterID <= 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid reporter_id")
+	}
+	reason := c.FormValue("reason")
+	if !models.ReportReasons[reason] {
+		reasons := make([]string, 0, len(models.ReportReasons))
+		for r := range models.ReportReasons {
+			reasons = append(reasons, r)
+		}
+		sort.Strings(reasons)
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "reason must be one of "+strings.Join(reasons, ", "))
+	}
+	details := strings.TrimSpace(c.FormValue("details"))
+	if len(details) > maxReportDetails {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "details is too long")
+	}
+	if reason == models.ReportReasonOther && details == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "details is required for reason 'other'")
+	}
+
+	timestamp := time.Now().UnixMicro()
+	return &models.Report{
+		TargetType: targetType,
+		TargetID:   targetID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Details:    details,
+		Status:     models.ReportOpen,
+		CreatedAt:  timestamp,
+		UpdatedAt:  timestamp,
+	}, nil
+}
+
+// ReportListing reports a listing on behalf of reporter_id. Reporting the
+// same listing again returns the first report.
+func (h *ReportHandler) ReportListing(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	report, err := newReport(c, models.ReportTargetListing, id)
+	if err != nil {
+		return err
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(id)
+	if err != nil || listing == nil || (!listing.IsPublic() && listing.Status != models.ListingStatusHidden) {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.Status == models.ListingStatusArchived {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is archived")
+	}
+	if listing.UserID == report.ReporterID {
+		return echo.NewHTTPError(http.StatusBadRequest, "You cannot report your own listing")
+	}
+	report.SubjectID = listing.UserID
+
+	return h.create(c, report)
+}
+
+// ReportUser reports the user on behalf of reporter_id.
+func (h *ReportHandler) ReportUser(c echo.Context) error {
+	userID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+	report, err := newReport(c, models.ReportTargetUser, userID)
+	if err != nil {
+		return err
+	}
+	if userID == report.ReporterID {
+		return echo.NewHTTPError(http.StatusBadRequest, "You cannot report yourself")
+	}
+	report.SubjectID = userID
+
+	return h.create(c, report)
+}
+
+func (h *ReportHandler) create(c echo.Context, report *models.Report) error {
+	created, err := h.Repo.CreateReport(report, h.HideAfter)
+	if errors.Is(err, models.ErrListingUnavailable) {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	return c.JSON(status, map[string]interface{}{
+		"result": true,
+		"report": report,
+	})
+}
+
+// GetReports lists reports for triage, oldest first, open ones unless
+// status says otherwise, optionally about one target_type and target_id.
+func (h *ReportHandler) GetReports(c echo.Context) error {
+	if _, err := admin(c); err != nil {
+		return err
+	}
+
+	filter := models.ReportFilter{Status: c.QueryParam("status"), TargetType: c.QueryParam("target_type")}
+	switch filter.Status {
+	case "":
+		filter.Status = models.ReportOpen
+	case "all":
+		filter.Status = ""
+	case models.ReportOpen, models.ReportUpheld, models.ReportDismissed:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be 'open', 'upheld', 'dismissed' or 'all'")
+	}
+	if filter.TargetType != "" && filter.TargetType != models.ReportTargetListing && filter.TargetType != models.ReportTargetUser {
+		return echo.NewHTTPError(http.StatusBadRequest, "target_type must be 'listing' or 'user'")
+	}
+	if raw := c.QueryParam("target_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid target_id")
+		}
+		filter.TargetID = id
+	}
+	pageNum, _ := strconv.Atoi(c.QueryParam("page_num"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	reports, err := h.Repo.GetReports(filter, pageNum, pageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"reports": reports,
+	})
+}
+
+// ResolveReport resolves the report and every other open report about the
+// same target with outcome 'upheld' or 'dismissed'. Upholding reports about
+// a listing archives it.
+func (h *ReportHandler) ResolveReport(c echo.Context) error {
+	adminID, err := admin(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(c.Param("report_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid report ID")
+	}
+	outcome := c.FormValue("outcome")
+	if outcome != models.ReportUpheld && outcome != models.ReportDismissed {
+		return echo.NewHTTPError(http.StatusBadRequest, "outcome must be 'upheld' or 'dismissed'")
+	}
+	note := strings.TrimSpace(c.FormValue("note"))
+	if len(note) > maxReviewNote {
+		return echo.NewHTTPError(http.StatusBadRequest, "note is too long")
+	}
+
+	report, err := h.Repo.GetReport(id)
+	if err != nil || report == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Report not found")
+	}
+
+	resolved, err := h.Repo.ResolveReports(report, outcome, adminID, note)
+	if errors.Is(err, models.ErrReportResolved) || errors.Is(err, models.ErrListingUnavailable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"reports": resolved,
+	})
+}
+
+// GetTrustRecord returns the user's trust record with the reports about
+// them and their listings, newest last.
+func (h *ReportHandler) GetTrustRecord(c echo.Context) error {
+	if _, err := admin(c); err != nil {
+		return err
+	}
+	userID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	record, err := h.Repo.GetTrustRecord(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	reports, err := h.Repo.GetReports(models.ReportFilter{SubjectID: userID}, 1, 100)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"trust":   record,
+		"reports": reports,
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/repository/interfaces"
+	"real-estate-system/listing-service/tenant"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultStatsWeeks = 12
+	maxStatsWeeks     = 104
+)
+
+type StatsHandler struct {
+	Repo interfaces.StatsRepository
+}
+
+func NewStatsHandler(repo interfaces.StatsRepository) *StatsHandler {
+	return &StatsHandler{Repo: repo}
+}
+
+// GetAreaStats returns market statistics of the public listings grouped by
+// group_by (comma separated city, district, property_type, listing_type),
+// optionally filtered by those dimensions and currency. Each group has a
+// series of its last weeks (weeks, 12 by default). Statistics come from
+// views refreshed on a schedule, as of refreshed_at.
+func (h *StatsHandler) GetAreaStats(c echo.Context) error {
+	query := models.AreaStatsQuery{
+		TenantID:     tenant.ID(c),
+		City:         strings.TrimSpace(c.QueryParam("city")),
+		District:     strings.TrimSpace(c.QueryParam("district")),
+		PropertyType: c.QueryParam("property_type"),
+		ListingType:  c.QueryParam("listing_type"),
+		Currency:     money.Normalize(c.QueryParam("currency")),
+		Weeks:        defaultStatsWeeks,
+	}
+	if raw := c.QueryParam("group_by"); raw != "" {
+		for _, dimension := range strings.Split(raw, ",") {
+			dimension = strings.TrimSpace(dimension)
+			if !slices.Contains(models.StatsDimensions, dimension) {
+				return echo.NewHTTPError(http.StatusBadRequest, "group_by must be a list of 'city', 'district', 'property_type' and 'listing_type'")
+			}
+			query.GroupBy = append(query.GroupBy, dimension)
+		}
+	}
+	if query.PropertyType != "" && !models.ValidPropertyType(query.PropertyType) {
+		return echo.NewHTTPError(http.StatusBadRequest, "property_type must be 'house', 'apartment', 'townhouse', 'land' or 'commercial'")
+	}
+	if query.ListingType != "" && query.ListingType != "rent" && query.ListingType != "sale" {
+		return echo.NewHTTPError(http.StatusBadRequest, "listing_type must be 'rent' or 'sale'")
+	}
+	if query.Currency != "" && !money.Valid(query.Currency) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Unsupported currency")
+	}
+	if raw := c.QueryParam("weeks"); raw != "" {
+		weeks, err := strconv.Atoi(raw)
+		if err != nil || weeks < 1 || weeks > maxStatsWeeks {
+			return echo.NewHTTPError(http.StatusBadRequest, "weeks must be 1 to 104")
+		}
+		query.Weeks = weeks
+	}
+	query.SeriesSince = seriesStart(time.Now(), query.Weeks)
+
+	stats, err := h.Repo.GetAreaStats(query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"stats":  stats,
+	})
+}
+
+// seriesStart is the Monday (UTC) starting the first of the weeks up to and
+// including the current one.
+func seriesStart(now time.Time, weeks int) int64 {
+	day := now.UTC().Truncate(24 * time.Hour)
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	return monday.AddDate(0, 0, -7*(weeks-1)).UnixMicro()
+}
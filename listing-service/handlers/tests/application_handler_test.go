@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateApplication_Success(t *testing.T) {
+	repo := new(mocks.ApplicationRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewApplicationHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "rent", Status: models.ListingStatusActive}, nil)
+	repo.On("HasOpenApplication", 7, 5).Return(false, nil)
+	repo.On("CreateApplication", mock.MatchedBy(func(a *models.Application) bool {
+		return a.LandlordID == 2 && a.MonthlyIncome == 4500 && len(a.References) == 1 && a.Documents[0].Kind == models.DocumentPayslip
+	})).Return(nil)
+
+	form := url.Values{
+		"applicant_id":      {"5"},
+		"monthly_income":    {"4500"},
+		"employment_status": {"employed"},
+		"employment_months": {"18"},
+		"references":        {`[{"name":"Previous landlord","email":"pl@example.com"}]`},
+		"documents":         {`[{"kind":"payslip","name":"may.pdf","url":"https://files.example.com/may.pdf"}]`},
+	}
+	c, rec := newInquiryContext(http.MethodPost, "/listings/7/applications", form, []string{"id"}, []string{"7"})
+
+	assert.NoError(t, h.CreateApplication(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateApplication_SaleListing(t *testing.T) {
+	repo := new(mocks.ApplicationRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewApplicationHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "sale", Status: models.ListingStatusActive}, nil)
+
+	form := url.Values{"applicant_id": {"5"}, "monthly_income": {"4500"}, "employment_status": {"employed"}}
+	c, _ := newInquiryContext(http.MethodPost, "/listings/7/applications", form, []string{"id"}, []string{"7"})
+
+	err := h.CreateApplication(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "CreateApplication", mock.Anything)
+}
+
+func TestGetReceivedApplications_SortsByScore(t *testing.T) {
+	repo := new(mocks.ApplicationRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewApplicationHandler(repo, listings)
+
+	rules := models.ScreeningRules{LandlordID: 2, MinIncomeRatio: 3, IncomeWeight: 1}
+	repo.On("GetScreeningRules", 2).Return(&rules, nil)
+	repo.On("GetApplications", models.ApplicationFilter{LandlordID: 2}).Return([]models.Application{
+		{ID: 1, ListingID: 7, MonthlyIncome: 1500},
+		{ID: 2, ListingID: 7, MonthlyIncome: 3000},
+	}, nil)
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, Price: 1000}, nil).Once()
+
+	c, rec := newInquiryContext(http.MethodGet, "/users/2/received-applications?sort=score", nil, []string{"user_id"}, []string{"2"})
+
+	assert.NoError(t, h.GetReceivedApplications(c))
+	var body struct {
+		Applications []handlers.ScreenedApplication `json:"applications"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, int64(2), body.Applications[0].ID)
+	assert.Equal(t, 100, body.Applications[0].Screening.Score)
+	assert.Equal(t, 50, body.Applications[1].Screening.Score)
+	listings.AssertExpectations(t)
+}
+
+func TestGetReceivedApplications_ScreensYearlyRentPerMonth(t *testing.T) {
+	repo := new(mocks.ApplicationRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewApplicationHandler(repo, listings)
+
+	rules := models.ScreeningRules{LandlordID: 2, MinIncomeRatio: 3, IncomeWeight: 1}
+	repo.On("GetScreeningRules", 2).Return(&rules, nil)
+	repo.On("GetApplications", models.ApplicationFilter{LandlordID: 2}).Return([]models.Application{
+		{ID: 1, ListingID: 7, MonthlyIncome: 3000},
+	}, nil)
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, Price: 12000, Currency: "USD", RentPeriod: models.RentPerYear}, nil)
+
+	c, rec := newInquiryContext(http.MethodGet, "/users/2/received-applications", nil, []string{"user_id"}, []string{"2"})
+
+	assert.NoError(t, h.GetReceivedApplications(c))
+	var body struct {
+		Applications []handlers.ScreenedApplication `json:"applications"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, 100, body.Applications[0].Screening.Score)
+}
+
+func TestUpdateApplicationStatus_Approve(t *testing.T) {
+	repo := new(mocks.ApplicationRepositoryMock)
+	h := handlers.NewApplicationHandler(repo, new(mocks.ListingRepositoryMock))
+
+	app := &models.Application{ID: 4, ListingID: 7, ApplicantID: 5, LandlordID: 2, Status: models.ApplicationStatusReviewing}
+	repo.On("GetApplication", int64(4)).Return(app, nil)
+	repo.On("ApproveApplication", app, "Welcome").Return(nil)
+
+	form := url.Values{"status": {"approved"}, "note": {"Welcome"}}
+	c, rec := newInquiryContext(http.MethodPatch, "/users/2/applications/4/status", form, []string{"user_id", "application_id"}, []string{"2", "4"})
+
+	assert.NoError(t, h.UpdateApplicationStatus(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateApplicationStatus_ApplicantForbidden(t *testing.T) {
+	repo := new(mocks.ApplicationRepositoryMock)
+	h := handlers.NewApplicationHandler(repo, new(mocks.ListingRepositoryMock))
+
+	app := &models.Application{ID: 4, ListingID: 7, ApplicantID: 5, LandlordID: 2, Status: models.ApplicationStatusSubmitted}
+	repo.On("GetApplication", int64(4)).Return(app, nil)
+
+	c, _ := newInquiryContext(http.MethodPatch, "/users/5/applications/4/status", url.Values{"status": {"approved"}}, []string{"user_id", "application_id"}, []string{"5", "4"})
+
+	err := h.UpdateApplicationStatus(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateScreeningRules_KeepsUnsetFields(t *testing.T) {
+	repo := new(mocks.ApplicationRepositoryMock)
+	h := handlers.NewApplicationHandler(repo, new(mocks.ListingRepositoryMock))
+
+	defaults := models.DefaultScreeningRules(2)
+	repo.On("GetScreeningRules", 2).Return(&defaults, nil)
+	repo.On("SaveScreeningRules", mock.MatchedBy(func(r *models.ScreeningRules) bool {
+		return r.MinIncomeRatio == 2.5 && r.IncomeWeight == 50 && len(r.RequiredDocuments) == 1
+	})).Return(nil)
+
+	form := url.Values{"min_income_ratio": {"2.5"}, "required_documents": {"identity"}}
+	c, rec := newInquiryContext(http.MethodPut, "/users/2/screening-rules", form, []string{"user_id"}, []string{"2"})
+
+	assert.NoError(t, h.UpdateScreeningRules(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetDuplicates_AdminsOnly(t *testing.T) {
+	repo := new(mocks.DuplicateRepositoryMock)
+	h := handlers.NewDuplicateHandler(repo)
+
+	c, _ := newInquiryContext(http.MethodGet, "/duplicates?user_id=1", nil, nil, nil)
+	err := h.GetDuplicates(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+
+	repo.On("GetDuplicatePairs", models.DuplicateFilter{Status: models.DuplicatePending, ListingID: 4}, 1, 20).
+		Return([]models.DuplicatePair{{ID: 3, ListingID: 4, DuplicateID: 9}}, nil)
+	c, rec := newInquiryContext(http.MethodGet, "/duplicates?user_id=1&role=admin&listing_id=4", nil, nil, nil)
+	assert.NoError(t, h.GetDuplicates(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestMergeDuplicate_KeepsOneOfThePair(t *testing.T) {
+	repo := new(mocks.DuplicateRepositoryMock)
+	h := handlers.NewDuplicateHandler(repo)
+
+	pair := &models.DuplicatePair{ID: 3, ListingID: 4, DuplicateID: 9, Status: models.DuplicatePending}
+	repo.On("GetDuplicatePair", int64(3)).Return(pair, nil)
+	repo.On("MergeDuplicatePair", pair, 9, 1).Return(nil)
+
+	form := url.Values{"user_id": {"1"}, "role": {"admin"}, "keep_listing_id": {"5"}}
+	c, _ := newInquiryContext(http.MethodPost, "/duplicates/3/merge", form, []string{"pair_id"}, []string{"3"})
+	err := h.MergeDuplicate(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+
+	form.Set("keep_listing_id", "9")
+	c, rec := newInquiryContext(http.MethodPost, "/duplicates/3/merge", form, []string{"pair_id"}, []string{"3"})
+	assert.NoError(t, h.MergeDuplicate(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestDismissDuplicate_AlreadyResolved(t *testing.T) {
+	repo := new(mocks.DuplicateRepositoryMock)
+	h := handlers.NewDuplicateHandler(repo)
+
+	pair := &models.DuplicatePair{ID: 3, ListingID: 4, DuplicateID: 9, Status: models.DuplicateMerged}
+	repo.On("GetDuplicatePair", int64(3)).Return(pair, nil)
+	repo.On("DismissDuplicatePair", pair, 1).Return(models.ErrDuplicateResolved)
+
+	form := url.Values{"user_id": {"1"}, "role": {"admin"}}
+	c, _ := newInquiryContext(http.MethodPost, "/duplicates/3/dismiss", form, []string{"pair_id"}, []string{"3"})
+	err := h.DismissDuplicate(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+
+	form.Set("role", "user")
+	c, _ = newInquiryContext(http.MethodPost, "/duplicates/3/dismiss", form, []string{"pair_id"}, []string{"3"})
+	err = h.DismissDuplicate(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	repo.AssertNumberOfCalls(t, "DismissDuplicatePair", 1)
+	repo.AssertNotCalled(t, "MergeDuplicatePair", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newFavoriteContext(method, target string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("user_id", "listing_id")
+	c.SetParamValues("5", "7")
+	return c, rec
+}
+
+func TestAddFavorite_Created(t *testing.T) {
+	repo := new(mocks.FavoriteRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewFavoriteHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, Status: models.ListingStatusActive}, nil)
+	repo.On("AddFavorite", mock.MatchedBy(func(f *models.Favorite) bool {
+		return f.UserID == 5 && f.ListingID == 7
+	})).Return(true, nil)
+
+	c, rec := newFavoriteContext(http.MethodPut, "/users/5/favorites/7")
+
+	assert.NoError(t, h.AddFavorite(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestAddFavorite_AlreadyFavorited(t *testing.T) {
+	repo := new(mocks.FavoriteRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewFavoriteHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, Status: models.ListingStatusActive}, nil)
+	repo.On("AddFavorite", mock.Anything).Return(false, nil)
+
+	c, rec := newFavoriteContext(http.MethodPut, "/users/5/favorites/7")
+
+	assert.NoError(t, h.AddFavorite(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAddFavorite_ArchivedListing(t *testing.T) {
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewFavoriteHandler(new(mocks.FavoriteRepositoryMock), listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, Status: models.ListingStatusArchived}, nil)
+
+	c, _ := newFavoriteContext(http.MethodPut, "/users/5/favorites/7")
+
+	err := h.AddFavorite(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestAddFavorite_ListingNotFound(t *testing.T) {
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewFavoriteHandler(new(mocks.FavoriteRepositoryMock), listings)
+
+	listings.On("GetListing", 7).Return(nil, errors.New("record not found"))
+
+	c, _ := newFavoriteContext(http.MethodPut, "/users/5/favorites/7")
+
+	err := h.AddFavorite(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestRemoveFavorite_Success(t *testing.T) {
+	repo := new(mocks.FavoriteRepositoryMock)
+	h := handlers.NewFavoriteHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("RemoveFavorite", 5, 7).Return(nil)
+
+	c, rec := newFavoriteContext(http.MethodDelete, "/users/5/favorites/7")
+
+	assert.NoError(t, h.RemoveFavorite(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetFavorites_Success(t *testing.T) {
+	repo := new(mocks.FavoriteRepositoryMock)
+	h := handlers.NewFavoriteHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetFavorites", 5).Return([]models.FavoriteListing{{ListingID: 7, NoLongerAvailable: true}}, nil)
+
+	c, rec := newFavoriteContext(http.MethodGet, "/users/5/favorites")
+
+	assert.NoError(t, h.GetFavorites(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"no_longer_available":true`)
+}
+
+func TestGetFavoriteCounts(t *testing.T) {
+	repo := new(mocks.FavoriteRepositoryMock)
+	h := handlers.NewFavoriteHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("CountFavorites", []int{7, 8}).Return(map[int]int64{7: 3, 8: 0}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/favorite-counts?ids=7,8", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.GetFavoriteCounts(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"7":3`)
+}
+
+func TestGetFavoriteCounts_MissingIDs(t *testing.T) {
+	h := handlers.NewFavoriteHandler(new(mocks.FavoriteRepositoryMock), new(mocks.ListingRepositoryMock))
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/favorite-counts", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := h.GetFavoriteCounts(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newInquiryContext(method, target string, form url.Values, names, values []string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	return c, rec
+}
+
+func TestCreateInquiry_Success(t *testing.T) {
+	repo := new(mocks.InquiryRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewInquiryHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("CreateInquiry", mock.MatchedBy(func(i *models.Inquiry) bool {
+		return i.OwnerID == 2 && i.BuyerID == 5 && i.Status == models.InquiryStatusNew &&
+			i.Source == models.InquirySourceWeb && i.PreferredContact == models.ContactPhone
+	})).Return(nil)
+
+	form := url.Values{"buyer_id": {"5"}, "message": {"Can I visit on Saturday?"}, "phone": {"+4512345678"}, "schedule_preference": {"weekends"}}
+	c, rec := newInquiryContext(http.MethodPost, "/listings/7/inquiries", form, []string{"id"}, []string{"7"})
+
+	assert.NoError(t, h.CreateInquiry(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateInquiry_OwnListing(t *testing.T) {
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewInquiryHandler(new(mocks.InquiryRepositoryMock), listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 5, Status: models.ListingStatusActive}, nil)
+
+	form := url.Values{"buyer_id": {"5"}, "message": {"Hi"}, "email": {"me@example.com"}}
+	c, _ := newInquiryContext(http.MethodPost, "/listings/7/inquiries", form, []string{"id"}, []string{"7"})
+
+	err := h.CreateInquiry(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestCreateInquiry_PartnerRequiresSourceRef(t *testing.T) {
+	h := handlers.NewInquiryHandler(new(mocks.InquiryRepositoryMock), new(mocks.ListingRepositoryMock))
+
+	form := url.Values{"message": {"Hi"}, "email": {"lead@example.com"}, "source": {"partner"}}
+	c, _ := newInquiryContext(http.MethodPost, "/listings/7/inquiries", form, []string{"id"}, []string{"7"})
+
+	err := h.CreateInquiry(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateInquiryStatus_OtherOwner(t *testing.T) {
+	repo := new(mocks.InquiryRepositoryMock)
+	h := handlers.NewInquiryHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetInquiry", int64(3)).Return(&models.Inquiry{ID: 3, OwnerID: 2, Status: models.InquiryStatusNew}, nil)
+
+	form := url.Values{"status": {"contacted"}}
+	c, _ := newInquiryContext(http.MethodPatch, "/users/9/inquiries/3/status", form, []string{"user_id", "inquiry_id"}, []string{"9", "3"})
+
+	err := h.UpdateInquiryStatus(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "UpdateInquiryStatus", mock.Anything, mock.Anything)
+}
+
+func TestUpdateInquiryStatus_Success(t *testing.T) {
+	repo := new(mocks.InquiryRepositoryMock)
+	h := handlers.NewInquiryHandler(repo, new(mocks.ListingRepositoryMock))
+
+	inquiry := &models.Inquiry{ID: 3, OwnerID: 2, Status: models.InquiryStatusNew}
+	repo.On("GetInquiry", int64(3)).Return(inquiry, nil)
+	repo.On("UpdateInquiryStatus", inquiry, models.InquiryStatusQualified).Return(nil)
+
+	form := url.Values{"status": {"qualified"}}
+	c, rec := newInquiryContext(http.MethodPatch, "/users/2/inquiries/3/status", form, []string{"user_id", "inquiry_id"}, []string{"2", "3"})
+
+	assert.NoError(t, h.UpdateInquiryStatus(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetInteractions_WithCounterpart(t *testing.T) {
+	repo := new(mocks.InteractionRepositoryMock)
+	h := handlers.NewInteractionHandler(repo)
+	repo.On("GetCompletedInteractions", 6, 5, mock.Anything).Return([]models.Interaction{
+		{Type: models.InteractionLease, ID: 9, ListingID: 8, OccurredAt: 200},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/6/interactions?with=5", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("user_id")
+	c.SetParamValues("6")
+
+	assert.NoError(t, h.GetInteractions(c))
+	var response struct {
+		Interactions []models.Interaction `json:"interactions"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Interactions, 1)
+	assert.Equal(t, models.InteractionLease, response.Interactions[0].Type)
+}
+
+func TestGetInteractions_RequiresCounterpart(t *testing.T) {
+	h := handlers.NewInteractionHandler(new(mocks.InteractionRepositoryMock))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/6/interactions", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("user_id")
+	c.SetParamValues("6")
+
+	err := h.GetInteractions(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLeaseHandler() (*handlers.LeaseHandler, *mocks.LeaseRepositoryMock, *mocks.ListingRepositoryMock, *mocks.ApplicationRepositoryMock) {
+	repo := new(mocks.LeaseRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	applications := new(mocks.ApplicationRepositoryMock)
+	return handlers.NewLeaseHandler(repo, listings, applications), repo, listings, applications
+}
+
+func TestCreateLease_FromApprovedApplication(t *testing.T) {
+	h, repo, listings, applications := newLeaseHandler()
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "rent", Price: 5000, Status: models.ListingStatusRented}, nil)
+	applications.On("GetApplication", int64(4)).Return(&models.Application{ID: 4, ListingID: 7, ApplicantID: 5, Status: models.ApplicationStatusApproved}, nil)
+	repo.On("CreateLease", mock.MatchedBy(func(l *models.Lease) bool {
+		return l.TenantID == 5 && l.MonthlyRent == 5000 && l.PaymentDueDay == 20 &&
+			l.EndsAt == time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC).UnixMicro()
+	}), mock.MatchedBy(func(charges []models.RentCharge) bool {
+		return len(charges) == 1 && charges[0].Amount == 60000
+	})).Return(nil)
+
+	form := url.Values{"listing_id": {"7"}, "application_id": {"4"}, "starts_on": {"2025-01-20"}, "term_months": {"12"}, "billing_period": {"yearly"}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/2/leases", form, []string{"user_id"}, []string{"2"})
+
+	assert.NoError(t, h.CreateLease(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateLease_DefaultsToMonthlyRentInListingCurrency(t *testing.T) {
+	h, repo, listings, _ := newLeaseHandler()
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "rent", Price: 30000, Currency: "USD", RentPeriod: models.RentPerYear}, nil)
+	repo.On("CreateLease", mock.MatchedBy(func(l *models.Lease) bool {
+		return l.MonthlyRent == 2500 && l.Currency == "USD"
+	}), mock.Anything).Return(nil)
+
+	form := url.Values{"listing_id": {"7"}, "tenant_id": {"5"}, "starts_on": {"2025-01-20"}, "term_months": {"6"}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/2/leases", form, []string{"user_id"}, []string{"2"})
+
+	assert.NoError(t, h.CreateLease(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateLease_OtherCurrencyNeedsRentAndDeposit(t *testing.T) {
+	h, repo, listings, _ := newLeaseHandler()
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "rent", Price: 5000000, Currency: "IDR"}, nil)
+	repo.On("CreateLease", mock.MatchedBy(func(l *models.Lease) bool {
+		return l.MonthlyRent == 320 && l.Deposit == 640 && l.Currency == "USD"
+	}), mock.Anything).Return(nil)
+
+	form := url.Values{"listing_id": {"7"}, "tenant_id": {"5"}, "starts_on": {"2025-01-20"}, "term_months": {"6"}, "currency": {"usd"}}
+	c, _ := newInquiryContext(http.MethodPost, "/users/2/leases", form, []string{"user_id"}, []string{"2"})
+	err := h.CreateLease(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "CreateLease", mock.Anything, mock.Anything)
+
+	form.Set("monthly_rent", "320")
+	form.Set("deposit", "640")
+	c, rec := newInquiryContext(http.MethodPost, "/users/2/leases", form, []string{"user_id"}, []string{"2"})
+	assert.NoError(t, h.CreateLease(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateLease_Overlap(t *testing.T) {
+	h, repo, listings, _ := newLeaseHandler()
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "rent", Price: 5000, Status: models.ListingStatusRented}, nil)
+	repo.On("CreateLease", mock.Anything, mock.Anything).Return(models.ErrLeaseOverlap)
+
+	form := url.Values{"listing_id": {"7"}, "tenant_id": {"5"}, "starts_on": {"2025-01-20"}, "term_months": {"6"}}
+	c, _ := newInquiryContext(http.MethodPost, "/users/2/leases", form, []string{"user_id"}, []string{"2"})
+
+	err := h.CreateLease(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestRenewLease_StartsWhenLeaseEnds(t *testing.T) {
+	h, repo, _, _ := newLeaseHandler()
+
+	end := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	lease := &models.Lease{ID: 3, ListingID: 7, LandlordID: 2, TenantID: 5, Status: models.LeaseStatusActive,
+		EndsAt: end.UnixMicro(), MonthlyRent: 5000, PaymentDueDay: 20, BillingPeriod: models.BillingMonthly}
+	repo.On("GetLease", int64(3)).Return(lease, nil)
+	repo.On("RenewLease", lease, mock.MatchedBy(func(r *models.Lease) bool {
+		return r.StartsAt == end.UnixMicro() && r.MonthlyRent == 5500 && r.RenewedFromID == 3 && r.TermMonths == 12
+	}), mock.MatchedBy(func(charges []models.RentCharge) bool {
+		return len(charges) == 12
+	})).Return(nil)
+
+	form := url.Values{"term_months": {"12"}, "monthly_rent": {"5500"}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/2/leases/3/renew", form, []string{"user_id", "lease_id"}, []string{"2", "3"})
+
+	assert.NoError(t, h.RenewLease(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestTerminateLease_RequiresReason(t *testing.T) {
+	h, repo, _, _ := newLeaseHandler()
+
+	lease := &models.Lease{ID: 3, LandlordID: 2, TenantID: 5, Status: models.LeaseStatusActive, EndsAt: time.Now().AddDate(0, 6, 0).UnixMicro()}
+	repo.On("GetLease", int64(3)).Return(lease, nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/5/leases/3/terminate", url.Values{}, []string{"user_id", "lease_id"}, []string{"5", "3"})
+
+	err := h.TerminateLease(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "TerminateLease", mock.Anything, mock.Anything, mock.Anything)
+}
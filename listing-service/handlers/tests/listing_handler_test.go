@@ -1,23 +1,43 @@
 package tests
 
 import (
+	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"real-estate-system/listing-service/dedup"
+	"real-estate-system/listing-service/fx"
 	"real-estate-system/listing-service/handlers"
 	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/moderation"
+	"real-estate-system/listing-service/mortgage"
 	"real-estate-system/listing-service/repository/mocks"
+	"real-estate-system/listing-service/tenant"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var testRates, _ = fx.Parse([]byte(`{"base": "USD", "rates": {"IDR": "16000", "SGD": "1.25"}}`))
+
+func newListingHandler(repo *mocks.ListingRepositoryMock) *handlers.ListingHandler {
+	return newModeratedListingHandler(repo, new(mocks.ModerationRepositoryMock), &moderation.Rules{})
+}
+
+func newModeratedListingHandler(repo *mocks.ListingRepositoryMock, reviews *mocks.ModerationRepositoryMock, rules *moderation.Rules) *handlers.ListingHandler {
+	return handlers.NewListingHandler(repo, fx.NewStaticService(testRates), dedup.NewDetector(new(mocks.DuplicateRepositoryMock)),
+		moderation.NewModerator(reviews, moderation.NewStaticService(rules)), mortgage.Defaults)
+}
+
 func TestCreateListing_Success(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
 	form := "user_id=1&listing_type=rent&price=200000"
 	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader(form))
@@ -36,13 +56,13 @@ func TestCreateListing_Success(t *testing.T) {
 
 func TestGetListings_Success(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
 	expected := []models.Listing{
 		{ID: 1, ListingType: "rent", Price: 100000},
 		{ID: 2, ListingType: "sale", Price: 200000},
 	}
-	mockRepo.On("GetListings", 1, 10).Return(expected, nil)
+	mockRepo.On("GetListings", models.ListingFilter{}, 1, 10).Return(expected, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/listings?page_num=1&page_size=10", nil)
 	rec := httptest.NewRecorder()
@@ -56,7 +76,7 @@ func TestGetListings_Success(t *testing.T) {
 
 func TestCreateListing_InvalidUserID(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
 	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader("user_id=abc&listing_type=rent&price=100000"))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
@@ -70,7 +90,7 @@ func TestCreateListing_InvalidUserID(t *testing.T) {
 
 func TestCreateListing_InvalidPrice(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
 	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader("user_id=1&listing_type=rent&price=-100"))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
@@ -84,7 +104,7 @@ func TestCreateListing_InvalidPrice(t *testing.T) {
 
 func TestCreateListing_InvalidListingType(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
 	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader("user_id=1&listing_type=other&price=100000"))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
@@ -96,9 +116,20 @@ func TestCreateListing_InvalidListingType(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
 }
 
+func TestCreateListing_InvalidPropertyType(t *testing.T) {
+	handler := newListingHandler(new(mocks.ListingRepositoryMock))
+
+	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader("user_id=1&listing_type=sale&price=100000&property_type=castle"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := handler.CreateListing(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
 func TestCreateListing_RepoError(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
 	mockRepo.On("CreateListing", mock.Anything).Return(errors.New("db error"))
 
@@ -114,9 +145,9 @@ func TestCreateListing_RepoError(t *testing.T) {
 
 func TestGetListings_RepoError(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
-	mockRepo.On("GetListings", 1, 10).Return([]models.Listing{}, errors.New("db error"))
+	mockRepo.On("GetListings", models.ListingFilter{}, 1, 10).Return([]models.Listing{}, errors.New("db error"))
 
 	req := httptest.NewRequest(http.MethodGet, "/listings?page_num=1&page_size=10", nil)
 	rec := httptest.NewRecorder()
@@ -129,9 +160,9 @@ func TestGetListings_RepoError(t *testing.T) {
 
 func TestGetListings_DefaultPagination(t *testing.T) {
 	mockRepo := new(mocks.ListingRepositoryMock)
-	handler := handlers.NewListingHandler(mockRepo)
+	handler := newListingHandler(mockRepo)
 
-	mockRepo.On("GetListings", 1, 10).Return([]models.Listing{}, nil)
+	mockRepo.On("GetListings", models.ListingFilter{}, 1, 10).Return([]models.Listing{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/listings", nil)
 	rec := httptest.NewRecorder()
@@ -141,3 +172,385 @@ func TestGetListings_DefaultPagination(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
+
+func TestGetListings_Filters(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	expected := models.ListingFilter{UserID: 3, ListingType: "rent", MinPrice: 1000, MaxPrice: 4000, Area: "Jakarta Selatan",
+		PriceRanges: []models.PriceRange{
+			{Currency: "IDR", Min: 1000, Max: 4000},
+			{Currency: "SGD", Min: 0, Max: 1},
+			{Currency: "USD", Min: 0, Max: 1},
+		}}
+	mockRepo.On("GetListings", expected, 1, 10).Return([]models.Listing{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings?user_id=3&listing_type=rent&min_price=1000&max_price=4000&area=Jakarta+Selatan", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := handler.GetListings(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetListings_ConvertsPricesToCurrency(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListings", mock.MatchedBy(func(f models.ListingFilter) bool {
+		return len(f.PriceRanges) == 3 && f.PriceRanges[0] == models.PriceRange{Currency: "IDR", Min: 0, Max: 32000000}
+	}), 1, 10).Return([]models.Listing{
+		{ID: 1, ListingType: "rent", Price: 24000000, Currency: "IDR", RentPeriod: "month"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings?currency=usd&max_price=2000", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, handler.GetListings(c))
+	assert.Contains(t, rec.Body.String(), `"price":24000000,"currency":"IDR"`)
+	assert.Contains(t, rec.Body.String(), `"display_price":{"amount":150000,"currency":"USD"}`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateListing_UnsupportedCurrency(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader("user_id=1&listing_type=rent&price=100000&currency=XYZ"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := handler.CreateListing(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	mockRepo.AssertNotCalled(t, "CreateListing", mock.Anything)
+}
+
+func TestGetListings_InvalidFilter(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings?min_price=cheap", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := handler.GetListings(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetListing_NotFound(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 99).Return(nil, errors.New("record not found"))
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/99", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("99")
+
+	err := handler.GetListing(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestGetListing_SaleHasMortgageEstimate(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 7).Return(&models.Listing{ID: 7, ListingType: "sale", Price: 1000000000, Currency: "IDR", Status: models.ListingStatusActive}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/7", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, handler.GetListing(c))
+	var response struct {
+		Estimate *mortgage.Schedule `json:"mortgage_estimate"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, int64(80000000000), response.Estimate.Loan)
+	assert.Greater(t, response.Estimate.FloatingInstallment, response.Estimate.FixedInstallment)
+	assert.Empty(t, response.Estimate.Payments)
+}
+
+func TestGetListing_NoMortgageEstimateBeyondMinorUnits(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 7).Return(&models.Listing{ID: 7, ListingType: "sale", Price: math.MaxInt64/100 + 1, Currency: "IDR", Status: models.ListingStatusActive}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/7", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, handler.GetListing(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "mortgage_estimate")
+}
+
+func TestUpdateListingStatus_Archive(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	listing := &models.Listing{ID: 7, Status: models.ListingStatusActive}
+	mockRepo.On("GetListing", 7).Return(listing, nil)
+	mockRepo.On("UpdateListingStatus", listing, models.ListingStatusArchived).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/listings/7/status", strings.NewReader("status=archived"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	err := handler.UpdateListingStatus(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"archived"`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateListingStatus_InvalidStatus(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	req := httptest.NewRequest(http.MethodPatch, "/listings/7/status", strings.NewReader("status=sold"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	err := handler.UpdateListingStatus(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateListingPrice_OnlyOwner(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Price: 4000, Status: models.ListingStatusActive}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/listings/7/price", strings.NewReader("user_id=3&price=3500"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	err := handler.UpdateListingPrice(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	mockRepo.AssertNotCalled(t, "UpdateListingPrice", mock.Anything, mock.Anything)
+}
+
+func TestGetListings_PriceDroppedSince(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	since := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC).UnixMicro()
+	mockRepo.On("GetListings", models.ListingFilter{PriceDroppedSince: since}, 1, 10).Return([]models.Listing{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings?price_dropped_since=2026-09-01", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	assert.NoError(t, handler.GetListings(c))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateListing_ReportsPossibleDuplicates(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	duplicates := new(mocks.DuplicateRepositoryMock)
+	handler := handlers.NewListingHandler(mockRepo, fx.NewStaticService(testRates), dedup.NewDetector(duplicates),
+		moderation.NewModerator(new(mocks.ModerationRepositoryMock), moderation.NewStaticService(&moderation.Rules{})), mortgage.Defaults)
+
+	form := "user_id=1&listing_type=sale&price=2000000000&city=Jakarta&district=Kemang" +
+		"&address=Jl.+Kemang+Raya+No.+12&latitude=-6.2607&longitude=106.8137&bedrooms=3&bathrooms=2&floor_area=140"
+	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader(form))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	mockRepo.On("CreateListing", mock.MatchedBy(func(l *models.Listing) bool {
+		return l.Address == "Jl. Kemang Raya No. 12" && l.Latitude == -6.2607 && l.Bedrooms == 3 && l.FloorArea == 140
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Listing).ID = 9
+	}).Return(nil)
+	duplicates.On("GetCandidates", mock.AnythingOfType("*models.Listing"), float64(dedup.SearchRadius), mock.Anything).
+		Return([]models.Listing{
+			{ID: 4, ListingType: "sale", Price: 1950000000, District: "Kemang", Address: "Jalan Kemang Raya 12",
+				Latitude: -6.2608, Longitude: 106.8138, Bedrooms: 3, Bathrooms: 2, FloorArea: 138},
+			{ID: 5, ListingType: "sale", Price: 900000000, District: "Menteng", Address: "Jl. Cikini Raya 40",
+				Latitude: -6.1900, Longitude: 106.8390, Bedrooms: 2},
+		}, nil)
+	duplicates.On("SaveDuplicatePairs", mock.MatchedBy(func(pairs []models.DuplicatePair) bool {
+		return len(pairs) == 1 && pairs[0].ListingID == 4 && pairs[0].DuplicateID == 9
+	})).Return(nil)
+
+	assert.NoError(t, handler.CreateListing(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"possible_duplicates":[{"pair_id":0,"listing_id":4,`)
+	duplicates.AssertExpectations(t)
+}
+
+func TestCreateListing_InvalidCoordinates(t *testing.T) {
+	handler := newListingHandler(new(mocks.ListingRepositoryMock))
+
+	for _, form := range []string{"latitude=-6.2", "latitude=91&longitude=10", "latitude=x&longitude=1", "latitude=NaN&longitude=10", "latitude=1&longitude=-Inf", "bedrooms=-1"} {
+		req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader("user_id=1&listing_type=rent&price=200000&"+form))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := handler.CreateListing(c)
+		assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code, form)
+	}
+}
+
+func TestCreateListing_HeldForReview(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	reviews := new(mocks.ModerationRepositoryMock)
+	rules := &moderation.Rules{BlockPhoneNumbers: true, MaxListingsPerHour: 5}
+	rules.PriceOutlier.MinSamples, rules.PriceOutlier.Below = 5, 0.3
+	handler := newModeratedListingHandler(mockRepo, reviews, rules)
+
+	form := "user_id=1&listing_type=rent&price=500000&description=" + url.QueryEscape("Cheap! WA 0812-3456-7890")
+	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader(form))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	reviews.On("GetModerationStats", mock.AnythingOfType("*models.Listing"), mock.AnythingOfType("int64")).
+		Return(models.ModerationStats{AreaMedian: 6000000, AreaSamples: 10, RecentListings: 1}, nil)
+	reviews.On("SubmitListing", mock.AnythingOfType("*models.Listing"), mock.MatchedBy(func(r *models.ListingReview) bool {
+		return len(r.Reasons) == 2 && r.Reasons[0].Rule == models.RulePriceOutlier && r.Reasons[1].Rule == models.RulePhoneNumber
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Listing).Status = models.ListingStatusPendingReview
+	}).Return(nil)
+
+	assert.NoError(t, handler.CreateListing(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"pending_review"`)
+	assert.Contains(t, rec.Body.String(), `"moderation_reasons":[{"rule":"price_outlier"`)
+	mockRepo.AssertNotCalled(t, "CreateListing", mock.Anything)
+	reviews.AssertExpectations(t)
+}
+
+func TestGetListing_UnderReviewOnlyForOwnerAndAdmins(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 3, Status: models.ListingStatusPendingReview}, nil)
+
+	for query, code := range map[string]int{"": http.StatusNotFound, "?user_id=4": http.StatusNotFound, "?user_id=3": http.StatusOK, "?user_id=1&role=admin": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "/listings/7"+query, nil)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+
+		err := handler.GetListing(c)
+		if code == http.StatusOK {
+			assert.NoError(t, err, query)
+		} else {
+			assert.Equal(t, code, err.(*echo.HTTPError).Code, query)
+		}
+	}
+}
+
+func TestGetPriceHistory_HiddenOnlyForOwnerAndAdmins(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 3, Status: models.ListingStatusHidden}, nil)
+	mockRepo.On("GetPriceHistory", 7).Return([]models.ListingPriceChange{}, nil)
+
+	for query, code := range map[string]int{"": http.StatusNotFound, "?user_id=4": http.StatusNotFound, "?user_id=3": http.StatusOK, "?user_id=1&role=admin": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "/listings/7/price-history"+query, nil)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+
+		err := handler.GetPriceHistory(c)
+		if code == http.StatusOK {
+			assert.NoError(t, err, query)
+		} else {
+			assert.Equal(t, code, err.(*echo.HTTPError).Code, query)
+		}
+	}
+}
+
+func TestGetListings_OwnerSeesListingsUnderReview(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListings", models.ListingFilter{UserID: 3, IncludeUnreviewed: true}, 1, 10).Return([]models.Listing{}, nil)
+	mockRepo.On("GetListings", models.ListingFilter{UserID: 3}, 1, 10).Return([]models.Listing{}, nil)
+
+	for _, query := range []string{"user_id=3&viewer_id=3", "user_id=3&viewer_id=4"} {
+		req := httptest.NewRequest(http.MethodGet, "/listings?"+query, nil)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+		assert.NoError(t, handler.GetListings(c))
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateListingStatus_UnderReview(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 7).Return(&models.Listing{ID: 7, Status: models.ListingStatusPendingReview}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/listings/7/status", strings.NewReader("status=active"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	err := handler.UpdateListingStatus(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+	mockRepo.AssertNotCalled(t, "UpdateListingStatus", mock.Anything, mock.Anything)
+}
+
+func TestGetListing_ScopedToRequestTenant(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("GetListing", 7).Return(nil, errors.New("record not found"))
+
+	e := echo.New()
+	e.Use(tenant.Middleware())
+	e.GET("/listings/:id", handler.GetListing)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/7", nil)
+	req.Header.Set(tenant.Header, "acme")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "acme", mockRepo.TenantID)
+}
+
+func TestCreateListing_BelongsToRequestTenant(t *testing.T) {
+	mockRepo := new(mocks.ListingRepositoryMock)
+	handler := newListingHandler(mockRepo)
+
+	mockRepo.On("CreateListing", mock.MatchedBy(func(l *models.Listing) bool { return l.TenantID == "acme" })).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/listings", strings.NewReader("user_id=1&listing_type=rent&price=200000"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(tenant.Header, "acme")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, handler.CreateListing(c))
+	assert.Equal(t, "acme", mockRepo.TenantID)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,370 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/media"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: map[string][]byte{}}
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = data
+	return err
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *memoryStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, media.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStorage) URL(key string) string {
+	return "/media/" + key
+}
+
+var testSigner = media.NewSigner([]byte("test-key"), "/media/private", time.Minute)
+
+func newUploadContext(t *testing.T, userID string, file []byte) (echo.Context, *httptest.ResponseRecorder) {
+	return newMultipartContext(t, "/listings/7/photos", map[string]string{"user_id": userID}, "photo", file)
+}
+
+func newMultipartContext(t *testing.T, target string, fields map[string]string, fileField string, file []byte) (echo.Context, *httptest.ResponseRecorder) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	if file != nil {
+		part, err := writer.CreateFormFile(fileField, "upload")
+		require.NoError(t, err)
+		_, err = part.Write(file)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	return c, rec
+}
+
+func pngPhoto(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 400, 300))))
+	return buf.Bytes()
+}
+
+func TestUploadPhoto_StoresImageAndThumbnails(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	storage := newMemoryStorage()
+	h := handlers.NewMediaHandler(repo, listings, storage, testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("AddMedia", mock.MatchedBy(func(p *models.ListingMedia) bool {
+		return p.ListingID == 7 && p.UploadedBy == 2 && p.ContentType == "image/png" &&
+			p.Width == 400 && p.Height == 300 && len(p.Keys) == 4 && len(p.Thumbnails) == 3 &&
+			p.URL == "/media/"+p.Keys[0]
+	})).Return(nil)
+
+	c, rec := newUploadContext(t, "2", pngPhoto(t))
+
+	assert.NoError(t, h.UploadPhoto(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Len(t, storage.objects, 4)
+	assert.Contains(t, rec.Body.String(), `"small":"/media/listings/7/`)
+	assert.NotContains(t, rec.Body.String(), `"keys"`)
+	repo.AssertExpectations(t)
+}
+
+func TestUploadPhoto_OnlyOwner(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewMediaHandler(repo, listings, newMemoryStorage(), testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+
+	c, _ := newUploadContext(t, "5", pngPhoto(t))
+
+	err := h.UploadPhoto(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "AddMedia", mock.Anything)
+}
+
+func TestUploadPhoto_RejectsOtherFiles(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	storage := newMemoryStorage()
+	h := handlers.NewMediaHandler(repo, listings, storage, testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+
+	c, _ := newUploadContext(t, "2", []byte("%PDF-1.7 floor plan"))
+
+	err := h.UploadPhoto(c)
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.(*echo.HTTPError).Code)
+	assert.Empty(t, storage.objects)
+}
+
+func TestUploadPhoto_RemovesFilesWhenListingIsFull(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	storage := newMemoryStorage()
+	h := handlers.NewMediaHandler(repo, listings, storage, testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("AddMedia", mock.Anything).Return(models.ErrTooManyMedia)
+
+	c, _ := newUploadContext(t, "2", pngPhoto(t))
+
+	err := h.UploadPhoto(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+	assert.Empty(t, storage.objects)
+}
+
+func TestDeletePhoto_RemovesFiles(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	storage := newMemoryStorage()
+	storage.objects["listings/7/a/original.png"] = []byte("x")
+	storage.objects["listings/7/a/small.png"] = []byte("x")
+	h := handlers.NewMediaHandler(repo, listings, storage, testSigner)
+
+	photo := &models.ListingMedia{ID: 3, ListingID: 7, Type: models.MediaPhoto, Keys: []string{"listings/7/a/original.png", "listings/7/a/small.png"}}
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2}, nil)
+	repo.On("GetMediaItem", int64(3)).Return(photo, nil)
+	repo.On("DeleteMedia", photo).Return(nil)
+
+	c, rec := newInquiryContext(http.MethodDelete, "/listings/7/photos/3?user_id=2", nil, []string{"id", "photo_id"}, []string{"7", "3"})
+
+	assert.NoError(t, h.DeletePhoto(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, storage.objects)
+}
+
+func TestDeletePhoto_OtherListing(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewMediaHandler(repo, listings, newMemoryStorage(), testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2}, nil)
+	repo.On("GetMediaItem", int64(3)).Return(&models.ListingMedia{ID: 3, ListingID: 8}, nil)
+
+	c, _ := newInquiryContext(http.MethodDelete, "/listings/7/photos/3?user_id=2", nil, []string{"id", "photo_id"}, []string{"7", "3"})
+
+	err := h.DeletePhoto(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "DeleteMedia", mock.Anything)
+}
+
+func TestReorderPhotos(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewMediaHandler(repo, listings, newMemoryStorage(), testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2}, nil)
+	repo.On("ReorderPhotos", 7, []int64{5, 3, 4}).Return([]models.ListingMedia{{ID: 5}, {ID: 3}, {ID: 4}}, nil)
+	repo.On("ReorderPhotos", 7, []int64{5, 3}).Return(nil, models.ErrPhotoOrder)
+
+	c, rec := newInquiryContext(http.MethodPut, "/listings/7/photos/order", url.Values{"user_id": {"2"}, "photo_ids": {"5, 3,4"}}, []string{"id"}, []string{"7"})
+	assert.NoError(t, h.ReorderPhotos(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	c, _ = newInquiryContext(http.MethodPut, "/listings/7/photos/order", url.Values{"user_id": {"2"}, "photo_ids": {"5,3"}}, []string{"id"}, []string{"7"})
+	err := h.ReorderPhotos(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+
+	c, _ = newInquiryContext(http.MethodPut, "/listings/7/photos/order", url.Values{"user_id": {"2"}, "photo_ids": {"5,x"}}, []string{"id"}, []string{"7"})
+	err = h.ReorderPhotos(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateAttachment_FloorPlanPDF(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	storage := newMemoryStorage()
+	h := handlers.NewMediaHandler(repo, listings, storage, testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("AddMedia", mock.MatchedBy(func(m *models.ListingMedia) bool {
+		return m.Type == models.MediaFloorPlan && m.ContentType == "application/pdf" && !m.Private &&
+			m.Title == "Ground floor" && len(m.Keys) == 1 && strings.HasSuffix(m.Keys[0], "/original.pdf")
+	})).Return(nil)
+
+	c, rec := newMultipartContext(t, "/listings/7/attachments",
+		map[string]string{"user_id": "2", "type": "floor_plan", "title": "Ground floor"}, "file", []byte("%PDF-1.7 floor plan"))
+
+	assert.NoError(t, h.CreateAttachment(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Len(t, storage.objects, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateAttachment_DocumentIsPrivate(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	storage := newMemoryStorage()
+	h := handlers.NewMediaHandler(repo, listings, storage, testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("AddMedia", mock.MatchedBy(func(m *models.ListingMedia) bool {
+		return m.Private && m.URL == "" && m.Thumbnails == nil && len(m.Keys) == 1 &&
+			strings.HasPrefix(m.Keys[0], media.PrivatePrefix)
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.ListingMedia).ID = 9
+	}).Return(nil)
+
+	c, rec := newMultipartContext(t, "/listings/7/attachments",
+		map[string]string{"user_id": "2", "type": "document"}, "file", pngPhoto(t))
+
+	assert.NoError(t, h.CreateAttachment(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"url":"/media/private/9?expires=`)
+	assert.Contains(t, rec.Body.String(), `"url_expires_at"`)
+}
+
+func TestCreateAttachment_Validation(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	storage := newMemoryStorage()
+	h := handlers.NewMediaHandler(repo, listings, storage, testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+
+	cases := []struct {
+		fields map[string]string
+		file   []byte
+		code   int
+	}{
+		{map[string]string{"user_id": "2", "type": "video"}, nil, http.StatusBadRequest},
+		{map[string]string{"user_id": "2", "type": "floor_plan"}, nil, http.StatusBadRequest},
+		{map[string]string{"user_id": "2", "type": "photo"}, []byte("%PDF-1.7"), http.StatusUnsupportedMediaType},
+		{map[string]string{"user_id": "2", "type": "virtual_tour_url", "url": "http://tour.example.com/1"}, nil, http.StatusBadRequest},
+		{map[string]string{"user_id": "2", "type": "virtual_tour_url", "url": "https://user:pw@tour.example.com/1"}, nil, http.StatusBadRequest},
+		{map[string]string{"user_id": "2", "type": "virtual_tour_url", "url": "javascript:alert(1)"}, nil, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		c, _ := newMultipartContext(t, "/listings/7/attachments", tc.fields, "file", tc.file)
+		err := h.CreateAttachment(c)
+		assert.Equal(t, tc.code, err.(*echo.HTTPError).Code, tc.fields)
+	}
+	assert.Empty(t, storage.objects)
+	repo.AssertNotCalled(t, "AddMedia", mock.Anything)
+}
+
+func TestCreateAttachment_VirtualTour(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewMediaHandler(repo, listings, newMemoryStorage(), testSigner)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("AddMedia", mock.MatchedBy(func(m *models.ListingMedia) bool {
+		return m.Type == models.MediaVirtualTourURL && m.URL == "https://tour.example.com/1" && len(m.Keys) == 0
+	})).Return(nil)
+
+	c, rec := newMultipartContext(t, "/listings/7/attachments",
+		map[string]string{"user_id": "2", "type": "virtual_tour_url", "url": " https://tour.example.com/1 "}, "file", nil)
+
+	assert.NoError(t, h.CreateAttachment(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetAttachments_PrivateForOwnerAndAdmin(t *testing.T) {
+	cases := []struct {
+		query   string
+		private bool
+	}{
+		{"", false},
+		{"?user_id=5", false},
+		{"?user_id=2", true},
+		{"?user_id=5&role=admin", true},
+	}
+	for _, tc := range cases {
+		repo := new(mocks.MediaRepositoryMock)
+		listings := new(mocks.ListingRepositoryMock)
+		h := handlers.NewMediaHandler(repo, listings, newMemoryStorage(), testSigner)
+
+		listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2}, nil)
+		repo.On("GetMedia", models.MediaFilter{ListingID: 7, IncludePrivate: tc.private}).
+			Return([]models.ListingMedia{{ID: 9, Type: models.MediaDocument, Private: tc.private}}, nil)
+
+		c, rec := newInquiryContext(http.MethodGet, "/listings/7/attachments"+tc.query, nil, []string{"id"}, []string{"7"})
+
+		assert.NoError(t, h.GetAttachments(c))
+		assert.Equal(t, tc.private, strings.Contains(rec.Body.String(), "/media/private/9?"), tc.query)
+		repo.AssertExpectations(t)
+	}
+}
+
+func TestGetPrivateFile(t *testing.T) {
+	repo := new(mocks.MediaRepositoryMock)
+	storage := newMemoryStorage()
+	storage.objects["private/listings/7/a/original.pdf"] = []byte("%PDF-1.7 deed")
+	h := handlers.NewMediaHandler(repo, new(mocks.ListingRepositoryMock), storage, testSigner)
+
+	repo.On("GetMediaItem", int64(9)).Return(&models.ListingMedia{
+		ID: 9, ListingID: 7, Private: true, ContentType: "application/pdf",
+		Keys: []string{"private/listings/7/a/original.pdf"},
+	}, nil)
+
+	link, _ := testSigner.Sign(9, time.Now())
+	c, rec := newInquiryContext(http.MethodGet, link, nil, []string{"attachment_id"}, []string{"9"})
+	assert.NoError(t, h.GetPrivateFile(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "%PDF-1.7 deed", rec.Body.String())
+	assert.Equal(t, "private, no-store", rec.Header().Get("Cache-Control"))
+
+	expired, _ := testSigner.Sign(9, time.Now().Add(-2*time.Minute))
+	other, _ := testSigner.Sign(10, time.Now())
+	for _, target := range []string{expired, strings.Replace(other, "/10?", "/9?", 1), "/media/private/9"} {
+		c, _ = newInquiryContext(http.MethodGet, target, nil, []string{"attachment_id"}, []string{"9"})
+		err := h.GetPrivateFile(c)
+		assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code, target)
+	}
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReviews_AdminsOnly(t *testing.T) {
+	repo := new(mocks.ModerationRepositoryMock)
+	h := handlers.NewModerationHandler(repo)
+
+	c, _ := newInquiryContext(http.MethodGet, "/moderation/reviews?user_id=1", nil, nil, nil)
+	err := h.GetReviews(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+
+	repo.On("GetReviews", models.ReviewFilter{Status: models.ReviewPending}, 1, 20).
+		Return([]models.ListingReview{{ID: 5, ListingID: 9}}, nil)
+	c, rec := newInquiryContext(http.MethodGet, "/moderation/reviews?user_id=1&role=admin", nil, nil, nil)
+	assert.NoError(t, h.GetReviews(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestApproveListing(t *testing.T) {
+	repo := new(mocks.ModerationRepositoryMock)
+	h := handlers.NewModerationHandler(repo)
+
+	review := &models.ListingReview{ID: 5, ListingID: 9, Status: models.ReviewPending}
+	repo.On("GetReview", int64(5)).Return(review, nil)
+	repo.On("ApproveListing", review, 1, "").Return(nil)
+
+	form := url.Values{"user_id": {"1"}, "role": {"admin"}}
+	c, rec := newInquiryContext(http.MethodPost, "/moderation/reviews/5/approve", form, []string{"review_id"}, []string{"5"})
+	assert.NoError(t, h.ApproveListing(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestRejectListing_NeedsNote(t *testing.T) {
+	repo := new(mocks.ModerationRepositoryMock)
+	h := handlers.NewModerationHandler(repo)
+
+	review := &models.ListingReview{ID: 5, ListingID: 9, Status: models.ReviewPending}
+	repo.On("GetReview", int64(5)).Return(review, nil)
+	repo.On("RejectListing", review, 1, "Asks for payment by wire transfer").Return(models.ErrReviewClosed)
+
+	form := url.Values{"user_id": {"1"}, "role": {"admin"}}
+	c, _ := newInquiryContext(http.MethodPost, "/moderation/reviews/5/reject", form, []string{"review_id"}, []string{"5"})
+	err := h.RejectListing(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+
+	form.Set("note", "Asks for payment by wire transfer")
+	c, _ = newInquiryContext(http.MethodPost, "/moderation/reviews/5/reject", form, []string{"review_id"}, []string{"5"})
+	err = h.RejectListing(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+	repo.AssertExpectations(t)
+}
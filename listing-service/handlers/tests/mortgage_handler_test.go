@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/mortgage"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSchedule_DefaultTerms(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	req := httptest.NewRequest(http.MethodGet, "/mortgage/schedule?price=1000000000&down_payment=300000000&tenor_years=15", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.GetSchedule(c))
+	var response struct {
+		Currency string            `json:"currency"`
+		Schedule mortgage.Schedule `json:"schedule"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "IDR", response.Currency)
+	assert.Equal(t, int64(70000000000), response.Schedule.Loan)
+	assert.Equal(t, 180, response.Schedule.TenorMonths)
+	assert.Equal(t, mortgage.Defaults.FixedRate, response.Schedule.FixedRate)
+	assert.Len(t, response.Schedule.Payments, 180)
+}
+
+func TestGetSchedule_ExportsCSV(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	req := httptest.NewRequest(http.MethodGet, "/mortgage/schedule?price=1000000000&tenor_years=10&format=csv", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.GetSchedule(c))
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "mortgage-schedule.csv")
+	assert.Len(t, strings.Split(strings.TrimSpace(rec.Body.String()), "\n"), 121)
+}
+
+func TestGetSchedule_RejectsFixedPeriodBeyondTenor(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	req := httptest.NewRequest(http.MethodGet, "/mortgage/schedule?price=1000000000&tenor_years=5&fixed_years=10", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := h.GetSchedule(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetSchedule_DecimalPrice(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	req := httptest.NewRequest(http.MethodGet, "/mortgage/schedule?price=250000.50&down_payment=50000.25&currency=usd", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.GetSchedule(c))
+	var response struct {
+		Schedule mortgage.Schedule `json:"schedule"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "USD", response.Schedule.Currency)
+	assert.Equal(t, int64(20000025), response.Schedule.Loan)
+}
+
+func TestGetSchedule_RejectsNonFiniteInput(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	for _, query := range []string{
+		"price=NaN",
+		"price=Inf",
+		"price=1000000000&down_payment=NaN",
+		"price=1000000000&down_payment=-Inf",
+		"price=1000000000&down_payment_pct=NaN",
+		"price=1000000000&fixed_rate=NaN",
+		"price=1000000000&floating_rate=Inf",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/mortgage/schedule?"+query, nil)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := h.GetSchedule(c)
+		if assert.Error(t, err, query) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code, query)
+		}
+	}
+}
+
+func TestGetAffordability_MaxPrice(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	req := httptest.NewRequest(http.MethodGet, "/mortgage/affordability?monthly_income=20000000&monthly_debts=2000000", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.GetAffordability(c))
+	var response struct {
+		Affordability mortgage.Affordability `json:"affordability"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, int64(400000000), response.Affordability.MaxInstallment)
+	assert.Greater(t, response.Affordability.MaxPrice, response.Affordability.MaxLoan)
+}
+
+func TestGetAffordability_RequiresIncome(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	req := httptest.NewRequest(http.MethodGet, "/mortgage/affordability", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := h.GetAffordability(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetAffordability_RejectsNonFiniteInput(t *testing.T) {
+	h := handlers.NewMortgageHandler(mortgage.Defaults)
+
+	for _, query := range []string{
+		"monthly_income=NaN",
+		"monthly_income=Inf",
+		"monthly_income=20000000&monthly_debts=NaN",
+		"monthly_income=20000000&debt_ratio=NaN",
+		"monthly_income=20000000&debt_ratio=Inf",
+		"monthly_income=20000000&fixed_rate=NaN",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/mortgage/affordability?"+query, nil)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := h.GetAffordability(c)
+		if assert.Error(t, err, query) {
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code, query)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pendingOffer() *models.Offer {
+	return &models.Offer{
+		ID:         4,
+		ListingID:  7,
+		BuyerID:    5,
+		SellerID:   2,
+		Amount:     900,
+		Status:     models.OfferStatusPending,
+		AwaitingID: 2,
+		ExpiresAt:  time.Now().Add(time.Hour).UnixMicro(),
+		Version:    1,
+	}
+}
+
+func TestCreateOffer_Success(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewOfferHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "sale", Status: models.ListingStatusActive}, nil)
+	repo.On("HasPendingOffer", 7, 5).Return(false, nil)
+	repo.On("CreateOffer", mock.MatchedBy(func(o *models.Offer) bool {
+		return o.SellerID == 2 && o.AwaitingID == 2 && o.Amount == 900 && o.ExpiresAt > time.Now().Add(71*time.Hour).UnixMicro()
+	}), mock.MatchedBy(func(e *models.OfferEvent) bool {
+		return e.ActorID == 5 && e.Action == models.OfferActionSubmit
+	})).Return(nil)
+
+	form := url.Values{"buyer_id": {"5"}, "amount": {"900"}, "conditions": {"Subject to survey"}}
+	c, rec := newInquiryContext(http.MethodPost, "/listings/7/offers", form, []string{"id"}, []string{"7"})
+
+	assert.NoError(t, h.CreateOffer(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateOffer_RentListing(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewOfferHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, ListingType: "rent", Status: models.ListingStatusActive}, nil)
+
+	form := url.Values{"buyer_id": {"5"}, "amount": {"900"}}
+	c, _ := newInquiryContext(http.MethodPost, "/listings/7/offers", form, []string{"id"}, []string{"7"})
+
+	err := h.CreateOffer(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "CreateOffer", mock.Anything, mock.Anything)
+}
+
+func TestCounterOffer_HandsTurnToBuyer(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	h := handlers.NewOfferHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetOffer", int64(4)).Return(pendingOffer(), nil)
+	repo.On("UpdateOffer", mock.MatchedBy(func(o *models.Offer) bool {
+		return o.Amount == 950 && o.AwaitingID == 5
+	}), mock.MatchedBy(func(e *models.OfferEvent) bool {
+		return e.ActorID == 2 && e.Action == models.OfferActionCounter && e.Amount == 950
+	})).Return(nil)
+
+	c, rec := newInquiryContext(http.MethodPost, "/users/2/offers/4/counter", url.Values{"amount": {"950"}}, []string{"user_id", "offer_id"}, []string{"2", "4"})
+
+	assert.NoError(t, h.CounterOffer(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestAcceptOffer_NotYourTurn(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	h := handlers.NewOfferHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetOffer", int64(4)).Return(pendingOffer(), nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/5/offers/4/accept", url.Values{}, []string{"user_id", "offer_id"}, []string{"5", "4"})
+
+	err := h.AcceptOffer(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "AcceptOffer", mock.Anything, mock.Anything)
+}
+
+func TestAcceptOffer_Expired(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	h := handlers.NewOfferHandler(repo, new(mocks.ListingRepositoryMock))
+
+	offer := pendingOffer()
+	offer.ExpiresAt = time.Now().Add(-time.Minute).UnixMicro()
+	repo.On("GetOffer", int64(4)).Return(offer, nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/2/offers/4/accept", url.Values{}, []string{"user_id", "offer_id"}, []string{"2", "4"})
+
+	err := h.AcceptOffer(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestAcceptOffer_ListingUnavailable(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	h := handlers.NewOfferHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetOffer", int64(4)).Return(pendingOffer(), nil)
+	repo.On("AcceptOffer", mock.MatchedBy(func(o *models.Offer) bool {
+		return o.Status == models.OfferStatusAccepted && o.AwaitingID == 0
+	}), mock.Anything).Return(models.ErrListingUnavailable)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/2/offers/4/accept", url.Values{}, []string{"user_id", "offer_id"}, []string{"2", "4"})
+
+	err := h.AcceptOffer(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestWithdrawOffer_SellerForbidden(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	h := handlers.NewOfferHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetOffer", int64(4)).Return(pendingOffer(), nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/2/offers/4/withdraw", url.Values{}, []string{"user_id", "offer_id"}, []string{"2", "4"})
+
+	err := h.WithdrawOffer(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+}
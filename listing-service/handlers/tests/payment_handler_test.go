@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/payments"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPaymentHandler() (*handlers.PaymentHandler, *mocks.LedgerRepositoryMock, *mocks.LeaseRepositoryMock) {
+	repo := new(mocks.LedgerRepositoryMock)
+	leases := new(mocks.LeaseRepositoryMock)
+	leases.On("GetLease", int64(3)).Return(&models.Lease{ID: 3, LandlordID: 2, TenantID: 5, Currency: "IDR"}, nil)
+	return handlers.NewPaymentHandler(repo, leases, payments.NewFakeProvider()), repo, leases
+}
+
+func TestCreatePayment_TenantChargedThroughProvider(t *testing.T) {
+	h, repo, _ := newPaymentHandler()
+
+	repo.On("CreatePayment", mock.MatchedBy(func(p *models.Payment) bool {
+		return p.Provider == "fake" && p.Amount == 500000 && p.Currency == "IDR" && p.IdempotencyKey == "3:key-1"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Payment).ID = 9
+	}).Return(true, nil)
+	repo.On("CompletePayment", mock.MatchedBy(func(p *models.Payment) bool {
+		return p.Status == models.PaymentStatusSucceeded && p.ProviderRef != ""
+	}), mock.MatchedBy(func(txn *models.LedgerTransaction) bool {
+		return txn.Reference == "payment:9" && txn.Entries[0].Account == models.AccountCash &&
+			txn.Entries[1].Account == models.AccountReceivable && txn.Entries[1].Amount == -500000
+	})).Return(nil)
+
+	form := url.Values{"amount": {"500000"}, "payment_method": {"card_123"}, "idempotency_key": {"key-1"}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/5/leases/3/payments", form, []string{"user_id", "lease_id"}, []string{"5", "3"})
+
+	assert.NoError(t, h.CreatePayment(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreatePayment_Declined(t *testing.T) {
+	h, repo, _ := newPaymentHandler()
+
+	repo.On("CreatePayment", mock.Anything).Return(true, nil)
+	repo.On("CompletePayment", mock.MatchedBy(func(p *models.Payment) bool {
+		return p.Status == models.PaymentStatusFailed && p.FailureReason != ""
+	}), mock.Anything).Return(nil)
+
+	form := url.Values{"amount": {"500000"}, "payment_method": {payments.DeclinedMethod}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/5/leases/3/payments", form, []string{"user_id", "lease_id"}, []string{"5", "3"})
+
+	assert.NoError(t, h.CreatePayment(c))
+	assert.Contains(t, rec.Body.String(), `"status":"failed"`)
+	repo.AssertExpectations(t)
+}
+
+func TestCreatePayment_ReplayedKeyReturnsOriginal(t *testing.T) {
+	h, repo, _ := newPaymentHandler()
+
+	repo.On("CreatePayment", mock.Anything).Run(func(args mock.Arguments) {
+		p := args.Get(0).(*models.Payment)
+		p.ID, p.Status = 9, models.PaymentStatusSucceeded
+	}).Return(false, nil)
+
+	form := url.Values{"amount": {"500000"}, "payment_method": {"card_123"}, "idempotency_key": {"key-1"}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/5/leases/3/payments", form, []string{"user_id", "lease_id"}, []string{"5", "3"})
+
+	assert.NoError(t, h.CreatePayment(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertNotCalled(t, "CompletePayment", mock.Anything, mock.Anything)
+}
+
+func TestCreatePayment_LandlordRecordsManualPayment(t *testing.T) {
+	h, repo, _ := newPaymentHandler()
+
+	repo.On("CreatePayment", mock.MatchedBy(func(p *models.Payment) bool {
+		return p.Provider == models.PaymentProviderManual && p.RecordedBy == 2
+	})).Return(true, nil)
+	repo.On("CompletePayment", mock.MatchedBy(func(p *models.Payment) bool {
+		return p.Status == models.PaymentStatusSucceeded && p.ProviderRef == ""
+	}), mock.Anything).Return(nil)
+
+	form := url.Values{"amount": {"500000"}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/2/leases/3/payments", form, []string{"user_id", "lease_id"}, []string{"2", "3"})
+
+	assert.NoError(t, h.CreatePayment(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportListing(t *testing.T) {
+	repo := new(mocks.ReportRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewReportHandler(repo, listings, 3)
+
+	listings.On("GetListing", 9).Return(&models.Listing{ID: 9, UserID: 3, Status: models.ListingStatusActive}, nil)
+	repo.On("CreateReport", mock.MatchedBy(func(r *models.Report) bool {
+		return r.TargetType == models.ReportTargetListing && r.TargetID == 9 && r.ReporterID == 5 && r.SubjectID == 3 && r.Reason == "deposit_first"
+	}), 3).Return(true, nil).Once()
+	repo.On("CreateReport", mock.Anything, 3).Return(false, nil).Once()
+
+	form := url.Values{"reporter_id": {"5"}, "reason": {"deposit_first"}, "details": {"Asked for a deposit before any viewing"}}
+	c, rec := newInquiryContext(http.MethodPost, "/listings/9/reports", form, []string{"id"}, []string{"9"})
+	assert.NoError(t, h.ReportListing(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	c, rec = newInquiryContext(http.MethodPost, "/listings/9/reports", form, []string{"id"}, []string{"9"})
+	assert.NoError(t, h.ReportListing(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestReportListing_Validation(t *testing.T) {
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewReportHandler(new(mocks.ReportRepositoryMock), listings, 3)
+
+	listings.On("GetListing", 9).Return(&models.Listing{ID: 9, UserID: 3, Status: models.ListingStatusActive}, nil)
+	listings.On("GetListing", 10).Return(&models.Listing{ID: 10, UserID: 4, Status: models.ListingStatusPendingReview}, nil)
+
+	cases := []struct {
+		id   string
+		form url.Values
+		code int
+	}{
+		{"9", url.Values{"reporter_id": {"5"}, "reason": {"ugly"}}, http.StatusBadRequest},
+		{"9", url.Values{"reporter_id": {"5"}, "reason": {"other"}}, http.StatusBadRequest},
+		{"9", url.Values{"reporter_id": {"3"}, "reason": {"scam"}}, http.StatusBadRequest},
+		{"10", url.Values{"reporter_id": {"5"}, "reason": {"scam"}}, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		c, _ := newInquiryContext(http.MethodPost, "/listings/"+tc.id+"/reports", tc.form, []string{"id"}, []string{tc.id})
+		err := h.ReportListing(c)
+		assert.Equal(t, tc.code, err.(*echo.HTTPError).Code, tc.form.Encode())
+	}
+}
+
+func TestReportUser_NotYourself(t *testing.T) {
+	h := handlers.NewReportHandler(new(mocks.ReportRepositoryMock), new(mocks.ListingRepositoryMock), 3)
+
+	form := url.Values{"reporter_id": {"5"}, "reason": {"harassment"}}
+	c, _ := newInquiryContext(http.MethodPost, "/users/5/reports", form, []string{"user_id"}, []string{"5"})
+	err := h.ReportUser(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestResolveReport_AdminsOnly(t *testing.T) {
+	repo := new(mocks.ReportRepositoryMock)
+	h := handlers.NewReportHandler(repo, new(mocks.ListingRepositoryMock), 3)
+
+	form := url.Values{"user_id": {"1"}, "outcome": {"upheld"}}
+	c, _ := newInquiryContext(http.MethodPost, "/reports/12/resolve", form, []string{"report_id"}, []string{"12"})
+	err := h.ResolveReport(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+
+	report := &models.Report{ID: 12, TargetType: models.ReportTargetListing, TargetID: 9, Status: models.ReportOpen}
+	repo.On("GetReport", int64(12)).Return(report, nil)
+	repo.On("ResolveReports", report, models.ReportUpheld, 1, "Deposit scam").
+		Return([]models.Report{{ID: 12, Status: models.ReportUpheld}}, nil)
+
+	form.Set("role", "admin")
+	form.Set("note", "Deposit scam")
+	c, rec := newInquiryContext(http.MethodPost, "/reports/12/resolve", form, []string{"report_id"}, []string{"12"})
+	assert.NoError(t, h.ResolveReport(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetTrustRecord(t *testing.T) {
+	repo := new(mocks.ReportRepositoryMock)
+	h := handlers.NewReportHandler(repo, new(mocks.ListingRepositoryMock), 3)
+
+	repo.On("GetTrustRecord", 3).Return(&models.TrustRecord{UserID: 3, ReportsReceived: 4, ReportsUpheld: 2}, nil)
+	repo.On("GetReports", models.ReportFilter{SubjectID: 3}, 1, 100).Return([]models.Report{{ID: 12}}, nil)
+
+	c, rec := newInquiryContext(http.MethodGet, "/trust-records/3?user_id=1&role=admin", nil, []string{"user_id"}, []string{"3"})
+	assert.NoError(t, h.GetTrustRecord(c))
+	assert.Contains(t, rec.Body.String(), `"reports_upheld":2`)
+	repo.AssertExpectations(t)
+}
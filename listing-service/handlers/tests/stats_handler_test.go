@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAreaStats_GroupsAndFilters(t *testing.T) {
+	repo := new(mocks.StatsRepositoryMock)
+	h := handlers.NewStatsHandler(repo)
+	district := "Mitte"
+	repo.On("GetAreaStats", mock.MatchedBy(func(q models.AreaStatsQuery) bool {
+		since := time.UnixMicro(q.SeriesSince).UTC()
+		return assert.ObjectsAreEqual([]string{"district", "property_type"}, q.GroupBy) &&
+			q.City == "Berlin" && q.ListingType == "rent" && q.Weeks == 12 &&
+			since.Weekday() == time.Monday && time.Since(since) < 12*7*24*time.Hour
+	})).Return([]models.AreaStats{{District: &district, ListingType: "rent", Currency: "EUR", ActiveListings: 3}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/stats?group_by=district,property_type&city=Berlin&listing_type=rent", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.GetAreaStats(c))
+	var response struct {
+		Stats []models.AreaStats `json:"stats"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Stats, 1)
+	assert.Equal(t, "Mitte", *response.Stats[0].District)
+	repo.AssertExpectations(t)
+}
+
+func TestGetAreaStats_RejectsUnknownDimension(t *testing.T) {
+	h := handlers.NewStatsHandler(new(mocks.StatsRepositoryMock))
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/stats?group_by=street", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := h.GetAreaStats(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestGetAreaStats_RejectsTooManyWeeks(t *testing.T) {
+	h := handlers.NewStatsHandler(new(mocks.StatsRepositoryMock))
+
+	req := httptest.NewRequest(http.MethodGet, "/listings/stats?weeks=500", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := h.GetAreaStats(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStartThread_Created(t *testing.T) {
+	repo := new(mocks.ThreadRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewThreadHandler(repo, listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("GetOrCreateThread", mock.MatchedBy(func(th *models.Thread) bool {
+		return th.ListingID == 7 && th.BuyerID == 5 && th.OwnerID == 2 && th.Status == models.ThreadStatusOpen
+	})).Return(true, nil)
+
+	c, rec := newInquiryContext(http.MethodPost, "/listings/7/threads", url.Values{"buyer_id": {"5"}}, []string{"id"}, []string{"7"})
+
+	assert.NoError(t, h.StartThread(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestStartThread_ArchivedListing(t *testing.T) {
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewThreadHandler(new(mocks.ThreadRepositoryMock), listings)
+
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusArchived}, nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/listings/7/threads", url.Values{"buyer_id": {"5"}}, []string{"id"}, []string{"7"})
+
+	err := h.StartThread(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestPostMessage_ClosedThread(t *testing.T) {
+	repo := new(mocks.ThreadRepositoryMock)
+	h := handlers.NewThreadHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetThread", int64(4)).Return(&models.Thread{ID: 4, BuyerID: 5, OwnerID: 2, Status: models.ThreadStatusClosed}, nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/5/threads/4/messages", url.Values{"body": {"Hi"}}, []string{"user_id", "thread_id"}, []string{"5", "4"})
+
+	err := h.PostMessage(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestPostMessage_NotParticipant(t *testing.T) {
+	repo := new(mocks.ThreadRepositoryMock)
+	h := handlers.NewThreadHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetThread", int64(4)).Return(&models.Thread{ID: 4, BuyerID: 5, OwnerID: 2, Status: models.ThreadStatusOpen}, nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/9/threads/4/messages", url.Values{"body": {"Hi"}}, []string{"user_id", "thread_id"}, []string{"9", "4"})
+
+	err := h.PostMessage(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestPostMessage_WithAttachments(t *testing.T) {
+	repo := new(mocks.ThreadRepositoryMock)
+	h := handlers.NewThreadHandler(repo, new(mocks.ListingRepositoryMock))
+
+	thread := &models.Thread{ID: 4, BuyerID: 5, OwnerID: 2, Status: models.ThreadStatusOpen}
+	repo.On("GetThread", int64(4)).Return(thread, nil)
+	repo.On("PostMessage", thread, mock.MatchedBy(func(m *models.Message) bool {
+		return m.SenderID == 5 && len(m.Attachments) == 1 && m.Attachments[0].Name == "payslip.pdf"
+	})).Return(nil)
+
+	form := url.Values{"attachments": {`[{"name":"payslip.pdf","url":"https://files.example.com/a.pdf","content_type":"application/pdf","size":1024}]`}}
+	c, rec := newInquiryContext(http.MethodPost, "/users/5/threads/4/messages", form, []string{"user_id", "thread_id"}, []string{"5", "4"})
+
+	assert.NoError(t, h.PostMessage(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetMessages_NextCursor(t *testing.T) {
+	repo := new(mocks.ThreadRepositoryMock)
+	h := handlers.NewThreadHandler(repo, new(mocks.ListingRepositoryMock))
+
+	repo.On("GetThread", int64(4)).Return(&models.Thread{ID: 4, BuyerID: 5, OwnerID: 2}, nil)
+	repo.On("GetMessages", int64(4), int64(20), 2).Return([]models.Message{{ID: 19}, {ID: 18}}, nil)
+
+	c, rec := newInquiryContext(http.MethodGet, "/users/2/threads/4/messages?before=20&limit=2", url.Values{}, []string{"user_id", "thread_id"}, []string{"2", "4"})
+
+	assert.NoError(t, h.GetMessages(c))
+	assert.Contains(t, rec.Body.String(), `"next_cursor":18`)
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"real-estate-system/listing-service/valuation"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newValuationHandler(repo *mocks.ValuationRepositoryMock) *handlers.ValuationHandler {
+	return handlers.NewValuationHandler(valuation.NewEstimator(repo, fx.NewStaticService(testRates)))
+}
+
+func TestEstimateValue_FromComparables(t *testing.T) {
+	repo := new(mocks.ValuationRepositoryMock)
+	h := newValuationHandler(repo)
+	now := time.Now().UnixMicro()
+	comparable := models.Listing{ListingType: "sale", Status: "active", Price: 2000, Currency: "IDR", City: "Jakarta", FloorArea: 100, CreatedAt: now}
+	comparables := []models.Listing{comparable, comparable, comparable}
+	for i := range comparables {
+		comparables[i].ID = i + 1
+	}
+	repo.On("GetComparables", mock.MatchedBy(func(subject *models.Listing) bool {
+		return subject.TenantID == "default" && subject.PropertyType == "house" && subject.FloorArea == 50
+	}), float64(valuation.SearchRadius), mock.Anything, 200).Return(comparables, nil)
+
+	form := "listing_type=sale&property_type=house&city=Jakarta&floor_area=50"
+	req := httptest.NewRequest(http.MethodPost, "/listings/valuation", strings.NewReader(form))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.EstimateValue(c))
+	var response struct {
+		Valuation models.Valuation `json:"valuation"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1000, response.Valuation.EstimatedValue)
+	assert.Len(t, response.Valuation.Comparables, 3)
+}
+
+func TestEstimateValue_NotEnoughComparables(t *testing.T) {
+	repo := new(mocks.ValuationRepositoryMock)
+	h := newValuationHandler(repo)
+	repo.On("GetComparables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.Listing{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/listings/valuation", strings.NewReader("listing_type=rent&city=Jakarta"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := h.EstimateValue(c)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(*echo.HTTPError).Code)
+}
+
+func TestEstimateValue_RequiresLocation(t *testing.T) {
+	h := newValuationHandler(new(mocks.ValuationRepositoryMock))
+
+	req := httptest.NewRequest(http.MethodPost, "/listings/valuation", strings.NewReader("listing_type=sale&floor_area=50"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	err := h.EstimateValue(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/media"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var feedSigner = media.NewSigner([]byte("key"), "https://api.example.com/calendar/viewings", 90*24*time.Hour)
+
+func futureSlot() (*models.ViewingSlot, time.Time) {
+	start := time.Now().Add(72 * time.Hour).Truncate(time.Hour)
+	return &models.ViewingSlot{
+		ID:       3,
+		AgentID:  2,
+		StartsAt: start.UnixMicro(),
+		EndsAt:   start.Add(3 * time.Hour).UnixMicro(),
+	}, start
+}
+
+func TestBookViewing_Success(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, listings, feedSigner)
+
+	slot, start := futureSlot()
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("GetSlot", int64(3)).Return(slot, nil)
+	repo.On("BookViewing", mock.MatchedBy(func(v *models.Viewing) bool {
+		return v.AgentID == 2 && v.BuyerID == 5 && v.EndsAt-v.StartsAt == (45*time.Minute).Microseconds()
+	}), slot).Return(nil)
+
+	form := url.Values{"buyer_id": {"5"}, "slot_id": {"3"}, "starts_at": {start.Add(time.Hour).Format(time.RFC3339)}, "duration_minutes": {"45"}}
+	c, rec := newInquiryContext(http.MethodPost, "/listings/7/viewings", form, []string{"id"}, []string{"7"})
+
+	assert.NoError(t, h.BookViewing(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestBookViewing_Conflict(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, listings, feedSigner)
+
+	slot, start := futureSlot()
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("GetSlot", int64(3)).Return(slot, nil)
+	repo.On("BookViewing", mock.Anything, slot).Return(models.ErrViewingConflict)
+
+	form := url.Values{"buyer_id": {"5"}, "slot_id": {"3"}, "starts_at": {start.Format(time.RFC3339)}}
+	c, _ := newInquiryContext(http.MethodPost, "/listings/7/viewings", form, []string{"id"}, []string{"7"})
+
+	err := h.BookViewing(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestBookViewing_OutsideSlot(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, listings, feedSigner)
+
+	slot, start := futureSlot()
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, UserID: 2, Status: models.ListingStatusActive}, nil)
+	repo.On("GetSlot", int64(3)).Return(slot, nil)
+
+	form := url.Values{"buyer_id": {"5"}, "slot_id": {"3"}, "starts_at": {start.Add(160 * time.Minute).Format(time.RFC3339)}}
+	c, _ := newInquiryContext(http.MethodPost, "/listings/7/viewings", form, []string{"id"}, []string{"7"})
+
+	err := h.BookViewing(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "BookViewing", mock.Anything, mock.Anything)
+}
+
+func TestRescheduleViewing_KeepsLength(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, new(mocks.ListingRepositoryMock), feedSigner)
+
+	slot, start := futureSlot()
+	viewing := &models.Viewing{ID: 9, SlotID: 3, ListingID: 7, AgentID: 2, BuyerID: 5, StartsAt: slot.StartsAt, EndsAt: slot.StartsAt + (time.Hour).Microseconds(), Status: models.ViewingStatusBooked}
+	newStart := start.Add(90 * time.Minute)
+	repo.On("GetViewing", int64(9)).Return(viewing, nil)
+	repo.On("GetSlot", int64(3)).Return(slot, nil)
+	repo.On("RescheduleViewing", viewing, slot, newStart.UnixMicro(), newStart.Add(time.Hour).UnixMicro()).Return(nil)
+
+	form := url.Values{"starts_at": {newStart.Format(time.RFC3339)}}
+	c, rec := newInquiryContext(http.MethodPatch, "/users/5/viewings/9", form, []string{"user_id", "viewing_id"}, []string{"5", "9"})
+
+	assert.NoError(t, h.RescheduleViewing(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCancelViewing_NotParticipant(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, new(mocks.ListingRepositoryMock), feedSigner)
+
+	repo.On("GetViewing", int64(9)).Return(&models.Viewing{ID: 9, AgentID: 2, BuyerID: 5, Status: models.ViewingStatusBooked}, nil)
+
+	c, _ := newInquiryContext(http.MethodPost, "/users/8/viewings/9/cancel", url.Values{}, []string{"user_id", "viewing_id"}, []string{"8", "9"})
+
+	err := h.CancelViewing(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestExportCalendar(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	listings := new(mocks.ListingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, listings, feedSigner)
+
+	start := time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)
+	repo.On("GetAgentViewings", 2, mock.Anything).Return([]models.Viewing{
+		{ID: 9, ListingID: 7, AgentID: 2, StartsAt: start.UnixMicro(), EndsAt: start.Add(30 * time.Minute).UnixMicro(), Status: models.ViewingStatusBooked},
+		{ID: 10, ListingID: 7, AgentID: 2, StartsAt: start.Add(time.Hour).UnixMicro(), EndsAt: start.Add(90 * time.Minute).UnixMicro(), Status: models.ViewingStatusCancelled, Sequence: 1},
+	}, nil)
+	listings.On("GetListing", 7).Return(&models.Listing{ID: 7, City: "Jakarta Selatan", District: "Tebet"}, nil).Once()
+
+	c, rec := newInquiryContext(http.MethodGet, "/users/2/viewings.ics", url.Values{}, []string{"user_id"}, []string{"2"})
+
+	assert.NoError(t, h.ExportCalendar(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/calendar"))
+	body := rec.Body.String()
+	assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT"))
+	assert.Contains(t, body, "UID:viewing-9@real-estate-system")
+	assert.Contains(t, body, "DTSTART:20300102T100000Z")
+	assert.Contains(t, body, `LOCATION:Tebet\, Jakarta Selatan`)
+	assert.Contains(t, body, "STATUS:CANCELLED")
+	listings.AssertExpectations(t)
+}
+
+func TestGetCalendarLink_OpensFeed(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, new(mocks.ListingRepositoryMock), feedSigner)
+
+	c, rec := newInquiryContext(http.MethodGet, "/users/2/viewings/calendar-link", url.Values{}, []string{"user_id"}, []string{"2"})
+	assert.NoError(t, h.GetCalendarLink(c))
+	var response struct {
+		URL       string `json:"url"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, strings.HasPrefix(response.URL, "https://api.example.com/calendar/viewings/2?"))
+	assert.Greater(t, response.ExpiresAt, time.Now().Add(89*24*time.Hour).UnixMicro())
+
+	link, err := url.Parse(response.URL)
+	assert.NoError(t, err)
+	repo.On("GetAgentViewings", 2, mock.Anything).Return([]models.Viewing{}, nil)
+
+	c, rec = newInquiryContext(http.MethodGet, "/calendar/viewings/2?"+link.RawQuery, url.Values{}, []string{"user_id"}, []string{"2"})
+	assert.NoError(t, h.GetCalendarFeed(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/calendar"))
+
+	// The link only opens the agent's own feed.
+	c, _ = newInquiryContext(http.MethodGet, "/calendar/viewings/3?"+link.RawQuery, url.Values{}, []string{"user_id"}, []string{"3"})
+	err = h.GetCalendarFeed(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "GetAgentViewings", 3, mock.Anything)
+}
+
+func TestGetCalendarFeed_RejectsUnsignedRequests(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	h := handlers.NewViewingHandler(repo, new(mocks.ListingRepositoryMock), feedSigner)
+
+	c, _ := newInquiryContext(http.MethodGet, "/calendar/viewings/2", url.Values{}, []string{"user_id"}, []string{"2"})
+	err := h.GetCalendarFeed(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "GetAgentViewings", mock.Anything, mock.Anything)
+}
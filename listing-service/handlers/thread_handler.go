@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	maxMessageBody     = 5000
+	maxAttachments     = 10
+	defaultMessagePage = 50
+)
+
+type ThreadHandler struct {
+	Repo     interfaces.ThreadRepository
+	Listings interfaces.ListingRepository
+}
+
+func NewThreadHandler(repo interfaces.ThreadRepository, listings interfaces.ListingRepository) *ThreadHandler {
+	return &ThreadHandler{Repo: repo, Listings: listings}
+}
+
+// participantThread loads the thread in the URL if user_id takes part in it.
+func (h *ThreadHandler) participantThread(c echo.Context) (*models.Thread, int, error) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	id, err := strconv.ParseInt(c.Param("thread_id"), 10, 64)
+	if err != nil {
+		return nil, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid thread ID")
+	}
+
+	thread, err := h.Repo.GetThread(id)
+	if err != nil || thread == nil || !thread.HasParticipant(userID) {
+		return nil, 0, echo.NewHTTPError(http.StatusNotFound, "Thread not found")
+	}
+	return thread, userID, nil
+}
+
+// StartThread opens the buyer's conversation with the owner of an active
+// listing, or returns the existing one.
+func (h *ThreadHandler) StartThread(c echo.Context) error {
+	listingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	buyerID, err := strconv.Atoi(c.FormValue("buyer_id"))
+	if err != nil || buyerID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid buyer_id")
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.UserID == buyerID {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot start a thread on your own listing")
+	}
+	if listing.Status != models.ListingStatusActive {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is no longer available")
+	}
+
+	timestamp := time.Now().UnixMicro()
+	thread := models.Thread{
+		ListingID: listingID,
+		BuyerID:   buyerID,
+		OwnerID:   listing.UserID,
+		Status:    models.ThreadStatusOpen,
+		CreatedAt: timestamp,
+		UpdatedAt: timestamp,
+	}
+
+	created, err := h.Repo.GetOrCreateThread(&thread)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	return c.JSON(status, map[string]interface{}{
+		"result": true,
+		"thread": thread,
+	})
+}
+
+// GetThreads lists the user's threads with unread counts.
+func (h *ThreadHandler) GetThreads(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+
+	threads, err := h.Repo.GetThreads(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	var unread int64
+	for _, thread := range threads {
+		unread += thread.UnreadCount
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":       true,
+		"threads":      threads,
+		"unread_count": unread,
+	})
+}
+
+// GetMessages returns history newest first. Pass next_cursor back as
+// before to load older messages; it is 0 on the last page.
+func (h *ThreadHandler) GetMessages(c echo.Context) error {
+	thread, _, err := h.participantThread(c)
+	if err != nil {
+		return err
+	}
+
+	var before int64
+	if raw := c.QueryParam("before"); raw != "" {
+		before, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || before < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid before")
+		}
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = defaultMessagePage
+	}
+
+	messages, err := h.Repo.GetMessages(thread.ID, before, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	var next int64
+	if len(messages) == limit {
+		next = messages[len(messages)-1].ID
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":      true,
+		"thread":      thread,
+		"messages":    messages,
+		"next_cursor": next,
+	})
+}
+
+func (h *ThreadHandler) PostMessage(c echo.Context) error {
+	thread, userID, err := h.participantThread(c)
+	if err != nil {
+		return err
+	}
+	if thread.Status != models.ThreadStatusOpen {
+		return echo.NewHTTPError(http.StatusConflict, "Thread is closed")
+	}
+
+	message := models.Message{
+		SenderID:  userID,
+		Body:      strings.TrimSpace(c.FormValue("body")),
+		CreatedAt: time.Now().UnixMicro(),
+	}
+	if raw := c.FormValue("attachments"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &message.Attachments); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "attachments must be a JSON array")
+		}
+	}
+	if err := validateMessage(&message); err != nil {
+		return err
+	}
+
+	if err := h.Repo.PostMessage(thread, &message); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":  true,
+		"message": message,
+	})
+}
+
+func validateMessage(message *models.Message) error {
+	if message.Body == "" && len(message.Attachments) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "body or attachments is required")
+	}
+	if len(message.Body) > maxMessageBody {
+		return echo.NewHTTPError(http.StatusBadRequest, "body must be at most 5000 characters")
+	}
+	if len(message.Attachments) > maxAttachments {
+		return echo.NewHTTPError(http.StatusBadRequest, "at most 10 attachments are allowed")
+	}
+	for _, attachment := range message.Attachments {
+		u, err := url.Parse(attachment.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "attachment url must be an absolute http(s) URL")
+		}
+		if strings.TrimSpace(attachment.Name) == "" || attachment.Size < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid attachment")
+		}
+	}
+	if message.Attachments == nil {
+		message.Attachments = []models.MessageAttachment{}
+	}
+	return nil
+}
+
+// MarkRead records a read receipt up to message_id, or the latest message.
+func (h *ThreadHandler) MarkRead(c echo.Context) error {
+	thread, userID, err := h.participantThread(c)
+	if err != nil {
+		return err
+	}
+
+	var messageID int64
+	if raw := c.FormValue("message_id"); raw != "" {
+		messageID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || messageID < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid message_id")
+		}
+	}
+
+	if err := h.Repo.MarkRead(thread, userID, messageID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"thread": thread,
+	})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/tenant"
+	"real-estate-system/listing-service/valuation"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ValuationHandler struct {
+	Estimator *valuation.Estimator
+}
+
+func NewValuationHandler(estimator *valuation.Estimator) *ValuationHandler {
+	return &ValuationHandler{Estimator: estimator}
+}
+
+// EstimateValue estimates the price of a property from comparable listings.
+// It takes the listing_type, rent_period, currency, property_type, city,
+// district, coordinates, room counts and floor_area a listing would have;
+// a city or coordinates are required.
+func (h *ValuationHandler) EstimateValue(c echo.Context) error {
+	subject := models.Listing{
+		TenantID:    tenant.ID(c),
+		ListingType: c.FormValue("listing_type"),
+		RentPeriod:  c.FormValue("rent_period"),
+		City:        strings.TrimSpace(c.FormValue("city")),
+		District:    strings.TrimSpace(c.FormValue("district")),
+		Currency:    money.DefaultCurrency,
+	}
+	if subject.ListingType != "rent" && subject.ListingType != "sale" {
+		return echo.NewHTTPError(http.StatusBadRequest, "listing_type must be 'rent' or 'sale'")
+	}
+	switch {
+	case subject.ListingType == "sale" && subject.RentPeriod != "":
+		return echo.NewHTTPError(http.StatusBadRequest, "rent_period only applies to rent listings")
+	case subject.ListingType == "rent" && subject.RentPeriod == "":
+		subject.RentPeriod = models.RentPerMonth
+	case subject.ListingType == "rent" && subject.RentPeriod != models.RentPerMonth && subject.RentPeriod != models.RentPerYear:
+		return echo.NewHTTPError(http.StatusBadRequest, "rent_period must be 'month' or 'year'")
+	}
+	if raw := c.FormValue("currency"); raw != "" {
+		subject.Currency = money.Normalize(raw)
+		if !money.Valid(subject.Currency) || !h.Estimator.FX.Rates().Has(subject.Currency) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Unsupported currency")
+		}
+	}
+	if err := parseListingDetails(c, &subject); err != nil {
+		return err
+	}
+	if subject.City == "" && !subject.HasLocation() {
+		return echo.NewHTTPError(http.StatusBadRequest, "city or latitude and longitude are required")
+	}
+
+	estimate, err := h.Estimator.Estimate(&subject)
+	if errors.Is(err, models.ErrNotEnoughComparables) {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"valuation": estimate,
+	})
+}
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/listing-service/calendar"
+	"real-estate-system/listing-service/media"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultViewingMinutes = 30
+	maxSlotLength         = 12 * time.Hour
+	calendarHistory       = 30 * 24 * time.Hour
+)
+
+// ViewingHandler manages viewings. Feeds signs the links calendar apps
+// subscribe to an agent's viewings with, which carry no user header.
+type ViewingHandler struct {
+	Repo     interfaces.ViewingRepository
+	Listings interfaces.ListingRepository
+	Feeds    *media.Signer
+}
+
+func NewViewingHandler(repo interfaces.ViewingRepository, listings interfaces.ListingRepository, feeds *media.Signer) *ViewingHandler {
+	return &ViewingHandler{Repo: repo, Listings: listings, Feeds: feeds}
+}
+
+// formTime parses an RFC 3339 form value into Unix microseconds.
+func formTime(c echo.Context, name string) (int64, error) {
+	t, err := time.Parse(time.RFC3339, c.FormValue(name))
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, name+" must be an RFC 3339 time")
+	}
+	return t.UnixMicro(), nil
+}
+
+func userParam(c echo.Context) (int, error) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	return userID, nil
+}
+
+// CreateSlot opens a window for viewings of one listing, or of all the
+// agent's listings when listing_id is omitted.
+func (h *ViewingHandler) CreateSlot(c echo.Context) error {
+	agentID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	slot := models.ViewingSlot{AgentID: agentID, CreatedAt: time.Now().UnixMicro()}
+	if slot.StartsAt, err = formTime(c, "starts_at"); err != nil {
+		return err
+	}
+	if slot.EndsAt, err = formTime(c, "ends_at"); err != nil {
+		return err
+	}
+	if slot.StartsAt < slot.CreatedAt {
+		return echo.NewHTTPError(http.StatusBadRequest, "starts_at must be in the future")
+	}
+	if slot.EndsAt <= slot.StartsAt || slot.EndsAt-slot.StartsAt > maxSlotLength.Microseconds() {
+		return echo.NewHTTPError(http.StatusBadRequest, "ends_at must be after starts_at and within 12 hours")
+	}
+
+	if raw := c.FormValue("buffer_minutes"); raw != "" {
+		slot.BufferMinutes, err = strconv.Atoi(raw)
+		if err != nil || slot.BufferMinutes < 0 || slot.BufferMinutes > 240 {
+			return echo.NewHTTPError(http.StatusBadRequest, "buffer_minutes must be between 0 and 240")
+		}
+	}
+
+	if raw := c.FormValue("listing_id"); raw != "" {
+		slot.ListingID, err = strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing_id")
+		}
+		listing, err := tenantListings(c, h.Listings).GetListing(slot.ListingID)
+		if err != nil || listing == nil || listing.UserID != agentID {
+			return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+		}
+	}
+
+	if err := h.Repo.CreateSlot(&slot); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result": true,
+		"slot":   slot,
+	})
+}
+
+func (h *ViewingHandler) DeleteSlot(c echo.Context) error {
+	agentID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(c.Param("slot_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid slot ID")
+	}
+
+	slot, err := h.Repo.GetSlot(id)
+	if err != nil || slot == nil || slot.AgentID != agentID {
+		return echo.NewHTTPError(http.StatusNotFound, "Slot not found")
+	}
+
+	// Viewings already booked in the slot are kept.
+	if err := h.Repo.DeleteSlot(slot.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+	})
+}
+
+// GetListingSlots returns upcoming slots for a listing and the times already
+// taken, without saying by whom.
+func (h *ViewingHandler) GetListingSlots(c echo.Context) error {
+	listingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+
+	now := time.Now().UnixMicro()
+	slots, err := h.Repo.GetSlots(listing.UserID, listingID, now)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	viewings, err := h.Repo.GetAgentViewings(listing.UserID, now)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	busy := []map[string]int64{}
+	for _, v := range viewings {
+		if v.Status == models.ViewingStatusBooked {
+			busy = append(busy, map[string]int64{"starts_at": v.StartsAt, "ends_at": v.EndsAt})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"slots":  slots,
+		"busy":   busy,
+	})
+}
+
+// slotFor loads a slot the agent offers for the listing and checks that
+// [startsAt, endsAt) lies inside it.
+func (h *ViewingHandler) slotFor(rawID string, agentID, listingID int, startsAt, endsAt int64) (*models.ViewingSlot, error) {
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid slot_id")
+	}
+
+	slot, err := h.Repo.GetSlot(id)
+	if err != nil || slot == nil || slot.AgentID != agentID || (slot.ListingID != 0 && slot.ListingID != listingID) {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Slot not found")
+	}
+	if startsAt < slot.StartsAt || endsAt > slot.EndsAt {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "The viewing must fit inside the slot")
+	}
+	if startsAt <= time.Now().UnixMicro() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "starts_at must be in the future")
+	}
+	return slot, nil
+}
+
+func (h *ViewingHandler) BookViewing(c echo.Context) error {
+	listingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid listing ID")
+	}
+	buyerID, err := strconv.Atoi(c.FormValue("buyer_id"))
+	if err != nil || buyerID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid buyer_id")
+	}
+	startsAt, err := formTime(c, "starts_at")
+	if err != nil {
+		return err
+	}
+
+	minutes := defaultViewingMinutes
+	if raw := c.FormValue("duration_minutes"); raw != "" {
+		minutes, err = strconv.Atoi(raw)
+		if err != nil || minutes < 15 || minutes > 180 {
+			return echo.NewHTTPError(http.StatusBadRequest, "duration_minutes must be between 15 and 180")
+		}
+	}
+	endsAt := startsAt + (time.Duration(minutes) * time.Minute).Microseconds()
+
+	listing, err := tenantListings(c, h.Listings).GetListing(listingID)
+	if err != nil || listing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if listing.Status != models.ListingStatusActive {
+		return echo.NewHTTPError(http.StatusConflict, "Listing is no longer available")
+	}
+	if listing.UserID == buyerID {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot book a viewing of your own listing")
+	}
+
+	slot, err := h.slotFor(c.FormValue("slot_id"), listing.UserID, listingID, startsAt, endsAt)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().UnixMicro()
+	viewing := models.Viewing{
+		ListingID: listingID,
+		AgentID:   listing.UserID,
+		BuyerID:   buyerID,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Status:    models.ViewingStatusBooked,
+		Note:      strings.TrimSpace(c.FormValue("note")),
+		CreatedAt: timestamp,
+		UpdatedAt: timestamp,
+	}
+
+	if err := h.Repo.BookViewing(&viewing, slot); err != nil {
+		return viewingError(err)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":  true,
+		"viewing": viewing,
+	})
+}
+
+func viewingError(err error) error {
+	if errors.Is(err, models.ErrViewingConflict) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// participantViewing loads the booked viewing in the URL if user_id is its
+// buyer or agent.
+func (h *ViewingHandler) participantViewing(c echo.Context) (*models.Viewing, error) {
+	userID, err := userParam(c)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseInt(c.Param("viewing_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid viewing ID")
+	}
+
+	viewing, err := h.Repo.GetViewing(id)
+	if err != nil || viewing == nil || !viewing.HasParticipant(userID) {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Viewing not found")
+	}
+	if viewing.Status != models.ViewingStatusBooked {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Viewing is cancelled")
+	}
+	return viewing, nil
+}
+
+// GetViewings lists the user's upcoming viewings as buyer or agent.
+func (h *ViewingHandler) GetViewings(c echo.Context) error {
+	userID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	viewings, err := h.Repo.GetViewings(userID, time.Now().UnixMicro())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":   true,
+		"viewings": viewings,
+	})
+}
+
+// RescheduleViewing moves a viewing to starts_at, keeping its length, in
+// slot_id or its current slot.
+func (h *ViewingHandler) RescheduleViewing(c echo.Context) error {
+	viewing, err := h.participantViewing(c)
+	if err != nil {
+		return err
+	}
+	startsAt, err := formTime(c, "starts_at")
+	if err != nil {
+		return err
+	}
+	endsAt := startsAt + viewing.EndsAt - viewing.StartsAt
+
+	slotID := c.FormValue("slot_id")
+	if slotID == "" {
+		slotID = strconv.FormatInt(viewing.SlotID, 10)
+	}
+	slot, err := h.slotFor(slotID, viewing.AgentID, viewing.ListingID, startsAt, endsAt)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.RescheduleViewing(viewing, slot, startsAt, endsAt); err != nil {
+		return viewingError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"viewing": viewing,
+	})
+}
+
+func (h *ViewingHandler) CancelViewing(c echo.Context) error {
+	viewing, err := h.participantViewing(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.CancelViewing(viewing, strings.TrimSpace(c.FormValue("reason"))); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"viewing": viewing,
+	})
+}
+
+// ExportCalendar serves the viewings an agent hosts as an iCalendar feed,
+// including the last 30 days and cancellations.
+func (h *ViewingHandler) ExportCalendar(c echo.Context) error {
+	agentID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+	return h.serveCalendar(c, agentID)
+}
+
+// GetCalendarLink returns a signed link to the agent's iCalendar feed that
+// calendar apps can subscribe to, and when it stops working.
+func (h *ViewingHandler) GetCalendarLink(c echo.Context) error {
+	agentID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+
+	link, expires := h.Feeds.Sign(int64(agentID), time.Now())
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":     true,
+		"url":        link,
+		"expires_at": expires.UnixMicro(),
+	})
+}
+
+// GetCalendarFeed serves the agent's iCalendar feed through a link made by
+// GetCalendarLink.
+func (h *ViewingHandler) GetCalendarFeed(c echo.Context) error {
+	agentID, err := userParam(c)
+	if err != nil {
+		return err
+	}
+	if !h.Feeds.Verify(int64(agentID), c.QueryParam("expires"), c.QueryParam("signature"), time.Now()) {
+		return echo.NewHTTPError(http.StatusForbidden, "Link is invalid or has expired")
+	}
+	c.Response().Header().Set("Cache-Control", "private, no-store")
+	return h.serveCalendar(c, agentID)
+}
+
+func (h *ViewingHandler) serveCalendar(c echo.Context, agentID int) error {
+	viewings, err := h.Repo.GetAgentViewings(agentID, time.Now().Add(-calendarHistory).UnixMicro())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	locations := map[int]string{}
+	events := make([]calendar.Event, len(viewings))
+	for i, v := range viewings {
+		location, ok := locations[v.ListingID]
+		if !ok {
+			if listing, err := tenantListings(c, h.Listings).GetListing(v.ListingID); err == nil && listing != nil {
+				location = strings.Trim(listing.District+", "+listing.City, ", ")
+			}
+			locations[v.ListingID] = location
+		}
+
+		events[i] = calendar.Event{
+			UID:         "viewing-" + strconv.FormatInt(v.ID, 10) + "@real-estate-system",
+			Sequence:    v.Sequence,
+			Start:       time.UnixMicro(v.StartsAt),
+			End:         time.UnixMicro(v.EndsAt),
+			Stamp:       time.UnixMicro(v.UpdatedAt),
+			Summary:     "Viewing: listing #" + strconv.Itoa(v.ListingID),
+			Description: v.Note,
+			Location:    location,
+			Cancelled:   v.Status == models.ViewingStatusCancelled,
+		}
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="viewings.ics"`)
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", calendar.Encode("Viewings", events))
+}
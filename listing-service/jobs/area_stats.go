@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+// RefreshAreaStats recomputes the area statistics views, so the statistics
+// API lags the listings by at most interval.
+func RefreshAreaStats(repo interfaces.StatsRepository, interval time.Duration) Job {
+	return Job{
+		Name:     "refresh-area-stats",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			return repo.RefreshViews()
+		},
+	}
+}
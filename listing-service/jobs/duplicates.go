@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/dedup"
+	"time"
+)
+
+// DuplicateScan checks existing listings for duplicates, a batch of them
+// per run. Once every listing has been checked it starts over, so listings
+// that have since gained photos or coordinates are compared again.
+func DuplicateScan(detector *dedup.Detector) Job {
+	const batch = 100
+	afterID := 0
+	return Job{
+		Name:     "duplicate-scan",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			listings, err := detector.Repo.GetListingsToScan(afterID, batch)
+			if err != nil {
+				return err
+			}
+			for i := range listings {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if _, err := detector.Check(&listings[i]); err != nil {
+					return err
+				}
+				afterID = listings[i].ID
+			}
+			if len(listings) < batch {
+				afterID = 0
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/fx"
+	"time"
+)
+
+// ReloadRates picks up changes to the exchange rates file.
+func ReloadRates(rates *fx.Service) Job {
+	return Job{
+		Name:     "reload-fx-rates",
+		Interval: 10 * time.Minute,
+		Run: func(ctx context.Context) error {
+			return rates.Reload()
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+// LeaseExpiryNotices emits lease.expiring once for each lease ending within
+// notice that has not been renewed.
+func LeaseExpiryNotices(repo interfaces.LeaseRepository, notice time.Duration) Job {
+	return Job{
+		Name:     "lease-expiry-notices",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			expiring, err := repo.ExpiringLeases(time.Now().Add(notice).UnixMicro(), 100)
+			if err != nil {
+				return err
+			}
+			for i := range expiring {
+				if err := repo.FlagExpiring(&expiring[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// LeaseEnds closes leases whose end has passed and frees their listings.
+func LeaseEnds(repo interfaces.LeaseRepository) Job {
+	return Job{
+		Name:     "lease-ends",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			ended, err := repo.EndedLeases(time.Now().UnixMicro(), 100)
+			if err != nil {
+				return err
+			}
+			for i := range ended {
+				if err := repo.EndLease(&ended[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/moderation"
+	"time"
+)
+
+// ReloadModerationRules picks up changes to the moderation rules file.
+func ReloadModerationRules(rules *moderation.Service) Job {
+	return Job{
+		Name:     "reload-moderation-rules",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			return rules.Reload()
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+// OfferExpiry expires pending offers whose deadline has passed.
+func OfferExpiry(repo interfaces.OfferRepository) Job {
+	return Job{
+		Name:     "offer-expiry",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := repo.ExpireOffers(time.Now().UnixMicro(), 100)
+			return err
+		},
+	}
+}
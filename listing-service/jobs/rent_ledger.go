@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/ledger"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+// PostRentCharges posts scheduled rent to the ledger once it falls due.
+func PostRentCharges(repo interfaces.LedgerRepository, leases interfaces.LeaseRepository) Job {
+	return Job{
+		Name:     "post-rent-charges",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			charges, err := repo.UnpostedRentCharges(time.Now().UnixMicro(), 100)
+			if err != nil {
+				return err
+			}
+			for i := range charges {
+				lease, err := leases.GetLease(charges[i].LeaseID)
+				if err != nil {
+					return err
+				}
+				txn := ledger.RentCharge(*lease, charges[i])
+				if err := repo.PostRentCharge(&charges[i], &txn); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// LateFees applies the landlord's late fee rule to charges whose grace
+// period has passed. Each charge is considered once.
+func LateFees(repo interfaces.LedgerRepository, leases interfaces.LeaseRepository) Job {
+	return Job{
+		Name:     "late-fees",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			now := time.Now().UnixMicro()
+			charges, err := repo.LateFeeCandidates(now, 100)
+			if err != nil {
+				return err
+			}
+			for i := range charges {
+				charge := &charges[i]
+				lease, err := leases.GetLease(charge.LeaseID)
+				if err != nil {
+					return err
+				}
+				rule, err := repo.GetLateFeeRule(lease.LandlordID)
+				if err != nil {
+					return err
+				}
+				txns, err := repo.GetTransactions(lease.ID)
+				if err != nil {
+					return err
+				}
+
+				outstanding := ledger.Outstanding(ledger.Allocate(txns, now), ledger.RentChargeReference(charge.ID))
+				if fee, ok := ledger.LateFee(*lease, *charge, *rule, outstanding, now); ok {
+					err = repo.ApplyLateFee(lease, charge, &fee)
+				} else {
+					err = repo.ApplyLateFee(lease, charge, nil)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
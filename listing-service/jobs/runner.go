@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is periodic background work. Run should handle a bounded batch and
+// return; the runner calls it again after Interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Runner runs each job on its own ticker. Jobs run once at start-up so work
+// that became due while the service was down is not delayed.
+type Runner struct {
+	Jobs []Job
+}
+
+func NewRunner(jobs ...Job) *Runner {
+	return &Runner{Jobs: jobs}
+}
+
+// Run blocks until ctx is cancelled and every job has returned.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range r.Jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx); err != nil {
+			log.Printf("job %s: %v", job.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
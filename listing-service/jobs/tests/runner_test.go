@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"real-estate-system/listing-service/dedup"
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/jobs"
+	"real-estate-system/listing-service/ledger"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/repository/mocks"
+	"real-estate-system/listing-service/valuation"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunner_RunsJobsUntilCancelled(t *testing.T) {
+	var runs int32
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		jobs.NewRunner(jobs.Job{
+			Name:     "count",
+			Interval: 10 * time.Millisecond,
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				return errors.New("keeps running after errors")
+			},
+		}).Run(ctx)
+		close(done)
+	}()
+
+	time.Sleep(55 * time.Millisecond)
+	cancel()
+	<-done
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&runs), int32(3))
+}
+
+func TestViewingReminders_MarksDueViewings(t *testing.T) {
+	repo := new(mocks.ViewingRepositoryMock)
+	due := []models.Viewing{{ID: 1, RemindAt: 10}, {ID: 2, RemindAt: 20}}
+	repo.On("DueReminders", mock.Anything, 100).Return(due, nil)
+	repo.On("MarkReminded", mock.Anything).Return(nil)
+
+	assert.NoError(t, jobs.ViewingReminders(repo).Run(context.Background()))
+	repo.AssertNumberOfCalls(t, "MarkReminded", 2)
+}
+
+func TestOfferExpiry_ExpiresDueOffers(t *testing.T) {
+	repo := new(mocks.OfferRepositoryMock)
+	repo.On("ExpireOffers", mock.Anything, 100).Return(2, nil)
+
+	assert.NoError(t, jobs.OfferExpiry(repo).Run(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestLeaseExpiryNotices_UsesNoticeWindow(t *testing.T) {
+	repo := new(mocks.LeaseRepositoryMock)
+	horizon := time.Now().Add(30 * 24 * time.Hour).UnixMicro()
+	repo.On("ExpiringLeases", mock.MatchedBy(func(before int64) bool {
+		return before >= horizon && before < horizon+int64(time.Minute/time.Microsecond)
+	}), 100).Return([]models.Lease{{ID: 1}}, nil)
+	repo.On("FlagExpiring", mock.Anything).Return(nil)
+
+	assert.NoError(t, jobs.LeaseExpiryNotices(repo, 30*24*time.Hour).Run(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestLateFees_ChargesOutstandingRent(t *testing.T) {
+	repo := new(mocks.LedgerRepositoryMock)
+	leases := new(mocks.LeaseRepositoryMock)
+
+	charge := models.RentCharge{ID: 4, LeaseID: 3, Amount: 5000, DueAt: 100}
+	lease := &models.Lease{ID: 3, LandlordID: 2, Currency: "IDR"}
+	rent := ledger.RentCharge(*lease, charge)
+	paid := ledger.Transaction(3, models.LedgerPayment, "payment:1", "Payment", money.Amount{Minor: 100000, Currency: "IDR"}, 200,
+		models.AccountCash, models.AccountReceivable)
+
+	repo.On("LateFeeCandidates", mock.Anything, 100).Return([]models.RentCharge{charge}, nil)
+	leases.On("GetLease", int64(3)).Return(lease, nil)
+	repo.On("GetLateFeeRule", 2).Return(&models.LateFeeRule{LandlordID: 2, FlatFee: 1000, Currency: "IDR", PercentBps: 1000}, nil)
+	repo.On("GetTransactions", int64(3)).Return([]models.LedgerTransaction{rent, paid}, nil)
+	repo.On("ApplyLateFee", lease, mock.Anything, mock.MatchedBy(func(txn *models.LedgerTransaction) bool {
+		// 10% of the 400000 still owed plus the flat fee
+		return txn != nil && txn.Reference == "late_fee:4" && txn.Amount == 41000
+	})).Return(nil)
+
+	assert.NoError(t, jobs.LateFees(repo, leases).Run(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestDuplicateScan_ResumesAndStartsOver(t *testing.T) {
+	repo := new(mocks.DuplicateRepositoryMock)
+	full := make([]models.Listing, 100)
+	for i := range full {
+		full[i] = models.Listing{ID: i + 1}
+	}
+	repo.On("GetListingsToScan", 0, 100).Return(full, nil).Once()
+	repo.On("GetListingsToScan", 100, 100).Return([]models.Listing{{ID: 101, ListingType: "rent", City: "Bandung"}}, nil).Once()
+	repo.On("GetCandidates", mock.Anything, float64(dedup.SearchRadius), 200).Return([]models.Listing{}, nil).Once()
+	repo.On("SaveDuplicatePairs", []models.DuplicatePair(nil)).Return(nil).Once()
+	repo.On("GetListingsToScan", 0, 100).Return([]models.Listing{}, nil).Once()
+
+	job := jobs.DuplicateScan(dedup.NewDetector(repo))
+	for range 3 {
+		assert.NoError(t, job.Run(context.Background()))
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestRefreshAreaStats_RefreshesViews(t *testing.T) {
+	repo := new(mocks.StatsRepositoryMock)
+	repo.On("RefreshViews").Return(nil)
+
+	assert.NoError(t, jobs.RefreshAreaStats(repo, time.Minute).Run(context.Background()))
+	repo.AssertExpectations(t)
+}
+
+func TestListingEstimates_SavesAndClearsEstimates(t *testing.T) {
+	repo := new(mocks.ValuationRepositoryMock)
+	estimator := valuation.NewEstimator(repo, fx.NewStaticService(&fx.Rates{}))
+	listings := []models.Listing{{ID: 1, ListingType: "sale", City: "Jakarta", Currency: "IDR"}}
+	repo.On("GetListingsToValue", 0, 100).Return(listings, nil)
+	repo.On("GetComparables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]models.Listing{}, nil)
+	repo.On("SaveEstimate", mock.Anything, (*models.Valuation)(nil), mock.Anything).Return(nil)
+
+	assert.NoError(t, jobs.ListingEstimates(estimator).Run(context.Background()))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/valuation"
+	"time"
+)
+
+// ListingEstimates values active listings from their comparables, a batch
+// of them per run, and starts over once every listing has been valued, so
+// estimates follow the market. Listings without enough comparables have
+// their estimate cleared.
+func ListingEstimates(estimator *valuation.Estimator) Job {
+	const batch = 100
+	afterID := 0
+	return Job{
+		Name:     "listing-estimates",
+		Interval: 10 * time.Minute,
+		Run: func(ctx context.Context) error {
+			listings, err := estimator.Repo.GetListingsToValue(afterID, batch)
+			if err != nil {
+				return err
+			}
+			for i := range listings {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				estimate, err := estimator.Estimate(&listings[i])
+				if err != nil && !errors.Is(err, models.ErrNotEnoughComparables) {
+					return err
+				}
+				if err := estimator.Repo.SaveEstimate(&listings[i], estimate, time.Now().UnixMicro()); err != nil {
+					return err
+				}
+				afterID = listings[i].ID
+			}
+			if len(listings) < batch {
+				afterID = 0
+			}
+			return nil
+		},
+	}
+}
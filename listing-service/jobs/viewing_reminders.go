@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+// ViewingReminders emits viewing.reminder for booked viewings whose
+// reminder time has passed.
+func ViewingReminders(repo interfaces.ViewingRepository) Job {
+	return Job{
+		Name:     "viewing-reminders",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			due, err := repo.DueReminders(time.Now().UnixMicro(), 100)
+			if err != nil {
+				return err
+			}
+			for i := range due {
+				if err := repo.MarkReminded(&due[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,215 @@
+package ledger
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"sort"
+)
+
+const (
+	ItemPaid    = "paid"
+	ItemPartial = "partially_paid"
+	ItemDue     = "due"
+	ItemOverdue = "overdue"
+)
+
+// Transaction builds a posting of amount from the credit account to the
+// debit account of a lease's ledger.
+func Transaction(leaseID int64, kind, reference, description string, amount money.Amount, effectiveAt int64, debit, credit string) models.LedgerTransaction {
+	return models.LedgerTransaction{
+		LeaseID:     leaseID,
+		Kind:        kind,
+		Reference:   reference,
+		Description: description,
+		Amount:      amount.Minor,
+		Currency:    amount.Currency,
+		EffectiveAt: effectiveAt,
+		Entries: []models.LedgerEntry{
+			{LeaseID: leaseID, Account: debit, Amount: amount.Minor, Currency: amount.Currency},
+			{LeaseID: leaseID, Account: credit, Amount: -amount.Minor, Currency: amount.Currency},
+		},
+	}
+}
+
+// Balanced reports whether t has at least two entries, all in its currency,
+// summing to zero.
+func Balanced(t models.LedgerTransaction) bool {
+	if len(t.Entries) < 2 {
+		return false
+	}
+	var sum int64
+	for _, entry := range t.Entries {
+		if entry.Currency != t.Currency {
+			return false
+		}
+		sum += entry.Amount
+	}
+	return sum == 0
+}
+
+// Balances sums the entries of txns per account.
+func Balances(txns []models.LedgerTransaction) map[string]int64 {
+	balances := map[string]int64{}
+	for _, t := range txns {
+		for _, entry := range t.Entries {
+			balances[entry.Account] += entry.Amount
+		}
+	}
+	return balances
+}
+
+// receivable is the change t makes to what the tenant owes.
+func receivable(t models.LedgerTransaction) int64 {
+	var amount int64
+	for _, entry := range t.Entries {
+		if entry.Account == models.AccountReceivable {
+			amount += entry.Amount
+		}
+	}
+	return amount
+}
+
+func sortByEffective(txns []models.LedgerTransaction) []models.LedgerTransaction {
+	sorted := append([]models.LedgerTransaction(nil), txns...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].EffectiveAt != sorted[j].EffectiveAt {
+			return sorted[i].EffectiveAt < sorted[j].EffectiveAt
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// Item is a rent charge or late fee and how much of it has been paid.
+type Item struct {
+	Reference   string `json:"reference"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	DueAt       int64  `json:"due_at"`
+	Amount      int64  `json:"amount"`
+	Paid        int64  `json:"paid"`
+	Outstanding int64  `json:"outstanding"`
+	Status      string `json:"status"`
+}
+
+// Allocate applies the lease's payments to its charges and fees oldest
+// first and reports what is still owed on each. Items due before now and
+// not fully paid are overdue.
+func Allocate(txns []models.LedgerTransaction, now int64) []Item {
+	items := []Item{}
+	var paid int64
+	for _, t := range sortByEffective(txns) {
+		change := receivable(t)
+		if change < 0 {
+			paid -= change
+			continue
+		}
+		if change > 0 {
+			items = append(items, Item{
+				Reference:   t.Reference,
+				Kind:        t.Kind,
+				Description: t.Description,
+				DueAt:       t.EffectiveAt,
+				Amount:      change,
+			})
+		}
+	}
+
+	for i := range items {
+		item := &items[i]
+		item.Paid = min(paid, item.Amount)
+		paid -= item.Paid
+		item.Outstanding = item.Amount - item.Paid
+		switch {
+		case item.Outstanding == 0:
+			item.Status = ItemPaid
+		case item.DueAt < now:
+			item.Status = ItemOverdue
+		case item.Paid > 0:
+			item.Status = ItemPartial
+		default:
+			item.Status = ItemDue
+		}
+	}
+	return items
+}
+
+// Outstanding returns what is still owed on the item with the given
+// reference.
+func Outstanding(items []Item, reference string) int64 {
+	for _, item := range items {
+		if item.Reference == reference {
+			return item.Outstanding
+		}
+	}
+	return 0
+}
+
+// Line is one movement of the tenant receivable. Debit and Credit are in
+// minor units; Balance is what the tenant owes after the line.
+type Line struct {
+	Date        int64  `json:"date"`
+	Kind        string `json:"kind"`
+	Reference   string `json:"reference"`
+	Description string `json:"description"`
+	Debit       int64  `json:"debit"`
+	Credit      int64  `json:"credit"`
+	Balance     int64  `json:"balance"`
+}
+
+// Statement is a tenant's account for one lease over [From, To).
+type Statement struct {
+	LeaseID        int64  `json:"lease_id"`
+	TenantID       int    `json:"tenant_id"`
+	Currency       string `json:"currency"`
+	From           int64  `json:"from"`
+	To             int64  `json:"to"`
+	OpeningBalance int64  `json:"opening_balance"`
+	Lines          []Line `json:"lines"`
+	ClosingBalance int64  `json:"closing_balance"`
+	Overdue        int64  `json:"overdue"`
+	Items          []Item `json:"items"`
+}
+
+// BuildStatement lists the lease's postings between from and to with a
+// running balance. Overdue and Items reflect everything posted as of now.
+func BuildStatement(lease models.Lease, txns []models.LedgerTransaction, from, to, now int64) Statement {
+	statement := Statement{
+		LeaseID:  lease.ID,
+		TenantID: lease.TenantID,
+		Currency: lease.Currency,
+		From:     from,
+		To:       to,
+		Lines:    []Line{},
+		Items:    Allocate(txns, now),
+	}
+
+	for _, t := range sortByEffective(txns) {
+		change := receivable(t)
+		if t.EffectiveAt < from {
+			statement.OpeningBalance += change
+			continue
+		}
+		if t.EffectiveAt >= to {
+			continue
+		}
+
+		line := Line{Date: t.EffectiveAt, Kind: t.Kind, Reference: t.Reference, Description: t.Description}
+		if change >= 0 {
+			line.Debit = change
+		} else {
+			line.Credit = -change
+		}
+		statement.ClosingBalance += change
+		line.Balance = statement.OpeningBalance + statement.ClosingBalance
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.ClosingBalance += statement.OpeningBalance
+
+	for _, item := range statement.Items {
+		if item.Status == ItemOverdue {
+			statement.Overdue += item.Outstanding
+		}
+	}
+	return statement
+}
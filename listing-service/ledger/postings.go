@@ -0,0 +1,75 @@
+package ledger
+
+import (
+	"fmt"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"time"
+)
+
+// Currency returns the currency of the lease's ledger. Leases created
+// before currencies were recorded are in the default currency.
+func Currency(lease models.Lease) string {
+	if lease.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return lease.Currency
+}
+
+func RentChargeReference(chargeID int64) string {
+	return fmt.Sprintf("%s:%d", models.LedgerRentCharge, chargeID)
+}
+
+func LateFeeReference(chargeID int64) string {
+	return fmt.Sprintf("%s:%d", models.LedgerLateFee, chargeID)
+}
+
+func PaymentReference(paymentID int64) string {
+	return fmt.Sprintf("%s:%d", models.LedgerPayment, paymentID)
+}
+
+// RentCharge posts a scheduled charge, whose amount is in whole units, as
+// owed by the tenant on its due date.
+func RentCharge(lease models.Lease, charge models.RentCharge) models.LedgerTransaction {
+	amount := money.FromMajor(int64(charge.Amount), Currency(lease))
+	description := fmt.Sprintf("Rent %s", formatPeriod(charge.PeriodStart, charge.PeriodEnd))
+	return Transaction(lease.ID, models.LedgerRentCharge, RentChargeReference(charge.ID), description,
+		amount, charge.DueAt, models.AccountReceivable, models.AccountRentIncome)
+}
+
+// LateFee computes the rule's fee on what is still owed on charge. It
+// returns false if nothing is owed or the fee is zero.
+func LateFee(lease models.Lease, charge models.RentCharge, rule models.LateFeeRule, outstanding int64, now int64) (models.LedgerTransaction, bool) {
+	if outstanding <= 0 {
+		return models.LedgerTransaction{}, false
+	}
+
+	currency := Currency(lease)
+	fee := money.Amount{Minor: outstanding, Currency: currency}.Percent(rule.PercentBps)
+	if rule.Currency == currency {
+		fee.Minor += rule.FlatFee
+	}
+	if fee.Minor <= 0 {
+		return models.LedgerTransaction{}, false
+	}
+
+	description := fmt.Sprintf("Late fee on rent %s", formatPeriod(charge.PeriodStart, charge.PeriodEnd))
+	return Transaction(lease.ID, models.LedgerLateFee, LateFeeReference(charge.ID), description,
+		fee, now, models.AccountReceivable, models.AccountLateFeeIncome), true
+}
+
+// Payment posts a successful payment as received from the tenant.
+func Payment(payment models.Payment) models.LedgerTransaction {
+	amount := money.Amount{Minor: payment.Amount, Currency: payment.Currency}
+	description := "Payment"
+	if payment.Provider == models.PaymentProviderManual {
+		description = "Payment recorded by landlord"
+	}
+	return Transaction(payment.LeaseID, models.LedgerPayment, PaymentReference(payment.ID), description,
+		amount, payment.UpdatedAt, models.AccountCash, models.AccountReceivable)
+}
+
+func formatPeriod(start, end int64) string {
+	const layout = "2006-01-02"
+	return fmt.Sprintf("%s to %s", time.UnixMicro(start).UTC().Format(layout), time.UnixMicro(end).UTC().AddDate(0, 0, -1).Format(layout))
+}
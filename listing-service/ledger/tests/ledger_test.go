@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/ledger"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rent(ref string, amount, at int64) models.LedgerTransaction {
+	return ledger.Transaction(1, models.LedgerRentCharge, ref, "Rent", money.Amount{Minor: amount, Currency: "IDR"}, at, models.AccountReceivable, models.AccountRentIncome)
+}
+
+func payment(ref string, amount, at int64) models.LedgerTransaction {
+	return ledger.Transaction(1, models.LedgerPayment, ref, "Payment", money.Amount{Minor: amount, Currency: "IDR"}, at, models.AccountCash, models.AccountReceivable)
+}
+
+func TestTransaction_IsBalanced(t *testing.T) {
+	txn := rent("rent_charge:1", 500, 10)
+	assert.True(t, ledger.Balanced(txn))
+
+	txn.Entries[1].Amount = -400
+	assert.False(t, ledger.Balanced(txn))
+}
+
+func TestAllocate_OldestFirst(t *testing.T) {
+	txns := []models.LedgerTransaction{
+		rent("rent_charge:2", 500, 20),
+		rent("rent_charge:1", 500, 10),
+		payment("payment:1", 700, 15),
+	}
+
+	items := ledger.Allocate(txns, 30)
+	assert.Equal(t, "rent_charge:1", items[0].Reference)
+	assert.Equal(t, ledger.ItemPaid, items[0].Status)
+	assert.Equal(t, int64(300), items[1].Outstanding)
+	assert.Equal(t, ledger.ItemOverdue, items[1].Status)
+
+	items = ledger.Allocate(txns, 20)
+	assert.Equal(t, ledger.ItemPartial, items[1].Status)
+	assert.Equal(t, int64(300), ledger.Outstanding(items, "rent_charge:2"))
+}
+
+func TestBuildStatement_RunningBalance(t *testing.T) {
+	txns := []models.LedgerTransaction{
+		rent("rent_charge:1", 500, 10),
+		payment("payment:1", 500, 12),
+		rent("rent_charge:2", 500, 20),
+		payment("payment:2", 200, 25),
+	}
+	lease := models.Lease{ID: 1, TenantID: 5, Currency: "IDR"}
+
+	statement := ledger.BuildStatement(lease, txns, 15, 100, 30)
+	assert.Equal(t, int64(0), statement.OpeningBalance)
+	assert.Len(t, statement.Lines, 2)
+	assert.Equal(t, int64(500), statement.Lines[0].Balance)
+	assert.Equal(t, int64(200), statement.Lines[1].Credit)
+	assert.Equal(t, int64(300), statement.ClosingBalance)
+	assert.Equal(t, int64(300), statement.Overdue)
+}
@@ -1,16 +1,30 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"real-estate-system/listing-service/dedup"
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/fx"
 	"real-estate-system/listing-service/handlers"
+	"real-estate-system/listing-service/jobs"
+	"real-estate-system/listing-service/media"
 	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/moderation"
+	"real-estate-system/listing-service/mortgage"
+	"real-estate-system/listing-service/payments"
 	"real-estate-system/listing-service/repository"
 	"real-estate-system/listing-service/repository/interfaces"
 	"real-estate-system/listing-service/seeders"
+	"real-estate-system/listing-service/tenant"
+	"real-estate-system/listing-service/valuation"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -23,19 +37,192 @@ func main() {
 		log.Fatalf("failed to connect to DB: %v", err)
 	}
 
-	if err := db.AutoMigrate(&models.Listing{}); err != nil {
+	// Photos became one type of listing media.
+	if db.Migrator().HasTable("photos") && !db.Migrator().HasTable(&models.ListingMedia{}) {
+		if err := db.Migrator().RenameTable("photos", &models.ListingMedia{}); err != nil {
+			log.Fatalf("failed to migrate photos: %v", err)
+		}
+	}
+	if err := db.AutoMigrate(&models.Listing{}, &models.ListingPriceChange{}, &models.ListingMedia{}, &models.DuplicatePair{}, &models.ListingReview{}, &models.Report{}, &models.TrustRecord{}, &models.OutboxEvent{}, &models.Favorite{}, &models.Inquiry{}, &models.Thread{}, &models.Message{}, &models.ViewingSlot{}, &models.Viewing{}, &models.Offer{}, &models.OfferEvent{}, &models.Application{}, &models.ScreeningRules{}, &models.Lease{}, &models.RentCharge{}, &models.LedgerTransaction{}, &models.LedgerEntry{}, &models.Payment{}, &models.LateFeeRule{}); err != nil {
 		log.Fatalf("failed to migrate: %v", err)
 	}
+	statsRepo := repository.NewGormStatsRepository(db)
+	if err := statsRepo.CreateViews(); err != nil {
+		log.Fatalf("failed to create area statistics views: %v", err)
+	}
 
 	var repo interfaces.ListingRepository = repository.NewGormListingRepository(db)
 
+	rates, err := fx.NewService(ratesFile())
+	if err != nil {
+		log.Fatalf("failed to load exchange rates: %v", err)
+	}
+	rules, err := moderation.NewService(moderationRulesFile())
+	if err != nil {
+		log.Fatalf("failed to load moderation rules: %v", err)
+	}
+
 	seeders.SeedListings(db)
 
+	// Publish outbox events to Redis Streams
+	rdb := redis.NewClient(&redis.Options{
+		Addr: redisAddr(),
+	})
+	publisher := events.NewRedisStreamPublisher(rdb, events.DefaultStream)
+	publisher.Rates = rates
+	relay := events.NewRelay(repository.NewGormOutboxRepository(db), publisher)
+	go relay.Run(context.Background())
+
+	duplicateRepo := repository.NewGormDuplicateRepository(db)
+	detector := dedup.NewDetector(duplicateRepo)
+	moderationRepo := repository.NewGormModerationRepository(db)
+	estimator := valuation.NewEstimator(repository.NewGormValuationRepository(db), rates)
+
+	viewingRepo := repository.NewGormViewingRepository(db)
+	offerRepo := repository.NewGormOfferRepository(db)
+	leaseRepo := repository.NewGormLeaseRepository(db)
+	ledgerRepo := repository.NewGormLedgerRepository(db)
+	go jobs.NewRunner(
+		jobs.ViewingReminders(viewingRepo),
+		jobs.OfferExpiry(offerRepo),
+		jobs.LeaseExpiryNotices(leaseRepo, leaseNoticePeriod()),
+		jobs.LeaseEnds(leaseRepo),
+		jobs.PostRentCharges(ledgerRepo, leaseRepo),
+		jobs.LateFees(ledgerRepo, leaseRepo),
+		jobs.ReloadRates(rates),
+		jobs.DuplicateScan(detector),
+		jobs.ReloadModerationRules(rules),
+		jobs.RefreshAreaStats(statsRepo, areaStatsRefreshInterval()),
+		jobs.ListingEstimates(estimator),
+	).Run(context.Background())
+
 	e := echo.New()
-	handler := handlers.NewListingHandler(repo)
+	e.Use(tenant.Middleware())
+	mortgageTerms := mortgageDefaults()
+	handler := handlers.NewListingHandler(repo, rates, detector, moderation.NewModerator(moderationRepo, rules), mortgageTerms)
 
 	e.GET("/listings", handler.GetListings)
+	e.GET("/listings/stats", handlers.NewStatsHandler(statsRepo).GetAreaStats)
+	e.POST("/listings/valuation", handlers.NewValuationHandler(estimator).EstimateValue)
 	e.POST("/listings", handler.CreateListing)
+	e.GET("/listings/:id", handler.GetListing)
+	e.PATCH("/listings/:id/status", handler.UpdateListingStatus)
+	e.PATCH("/listings/:id/price", handler.UpdateListingPrice)
+	e.GET("/listings/:id/price-history", handler.GetPriceHistory)
+
+	duplicates := handlers.NewDuplicateHandler(duplicateRepo)
+	e.GET("/duplicates", duplicates.GetDuplicates)
+	e.POST("/duplicates/:pair_id/merge", duplicates.MergeDuplicate)
+	e.POST("/duplicates/:pair_id/dismiss", duplicates.DismissDuplicate)
+
+	reviews := handlers.NewModerationHandler(moderationRepo)
+	e.GET("/moderation/reviews", reviews.GetReviews)
+	e.POST("/moderation/reviews/:review_id/approve", reviews.ApproveListing)
+	e.POST("/moderation/reviews/:review_id/reject", reviews.RejectListing)
+
+	reports := handlers.NewReportHandler(repository.NewGormReportRepository(db), repo, reportHideThreshold())
+	e.POST("/listings/:id/reports", reports.ReportListing)
+	e.POST("/users/:user_id/reports", reports.ReportUser)
+	e.GET("/reports", reports.GetReports)
+	e.POST("/reports/:report_id/resolve", reports.ResolveReport)
+	e.GET("/trust-records/:user_id", reports.GetTrustRecord)
+
+	storage, err := media.NewStorageFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure media storage: %v", err)
+	}
+	if local, ok := storage.(*media.LocalStorage); ok {
+		e.Static("/media/listings", local.PublicDir())
+	}
+	signer, err := media.NewSignerFromEnv()
+	if err != nil {
+		log.Fatalf("failed to configure media signing: %v", err)
+	}
+	mediaHandler := handlers.NewMediaHandler(repository.NewGormMediaRepository(db), repo, storage, signer)
+	e.GET("/listings/:id/photos", mediaHandler.GetPhotos)
+	e.POST("/listings/:id/photos", mediaHandler.UploadPhoto)
+	e.PUT("/listings/:id/photos/order", mediaHandler.ReorderPhotos)
+	e.POST("/listings/:id/photos/:photo_id/cover", mediaHandler.SetCover)
+	e.DELETE("/listings/:id/photos/:photo_id", mediaHandler.DeletePhoto)
+	e.GET("/listings/:id/attachments", mediaHandler.GetAttachments)
+	e.POST("/listings/:id/attachments", mediaHandler.CreateAttachment)
+	e.DELETE("/listings/:id/attachments/:attachment_id", mediaHandler.DeleteAttachment)
+	e.GET("/media/private/:attachment_id", mediaHandler.GetPrivateFile)
+
+	favorites := handlers.NewFavoriteHandler(repository.NewGormFavoriteRepository(db), repo)
+	e.GET("/listings/favorite-counts", favorites.GetFavoriteCounts)
+	e.GET("/users/:user_id/favorites", favorites.GetFavorites)
+	e.PUT("/users/:user_id/favorites/:listing_id", favorites.AddFavorite)
+	e.DELETE("/users/:user_id/favorites/:listing_id", favorites.RemoveFavorite)
+
+	inquiries := handlers.NewInquiryHandler(repository.NewGormInquiryRepository(db), repo)
+	e.POST("/listings/:id/inquiries", inquiries.CreateInquiry)
+	e.GET("/users/:user_id/inquiries", inquiries.GetInquiries)
+	e.PATCH("/users/:user_id/inquiries/:inquiry_id/status", inquiries.UpdateInquiryStatus)
+
+	threads := handlers.NewThreadHandler(repository.NewGormThreadRepository(db), repo)
+	e.POST("/listings/:id/threads", threads.StartThread)
+	e.GET("/users/:user_id/threads", threads.GetThreads)
+	e.GET("/users/:user_id/threads/:thread_id/messages", threads.GetMessages)
+	e.POST("/users/:user_id/threads/:thread_id/messages", threads.PostMessage)
+	e.POST("/users/:user_id/threads/:thread_id/read", threads.MarkRead)
+
+	viewings := handlers.NewViewingHandler(viewingRepo, repo, signer.Derive("calendar", calendarFeedURL(), calendarLinkTTL()))
+	e.GET("/listings/:id/viewing-slots", viewings.GetListingSlots)
+	e.POST("/listings/:id/viewings", viewings.BookViewing)
+	e.POST("/users/:user_id/viewing-slots", viewings.CreateSlot)
+	e.DELETE("/users/:user_id/viewing-slots/:slot_id", viewings.DeleteSlot)
+	e.GET("/users/:user_id/viewings", viewings.GetViewings)
+	e.GET("/users/:user_id/viewings.ics", viewings.ExportCalendar)
+	e.GET("/users/:user_id/viewings/calendar-link", viewings.GetCalendarLink)
+	e.GET("/calendar/viewings/:user_id", viewings.GetCalendarFeed)
+	e.PATCH("/users/:user_id/viewings/:viewing_id", viewings.RescheduleViewing)
+	e.POST("/users/:user_id/viewings/:viewing_id/cancel", viewings.CancelViewing)
+
+	offers := handlers.NewOfferHandler(offerRepo, repo)
+	e.POST("/listings/:id/offers", offers.CreateOffer)
+	e.GET("/users/:user_id/offers", offers.GetOffers)
+	e.GET("/users/:user_id/offers/:offer_id", offers.GetOffer)
+	e.POST("/users/:user_id/offers/:offer_id/counter", offers.CounterOffer)
+	e.POST("/users/:user_id/offers/:offer_id/accept", offers.AcceptOffer)
+	e.POST("/users/:user_id/offers/:offer_id/reject", offers.RejectOffer)
+	e.POST("/users/:user_id/offers/:offer_id/withdraw", offers.WithdrawOffer)
+
+	applicationRepo := repository.NewGormApplicationRepository(db)
+	applications := handlers.NewApplicationHandler(applicationRepo, repo)
+	e.POST("/listings/:id/applications", applications.CreateApplication)
+	e.GET("/users/:user_id/applications", applications.GetApplications)
+	e.GET("/users/:user_id/applications/:application_id", applications.GetApplication)
+	e.PATCH("/users/:user_id/applications/:application_id/status", applications.UpdateApplicationStatus)
+	e.GET("/users/:user_id/received-applications", applications.GetReceivedApplications)
+	e.GET("/users/:user_id/screening-rules", applications.GetScreeningRules)
+	e.PUT("/users/:user_id/screening-rules", applications.UpdateScreeningRules)
+
+	leases := handlers.NewLeaseHandler(leaseRepo, repo, applicationRepo)
+	e.POST("/users/:user_id/leases", leases.CreateLease)
+	e.GET("/users/:user_id/leases", leases.GetLeases)
+	e.GET("/users/:user_id/leases/:lease_id", leases.GetLease)
+	e.POST("/users/:user_id/leases/:lease_id/renew", leases.RenewLease)
+	e.POST("/users/:user_id/leases/:lease_id/terminate", leases.TerminateLease)
+
+	mortgages := handlers.NewMortgageHandler(mortgageTerms)
+	e.GET("/mortgage/schedule", mortgages.GetSchedule)
+	e.GET("/mortgage/affordability", mortgages.GetAffordability)
+
+	interactions := handlers.NewInteractionHandler(repository.NewGormInteractionRepository(db))
+	e.GET("/users/:user_id/interactions", interactions.GetInteractions)
+
+	provider, err := payments.NewProvider(os.Getenv("PAYMENT_PROVIDER"))
+	if err != nil {
+		log.Fatalf("failed to configure payments: %v", err)
+	}
+	rentPayments := handlers.NewPaymentHandler(ledgerRepo, leaseRepo, provider)
+	e.POST("/users/:user_id/leases/:lease_id/payments", rentPayments.CreatePayment)
+	e.GET("/users/:user_id/leases/:lease_id/payments", rentPayments.GetPayments)
+	e.GET("/users/:user_id/leases/:lease_id/statement", rentPayments.GetStatement)
+	e.GET("/users/:user_id/rent-roll", rentPayments.GetRentRoll)
+	e.GET("/users/:user_id/late-fee-rule", rentPayments.GetLateFeeRule)
+	e.PUT("/users/:user_id/late-fee-rule", rentPayments.UpdateLateFeeRule)
 
 	fmt.Println("Listing service running on :6000")
 	e.Logger.Fatal(e.Start(":6000"))
@@ -53,3 +240,111 @@ func buildDSN() string {
 		os.Getenv("DB_TIMEZONE"),
 	)
 }
+
+// leaseNoticePeriod is how long before its end a lease is flagged as
+// expiring, LEASE_EXPIRY_NOTICE_DAYS or 60 days.
+func leaseNoticePeriod() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("LEASE_EXPIRY_NOTICE_DAYS"))
+	if err != nil || days <= 0 {
+		days = 60
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// calendarFeedURL is where the signed viewing calendar feeds are served,
+// CALENDAR_FEED_URL or the listing service's /calendar/viewings.
+func calendarFeedURL() string {
+	if url := os.Getenv("CALENDAR_FEED_URL"); url != "" {
+		return url
+	}
+	return "/calendar/viewings"
+}
+
+// calendarLinkTTL is how long a calendar feed link works,
+// CALENDAR_LINK_TTL_DAYS or 90 days.
+func calendarLinkTTL() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("CALENDAR_LINK_TTL_DAYS"))
+	if err != nil || days <= 0 {
+		days = 90
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// reportHideThreshold is how many users must report a listing before it is
+// hidden, REPORT_HIDE_THRESHOLD or 3. Zero never hides listings.
+func reportHideThreshold() int {
+	threshold, err := strconv.Atoi(os.Getenv("REPORT_HIDE_THRESHOLD"))
+	if err != nil || threshold < 0 {
+		threshold = 3
+	}
+	return threshold
+}
+
+// areaStatsRefreshInterval is how often the area statistics are
+// recomputed, AREA_STATS_REFRESH_MINUTES or 15 minutes.
+func areaStatsRefreshInterval() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("AREA_STATS_REFRESH_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// mortgageDefaults are the loan terms of listing estimates and of
+// calculations that leave some out: the MORTGAGE_DOWN_PAYMENT_PCT,
+// MORTGAGE_TENOR_YEARS, MORTGAGE_FIXED_RATE, MORTGAGE_FIXED_YEARS and
+// MORTGAGE_FLOATING_RATE given, and mortgage.Defaults for the rest.
+func mortgageDefaults() mortgage.Terms {
+	terms := mortgage.Defaults
+	for name, dst := range map[string]*float64{
+		"MORTGAGE_DOWN_PAYMENT_PCT": &terms.DownPaymentPct,
+		"MORTGAGE_FIXED_RATE":       &terms.FixedRate,
+		"MORTGAGE_FLOATING_RATE":    &terms.FloatingRate,
+	} {
+		if v, err := strconv.ParseFloat(os.Getenv(name), 64); err == nil {
+			*dst = v
+		}
+	}
+	for name, dst := range map[string]*int{
+		"MORTGAGE_TENOR_YEARS": &terms.TenorMonths,
+		"MORTGAGE_FIXED_YEARS": &terms.FixedMonths,
+	} {
+		if v, err := strconv.Atoi(os.Getenv(name)); err == nil {
+			*dst = v * 12
+		}
+	}
+	if err := terms.Validate(); err != nil {
+		log.Fatalf("invalid mortgage defaults: %v", err)
+	}
+	return terms
+}
+
+// ratesFile is the exchange rates table, FX_RATES_FILE or the one shipped
+// with the service.
+func ratesFile() string {
+	if path := os.Getenv("FX_RATES_FILE"); path != "" {
+		return path
+	}
+	return "fx/rates.json"
+}
+
+// moderationRulesFile is the moderation rules, MODERATION_RULES_FILE or the
+// ones shipped with the service.
+func moderationRulesFile() string {
+	if path := os.Getenv("MODERATION_RULES_FILE"); path != "" {
+		return path
+	}
+	return "moderation/rules.json"
+}
+
+func redisAddr() string {
+	host := os.Getenv("REDIS_HOST")
+	port := os.Getenv("REDIS_PORT")
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" {
+		port = "6379"
+	}
+	return host + ":" + port
+}
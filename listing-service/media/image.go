@@ -0,0 +1,339 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	MaxUploadBytes = 10 << 20
+	MaxPixels      = 50_000_000
+	MinDimension   = 200
+	jpegQuality    = 85
+)
+
+var (
+	ErrUnsupportedType = errors.New("only JPEG and PNG images are supported")
+	ErrTooLarge        = errors.New("image is larger than 10 MB")
+	ErrDimensions      = errors.New("image must be at least 200 pixels on each side and at most 50 megapixels")
+	ErrCorrupt         = errors.New("image could not be decoded")
+)
+
+// ThumbnailSizes maps each thumbnail name to the length of its longest edge.
+var ThumbnailSizes = []struct {
+	Name string
+	Edge int
+}{
+	{"large", 1600},
+	{"medium", 800},
+	{"small", 320},
+}
+
+// Rendition is one encoded version of an uploaded image.
+type Rendition struct {
+	Name   string
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// Processed is an upload that passed validation, re-encoded without its
+// metadata, with its thumbnails and perceptual hash.
+type Processed struct {
+	ContentType string
+	Extension   string
+	Original    Rendition
+	Thumbnails  []Rendition
+	Hash        uint64
+}
+
+// Process validates an uploaded photo and renders it and its thumbnails.
+// Images are decoded and encoded again, which drops EXIF and any other
+// metadata; the EXIF orientation of JPEGs is applied to the pixels first.
+func Process(data []byte) (*Processed, error) {
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+
+	contentType := DetectType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	if cfg.Width < MinDimension || cfg.Height < MinDimension || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrDimensions
+	}
+
+	var src image.Image
+	if contentType == "image/jpeg" {
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		src, err = png.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+
+	img := toNRGBA(src)
+	if contentType == "image/jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+
+	processed := &Processed{ContentType: contentType, Extension: ".jpg", Hash: Hash(img)}
+	if contentType == "image/png" {
+		processed.Extension = ".png"
+	}
+
+	if processed.Original, err = encode("original", img, contentType); err != nil {
+		return nil, err
+	}
+	// Each thumbnail is scaled from the previous, larger one.
+	current := img
+	for _, size := range ThumbnailSizes {
+		current = Fit(current, size.Edge)
+		rendition, err := encode(size.Name, current, contentType)
+		if err != nil {
+			return nil, err
+		}
+		processed.Thumbnails = append(processed.Thumbnails, rendition)
+	}
+	return processed, nil
+}
+
+func encode(name string, img *image.NRGBA, contentType string) (Rendition, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	bounds := img.Bounds()
+	return Rendition{Name: name, Data: buf.Bytes(), Width: bounds.Dx(), Height: bounds.Dy()}, err
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if img, ok := src.(*image.NRGBA); ok && img.Rect.Min == (image.Point{}) {
+		return img
+	}
+	bounds := src.Bounds()
+	img := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Src)
+	return img
+}
+
+// Fit scales img down so that its longest edge is at most edge pixels,
+// keeping its aspect ratio. Smaller images are returned as they are.
+func Fit(img *image.NRGBA, edge int) *image.NRGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if w <= edge && h <= edge {
+		return img
+	}
+	if w >= h {
+		h = max(1, (h*edge+w/2)/w)
+		w = edge
+	} else {
+		w = max(1, (w*edge+h/2)/h)
+		h = edge
+	}
+	return resize(img, w, h)
+}
+
+// resize shrinks src to w×h by averaging the source pixels that each
+// destination pixel covers, weighting partially covered ones. Colours are
+// averaged premultiplied by alpha so transparent pixels do not bleed.
+func resize(src *image.NRGBA, w, h int) *image.NRGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	xs := spans(sw, w)
+	ys := spans(sh, h)
+
+	sums := make([]float64, w*4)
+	for dy, yspan := range ys {
+		clear(sums)
+		for _, yw := range yspan {
+			row := src.Pix[yw.index*src.Stride:]
+			for dx, xspan := range xs {
+				sum := sums[dx*4 : dx*4+4]
+				for _, xw := range xspan {
+					p := row[xw.index*4 : xw.index*4+4]
+					weight := xw.weight * yw.weight
+					a := float64(p[3]) * weight
+					sum[0] += float64(p[0]) * a
+					sum[1] += float64(p[1]) * a
+					sum[2] += float64(p[2]) * a
+					sum[3] += a
+				}
+			}
+		}
+
+		out := dst.Pix[dy*dst.Stride:]
+		for dx := range w {
+			sum := sums[dx*4 : dx*4+4]
+			area := spanWeight(yspan) * spanWeight(xs[dx])
+			if sum[3] == 0 {
+				continue
+			}
+			out[dx*4] = clamp(sum[0] / sum[3])
+			out[dx*4+1] = clamp(sum[1] / sum[3])
+			out[dx*4+2] = clamp(sum[2] / sum[3])
+			out[dx*4+3] = clamp(sum[3] / area)
+		}
+	}
+	return dst
+}
+
+type contribution struct {
+	index  int
+	weight float64
+}
+
+// spans lists, for each of the n destination pixels along an edge of length
+// size, the source pixels it covers and by how much.
+func spans(size, n int) [][]contribution {
+	scale := float64(size) / float64(n)
+	result := make([][]contribution, n)
+	for i := range n {
+		start, end := float64(i)*scale, float64(i+1)*scale
+		for j := int(start); j < size && float64(j) < end; j++ {
+			weight := min(end, float64(j+1)) - max(start, float64(j))
+			if weight > 0 {
+				result[i] = append(result[i], contribution{j, weight})
+			}
+		}
+	}
+	return result
+}
+
+func spanWeight(span []contribution) float64 {
+	var total float64
+	for _, c := range span {
+		total += c.weight
+	}
+	return total
+}
+
+func clamp(v float64) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
+
+// exifOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 when it
+// has none.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := range count {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value < 1 || value > 8 {
+				return 1
+			}
+			return value
+		}
+	}
+	return 1
+}
+
+// orient transforms img so that it displays upright given its EXIF
+// orientation.
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := range h {
+		for x := range w {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.SetNRGBA(dx, dy, img.NRGBAAt(x, y))
+		}
+	}
+	return dst
+}
+
+// DetectType returns the content type of a file from its first bytes.
+func DetectType(data []byte) string {
+	return http.DetectContentType(data)
+}
@@ -0,0 +1,54 @@
+package media
+
+import (
+	"image"
+	"math/bits"
+	"strconv"
+)
+
+// Hash is a 64-bit difference hash of an image's luminance. Resized,
+// recompressed or slightly edited copies of a photo hash to values a few
+// bits apart.
+func Hash(img *image.NRGBA) uint64 {
+	small := resize(img, 9, 8)
+	var hash uint64
+	for y := range 8 {
+		row := small.Pix[y*small.Stride:]
+		for x := range 8 {
+			hash <<= 1
+			if luma(row[x*4:]) > luma(row[(x+1)*4:]) {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// luma is the brightness of an NRGBA pixel, transparent pixels counting as
+// black.
+func luma(p []uint8) float64 {
+	return (0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])) * float64(p[3]) / 255
+}
+
+// FormatHash encodes a hash as 16 hex digits.
+func FormatHash(hash uint64) string {
+	s := strconv.FormatUint(hash, 16)
+	for len(s) < 16 {
+		s = "0" + s
+	}
+	return s
+}
+
+// HashDistance is the number of bits in which two formatted hashes differ.
+// It is false if either is not a hash.
+func HashDistance(a, b string) (int, bool) {
+	x, err := strconv.ParseUint(a, 16, 64)
+	if err != nil || len(a) != 16 {
+		return 0, false
+	}
+	y, err := strconv.ParseUint(b, 16, 64)
+	if err != nil || len(b) != 16 {
+		return 0, false
+	}
+	return bits.OnesCount64(x ^ y), true
+}
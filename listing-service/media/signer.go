@@ -0,0 +1,83 @@
+package media
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultURLTTL = 15 * time.Minute
+
+// Signer issues expiring links to private attachments, served by the
+// service under BaseURL/<id>.
+type Signer struct {
+	key     []byte
+	BaseURL string
+	TTL     time.Duration
+}
+
+func NewSigner(key []byte, baseURL string, ttl time.Duration) *Signer {
+	return &Signer{key: key, BaseURL: strings.TrimSuffix(baseURL, "/"), TTL: ttl}
+}
+
+// NewSignerFromEnv signs with MEDIA_SIGNING_KEY, links to MEDIA_PRIVATE_URL
+// and keeps links valid for MEDIA_URL_TTL_MINUTES. Without a key one is
+// made up, so links stop working when the service restarts.
+func NewSignerFromEnv() (*Signer, error) {
+	key := []byte(os.Getenv("MEDIA_SIGNING_KEY"))
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		log.Println("media: MEDIA_SIGNING_KEY is not set, private links will not survive a restart")
+	}
+	ttl := DefaultURLTTL
+	if minutes, err := strconv.Atoi(os.Getenv("MEDIA_URL_TTL_MINUTES")); err == nil && minutes > 0 {
+		ttl = time.Duration(minutes) * time.Minute
+	}
+	return NewSigner(key, envOr("MEDIA_PRIVATE_URL", "/media/private"), ttl), nil
+}
+
+// Derive returns a signer of links to something other than attachments,
+// served under baseURL and valid for ttl. Its key is derived from s's for
+// purpose, so a link of one kind never opens another.
+func (s *Signer) Derive(purpose, baseURL string, ttl time.Duration) *Signer {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(purpose))
+	return NewSigner(mac.Sum(nil), baseURL, ttl)
+}
+
+func (s *Signer) signature(id int64, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns a link to the attachment and the time it stops working.
+func (s *Signer) Sign(id int64, now time.Time) (string, time.Time) {
+	expires := now.Add(s.TTL).Unix()
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {s.signature(id, expires)},
+	}
+	return fmt.Sprintf("%s/%d?%s", s.BaseURL, id, query.Encode()), time.Unix(expires, 0)
+}
+
+// Verify reports whether expires and signature, from a link made by Sign,
+// grant access to the attachment at now.
+func (s *Signer) Verify(id int64, expires, signature string, now time.Time) bool {
+	at, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > at {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.signature(id, at)))
+}
@@ -0,0 +1,73 @@
+package media
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	ErrUnknownStorage = errors.New("unknown media storage")
+	ErrNotFound       = errors.New("media file not found")
+)
+
+// PrivatePrefix starts the keys of files that must only be read through
+// Get; URL does not work for them.
+const PrivatePrefix = "private/"
+
+// Storage keeps uploaded files under slash-separated keys and serves them
+// from URL(key).
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// NewStorageFromEnv returns the storage selected by MEDIA_STORAGE: "local"
+// (the default) or "s3" for any S3-compatible service such as MinIO.
+// MEDIA_BASE_URL is the public address of local files.
+func NewStorageFromEnv() (Storage, error) {
+	switch os.Getenv("MEDIA_STORAGE") {
+	case "", "local":
+		return NewLocalStorage(envOr("MEDIA_DIR", "uploads"), envOr("MEDIA_BASE_URL", "/media")), nil
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    envOr("S3_REGION", "us-east-1"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+	}
+	return nil, ErrUnknownStorage
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// publicRoot is the first segment of the keys of public files.
+const publicRoot = "listings"
+
+// KeyPrefix is a fresh location for the files of one attachment of the
+// listing, ending in a slash.
+func KeyPrefix(listingID int, private bool) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	prefix := fmt.Sprintf("%s/%d/%s/", publicRoot, listingID, hex.EncodeToString(buf))
+	if private {
+		prefix = PrivatePrefix + prefix
+	}
+	return prefix, nil
+}
@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"real-estate-system/listing-service/media"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testImage is w×h grey with a red square in the top-left corner.
+func testImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			c := color.NRGBA{128, 128, 128, 255}
+			if x < w/4 && y < h/4 {
+				c = color.NRGBA{255, 0, 0, 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// withOrientation inserts an EXIF segment with the given orientation after
+// the start of the JPEG.
+func withOrientation(t *testing.T, jpg []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	require.Equal(t, []byte{0xFF, 0xD8}, jpg[:2])
+	return append(append([]byte{0xFF, 0xD8}, segment...), jpg[2:]...)
+}
+
+func TestProcess_RotatesByOrientationAndStripsExif(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(400, 240), &jpeg.Options{Quality: 95}))
+	data := withOrientation(t, buf.Bytes(), 6)
+
+	processed, err := media.Process(data)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", processed.ContentType)
+	assert.Equal(t, 240, processed.Original.Width)
+	assert.Equal(t, 400, processed.Original.Height)
+	assert.NotContains(t, string(processed.Original.Data), "Exif")
+
+	// Rotated a quarter turn clockwise, the red corner is now top right.
+	img, err := jpeg.Decode(bytes.NewReader(processed.Original.Data))
+	require.NoError(t, err)
+	r, g, _, _ := img.At(230, 10).RGBA()
+	assert.Greater(t, r>>8, uint32(200))
+	assert.Less(t, g>>8, uint32(60))
+	r, _, _, _ = img.At(10, 10).RGBA()
+	assert.Less(t, r>>8, uint32(160))
+}
+
+func TestProcess_ThumbnailsFitLongestEdge(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(2000, 1000)))
+
+	processed, err := media.Process(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", processed.ContentType)
+	assert.Equal(t, ".png", processed.Extension)
+
+	sizes := map[string][2]int{}
+	for _, thumb := range processed.Thumbnails {
+		sizes[thumb.Name] = [2]int{thumb.Width, thumb.Height}
+	}
+	assert.Equal(t, map[string][2]int{
+		"large":  {1600, 800},
+		"medium": {800, 400},
+		"small":  {320, 160},
+	}, sizes)
+
+	small, err := png.Decode(bytes.NewReader(processed.Thumbnails[2].Data))
+	require.NoError(t, err)
+	assert.Equal(t, color.NRGBA{255, 0, 0, 255}, color.NRGBAModel.Convert(small.At(10, 10)))
+	assert.Equal(t, color.NRGBA{128, 128, 128, 255}, color.NRGBAModel.Convert(small.At(300, 150)))
+}
+
+func TestProcess_DoesNotUpscale(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(300, 250)))
+
+	processed, err := media.Process(buf.Bytes())
+	require.NoError(t, err)
+	for _, thumb := range processed.Thumbnails {
+		assert.Equal(t, 300, thumb.Width)
+		assert.Equal(t, 250, thumb.Height)
+	}
+}
+
+func TestProcess_Rejects(t *testing.T) {
+	var small, animated bytes.Buffer
+	require.NoError(t, png.Encode(&small, testImage(150, 400)))
+	require.NoError(t, gif.Encode(&animated, testImage(400, 400), nil))
+
+	_, err := media.Process([]byte("not an image at all"))
+	assert.ErrorIs(t, err, media.ErrUnsupportedType)
+	_, err = media.Process(animated.Bytes())
+	assert.ErrorIs(t, err, media.ErrUnsupportedType)
+	_, err = media.Process(small.Bytes())
+	assert.ErrorIs(t, err, media.ErrDimensions)
+	_, err = media.Process(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 100)...))
+	assert.ErrorIs(t, err, media.ErrCorrupt)
+	_, err = media.Process(make([]byte, media.MaxUploadBytes+1))
+	assert.ErrorIs(t, err, media.ErrTooLarge)
+}
+
+// gradient is w×h with brightness rising left to right, mirrored if flip.
+func gradient(w, h int, flip bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			v := uint8(x * 255 / w)
+			if flip {
+				v = 255 - v
+			}
+			img.SetNRGBA(x, y, color.NRGBA{v, uint8(y * 255 / h), 64, 255})
+		}
+	}
+	return img
+}
+
+func TestHash_MatchesResizedCopies(t *testing.T) {
+	original := gradient(1200, 900, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, media.Fit(original, 400), &jpeg.Options{Quality: 60}))
+	processed, err := media.Process(buf.Bytes())
+	require.NoError(t, err)
+
+	distance, ok := media.HashDistance(media.FormatHash(media.Hash(original)), media.FormatHash(processed.Hash))
+	assert.True(t, ok)
+	assert.LessOrEqual(t, distance, 4)
+
+	distance, _ = media.HashDistance(media.FormatHash(media.Hash(original)), media.FormatHash(media.Hash(gradient(1200, 900, true))))
+	assert.Greater(t, distance, 16)
+
+	_, ok = media.HashDistance("", media.FormatHash(0))
+	assert.False(t, ok)
+	assert.Equal(t, "000000000000000f", media.FormatHash(15))
+}
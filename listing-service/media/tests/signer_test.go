@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"net/url"
+	"real-estate-system/listing-service/media"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner_LinksExpire(t *testing.T) {
+	signer := media.NewSigner([]byte("key"), "https://api.example.com/media/private/", 15*time.Minute)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	link, expires := signer.Sign(9, now)
+	assert.Equal(t, now.Add(15*time.Minute), expires.UTC())
+	require.True(t, strings.HasPrefix(link, "https://api.example.com/media/private/9?"))
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	query := u.Query()
+
+	assert.True(t, signer.Verify(9, query.Get("expires"), query.Get("signature"), now.Add(15*time.Minute)))
+	assert.False(t, signer.Verify(9, query.Get("expires"), query.Get("signature"), now.Add(16*time.Minute)))
+	assert.False(t, signer.Verify(10, query.Get("expires"), query.Get("signature"), now))
+	assert.False(t, signer.Verify(9, "9999999999", query.Get("signature"), now))
+	assert.False(t, media.NewSigner([]byte("other"), "", time.Minute).Verify(9, query.Get("expires"), query.Get("signature"), now))
+}
+
+func TestSigner_DerivedLinksDoNotCross(t *testing.T) {
+	signer := media.NewSigner([]byte("key"), "https://api.example.com/media/private", 15*time.Minute)
+	feeds := signer.Derive("calendar", "https://api.example.com/calendar/viewings", 24*time.Hour)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	link, expires := feeds.Sign(9, now)
+	assert.Equal(t, now.Add(24*time.Hour), expires.UTC())
+	require.True(t, strings.HasPrefix(link, "https://api.example.com/calendar/viewings/9?"))
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	query := u.Query()
+	assert.True(t, feeds.Verify(9, query.Get("expires"), query.Get("signature"), now))
+	assert.False(t, signer.Verify(9, query.Get("expires"), query.Get("signature"), now))
+}
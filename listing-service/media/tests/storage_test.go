@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"real-estate-system/listing-service/media"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	storage := media.NewLocalStorage(dir, "/media/")
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, "listings/7/abc/original.jpg", strings.NewReader("data"), 4, "image/jpeg"))
+	content, err := os.ReadFile(filepath.Join(dir, "listings", "7", "abc", "original.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+	assert.Equal(t, "/media/listings/7/abc/original.jpg", storage.URL("listings/7/abc/original.jpg"))
+
+	require.NoError(t, storage.Delete(ctx, "listings/7/abc/original.jpg"))
+	assert.NoFileExists(t, filepath.Join(dir, "listings", "7", "abc", "original.jpg"))
+	assert.NoError(t, storage.Delete(ctx, "listings/7/abc/original.jpg"))
+}
+
+func TestLocalStorage_StaysInsideDir(t *testing.T) {
+	dir := t.TempDir()
+	storage := media.NewLocalStorage(filepath.Join(dir, "media"), "/media")
+
+	require.NoError(t, storage.Put(context.Background(), "../escape.jpg", strings.NewReader("x"), 1, "image/jpeg"))
+	assert.NoFileExists(t, filepath.Join(dir, "escape.jpg"))
+	assert.FileExists(t, filepath.Join(dir, "media", "escape.jpg"))
+}
+
+func TestLocalStorage_KeepsPrivateFilesOutOfPublicDir(t *testing.T) {
+	dir := t.TempDir()
+	storage := media.NewLocalStorage(dir, "/media")
+	ctx := context.Background()
+
+	prefix, err := media.KeyPrefix(7, true)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(prefix, "private/listings/7/"))
+
+	require.NoError(t, storage.Put(ctx, prefix+"original.pdf", strings.NewReader("deed"), 4, "application/pdf"))
+	path := filepath.Join(dir, filepath.FromSlash(prefix), "original.pdf")
+	assert.FileExists(t, path)
+	assert.False(t, strings.HasPrefix(path, storage.PublicDir()+string(filepath.Separator)))
+
+	file, err := storage.Get(ctx, prefix+"original.pdf")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "deed", string(content))
+
+	_, err = storage.Get(ctx, prefix+"missing.pdf")
+	assert.ErrorIs(t, err, media.ErrNotFound)
+}
+
+func TestS3Storage_SignsPathStyleRequests(t *testing.T) {
+	var method, path, auth, hash, contentType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		auth = r.Header.Get("Authorization")
+		hash = r.Header.Get("X-Amz-Content-Sha256")
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	storage, err := media.NewS3Storage(media.S3Config{
+		Endpoint:  server.URL,
+		Region:    "us-east-1",
+		Bucket:    "photos",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+		PublicURL: "https://cdn.example.com/photos/",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, storage.Put(context.Background(), "listings/7/small.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"))
+	sum := sha256.Sum256([]byte("jpeg"))
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/photos/listings/7/small.jpg", path)
+	assert.Equal(t, "jpeg", string(body))
+	assert.Equal(t, "image/jpeg", contentType)
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+	assert.Regexp(t, `^AWS4-HMAC-SHA256 Credential=minio/\d{8}/us-east-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`, auth)
+	assert.Equal(t, "https://cdn.example.com/photos/listings/7/small.jpg", storage.URL("listings/7/small.jpg"))
+
+	require.NoError(t, storage.Delete(context.Background(), "listings/7/small.jpg"))
+	assert.Equal(t, http.MethodDelete, method)
+	assert.Contains(t, auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date,")
+}
+
+func TestS3Storage_ReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	storage, err := media.NewS3Storage(media.S3Config{Endpoint: server.URL, Region: "us-east-1", Bucket: "photos", AccessKey: "a", SecretKey: "b"})
+	require.NoError(t, err)
+
+	err = storage.Put(context.Background(), "x.jpg", strings.NewReader("x"), 1, "image/jpeg")
+	assert.ErrorContains(t, err, "AccessDenied")
+}
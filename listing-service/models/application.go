@@ -0,0 +1,125 @@
+package models
+
+const (
+	ApplicationStatusSubmitted = "submitted"
+	ApplicationStatusReviewing = "reviewing"
+	ApplicationStatusApproved  = "approved"
+	ApplicationStatusRejected  = "rejected"
+
+	EmploymentEmployed     = "employed"
+	EmploymentSelfEmployed = "self_employed"
+	EmploymentStudent      = "student"
+	EmploymentRetired      = "retired"
+	EmploymentUnemployed   = "unemployed"
+
+	DocumentIdentity         = "identity"
+	DocumentPayslip          = "payslip"
+	DocumentBankStatement    = "bank_statement"
+	DocumentEmploymentLetter = "employment_letter"
+	DocumentOther            = "other"
+)
+
+// ApplicationReference is someone the landlord may contact about the
+// applicant, such as a previous landlord or employer.
+type ApplicationReference struct {
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+}
+
+// ApplicationDocument is metadata of a supporting document stored elsewhere.
+type ApplicationDocument struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Application is a request to rent a listing. MonthlyIncome is in the
+// listing's currency.
+type Application struct {
+	ID               int64                  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID        int                    `gorm:"index" json:"listing_id"`
+	ApplicantID      int                    `gorm:"index" json:"applicant_id"`
+	LandlordID       int                    `gorm:"index:idx_application_landlord_status" json:"landlord_id"`
+	Status           string                 `gorm:"index:idx_application_landlord_status" json:"status"`
+	MonthlyIncome    int                    `json:"monthly_income"`
+	EmploymentStatus string                 `json:"employment_status"`
+	Employer         string                 `json:"employer"`
+	EmploymentMonths int                    `json:"employment_months"`
+	References       []ApplicationReference `gorm:"type:jsonb;serializer:json" json:"references"`
+	Documents        []ApplicationDocument  `gorm:"type:jsonb;serializer:json" json:"documents"`
+	Message          string                 `gorm:"type:text" json:"message"`
+	DecisionNote     string                 `json:"decision_note"`
+	CreatedAt        int64                  `json:"created_at"`
+	UpdatedAt        int64                  `json:"updated_at"`
+}
+
+// Open reports whether the landlord has yet to decide on the application.
+func (a *Application) Open() bool {
+	return a.Status == ApplicationStatusSubmitted || a.Status == ApplicationStatusReviewing
+}
+
+type ApplicationFilter struct {
+	ApplicantID int
+	LandlordID  int
+	ListingID   int
+	Status      string
+}
+
+func ValidApplicationStatus(status string) bool {
+	switch status {
+	case ApplicationStatusSubmitted, ApplicationStatusReviewing, ApplicationStatusApproved, ApplicationStatusRejected:
+		return true
+	}
+	return false
+}
+
+func ValidEmploymentStatus(status string) bool {
+	switch status {
+	case EmploymentEmployed, EmploymentSelfEmployed, EmploymentStudent, EmploymentRetired, EmploymentUnemployed:
+		return true
+	}
+	return false
+}
+
+func ValidDocumentKind(kind string) bool {
+	switch kind {
+	case DocumentIdentity, DocumentPayslip, DocumentBankStatement, DocumentEmploymentLetter, DocumentOther:
+		return true
+	}
+	return false
+}
+
+// ScreeningRules configure how a landlord's applications are scored. Each
+// weight is the share of the score its rule contributes; a rule with weight
+// 0 is ignored.
+type ScreeningRules struct {
+	LandlordID          int      `gorm:"primaryKey;autoIncrement:false" json:"landlord_id"`
+	MinIncomeRatio      float64  `json:"min_income_ratio"` // monthly income as a multiple of rent
+	IncomeWeight        int      `json:"income_weight"`
+	MinEmploymentMonths int      `json:"min_employment_months"`
+	EmploymentWeight    int      `json:"employment_weight"`
+	MinReferences       int      `json:"min_references"`
+	ReferencesWeight    int      `json:"references_weight"`
+	RequiredDocuments   []string `gorm:"type:jsonb;serializer:json" json:"required_documents"`
+	DocumentsWeight     int      `json:"documents_weight"`
+	UpdatedAt           int64    `json:"updated_at"`
+}
+
+// DefaultScreeningRules apply to landlords who have not configured their own.
+func DefaultScreeningRules(landlordID int) ScreeningRules {
+	return ScreeningRules{
+		LandlordID:          landlordID,
+		MinIncomeRatio:      3,
+		IncomeWeight:        50,
+		MinEmploymentMonths: 6,
+		EmploymentWeight:    20,
+		MinReferences:       2,
+		ReferencesWeight:    15,
+		RequiredDocuments:   []string{DocumentIdentity, DocumentPayslip},
+		DocumentsWeight:     15,
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+// StatsDimensions are what area statistics can be grouped by. Prices only
+// compare within a listing type and currency, so statistics are always
+// split by both.
+var StatsDimensions = []string{"city", "district", "property_type", "listing_type"}
+
+// AreaStatsQuery selects area statistics of a tenant. Filters are ignored
+// when empty, and filtered dimensions are grouped by too.
+type AreaStatsQuery struct {
+	TenantID     string
+	GroupBy      []string
+	City         string
+	District     string
+	PropertyType string
+	ListingType  string
+	Currency     string
+	SeriesSince  int64 // first week of Series
+	Weeks        int
+}
+
+// AreaStats sums up the public listings of one group. Prices are in whole
+// units of Currency, per month for rents, and only cover active listings;
+// they are null when there are none. Dimensions the group is not split by
+// are left out.
+type AreaStats struct {
+	City               *string         `json:"city,omitempty"`
+	District           *string         `json:"district,omitempty"`
+	PropertyType       *string         `json:"property_type,omitempty"`
+	ListingType        string          `json:"listing_type"`
+	Currency           string          `json:"currency"`
+	ActiveListings     int             `json:"active_listings"`
+	PriceP25           *float64        `json:"price_p25"`
+	PriceMedian        *float64        `json:"price_median"`
+	PriceP75           *float64        `json:"price_p75"`
+	PricePerM2Median   *float64        `json:"price_per_m2_median"`
+	AvgDaysOnMarket    *float64        `json:"avg_days_on_market"`
+	NewListingsPerWeek float64         `json:"new_listings_per_week"`
+	Series             []AreaStatsWeek `json:"series"`
+	RefreshedAt        int64           `json:"refreshed_at"`
+}
+
+// AreaStatsWeek holds the listings that came on the market in the week
+// starting WeekStart (Monday, UTC).
+type AreaStatsWeek struct {
+	WeekStart   int64    `json:"week_start"`
+	NewListings int      `json:"new_listings"`
+	MedianPrice *float64 `json:"median_price"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "errors"
+
+const (
+	DuplicatePending   = "pending"
+	DuplicateMerged    = "merged"
+	DuplicateDismissed = "dismissed"
+)
+
+var ErrDuplicateResolved = errors.New("duplicate pair was already merged or dismissed")
+
+// DuplicatePair is two listings that look like the same property, the lower
+// ID first. Score, between 0 and 1, is the weighted mean of the Signals
+// available for the pair. Merging keeps KeptListingID and archives the
+// other listing.
+type DuplicatePair struct {
+	ID            int64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID     int                `gorm:"uniqueIndex:idx_duplicate_pair" json:"listing_id"`
+	DuplicateID   int                `gorm:"uniqueIndex:idx_duplicate_pair;index" json:"duplicate_id"`
+	Score         float64            `json:"score"`
+	Signals       map[string]float64 `gorm:"type:jsonb;serializer:json" json:"signals"`
+	Status        string             `gorm:"default:pending;index" json:"status"`
+	KeptListingID int                `json:"kept_listing_id,omitempty"`
+	ResolvedBy    int                `json:"resolved_by,omitempty"`
+	ResolvedAt    int64              `json:"resolved_at,omitempty"`
+	CreatedAt     int64              `json:"created_at"`
+	UpdatedAt     int64              `json:"updated_at"`
+	Listing       *Listing           `gorm:"foreignKey:ListingID" json:"listing,omitempty"`
+	Duplicate     *Listing           `gorm:"foreignKey:DuplicateID" json:"duplicate,omitempty"`
+}
+
+// Other returns the ID of the pair's listing that is not id.
+func (p *DuplicatePair) Other(id int) int {
+	if p.ListingID == id {
+		return p.DuplicateID
+	}
+	return p.ListingID
+}
+
+// DuplicateFilter narrows GetDuplicatePairs. Zero values are ignored.
+type DuplicateFilter struct {
+	Status    string
+	ListingID int // either listing of the pair
+}
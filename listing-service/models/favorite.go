@@ -0,0 +1,23 @@
+package models
+
+// Favorite is a bookmarked listing. SavedPrice is the listing's price when
+// it was bookmarked.
+type Favorite struct {
+	ID         int64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     int   `gorm:"uniqueIndex:idx_favorite_user_listing" json:"user_id"`
+	ListingID  int   `gorm:"uniqueIndex:idx_favorite_user_listing;index" json:"listing_id"`
+	SavedPrice int   `json:"saved_price"`
+	CreatedAt  int64 `json:"created_at"`
+}
+
+// FavoriteListing is a favorite with its listing, which is nil once the
+// listing has been deleted or is no longer public. PriceDropped is set when
+// the listing is now cheaper than when it was bookmarked.
+type FavoriteListing struct {
+	ListingID         int      `json:"listing_id"`
+	FavoritedAt       int64    `json:"favorited_at"`
+	SavedPrice        int      `json:"saved_price"`
+	PriceDropped      bool     `json:"price_dropped"`
+	NoLongerAvailable bool     `json:"no_longer_available"`
+	Listing           *Listing `json:"listing"`
+}
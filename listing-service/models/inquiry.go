@@ -0,0 +1,49 @@
+package models
+
+const (
+	InquiryStatusNew       = "new"
+	InquiryStatusContacted = "contacted"
+	InquiryStatusQualified = "qualified"
+	InquiryStatusClosed    = "closed"
+
+	InquirySourceWeb     = "web"
+	InquirySourcePartner = "partner"
+
+	ContactEmail = "email"
+	ContactPhone = "phone"
+)
+
+// Inquiry is a lead sent by a buyer to the owner of a listing. BuyerID is
+// zero for partner leads from people without an account. SourceRef
+// identifies the partner without exposing its API key.
+type Inquiry struct {
+	ID                 int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID           string `gorm:"not null;default:default" json:"tenant_id"` // the listing's
+	ListingID          int    `gorm:"index" json:"listing_id"`
+	OwnerID            int    `gorm:"index:idx_inquiry_owner_status" json:"owner_id"`
+	Status             string `gorm:"index:idx_inquiry_owner_status" json:"status"`
+	BuyerID            int    `gorm:"index" json:"buyer_id"`
+	Name               string `json:"name"`
+	Email              string `json:"email"`
+	Phone              string `json:"phone"`
+	Message            string `gorm:"type:text" json:"message"`
+	PreferredContact   string `json:"preferred_contact"`
+	SchedulePreference string `json:"schedule_preference"`
+	Source             string `json:"source"`
+	SourceRef          string `json:"source_ref,omitempty"`
+	CreatedAt          int64  `json:"created_at"`
+	UpdatedAt          int64  `json:"updated_at"`
+}
+
+type InquiryFilter struct {
+	OwnerID int
+	Status  string
+}
+
+func ValidInquiryStatus(status string) bool {
+	switch status {
+	case InquiryStatusNew, InquiryStatusContacted, InquiryStatusQualified, InquiryStatusClosed:
+		return true
+	}
+	return false
+}
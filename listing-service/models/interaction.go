@@ -0,0 +1,17 @@
+package models
+
+const (
+	InteractionInquiry = "inquiry"
+	InteractionViewing = "viewing"
+	InteractionLease   = "lease"
+)
+
+// Interaction is a finished dealing of a buyer or tenant with the owner,
+// agent or landlord of a listing: a closed inquiry, a viewing that took
+// place, or a lease. It makes them eligible to review that user.
+type Interaction struct {
+	Type       string `json:"type"`
+	ID         int64  `json:"id"`
+	ListingID  int    `json:"listing_id"`
+	OccurredAt int64  `json:"occurred_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "errors"
+
+const (
+	LeaseStatusActive     = "active"
+	LeaseStatusEnded      = "ended"
+	LeaseStatusTerminated = "terminated"
+
+	BillingMonthly = "monthly"
+	BillingYearly  = "yearly"
+
+	RentChargeScheduled = "scheduled"
+	RentChargeCancelled = "cancelled"
+)
+
+// ErrLeaseRenewed is returned when renewing or terminating a lease that has
+// already been renewed.
+var ErrLeaseRenewed = errors.New("lease has already been renewed")
+
+// ErrLeaseOverlap is returned when a lease would overlap another active
+// lease of the same listing.
+var ErrLeaseOverlap = errors.New("the listing is already leased for part of this period")
+
+// Lease is a tenancy of a rent listing. StartsAt and EndsAt are midnight UTC
+// of the first day and of the day after the last day. A lease stays active
+// until EndsAt passes; TerminationReason is set when it was cut short.
+type Lease struct {
+	ID                int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID         int    `gorm:"index" json:"listing_id"`
+	LandlordID        int    `gorm:"index" json:"landlord_id"`
+	TenantID          int    `gorm:"index" json:"tenant_id"`
+	ApplicationID     int64  `json:"application_id,omitempty"`
+	Status            string `gorm:"index" json:"status"`
+	StartsAt          int64  `json:"starts_at"`
+	EndsAt            int64  `gorm:"index" json:"ends_at"`
+	TermMonths        int    `json:"term_months"`
+	MonthlyRent       int    `json:"monthly_rent"`
+	Currency          string `gorm:"size:3" json:"currency"`
+	Deposit           int    `json:"deposit"`
+	PaymentDueDay     int    `json:"payment_due_day"`
+	BillingPeriod     string `json:"billing_period"`
+	RenewedFromID     int64  `json:"renewed_from_id,omitempty"`
+	RenewedToID       int64  `json:"renewed_to_id,omitempty"`
+	ExpiryNotifiedAt  int64  `json:"expiry_notified_at,omitempty"`
+	TerminationReason string `json:"termination_reason,omitempty"`
+	CreatedAt         int64  `json:"created_at"`
+	UpdatedAt         int64  `json:"updated_at"`
+}
+
+func (l *Lease) HasParticipant(userID int) bool {
+	return userID == l.LandlordID || userID == l.TenantID
+}
+
+// RentCharge is one installment of a lease's rent schedule, covering
+// [PeriodStart, PeriodEnd). It is posted to the ledger when due; LateFeeAt
+// is set once the late fee rule has been applied to it.
+type RentCharge struct {
+	ID          int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	LeaseID     int64  `gorm:"index" json:"lease_id"`
+	PeriodStart int64  `json:"period_start"`
+	PeriodEnd   int64  `json:"period_end"`
+	DueAt       int64  `gorm:"index" json:"due_at"`
+	Amount      int    `json:"amount"`
+	Status      string `json:"status"`
+	PostedAt    int64  `json:"posted_at,omitempty"`
+	LateFeeAt   int64  `json:"late_fee_at,omitempty"`
+}
+
+type LeaseFilter struct {
+	UserID       int // landlord or tenant
+	ListingID    int
+	Status       string
+	EndingBefore int64
+}
@@ -0,0 +1,89 @@
+package models
+
+import "errors"
+
+// ErrUnbalanced is returned when posting a ledger transaction whose entries
+// do not sum to zero.
+var ErrUnbalanced = errors.New("ledger transaction is not balanced")
+
+const (
+	// Accounts of a lease's ledger. The tenant receivable is what the tenant
+	// owes; it is debited by rent and late fees and credited by payments.
+	AccountReceivable    = "tenant_receivable"
+	AccountRentIncome    = "rent_income"
+	AccountLateFeeIncome = "late_fee_income"
+	AccountCash          = "cash"
+
+	LedgerRentCharge = "rent_charge"
+	LedgerLateFee    = "late_fee"
+	LedgerPayment    = "payment"
+
+	PaymentStatusPending   = "pending"
+	PaymentStatusSucceeded = "succeeded"
+	PaymentStatusFailed    = "failed"
+
+	PaymentProviderManual = "manual"
+)
+
+// LedgerTransaction is one balanced posting to a lease's ledger. Reference
+// names what was posted, e.g. "rent_charge:12", so posting twice is a no-op.
+// Amounts are in minor units of Currency.
+type LedgerTransaction struct {
+	ID          int64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	LeaseID     int64         `gorm:"index" json:"lease_id"`
+	Kind        string        `json:"kind"`
+	Reference   string        `gorm:"uniqueIndex" json:"reference"`
+	Description string        `json:"description"`
+	Amount      int64         `json:"amount"`
+	Currency    string        `gorm:"size:3" json:"currency"`
+	EffectiveAt int64         `json:"effective_at"`
+	CreatedAt   int64         `json:"created_at"`
+	Entries     []LedgerEntry `gorm:"foreignKey:TransactionID" json:"entries,omitempty"`
+}
+
+// LedgerEntry is one side of a transaction: positive amounts are debits,
+// negative ones credits. The entries of a transaction sum to zero.
+type LedgerEntry struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TransactionID int64  `gorm:"index" json:"transaction_id"`
+	LeaseID       int64  `gorm:"index:idx_ledger_entry_account" json:"lease_id"`
+	Account       string `gorm:"index:idx_ledger_entry_account" json:"account"`
+	Amount        int64  `json:"amount"`
+	Currency      string `gorm:"size:3" json:"currency"`
+}
+
+// Payment is money received from a tenant, through a payment provider or
+// recorded by the landlord. IdempotencyKey makes retried requests safe.
+type Payment struct {
+	ID             int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	LeaseID        int64  `gorm:"index" json:"lease_id"`
+	TenantID       int    `gorm:"index" json:"tenant_id"`
+	LandlordID     int    `json:"landlord_id"`
+	RecordedBy     int    `json:"recorded_by"`
+	Amount         int64  `json:"amount"`
+	Currency       string `gorm:"size:3" json:"currency"`
+	Status         string `json:"status"`
+	Provider       string `json:"provider"`
+	ProviderRef    string `json:"provider_ref,omitempty"`
+	FailureReason  string `json:"failure_reason,omitempty"`
+	IdempotencyKey string `gorm:"uniqueIndex" json:"-"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
+
+// LateFeeRule is a landlord's late fee: after GraceDays past the due date,
+// an unpaid charge gets FlatFee plus PercentBps basis points of what is
+// still owed. FlatFee is in minor units of Currency and only applies to
+// leases in that currency.
+type LateFeeRule struct {
+	LandlordID int    `gorm:"primaryKey;autoIncrement:false" json:"landlord_id"`
+	GraceDays  int    `json:"grace_days"`
+	FlatFee    int64  `json:"flat_fee"`
+	Currency   string `gorm:"size:3" json:"currency"`
+	PercentBps int    `json:"percent_bps"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+func DefaultLateFeeRule(landlordID int) LateFeeRule {
+	return LateFeeRule{LandlordID: landlordID, GraceDays: 5, PercentBps: 500}
+}
@@ -1,10 +1,203 @@
 package models
 
+import (
+	"math"
+	"real-estate-system/listing-service/money"
+)
+
+const (
+	ListingStatusActive     = "active"
+	ListingStatusUnderOffer = "under_offer"
+	ListingStatusRented     = "rented"
+	ListingStatusArchived   = "archived"
+
+	// Listings that trip a moderation rule wait in pending_review until an
+	// administrator approves or rejects them. Neither is shown publicly.
+	ListingStatusPendingReview = "pending_review"
+	ListingStatusRejected      = "rejected"
+
+	// Active listings reported by enough users are hidden until an
+	// administrator triages the reports.
+	ListingStatusHidden = "hidden"
+
+	RentPerMonth = "month"
+	RentPerYear  = "year"
+
+	PropertyHouse      = "house"
+	PropertyApartment  = "apartment"
+	PropertyTownhouse  = "townhouse"
+	PropertyLand       = "land"
+	PropertyCommercial = "commercial"
+)
+
+// Listing is a property for rent or sale. Price is in whole units of
+// Currency; rents are per RentPeriod.
 type Listing struct {
-	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID      int    `json:"user_id"`
-	Price       int    `json:"price"`
-	ListingType string `json:"listing_type"` // rent or sale
-	CreatedAt   int64  `json:"created_at"`
-	UpdatedAt   int64  `json:"updated_at"`
+	ID int `gorm:"primaryKey;autoIncrement" json:"id"`
+
+	// TenantID is the tenant the listing was created for, the only one it
+	// is visible to.
+	TenantID string `gorm:"index;not null;default:default" json:"tenant_id"`
+
+	UserID       int    `json:"user_id"`
+	Price        int    `json:"price"`
+	Currency     string `gorm:"size:3;default:IDR" json:"currency"`
+	RentPeriod   string `json:"rent_period,omitempty"`
+	ListingType  string `json:"listing_type"` // rent or sale
+	PropertyType string `json:"property_type,omitempty"`
+	City         string `gorm:"index" json:"city"`
+	District     string `gorm:"index" json:"district"`
+	Description  string `gorm:"type:text" json:"description,omitempty"`
+
+	// The address, coordinates, room counts and FloorArea (in m²) are
+	// optional and zero when unknown.
+	Address   string  `json:"address,omitempty"`
+	Latitude  float64 `gorm:"index:idx_listing_location" json:"latitude,omitempty"`
+	Longitude float64 `gorm:"index:idx_listing_location" json:"longitude,omitempty"`
+	Bedrooms  int     `json:"bedrooms,omitempty"`
+	Bathrooms int     `json:"bathrooms,omitempty"`
+	FloorArea int     `json:"floor_area,omitempty"`
+
+	Status string `gorm:"default:active;index" json:"status"`
+
+	// ListedAt is when the listing last came on the market and OffMarketAt
+	// when it last left it, under offer, rented or archived.
+	ListedAt    int64 `json:"listed_at,omitempty"`
+	OffMarketAt int64 `json:"off_market_at,omitempty"`
+
+	// PreviousPrice, PriceChangedAt and PriceChangePct describe the last
+	// price change.
+	PreviousPrice  int     `json:"previous_price,omitempty"`
+	PriceChangedAt int64   `json:"price_changed_at,omitempty"`
+	PriceChangePct float64 `json:"price_change_pct,omitempty"`
+
+	// EstimatedValue, in the listing's currency and rent period, is the
+	// last valuation from comparable listings, if there were enough of
+	// them, and Confidence its confidence.
+	EstimatedValue int   `json:"estimated_value,omitempty"`
+	Confidence     int   `gorm:"column:estimate_confidence" json:"estimate_confidence,omitempty"`
+	EstimatedAt    int64 `json:"estimated_at,omitempty"`
+
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+
+	// DisplayPrice is Price converted to the currency a client asked for
+	// and is not stored.
+	DisplayPrice *money.Amount `gorm:"-" json:"display_price,omitempty"`
+
+	// Photos and the public attachments of other types are loaded in
+	// display order.
+	Photos      []ListingMedia `gorm:"foreignKey:ListingID" json:"photos,omitempty"`
+	Attachments []ListingMedia `gorm:"foreignKey:ListingID" json:"attachments,omitempty"`
+}
+
+// ListingPriceChange records one change of a listing's asking price.
+type ListingPriceChange struct {
+	ID        int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID int    `gorm:"index:idx_price_history_listing" json:"listing_id"`
+	OldPrice  int    `json:"old_price"`
+	NewPrice  int    `json:"new_price"`
+	Currency  string `gorm:"size:3" json:"currency"`
+	ChangedAt int64  `gorm:"index:idx_price_history_listing" json:"changed_at"`
+}
+
+func (ListingPriceChange) TableName() string {
+	return "listing_price_history"
+}
+
+// PriceChangePct is the change from old to new in percent, rounded to two
+// decimals.
+func PriceChangePct(old, new int) float64 {
+	if old == 0 {
+		return 0
+	}
+	return math.Round(float64(new-old)/float64(old)*10000) / 100
+}
+
+// NonPublicStatuses are the statuses of listings only their owner and
+// administrators may see.
+var NonPublicStatuses = []string{ListingStatusPendingReview, ListingStatusRejected, ListingStatusHidden}
+
+// IsPublic reports whether anyone may see the listing, that is whether it is
+// not pending review, rejected or hidden.
+func (l *Listing) IsPublic() bool {
+	for _, status := range NonPublicStatuses {
+		if l.Status == status {
+			return false
+		}
+	}
+	return true
+}
+
+// OffMarketStatuses are the statuses of listings no longer on the market.
+var OffMarketStatuses = []string{ListingStatusUnderOffer, ListingStatusRented, ListingStatusArchived}
+
+func IsOffMarket(status string) bool {
+	for _, s := range OffMarketStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func ValidPropertyType(propertyType string) bool {
+	switch propertyType {
+	case PropertyHouse, PropertyApartment, PropertyTownhouse, PropertyLand, PropertyCommercial:
+		return true
+	}
+	return false
+}
+
+// HasLocation reports whether the listing's coordinates are known.
+func (l *Listing) HasLocation() bool {
+	return l.Latitude != 0 || l.Longitude != 0
+}
+
+// PriceAmount returns the price as money. Listings created before
+// currencies were recorded are in the default currency.
+func (l *Listing) PriceAmount() money.Amount {
+	currency := l.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+	return money.FromMajor(int64(l.Price), currency)
+}
+
+// MonthlyRent returns the rent per month in whole units of the listing's
+// currency, rounded for yearly rents.
+func (l *Listing) MonthlyRent() int {
+	if l.RentPeriod == RentPerYear {
+		return (l.Price + 6) / 12
+	}
+	return l.Price
+}
+
+// PriceRange bounds listing prices in whole units of Currency, per month
+// for rents. A zero bound is ignored.
+type PriceRange struct {
+	Currency string
+	Min      int
+	Max      int
+}
+
+// ListingFilter narrows GetListings. Zero values are ignored.
+type ListingFilter struct {
+	UserID      int
+	ListingType string
+	MinPrice    int
+	MaxPrice    int
+	Area        string // matches city or district, case-insensitive
+
+	// PriceDroppedSince keeps listings whose price is lower now than it was
+	// at this time.
+	PriceDroppedSince int64
+
+	// PriceRanges replaces MinPrice and MaxPrice with the same range in
+	// each listing currency, so prices in different currencies compare.
+	PriceRanges []PriceRange
+
+	// IncludeUnreviewed keeps listings pending review, rejected or hidden,
+	// which only their owner may see.
+	IncludeUnreviewed bool
 }
@@ -0,0 +1,82 @@
+package models
+
+import "errors"
+
+const (
+	MediaPhoto          = "photo"
+	MediaFloorPlan      = "floor_plan"
+	MediaDocument       = "document"
+	MediaVirtualTourURL = "virtual_tour_url"
+
+	MaxPhotosPerListing = 30
+)
+
+var (
+	ErrTooManyMedia = errors.New("listing already has the maximum number of attachments of this type")
+	ErrPhotoOrder   = errors.New("photo_ids must list every photo of the listing exactly once")
+)
+
+// MediaRule is what an attachment of one type must satisfy. Attachments
+// without ContentTypes are links rather than uploaded files. Private
+// attachments are only shown to the listing owner and admins.
+type MediaRule struct {
+	ContentTypes  []string
+	MaxPerListing int
+	Private       bool
+}
+
+var MediaRules = map[string]MediaRule{
+	MediaPhoto:          {ContentTypes: []string{"image/jpeg", "image/png"}, MaxPerListing: MaxPhotosPerListing},
+	MediaFloorPlan:      {ContentTypes: []string{"application/pdf", "image/png"}, MaxPerListing: 10},
+	MediaDocument:       {ContentTypes: []string{"application/pdf", "image/jpeg", "image/png"}, MaxPerListing: 20, Private: true},
+	MediaVirtualTourURL: {MaxPerListing: 5},
+}
+
+// Allows reports whether the rule accepts files of contentType.
+func (r MediaRule) Allows(contentType string) bool {
+	for _, allowed := range r.ContentTypes {
+		if allowed == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// ListingMedia is a photo, floor plan, document or virtual tour link of a
+// listing, shown in Position order within its type. The cover photo
+// represents the listing in search results. Keys are the storage keys of
+// the file and its thumbnails. PerceptualHash of a photo finds copies of it
+// on other listings. The URL of a private attachment is signed and expires
+// at URLExpiresAt; it is not stored.
+type ListingMedia struct {
+	ID             int64             `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID      int               `gorm:"index" json:"listing_id"`
+	UploadedBy     int               `json:"uploaded_by"`
+	Type           string            `gorm:"default:photo;index" json:"type"`
+	Title          string            `json:"title,omitempty"`
+	ContentType    string            `json:"content_type,omitempty"`
+	Width          int               `json:"width,omitempty"`
+	Height         int               `json:"height,omitempty"`
+	Size           int               `json:"size,omitempty"`
+	Position       int               `json:"position"`
+	IsCover        bool              `json:"is_cover"`
+	Private        bool              `json:"private"`
+	URL            string            `json:"url"`
+	Thumbnails     map[string]string `gorm:"type:jsonb;serializer:json" json:"thumbnails,omitempty"`
+	Keys           []string          `gorm:"type:jsonb;serializer:json" json:"-"`
+	PerceptualHash string            `gorm:"size:16" json:"-"`
+	CreatedAt      int64             `json:"created_at"`
+	URLExpiresAt   int64             `gorm:"-" json:"url_expires_at,omitempty"`
+}
+
+func (ListingMedia) TableName() string {
+	return "listing_media"
+}
+
+// MediaFilter selects a listing's attachments. An empty Type matches every
+// type.
+type MediaFilter struct {
+	ListingID      int
+	Type           string
+	IncludePrivate bool
+}
@@ -0,0 +1,54 @@
+package models
+
+import "errors"
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+
+	RulePriceOutlier = "price_outlier"
+	RuleBannedWords  = "banned_words"
+	RulePhoneNumber  = "phone_number"
+	RuleListingRate  = "listing_rate"
+)
+
+var ErrReviewClosed = errors.New("listing was already reviewed")
+
+// ModerationReason is a moderation rule a listing tripped, with what
+// tripped it.
+type ModerationReason struct {
+	Rule   string `json:"rule"`
+	Detail string `json:"detail"`
+}
+
+// ListingReview holds a listing back from publication until an
+// administrator approves or rejects it. Note is the administrator's
+// explanation to the lister.
+type ListingReview struct {
+	ID         int64              `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID  int                `gorm:"index" json:"listing_id"`
+	OwnerID    int                `json:"owner_id"`
+	Reasons    []ModerationReason `gorm:"type:jsonb;serializer:json" json:"reasons"`
+	Status     string             `gorm:"default:pending;index" json:"status"`
+	Note       string             `json:"note,omitempty"`
+	ReviewedBy int                `json:"reviewed_by,omitempty"`
+	ReviewedAt int64              `json:"reviewed_at,omitempty"`
+	CreatedAt  int64              `json:"created_at"`
+	Listing    *Listing           `gorm:"foreignKey:ListingID" json:"listing,omitempty"`
+}
+
+// ModerationStats is what the rules compare a new listing with. AreaMedian
+// is the median monthly price of comparable listings in the same city,
+// from AreaSamples listings. RecentListings counts the lister's listings of
+// the last hour.
+type ModerationStats struct {
+	AreaMedian     float64
+	AreaSamples    int
+	RecentListings int
+}
+
+// ReviewFilter narrows GetReviews. Zero values are ignored.
+type ReviewFilter struct {
+	Status string
+}
@@ -0,0 +1,78 @@
+package models
+
+import "errors"
+
+const (
+	OfferStatusPending   = "pending"
+	OfferStatusAccepted  = "accepted"
+	OfferStatusRejected  = "rejected"
+	OfferStatusDeclined  = "declined" // another offer was accepted or the listing was archived
+	OfferStatusWithdrawn = "withdrawn"
+	OfferStatusExpired   = "expired"
+
+	OfferActionSubmit   = "submitted"
+	OfferActionCounter  = "countered"
+	OfferActionAccept   = "accepted"
+	OfferActionReject   = "rejected"
+	OfferActionWithdraw = "withdrawn"
+	OfferActionDecline  = "declined"
+	OfferActionExpire   = "expired"
+)
+
+// ErrOfferChanged is returned when an offer was modified between loading
+// and updating it, e.g. countered and accepted at the same time.
+var ErrOfferChanged = errors.New("the offer has changed, reload and try again")
+
+// ErrListingUnavailable is returned when accepting an offer on a listing
+// that is no longer active.
+var ErrListingUnavailable = errors.New("listing is no longer available")
+
+// Offer is the current state of a negotiation on a sale listing. Amount,
+// Conditions and ExpiresAt hold the latest terms; AwaitingID is the party
+// expected to respond to them. Every change is kept in OfferEvent.
+type Offer struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID  int    `gorm:"index" json:"listing_id"`
+	BuyerID    int    `gorm:"index" json:"buyer_id"`
+	SellerID   int    `gorm:"index" json:"seller_id"`
+	Amount     int    `json:"amount"`
+	Conditions string `gorm:"type:text" json:"conditions"`
+	Status     string `gorm:"index" json:"status"`
+	AwaitingID int    `json:"awaiting_id"`
+	ExpiresAt  int64  `gorm:"index" json:"expires_at"`
+	Version    int    `json:"version"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+func (o *Offer) HasParticipant(userID int) bool {
+	return userID == o.BuyerID || userID == o.SellerID
+}
+
+// Counterparty returns the other side of the negotiation.
+func (o *Offer) Counterparty(userID int) int {
+	if userID == o.BuyerID {
+		return o.SellerID
+	}
+	return o.BuyerID
+}
+
+// OfferEvent is an append-only record of one step in a negotiation. ActorID
+// is 0 for steps taken by the system, such as expiry.
+type OfferEvent struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	OfferID    int64  `gorm:"index" json:"offer_id"`
+	ActorID    int    `json:"actor_id"`
+	Action     string `json:"action"`
+	Amount     int    `json:"amount"`
+	Conditions string `gorm:"type:text" json:"conditions"`
+	ExpiresAt  int64  `json:"expires_at"`
+	Note       string `json:"note"`
+	CreatedAt  int64  `json:"created_at"`
+}
+
+type OfferFilter struct {
+	UserID    int // buyer or seller
+	ListingID int
+	Status    string
+}
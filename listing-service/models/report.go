@@ -0,0 +1,69 @@
+package models
+
+import "errors"
+
+const (
+	ReportTargetListing = "listing"
+	ReportTargetUser    = "user"
+
+	ReportOpen      = "open"
+	ReportUpheld    = "upheld"
+	ReportDismissed = "dismissed"
+)
+
+// ReportReasons are the reason codes a report can give. ReportReasonOther
+// needs details.
+var ReportReasons = map[string]bool{
+	"scam":            true,
+	"deposit_first":   true,
+	"fake_price":      true,
+	"misleading":      true,
+	"not_available":   true,
+	"offensive":       true,
+	"harassment":      true,
+	"spam":            true,
+	ReportReasonOther: true,
+}
+
+const ReportReasonOther = "other"
+
+var ErrReportResolved = errors.New("report was already resolved")
+
+// Report is a user's complaint about a listing or another user. Each user
+// reports a target once. SubjectID is the user whose trust record the
+// outcome counts towards: the reported user or the listing's owner.
+type Report struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TargetType string `gorm:"size:20;uniqueIndex:idx_report_reporter" json:"target_type"`
+	TargetID   int    `gorm:"uniqueIndex:idx_report_reporter" json:"target_id"`
+	ReporterID int    `gorm:"uniqueIndex:idx_report_reporter" json:"reporter_id"`
+	SubjectID  int    `gorm:"index" json:"subject_id"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details,omitempty"`
+	Status     string `gorm:"default:open;index" json:"status"`
+	Note       string `json:"note,omitempty"`
+	ResolvedBy int    `json:"resolved_by,omitempty"`
+	ResolvedAt int64  `json:"resolved_at,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+// TrustRecord sums up the reports about a user and how they were resolved.
+// ListingsRemoved counts the user's listings archived over upheld reports.
+type TrustRecord struct {
+	UserID           int   `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	ReportsReceived  int   `json:"reports_received"`
+	ReportsUpheld    int   `json:"reports_upheld"`
+	ReportsDismissed int   `json:"reports_dismissed"`
+	ListingsRemoved  int   `json:"listings_removed"`
+	LastUpheldAt     int64 `json:"last_upheld_at,omitempty"`
+	UpdatedAt        int64 `json:"updated_at"`
+}
+
+// ReportFilter narrows GetReports. Zero values are ignored.
+type ReportFilter struct {
+	Status     string
+	TargetType string
+	TargetID   int
+	SubjectID  int
+}
@@ -0,0 +1,5 @@
+package models
+
+// DefaultTenant owns the data of the main portal and everything created
+// before tenants existed.
+const DefaultTenant = "default"
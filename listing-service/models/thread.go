@@ -0,0 +1,56 @@
+package models
+
+const (
+	ThreadStatusOpen   = "open"
+	ThreadStatusClosed = "closed"
+)
+
+// Thread is the conversation between a buyer and the owner about one
+// listing. Each side's read receipt is the ID of the last message it read.
+type Thread struct {
+	ID              int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	ListingID       int    `gorm:"uniqueIndex:idx_thread_participants" json:"listing_id"`
+	BuyerID         int    `gorm:"uniqueIndex:idx_thread_participants;index" json:"buyer_id"`
+	OwnerID         int    `gorm:"uniqueIndex:idx_thread_participants;index" json:"owner_id"`
+	Status          string `json:"status"`
+	BuyerLastReadID int64  `json:"buyer_last_read_id"`
+	OwnerLastReadID int64  `json:"owner_last_read_id"`
+	LastMessageAt   int64  `json:"last_message_at"`
+	CreatedAt       int64  `json:"created_at"`
+	UpdatedAt       int64  `json:"updated_at"`
+}
+
+func (t *Thread) HasParticipant(userID int) bool {
+	return userID == t.BuyerID || userID == t.OwnerID
+}
+
+// LastReadID returns the read receipt of the given participant.
+func (t *Thread) LastReadID(userID int) int64 {
+	if userID == t.OwnerID {
+		return t.OwnerLastReadID
+	}
+	return t.BuyerLastReadID
+}
+
+type ThreadSummary struct {
+	Thread
+	UnreadCount int64 `json:"unread_count"`
+}
+
+// MessageAttachment is metadata of a file stored elsewhere; the message
+// only references it.
+type MessageAttachment struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+type Message struct {
+	ID          int64               `gorm:"primaryKey;autoIncrement" json:"id"`
+	ThreadID    int64               `gorm:"index:idx_message_thread" json:"thread_id"`
+	SenderID    int                 `json:"sender_id"`
+	Body        string              `gorm:"type:text" json:"body"`
+	Attachments []MessageAttachment `gorm:"type:jsonb;serializer:json" json:"attachments"`
+	CreatedAt   int64               `json:"created_at"`
+}
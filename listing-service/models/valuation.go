@@ -0,0 +1,41 @@
+package models
+
+import "errors"
+
+var ErrNotEnoughComparables = errors.New("not enough comparable listings to estimate a price")
+
+// Valuation is an estimated price range for a property, in whole units of
+// Currency and, for rents, per RentPeriod. EstimatedValue is the weighted
+// median of the comparables' prices and Low and High their weighted
+// quartiles. Confidence goes from 0 to 100.
+type Valuation struct {
+	ListingType    string       `json:"listing_type"`
+	Currency       string       `json:"currency"`
+	RentPeriod     string       `json:"rent_period,omitempty"`
+	Low            int          `json:"low"`
+	EstimatedValue int          `json:"estimated_value"`
+	High           int          `json:"high"`
+	PricePerM2     int          `json:"price_per_m2,omitempty"`
+	Confidence     int          `json:"confidence"`
+	Comparables    []Comparable `json:"comparables"`
+}
+
+// Comparable is a listing a valuation is based on. AdjustedPrice is its
+// price in the valuation's currency and rent period, scaled to the floor
+// area being valued when both are known. PricedAt is when it was last
+// priced: when it left the market, or its last price change or listing
+// otherwise. Weight is its share of the estimate.
+type Comparable struct {
+	ListingID     int     `json:"listing_id"`
+	Status        string  `json:"status"`
+	Price         int     `json:"price"`
+	Currency      string  `json:"currency"`
+	RentPeriod    string  `json:"rent_period,omitempty"`
+	AdjustedPrice int     `json:"adjusted_price"`
+	FloorArea     int     `json:"floor_area,omitempty"`
+	Bedrooms      int     `json:"bedrooms,omitempty"`
+	District      string  `json:"district"`
+	DistanceM     *int    `json:"distance_m,omitempty"`
+	PricedAt      int64   `json:"priced_at"`
+	Weight        float64 `json:"weight"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "errors"
+
+const (
+	ViewingStatusBooked    = "booked"
+	ViewingStatusCancelled = "cancelled"
+)
+
+var ErrViewingConflict = errors.New("the requested time is no longer available")
+
+// ViewingSlot is a window in which an agent accepts viewings. A slot with
+// ListingID 0 applies to every listing of the agent. BufferMinutes is kept
+// free around each booking, e.g. for travel between properties.
+type ViewingSlot struct {
+	ID            int64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID       int   `gorm:"index:idx_slot_agent_start" json:"agent_id"`
+	ListingID     int   `gorm:"index" json:"listing_id"`
+	StartsAt      int64 `gorm:"index:idx_slot_agent_start" json:"starts_at"`
+	EndsAt        int64 `json:"ends_at"`
+	BufferMinutes int   `json:"buffer_minutes"`
+	CreatedAt     int64 `json:"created_at"`
+}
+
+func (s *ViewingSlot) Buffer() int64 {
+	return int64(s.BufferMinutes) * 60 * 1000000
+}
+
+// Viewing is a booked appointment. Sequence counts reschedules and
+// cancellations so calendar apps replace the earlier copy of the event.
+type Viewing struct {
+	ID           int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	SlotID       int64  `gorm:"index" json:"slot_id"`
+	ListingID    int    `gorm:"index" json:"listing_id"`
+	AgentID      int    `gorm:"index:idx_viewing_agent_start" json:"agent_id"`
+	BuyerID      int    `gorm:"index" json:"buyer_id"`
+	StartsAt     int64  `gorm:"index:idx_viewing_agent_start" json:"starts_at"`
+	EndsAt       int64  `json:"ends_at"`
+	Status       string `json:"status"`
+	Note         string `json:"note"`
+	CancelReason string `json:"cancel_reason,omitempty"`
+	Sequence     int    `json:"sequence"`
+	RemindAt     int64  `gorm:"index" json:"remind_at"` // 0 when no reminder is due
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+func (v *Viewing) HasParticipant(userID int) bool {
+	return userID == v.BuyerID || userID == v.AgentID
+}
@@ -0,0 +1,104 @@
+package moderation
+
+import (
+	"fmt"
+	"real-estate-system/listing-service/models"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// phonePattern finds runs of digits that start like a phone number, with
+// an optional country code and the usual separators. Prices, grouped with
+// dots or commas, do not match.
+var phonePattern = regexp.MustCompile(`(?:\+|\b0|\(0)[\d\s()-]{7,}\d`)
+
+// Check returns the rules the listing breaks, given stats about its area
+// and lister.
+func Check(rules *Rules, listing *models.Listing, stats models.ModerationStats) []models.ModerationReason {
+	var reasons []models.ModerationReason
+
+	if reason, ok := priceOutlier(rules, listing, stats); ok {
+		reasons = append(reasons, reason)
+	}
+
+	text := listing.Description + "\n" + listing.Address
+	if words := bannedWords(rules.BannedWords, text); len(words) > 0 {
+		reasons = append(reasons, models.ModerationReason{
+			Rule:   models.RuleBannedWords,
+			Detail: "Contains " + strings.Join(words, ", "),
+		})
+	}
+	if rules.BlockPhoneNumbers && hasPhoneNumber(listing.Description) {
+		reasons = append(reasons, models.ModerationReason{
+			Rule:   models.RulePhoneNumber,
+			Detail: "The description contains a phone number; buyers contact listers through inquiries",
+		})
+	}
+	if rules.MaxListingsPerHour > 0 && stats.RecentListings >= rules.MaxListingsPerHour {
+		reasons = append(reasons, models.ModerationReason{
+			Rule:   models.RuleListingRate,
+			Detail: fmt.Sprintf("More than %d listings in an hour", rules.MaxListingsPerHour),
+		})
+	}
+	return reasons
+}
+
+// MonthlyPrice is the listing's price per month for yearly rents and its
+// price otherwise, as the area median is computed.
+func MonthlyPrice(listing *models.Listing) float64 {
+	if listing.RentPeriod == models.RentPerYear {
+		return float64(listing.Price) / 12
+	}
+	return float64(listing.Price)
+}
+
+func priceOutlier(rules *Rules, listing *models.Listing, stats models.ModerationStats) (models.ModerationReason, bool) {
+	limits := rules.PriceOutlier
+	if stats.AreaMedian <= 0 || stats.AreaSamples < max(limits.MinSamples, 1) {
+		return models.ModerationReason{}, false
+	}
+	ratio := MonthlyPrice(listing) / stats.AreaMedian
+	if (limits.Below > 0 && ratio < limits.Below) || (limits.Above > 0 && ratio > limits.Above) {
+		return models.ModerationReason{
+			Rule:   models.RulePriceOutlier,
+			Detail: fmt.Sprintf("Price is %.0f%% of the median of %d comparable listings in %s", ratio*100, stats.AreaSamples, listing.City),
+		}, true
+	}
+	return models.ModerationReason{}, false
+}
+
+// bannedWords returns the banned words and phrases in text, matched whole
+// and ignoring case.
+func bannedWords(banned []string, text string) []string {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(text), isSeparator), " ") + " "
+	var found []string
+	for _, phrase := range banned {
+		normalized := strings.Join(strings.FieldsFunc(strings.ToLower(phrase), isSeparator), " ")
+		if normalized != "" && strings.Contains(words, " "+normalized+" ") {
+			found = append(found, phrase)
+		}
+	}
+	return found
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// hasPhoneNumber reports whether text holds 9 to 15 digits written like a
+// phone number.
+func hasPhoneNumber(text string) bool {
+	for _, match := range phonePattern.FindAllString(text, -1) {
+		digits := 0
+		for _, r := range match {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if digits >= 9 && digits <= 15 {
+			return true
+		}
+	}
+	return false
+}
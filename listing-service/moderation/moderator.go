@@ -0,0 +1,31 @@
+package moderation
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+// Moderator checks new listings against the current rules.
+type Moderator struct {
+	Repo  interfaces.ModerationRepository
+	Rules *Service
+}
+
+func NewModerator(repo interfaces.ModerationRepository, rules *Service) *Moderator {
+	return &Moderator{Repo: repo, Rules: rules}
+}
+
+// Check returns the rules the new listing breaks. A listing that breaks
+// none can go live straight away.
+func (m *Moderator) Check(listing *models.Listing) ([]models.ModerationReason, error) {
+	rules := m.Rules.Rules()
+	if !rules.UseStats() {
+		return Check(rules, listing, models.ModerationStats{}), nil
+	}
+	stats, err := m.Repo.GetModerationStats(listing, time.Now().Add(-time.Hour).UnixMicro())
+	if err != nil {
+		return nil, err
+	}
+	return Check(rules, listing, stats), nil
+}
@@ -0,0 +1,87 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync/atomic"
+)
+
+// Rules configures the checks run on new listings. Zero values turn a
+// check off.
+type Rules struct {
+	PriceOutlier struct {
+		// MinSamples is how many comparable listings the area needs before
+		// prices are compared with its median.
+		MinSamples int `json:"min_samples"`
+		// Below and Above are the shares of the median under and over which
+		// a price is an outlier, e.g. 0.3 and 4.
+		Below float64 `json:"below"`
+		Above float64 `json:"above"`
+	} `json:"price_outlier"`
+	BannedWords        []string `json:"banned_words"`
+	BlockPhoneNumbers  bool     `json:"block_phone_numbers"`
+	MaxListingsPerHour int      `json:"max_listings_per_hour"`
+}
+
+// UseStats reports whether any enabled rule compares the listing with
+// others.
+func (r *Rules) UseStats() bool {
+	return r.PriceOutlier.Below > 0 || r.PriceOutlier.Above > 0 || r.MaxListingsPerHour > 0
+}
+
+func Parse(data []byte) (*Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	limits := rules.PriceOutlier
+	if limits.Below < 0 || limits.Below >= 1 || (limits.Above != 0 && limits.Above <= 1) || limits.MinSamples < 0 {
+		return nil, errors.New("price_outlier needs below between 0 and 1 and above over 1")
+	}
+	if rules.MaxListingsPerHour < 0 {
+		return nil, errors.New("max_listings_per_hour must not be negative")
+	}
+	return &rules, nil
+}
+
+// Service holds the current rules and reloads them from their file, so
+// they can be changed without a redeploy.
+type Service struct {
+	path    string
+	current atomic.Pointer[Rules]
+}
+
+func NewService(path string) (*Service, error) {
+	s := &Service{path: path}
+	return s, s.Reload()
+}
+
+// NewStaticService serves fixed rules, e.g. in tests.
+func NewStaticService(rules *Rules) *Service {
+	s := &Service{}
+	s.current.Store(rules)
+	return s
+}
+
+// Reload replaces the rules with the file's contents. On error the
+// previous rules are kept.
+func (s *Service) Reload() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	rules, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	s.current.Store(rules)
+	return nil
+}
+
+func (s *Service) Rules() *Rules {
+	return s.current.Load()
+}
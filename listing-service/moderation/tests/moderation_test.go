@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/moderation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRules(t *testing.T) *moderation.Rules {
+	rules, err := moderation.Parse([]byte(`{
+		"price_outlier": {"min_samples": 5, "below": 0.3, "above": 4},
+		"banned_words": ["western union", "Bitcoin only"],
+		"block_phone_numbers": true,
+		"max_listings_per_hour": 3
+	}`))
+	require.NoError(t, err)
+	return rules
+}
+
+func rules(reasons []models.ModerationReason) []string {
+	names := []string{}
+	for _, reason := range reasons {
+		names = append(names, reason.Rule)
+	}
+	return names
+}
+
+func TestCheck_CleanListingPasses(t *testing.T) {
+	listing := &models.Listing{City: "Jakarta", Price: 5000000, Description: "Bright 2BR near the MRT, price 5.000.000 per month, 120 m2."}
+	stats := models.ModerationStats{AreaMedian: 6000000, AreaSamples: 20, RecentListings: 2}
+
+	assert.Empty(t, moderation.Check(testRules(t), listing, stats))
+}
+
+func TestCheck_PriceOutlier(t *testing.T) {
+	stats := models.ModerationStats{AreaMedian: 6000000, AreaSamples: 20}
+
+	low := moderation.Check(testRules(t), &models.Listing{City: "Jakarta", Price: 1000000}, stats)
+	assert.Equal(t, []string{models.RulePriceOutlier}, rules(low))
+	assert.Contains(t, low[0].Detail, "17% of the median of 20 comparable listings in Jakarta")
+
+	// Yearly rents compare per month.
+	yearly := &models.Listing{City: "Jakarta", Price: 72000000, RentPeriod: models.RentPerYear}
+	assert.Empty(t, moderation.Check(testRules(t), yearly, stats))
+
+	// Too few comparable listings to tell.
+	stats.AreaSamples = 4
+	assert.Empty(t, moderation.Check(testRules(t), &models.Listing{City: "Jakarta", Price: 1000000}, stats))
+}
+
+func TestCheck_BannedWordsMatchWholeWords(t *testing.T) {
+	listing := &models.Listing{Description: "Deposit by WESTERN-UNION; bitcoin only please"}
+	reasons := moderation.Check(testRules(t), listing, models.ModerationStats{})
+	assert.Equal(t, []string{models.RuleBannedWords}, rules(reasons))
+	assert.Equal(t, "Contains western union, Bitcoin only", reasons[0].Detail)
+
+	listing.Description = "Near the western unionville mall"
+	assert.Empty(t, moderation.Check(testRules(t), listing, models.ModerationStats{}))
+}
+
+func TestCheck_PhoneNumbers(t *testing.T) {
+	for _, text := range []string{"Call 0812-3456-7890", "WA +62 812 3456 7890", "ring (021) 555 1234"} {
+		reasons := moderation.Check(testRules(t), &models.Listing{Description: text}, models.ModerationStats{})
+		assert.Equal(t, []string{models.RulePhoneNumber}, rules(reasons), text)
+	}
+	for _, text := range []string{"Price 1.500.000.000 negotiable", "Built 2019, 3 floors", "Unit 0812"} {
+		assert.Empty(t, moderation.Check(testRules(t), &models.Listing{Description: text}, models.ModerationStats{}), text)
+	}
+}
+
+func TestCheck_ListingRate(t *testing.T) {
+	reasons := moderation.Check(testRules(t), &models.Listing{}, models.ModerationStats{RecentListings: 3})
+	assert.Equal(t, []string{models.RuleListingRate}, rules(reasons))
+}
+
+func TestCheck_ZeroRulesAreOff(t *testing.T) {
+	listing := &models.Listing{City: "Jakarta", Price: 1, Description: "Call 0812-3456-7890"}
+	stats := models.ModerationStats{AreaMedian: 6000000, AreaSamples: 20, RecentListings: 100}
+
+	assert.Empty(t, moderation.Check(&moderation.Rules{}, listing, stats))
+}
+
+func TestParse_RejectsInvalidLimits(t *testing.T) {
+	_, err := moderation.Parse([]byte(`{"price_outlier": {"below": 1.5}}`))
+	assert.Error(t, err)
+	_, err = moderation.Parse([]byte(`{"price_outlier": {"above": 0.5}}`))
+	assert.Error(t, err)
+	_, err = moderation.Parse([]byte(`{"max_listings_per_hour": -1}`))
+	assert.Error(t, err)
+}
+
+func TestService_ReloadKeepsRulesOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"max_listings_per_hour": 5}`), 0o644))
+
+	service, err := moderation.NewService(path)
+	require.NoError(t, err)
+	assert.Equal(t, 5, service.Rules().MaxListingsPerHour)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"max_listings_per_hour": 2, "banned_words": ["scam"]}`), 0o644))
+	assert.NoError(t, service.Reload())
+	assert.Equal(t, 2, service.Rules().MaxListingsPerHour)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o644))
+	assert.Error(t, service.Reload())
+	assert.Equal(t, []string{"scam"}, service.Rules().BannedWords)
+}
+
+func TestShippedRulesParse(t *testing.T) {
+	_, err := moderation.NewService("../rules.json")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,113 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used where no currency was given.
+const DefaultCurrency = "IDR"
+
+// exponents are the ISO 4217 minor unit digits of the supported currencies.
+var exponents = map[string]int{
+	"IDR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"SGD": 2,
+	"MYR": 2,
+	"AUD": 2,
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// Amount is an amount of money in minor units, e.g. cents.
+type Amount struct {
+	Minor    int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func Valid(currency string) bool {
+	_, ok := exponents[currency]
+	return ok
+}
+
+// Exponent returns the number of minor unit digits of currency.
+func Exponent(currency string) int {
+	return exponents[currency]
+}
+
+// FromMajor converts a whole amount, such as a lease's monthly rent, into
+// minor units.
+func FromMajor(major int64, currency string) Amount {
+	minor := major
+	for i := 0; i < Exponent(currency); i++ {
+		minor *= 10
+	}
+	return Amount{Minor: minor, Currency: currency}
+}
+
+// FitsMajor reports whether a whole amount can be held in minor units of
+// currency without overflowing.
+func FitsMajor(major int64, currency string) bool {
+	unit := FromMajor(1, currency).Minor
+	return major <= math.MaxInt64/unit && major >= math.MinInt64/unit
+}
+
+// Percent returns bps basis points of a, rounded half up. The product is
+// taken in a big.Int so large prices in minor units do not overflow.
+func (a Amount) Percent(bps int) Amount {
+	minor := new(big.Int).Mul(big.NewInt(a.Minor), big.NewInt(int64(bps)))
+	minor.Add(minor, big.NewInt(5000))
+	minor.Quo(minor, big.NewInt(10000))
+	return Amount{Minor: minor.Int64(), Currency: a.Currency}
+}
+
+// String formats a as e.g. "IDR 5000.00".
+func (a Amount) String() string {
+	return a.Currency + " " + a.Decimal()
+}
+
+// Decimal formats a in major units without the currency, e.g. "5000.00".
+func (a Amount) Decimal() string {
+	exp := Exponent(a.Currency)
+	if exp == 0 {
+		return strconv.FormatInt(a.Minor, 10)
+	}
+
+	sign, minor := "", a.Minor
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	unit := int64(1)
+	for i := 0; i < exp; i++ {
+		unit *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/unit, exp, minor%unit)
+}
+
+// Parse reads a decimal amount in major units, such as "1500000" or
+// "12.50", into minor units of currency. It takes no more decimals than the
+// currency has.
+func Parse(raw, currency string) (Amount, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(raw), ".")
+	exp := Exponent(currency)
+	if len(fraction) > exp || strings.ContainsAny(whole+fraction, "+-") || whole == "" && fraction == "" {
+		return Amount{}, fmt.Errorf("invalid %s amount %q", currency, raw)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", exp-len(fraction))
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid %s amount %q", currency, raw)
+	}
+	return Amount{Minor: minor, Currency: currency}, nil
+}
+
+// Normalize upper-cases a currency code from user input.
+func Normalize(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"math"
+	"real-estate-system/listing-service/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMajor(t *testing.T) {
+	assert.Equal(t, money.Amount{Minor: 500000, Currency: "IDR"}, money.FromMajor(5000, "IDR"))
+	assert.Equal(t, money.Amount{Minor: 5000, Currency: "JPY"}, money.FromMajor(5000, "JPY"))
+}
+
+func TestPercent_RoundsHalfUp(t *testing.T) {
+	assert.Equal(t, int64(25), money.Amount{Minor: 499, Currency: "USD"}.Percent(500).Minor)
+	assert.Equal(t, int64(24), money.Amount{Minor: 489, Currency: "USD"}.Percent(500).Minor)
+}
+
+func TestPercent_LargeAmountsDoNotOverflow(t *testing.T) {
+	price := money.Amount{Minor: math.MaxInt64 / 100, Currency: "IDR"}
+	assert.Equal(t, int64(18446744073709552), price.Percent(2000).Minor)
+}
+
+func TestFitsMajor(t *testing.T) {
+	assert.True(t, money.FitsMajor(math.MaxInt64/100, "IDR"))
+	assert.False(t, money.FitsMajor(math.MaxInt64/100+1, "IDR"))
+	assert.True(t, money.FitsMajor(math.MaxInt64, "JPY"))
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "USD 12.05", money.Amount{Minor: 1205, Currency: "USD"}.String())
+	assert.Equal(t, "USD -0.50", money.Amount{Minor: -50, Currency: "USD"}.String())
+	assert.Equal(t, "JPY 300", money.Amount{Minor: 300, Currency: "JPY"}.String())
+}
+
+func TestParse(t *testing.T) {
+	amount, err := money.Parse("1500000", "IDR")
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount{Minor: 150000000, Currency: "IDR"}, amount)
+
+	amount, err = money.Parse("12.5", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1250), amount.Minor)
+
+	for _, raw := range []string{"", ".", "-5", "+5", "1.005", "1e9", "NaN", "Inf", "1,000", "99999999999999999999"} {
+		_, err := money.Parse(raw, "USD")
+		assert.Error(t, err, raw)
+	}
+	_, err = money.Parse("300.5", "JPY")
+	assert.Error(t, err)
+}
@@ -0,0 +1,216 @@
+package mortgage
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"real-estate-system/listing-service/money"
+	"strconv"
+)
+
+// Terms are the conditions of a home loan (KPR). Rates are yearly
+// percentages: FixedRate for the first FixedMonths, FloatingRate for the
+// rest of the tenor.
+type Terms struct {
+	DownPaymentPct float64 `json:"down_payment_pct"`
+	TenorMonths    int     `json:"tenor_months"`
+	FixedRate      float64 `json:"fixed_rate"`
+	FixedMonths    int     `json:"fixed_months"`
+	FloatingRate   float64 `json:"floating_rate"`
+}
+
+// Defaults are typical terms of Indonesian banks: 20% down, 20 years, the
+// first 3 of them at a promotional fixed rate.
+var Defaults = Terms{DownPaymentPct: 20, TenorMonths: 240, FixedRate: 5, FixedMonths: 36, FloatingRate: 10.5}
+
+// DefaultDebtRatio is the share of income, in percent, banks let all
+// installments take.
+const DefaultDebtRatio = 30
+
+// Validate checks the terms are finite and within what banks offer.
+func (t Terms) Validate() error {
+	switch {
+	case !finite(t.DownPaymentPct, t.FixedRate, t.FloatingRate):
+		return errors.New("down payment and rates must be numbers")
+	case t.DownPaymentPct < 0 || t.DownPaymentPct >= 100:
+		return errors.New("down payment must be less than the price")
+	case t.TenorMonths < 12 || t.TenorMonths > 35*12:
+		return errors.New("tenor must be 1 to 35 years")
+	case t.FixedMonths < 0 || t.FixedMonths > t.TenorMonths:
+		return errors.New("the fixed rate period cannot be longer than the tenor")
+	case t.FixedRate < 0 || t.FixedRate > 100 || t.FloatingRate < 0 || t.FloatingRate > 100:
+		return errors.New("rates must be 0 to 100 percent")
+	}
+	return nil
+}
+
+// DownPayment is DownPaymentPct of price, rounded to the minor unit.
+func (t Terms) DownPayment(price money.Amount) money.Amount {
+	return price.Percent(int(math.Round(t.DownPaymentPct * 100)))
+}
+
+func (t Terms) rate(month int) float64 {
+	if month <= t.FixedMonths {
+		return t.FixedRate
+	}
+	return t.FloatingRate
+}
+
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// Payment is one monthly installment and the balance left after it.
+// Amounts are in minor units of the schedule's currency.
+type Payment struct {
+	Month       int     `json:"month"`
+	Rate        float64 `json:"rate"`
+	Installment int64   `json:"installment"`
+	Principal   int64   `json:"principal"`
+	Interest    int64   `json:"interest"`
+	Balance     int64   `json:"balance"`
+}
+
+// Schedule is the amortization of a loan of Price less DownPayment, in
+// minor units of Currency. FixedInstallment is paid during the fixed rate
+// period and FloatingInstallment after it, assuming the floating rate stays
+// as given.
+type Schedule struct {
+	Terms
+	Currency            string    `json:"currency"`
+	Price               int64     `json:"price"`
+	DownPayment         int64     `json:"down_payment"`
+	Loan                int64     `json:"loan"`
+	FixedInstallment    int64     `json:"fixed_installment,omitempty"`
+	FloatingInstallment int64     `json:"floating_installment,omitempty"`
+	TotalInterest       int64     `json:"total_interest"`
+	TotalPaid           int64     `json:"total_paid"`
+	Payments            []Payment `json:"payments,omitempty"`
+}
+
+// Amortize computes the monthly annuity schedule of buying at price with
+// downPayment, in the same currency. The installment is set when the loan
+// starts and again when the rate floats, to repay the balance over the
+// months left. Installments and interest are rounded to the minor unit, the
+// last installment making up the difference.
+func Amortize(price, downPayment money.Amount, terms Terms) Schedule {
+	s := amortize(price.Minor-downPayment.Minor, terms)
+	s.Currency, s.Price, s.DownPayment = price.Currency, price.Minor, downPayment.Minor
+	if price.Minor > 0 {
+		s.DownPaymentPct = math.Round(float64(downPayment.Minor)/float64(price.Minor)*10000) / 100
+	}
+	return s
+}
+
+func amortize(loan int64, terms Terms) Schedule {
+	s := Schedule{Terms: terms, Loan: loan}
+
+	balance := loan
+	var installment int64
+	for month := 1; month <= terms.TenorMonths; month++ {
+		rate := terms.rate(month)
+		if month == 1 || month == terms.FixedMonths+1 {
+			installment = int64(math.Round(annuity(float64(balance), rate, terms.TenorMonths-month+1)))
+			if month <= terms.FixedMonths {
+				s.FixedInstallment = installment
+			} else {
+				s.FloatingInstallment = installment
+			}
+		}
+
+		interest := int64(math.Round(float64(balance) * rate / 1200))
+		principal := installment - interest
+		if month == terms.TenorMonths || principal > balance {
+			principal = balance
+		}
+		balance -= principal
+
+		s.Payments = append(s.Payments, Payment{
+			Month:       month,
+			Rate:        rate,
+			Installment: principal + interest,
+			Principal:   principal,
+			Interest:    interest,
+			Balance:     balance,
+		})
+		s.TotalInterest += interest
+		s.TotalPaid += principal + interest
+	}
+	return s
+}
+
+// annuity is the monthly installment repaying loan over months at a yearly
+// rate in percent.
+func annuity(loan, rate float64, months int) float64 {
+	r := rate / 1200
+	if r == 0 {
+		return loan / float64(months)
+	}
+	return loan * r / (1 - math.Pow(1+r, -float64(months)))
+}
+
+// Affordability is the most a buyer can borrow and spend given their
+// income, other debts and the loan terms, in minor units of Currency.
+type Affordability struct {
+	Terms
+	Currency       string  `json:"currency"`
+	MonthlyIncome  int64   `json:"monthly_income"`
+	MonthlyDebts   int64   `json:"monthly_debts"`
+	DebtRatio      float64 `json:"debt_ratio"`
+	MaxInstallment int64   `json:"max_installment"`
+	MaxLoan        int64   `json:"max_loan"`
+	DownPayment    int64   `json:"down_payment"`
+	MaxPrice       int64   `json:"max_price"`
+}
+
+// Afford finds the highest price, in whole units, whose installments,
+// together with debts, take at most debtRatio percent of income, in the
+// fixed rate period and after it. Installments grow linearly with the loan,
+// so it is the installment left over divided by the highest installment of
+// a loan of 1.
+func Afford(income, debts money.Amount, debtRatio float64, terms Terms) Affordability {
+	a := Affordability{Terms: terms, Currency: income.Currency, MonthlyIncome: income.Minor, MonthlyDebts: debts.Minor, DebtRatio: debtRatio}
+	a.MaxInstallment = max(0, int64(math.Floor(float64(income.Minor)*debtRatio/100))-debts.Minor)
+
+	// Installments are rounded, so scale the unit loan up for precision.
+	const unit = 1e12
+	s := amortize(unit, terms)
+	highest := max(s.FixedInstallment, s.FloatingInstallment)
+	if highest <= 0 {
+		return a
+	}
+
+	whole := money.FromMajor(1, income.Currency).Minor
+	a.MaxLoan = int64(math.Floor(float64(a.MaxInstallment)/float64(highest)*unit)) / whole * whole
+	a.MaxPrice = int64(math.Floor(float64(a.MaxLoan)/(1-terms.DownPaymentPct/100))) / whole * whole
+	a.DownPayment = a.MaxPrice - a.MaxLoan
+	return a
+}
+
+// WriteCSV writes the schedule's payments as CSV, one row per month, with
+// amounts in major units.
+func WriteCSV(w io.Writer, s Schedule) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"month", "rate", "installment", "principal", "interest", "balance"})
+	amount := func(minor int64) string {
+		return money.Amount{Minor: minor, Currency: s.Currency}.Decimal()
+	}
+	for _, p := range s.Payments {
+		out.Write([]string{
+			strconv.Itoa(p.Month),
+			strconv.FormatFloat(p.Rate, 'f', -1, 64),
+			amount(p.Installment),
+			amount(p.Principal),
+			amount(p.Interest),
+			amount(p.Balance),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"bytes"
+	"math"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/mortgage"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func idr(major int64) money.Amount {
+	return money.FromMajor(major, "IDR")
+}
+
+func TestAmortize_FixedRateAnnuity(t *testing.T) {
+	terms := mortgage.Terms{TenorMonths: 12, FixedRate: 12, FixedMonths: 12}
+
+	s := mortgage.Amortize(idr(125000000), idr(25000000), terms)
+	assert.Equal(t, "IDR", s.Currency)
+	assert.Equal(t, int64(10000000000), s.Loan)
+	assert.Equal(t, 20.0, s.DownPaymentPct)
+	assert.Equal(t, int64(888487887), s.FixedInstallment)
+	assert.Zero(t, s.FloatingInstallment)
+	assert.Len(t, s.Payments, 12)
+	assert.Equal(t, int64(100000000), s.Payments[0].Interest)
+	assert.Equal(t, int64(0), s.Payments[11].Balance)
+	assert.InDelta(t, 888487887, s.Payments[11].Installment, 12)
+	assert.Equal(t, s.Loan+s.TotalInterest, s.TotalPaid)
+}
+
+func TestAmortize_FloatsAfterFixedPeriod(t *testing.T) {
+	terms := mortgage.Terms{TenorMonths: 24, FixedRate: 0, FixedMonths: 12, FloatingRate: 12}
+
+	s := mortgage.Amortize(money.FromMajor(2400, "USD"), money.Amount{Currency: "USD"}, terms)
+	assert.Equal(t, int64(10000), s.FixedInstallment)
+	assert.Equal(t, int64(120000), s.Payments[11].Balance)
+	// The balance left is repaid over the last 12 months at 12%.
+	assert.Equal(t, int64(10662), s.FloatingInstallment)
+	assert.Equal(t, 12.0, s.Payments[12].Rate)
+	assert.Equal(t, int64(0), s.Payments[23].Balance)
+}
+
+func TestAfford_HighestInstallmentFitsIncome(t *testing.T) {
+	terms := mortgage.Terms{DownPaymentPct: 20, TenorMonths: 120}
+
+	a := mortgage.Afford(idr(10000000), idr(0), 30, terms)
+	assert.Equal(t, int64(300000000), a.MaxInstallment)
+	assert.Equal(t, int64(36000000000), a.MaxLoan)
+	assert.Equal(t, int64(45000000000), a.MaxPrice)
+	assert.Equal(t, int64(9000000000), a.DownPayment)
+
+	// With a higher floating rate, the installment after the fixed period is
+	// what has to fit.
+	terms = mortgage.Terms{DownPaymentPct: 20, TenorMonths: 240, FixedRate: 5, FixedMonths: 36, FloatingRate: 10.5}
+	a = mortgage.Afford(idr(10000000), idr(1000000), 30, terms)
+	assert.Zero(t, a.MaxPrice%100, "whole rupiah")
+	s := mortgage.Amortize(money.Amount{Minor: a.MaxPrice, Currency: "IDR"}, money.Amount{Minor: a.DownPayment, Currency: "IDR"}, terms)
+	assert.LessOrEqual(t, s.FloatingInstallment, int64(200000000))
+	assert.Greater(t, s.FloatingInstallment, int64(199900000))
+}
+
+func TestTermsValidate(t *testing.T) {
+	assert.NoError(t, mortgage.Defaults.Validate())
+	assert.Error(t, mortgage.Terms{TenorMonths: 6}.Validate())
+	assert.Error(t, mortgage.Terms{TenorMonths: 120, FixedMonths: 240}.Validate())
+	assert.Error(t, mortgage.Terms{TenorMonths: 120, DownPaymentPct: 100}.Validate())
+	assert.Error(t, mortgage.Terms{TenorMonths: 120, DownPaymentPct: math.NaN()}.Validate())
+	assert.Error(t, mortgage.Terms{TenorMonths: 120, FixedRate: math.NaN()}.Validate())
+	assert.Error(t, mortgage.Terms{TenorMonths: 120, FloatingRate: math.Inf(1)}.Validate())
+}
+
+func TestTermsDownPayment(t *testing.T) {
+	terms := mortgage.Terms{DownPaymentPct: 12.5}
+	assert.Equal(t, money.Amount{Minor: 12500, Currency: "USD"}, terms.DownPayment(money.FromMajor(1000, "USD")))
+}
+
+func TestWriteCSV(t *testing.T) {
+	s := mortgage.Amortize(money.FromMajor(1200, "USD"), money.Amount{Currency: "USD"}, mortgage.Terms{TenorMonths: 12})
+
+	var buf bytes.Buffer
+	assert.NoError(t, mortgage.WriteCSV(&buf, s))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 13)
+	assert.Equal(t, "month,rate,installment,principal,interest,balance", lines[0])
+	assert.Equal(t, "1,0,100.00,100.00,0.00,1100.00", lines[1])
+}
@@ -0,0 +1,41 @@
+package payments
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// DeclinedMethod makes FakeProvider decline a charge.
+const DeclinedMethod = "fake_declined"
+
+// FakeProvider accepts every charge except those using DeclinedMethod. It is
+// meant for local development and tests; charges are only kept in memory.
+type FakeProvider struct {
+	mu      sync.Mutex
+	charges map[string]ChargeResult
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{charges: map[string]ChargeResult{}}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Charge(ctx context.Context, req ChargeRequest) (ChargeResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.charges[req.Reference]; ok {
+		return result, nil
+	}
+
+	result := ChargeResult{ProviderRef: "fake_" + strconv.Itoa(len(p.charges)+1), Succeeded: true}
+	if req.Method == DeclinedMethod {
+		result = ChargeResult{ProviderRef: result.ProviderRef, FailureReason: "card declined"}
+	}
+	p.charges[req.Reference] = result
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"real-estate-system/listing-service/money"
+)
+
+var ErrUnknownProvider = errors.New("unknown payment provider")
+
+// ChargeRequest asks a provider to collect Amount from the tenant using
+// Method, a provider-specific token such as a saved card. Reference is
+// unique per payment so providers can deduplicate retries.
+type ChargeRequest struct {
+	Reference   string
+	Amount      money.Amount
+	Method      string
+	Description string
+}
+
+// ChargeResult is the provider's answer. A declined charge is not an error:
+// Succeeded is false and FailureReason says why.
+type ChargeResult struct {
+	ProviderRef   string
+	Succeeded     bool
+	FailureReason string
+}
+
+// Provider collects rent payments. Errors mean the outcome is unknown, e.g.
+// the provider could not be reached.
+type Provider interface {
+	Name() string
+	Charge(ctx context.Context, req ChargeRequest) (ChargeResult, error)
+}
+
+// NewProvider returns the provider configured by name.
+func NewProvider(name string) (Provider, error) {
+	switch name {
+	case "", "fake":
+		return NewFakeProvider(), nil
+	}
+	return nil, ErrUnknownProvider
+}
@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"context"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/payments"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeProvider(t *testing.T) {
+	provider, err := payments.NewProvider("fake")
+	assert.NoError(t, err)
+
+	amount := money.Amount{Minor: 500000, Currency: "IDR"}
+	result, err := provider.Charge(context.Background(), payments.ChargeRequest{Reference: "payment:1", Amount: amount, Method: "tok"})
+	assert.NoError(t, err)
+	assert.True(t, result.Succeeded)
+
+	// Retrying a reference returns the first outcome.
+	again, _ := provider.Charge(context.Background(), payments.ChargeRequest{Reference: "payment:1", Amount: amount, Method: payments.DeclinedMethod})
+	assert.Equal(t, result, again)
+
+	declined, _ := provider.Charge(context.Background(), payments.ChargeRequest{Reference: "payment:2", Amount: amount, Method: payments.DeclinedMethod})
+	assert.False(t, declined.Succeeded)
+	assert.NotEmpty(t, declined.FailureReason)
+
+	_, err = payments.NewProvider("stripe")
+	assert.ErrorIs(t, err, payments.ErrUnknownProvider)
+}
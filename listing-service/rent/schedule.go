@@ -0,0 +1,54 @@
+package rent
+
+import (
+	"real-estate-system/listing-service/models"
+	"time"
+)
+
+// Schedule generates the rent charges of a lease. Periods follow the lease
+// start: one month each for monthly billing, twelve for yearly billing, with
+// a shorter last period if the term does not divide evenly. Rent is paid in
+// advance, on the payment due day on or before each period starts, but never
+// before the lease starts.
+func Schedule(lease models.Lease) []models.RentCharge {
+	months := 1
+	if lease.BillingPeriod == models.BillingYearly {
+		months = 12
+	}
+
+	start := time.UnixMicro(lease.StartsAt).UTC()
+	charges := []models.RentCharge{}
+	for offset := 0; offset < lease.TermMonths; offset += months {
+		length := months
+		if offset+length > lease.TermMonths {
+			length = lease.TermMonths - offset
+		}
+		periodStart := start.AddDate(0, offset, 0)
+		periodEnd := start.AddDate(0, offset+length, 0)
+
+		due := dueDate(periodStart, lease.PaymentDueDay)
+		if due.Before(start) {
+			due = start
+		}
+
+		charges = append(charges, models.RentCharge{
+			LeaseID:     lease.ID,
+			PeriodStart: periodStart.UnixMicro(),
+			PeriodEnd:   periodEnd.UnixMicro(),
+			DueAt:       due.UnixMicro(),
+			Amount:      lease.MonthlyRent * length,
+			Status:      models.RentChargeScheduled,
+		})
+	}
+	return charges
+}
+
+// dueDate is the latest date on or before t falling on the given day of the
+// month. day is at most 28 so it exists in every month.
+func dueDate(t time.Time, day int) time.Time {
+	due := time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.UTC)
+	if due.After(t) {
+		due = due.AddDate(0, -1, 0)
+	}
+	return due
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/rent"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestSchedule_Monthly(t *testing.T) {
+	lease := models.Lease{
+		StartsAt:      day("2025-01-20").UnixMicro(),
+		TermMonths:    3,
+		MonthlyRent:   5000,
+		PaymentDueDay: 5,
+		BillingPeriod: models.BillingMonthly,
+	}
+
+	charges := rent.Schedule(lease)
+	assert.Len(t, charges, 3)
+	// The first due date would fall before the lease, so it is the start.
+	assert.Equal(t, day("2025-01-20").UnixMicro(), charges[0].DueAt)
+	assert.Equal(t, day("2025-02-05").UnixMicro(), charges[1].DueAt)
+	assert.Equal(t, day("2025-03-20").UnixMicro(), charges[1].PeriodEnd)
+	assert.Equal(t, day("2025-04-20").UnixMicro(), charges[2].PeriodEnd)
+	assert.Equal(t, 5000, charges[2].Amount)
+}
+
+func TestSchedule_YearlyWithShortLastPeriod(t *testing.T) {
+	lease := models.Lease{
+		StartsAt:      day("2025-03-01").UnixMicro(),
+		TermMonths:    18,
+		MonthlyRent:   5000,
+		PaymentDueDay: 1,
+		BillingPeriod: models.BillingYearly,
+	}
+
+	charges := rent.Schedule(lease)
+	assert.Len(t, charges, 2)
+	assert.Equal(t, 60000, charges[0].Amount)
+	assert.Equal(t, day("2026-03-01").UnixMicro(), charges[1].DueAt)
+	assert.Equal(t, 30000, charges[1].Amount)
+	assert.Equal(t, day("2026-09-01").UnixMicro(), charges[1].PeriodEnd)
+}
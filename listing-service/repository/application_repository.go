@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormApplicationRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormApplicationRepository(db *gorm.DB) *GormApplicationRepository {
+	return &GormApplicationRepository{DB: db}
+}
+
+// applicationEvent is the outbox payload. Income, references and documents
+// stay in the database.
+type applicationEvent struct {
+	ID           int64  `json:"id"`
+	ListingID    int    `json:"listing_id"`
+	ApplicantID  int    `json:"applicant_id"`
+	LandlordID   int    `json:"landlord_id"`
+	Status       string `json:"status"`
+	DecisionNote string `json:"decision_note,omitempty"`
+}
+
+func writeApplicationEvent(tx *gorm.DB, eventType string, app *models.Application) error {
+	return writeOutboxFor(tx, eventType, events.AggregateApplication, int(app.ID), applicationEvent{
+		ID:           app.ID,
+		ListingID:    app.ListingID,
+		ApplicantID:  app.ApplicantID,
+		LandlordID:   app.LandlordID,
+		Status:       app.Status,
+		DecisionNote: app.DecisionNote,
+	})
+}
+
+func setApplicationStatus(tx *gorm.DB, app *models.Application, status, note string) error {
+	app.Status = status
+	app.DecisionNote = note
+	app.UpdatedAt = time.Now().UnixMicro()
+	err := tx.Model(app).Updates(map[string]interface{}{
+		"status":        app.Status,
+		"decision_note": app.DecisionNote,
+		"updated_at":    app.UpdatedAt,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return writeApplicationEvent(tx, events.ApplicationStatusChanged, app)
+}
+
+// rejectApplications rejects the listing's open applications except
+// exceptID, notifying each applicant.
+func rejectApplications(tx *gorm.DB, listingID int, exceptID int64, note string) error {
+	var open []models.Application
+	err := tx.Where("listing_id = ? AND status IN ? AND id <> ?", listingID,
+		[]string{models.ApplicationStatusSubmitted, models.ApplicationStatusReviewing}, exceptID).
+		Find(&open).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range open {
+		if err := setApplicationStatus(tx, &open[i], models.ApplicationStatusRejected, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormApplicationRepository) CreateApplication(app *models.Application) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(app).Error; err != nil {
+			return err
+		}
+		return writeApplicationEvent(tx, events.ApplicationSubmitted, app)
+	})
+}
+
+func (r *GormApplicationRepository) GetApplication(id int64) (*models.Application, error) {
+	var app models.Application
+	if err := r.DB.First(&app, id).Error; err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+func (r *GormApplicationRepository) GetApplications(filter models.ApplicationFilter) ([]models.Application, error) {
+	db := r.DB
+	if filter.ApplicantID > 0 {
+		db = db.Where("applicant_id = ?", filter.ApplicantID)
+	}
+	if filter.LandlordID > 0 {
+		db = db.Where("landlord_id = ?", filter.LandlordID)
+	}
+	if filter.ListingID > 0 {
+		db = db.Where("listing_id = ?", filter.ListingID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	apps := []models.Application{}
+	err := db.Order("created_at desc").Find(&apps).Error
+	return apps, err
+}
+
+func (r *GormApplicationRepository) HasOpenApplication(listingID, applicantID int) (bool, error) {
+	var count int64
+	err := r.DB.Model(&models.Application{}).
+		Where("listing_id = ? AND applicant_id = ? AND status IN ?", listingID, applicantID,
+			[]string{models.ApplicationStatusSubmitted, models.ApplicationStatusReviewing}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormApplicationRepository) UpdateApplicationStatus(app *models.Application, status, note string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return setApplicationStatus(tx, app, status, note)
+	})
+}
+
+// ApproveApplication approves the application, marks the listing rented and
+// rejects the other open applications. The listing row is locked so only one
+// application can be approved.
+func (r *GormApplicationRepository) ApproveApplication(app *models.Application, note string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockListing(tx, app.ListingID)
+		if err != nil {
+			return err
+		}
+		if listing.Status != models.ListingStatusActive {
+			return models.ErrListingUnavailable
+		}
+
+		if err := setApplicationStatus(tx, app, models.ApplicationStatusApproved, note); err != nil {
+			return err
+		}
+		if err := setListingStatus(tx, listing, models.ListingStatusRented, app.UpdatedAt); err != nil {
+			return err
+		}
+
+		return rejectApplications(tx, listing.ID, app.ID, "Another applicant was approved")
+	})
+}
+
+// GetScreeningRules returns the landlord's rules, or the defaults if they
+// have not configured any.
+func (r *GormApplicationRepository) GetScreeningRules(landlordID int) (*models.ScreeningRules, error) {
+	var rules models.ScreeningRules
+	err := r.DB.First(&rules, landlordID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		rules = models.DefaultScreeningRules(landlordID)
+		return &rules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// SaveScreeningRules creates or replaces the landlord's rules.
+func (r *GormApplicationRepository) SaveScreeningRules(rules *models.ScreeningRules) error {
+	return r.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(rules).Error
+}
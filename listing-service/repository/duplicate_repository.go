@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// metresPerDegree is the length of a degree of latitude.
+const metresPerDegree = 111320
+
+type GormDuplicateRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormDuplicateRepository(db *gorm.DB) *GormDuplicateRepository {
+	return &GormDuplicateRepository{DB: db}
+}
+
+// GetCandidates returns the listings of the same type that may be the same
+// property: those in the same city or within radius metres, newest first.
+// Archived listings and other tenants' listings are left out.
+func (r *GormDuplicateRepository) GetCandidates(listing *models.Listing, radius float64, limit int) ([]models.Listing, error) {
+	near := r.DB.Session(&gorm.Session{NewDB: true})
+	if listing.City != "" {
+		near = near.Or("lower(city) = lower(?)", listing.City)
+	}
+	if listing.HasLocation() {
+		dLat := radius / metresPerDegree
+		dLon := radius / (metresPerDegree * math.Max(math.Cos(listing.Latitude*math.Pi/180), 0.01))
+		near = near.Or("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+			listing.Latitude-dLat, listing.Latitude+dLat, listing.Longitude-dLon, listing.Longitude+dLon)
+	}
+
+	var listings []models.Listing
+	err := r.DB.Preload("Photos", orderPhotos).
+		Where("tenant_id = ? AND id <> ? AND listing_type = ? AND status <> ?", listing.TenantID, listing.ID, listing.ListingType, models.ListingStatusArchived).
+		Where(near).
+		Order("id desc").Limit(limit).Find(&listings).Error
+	return listings, err
+}
+
+// GetListingsToScan returns the listings after afterID that are not
+// archived, with their photos, in ID order.
+func (r *GormDuplicateRepository) GetListingsToScan(afterID, limit int) ([]models.Listing, error) {
+	var listings []models.Listing
+	err := r.DB.Preload("Photos", orderPhotos).
+		Where("id > ? AND status <> ?", afterID, models.ListingStatusArchived).
+		Order("id").Limit(limit).Find(&listings).Error
+	return listings, err
+}
+
+// SaveDuplicatePairs records new pairs and rescores pending ones. Pairs
+// already merged or dismissed are left alone.
+func (r *GormDuplicateRepository) SaveDuplicatePairs(pairs []models.DuplicatePair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "listing_id"}, {Name: "duplicate_id"}},
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: "duplicate_pairs", Name: "status"}, Value: models.DuplicatePending}}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "signals", "updated_at"}),
+	}).Create(&pairs).Error
+}
+
+// GetDuplicatePairs returns pairs with both listings and their photos,
+// likeliest first.
+func (r *GormDuplicateRepository) GetDuplicatePairs(filter models.DuplicateFilter, page, size int) ([]models.DuplicatePair, error) {
+	db := r.DB.Preload("Listing.Photos", orderPhotos).Preload("Duplicate.Photos", orderPhotos)
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.ListingID != 0 {
+		db = db.Where("listing_id = ? OR duplicate_id = ?", filter.ListingID, filter.ListingID)
+	}
+
+	var pairs []models.DuplicatePair
+	err := db.Order("score desc, id").Offset((page - 1) * size).Limit(size).Find(&pairs).Error
+	return pairs, err
+}
+
+func (r *GormDuplicateRepository) GetDuplicatePair(id int64) (*models.DuplicatePair, error) {
+	var pair models.DuplicatePair
+	if err := r.DB.First(&pair, id).Error; err != nil {
+		return nil, err
+	}
+	return &pair, nil
+}
+
+// MergeDuplicatePair keeps keepID and archives the other listing of the
+// pair, which ends its conversations, offers and applications.
+func (r *GormDuplicateRepository) MergeDuplicatePair(pair *models.DuplicatePair, keepID, adminID int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingPair(tx, pair); err != nil {
+			return err
+		}
+
+		now := time.Now().UnixMicro()
+		merged, err := lockListing(tx, pair.Other(keepID))
+		if err != nil {
+			return err
+		}
+		if merged.Status != models.ListingStatusArchived {
+			if err := archiveListing(tx, merged, now, "The listing was merged with another listing of the same property"); err != nil {
+				return err
+			}
+		}
+
+		pair.KeptListingID = keepID
+		return resolvePair(tx, pair, models.DuplicateMerged, adminID, now)
+	})
+}
+
+// DismissDuplicatePair records that the listings are different properties,
+// so the pair is not reported again.
+func (r *GormDuplicateRepository) DismissDuplicatePair(pair *models.DuplicatePair, adminID int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingPair(tx, pair); err != nil {
+			return err
+		}
+		return resolvePair(tx, pair, models.DuplicateDismissed, adminID, time.Now().UnixMicro())
+	})
+}
+
+func lockPendingPair(tx *gorm.DB, pair *models.DuplicatePair) error {
+	var locked models.DuplicatePair
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, pair.ID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ErrDuplicateResolved
+	}
+	if err != nil {
+		return err
+	}
+	if locked.Status != models.DuplicatePending {
+		return models.ErrDuplicateResolved
+	}
+	return nil
+}
+
+func resolvePair(tx *gorm.DB, pair *models.DuplicatePair, status string, adminID int, now int64) error {
+	pair.Status = status
+	pair.ResolvedBy = adminID
+	pair.ResolvedAt = now
+	pair.UpdatedAt = now
+	return tx.Model(pair).Updates(map[string]interface{}{
+		"status":          pair.Status,
+		"kept_listing_id": pair.KeptListingID,
+		"resolved_by":     pair.ResolvedBy,
+		"resolved_at":     pair.ResolvedAt,
+		"updated_at":      pair.UpdatedAt,
+	}).Error
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormFavoriteRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormFavoriteRepository(db *gorm.DB) *GormFavoriteRepository {
+	return &GormFavoriteRepository{DB: db}
+}
+
+// AddFavorite is idempotent and reports whether a new favorite was stored.
+func (r *GormFavoriteRepository) AddFavorite(favorite *models.Favorite) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(favorite)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *GormFavoriteRepository) RemoveFavorite(userID, listingID int) error {
+	return r.DB.Where("user_id = ? AND listing_id = ?", userID, listingID).Delete(&models.Favorite{}).Error
+}
+
+func (r *GormFavoriteRepository) GetFavorites(userID int) ([]models.FavoriteListing, error) {
+	var favorites []models.Favorite
+	err := r.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&favorites).Error
+	if err != nil || len(favorites) == 0 {
+		return []models.FavoriteListing{}, err
+	}
+
+	ids := make([]int, len(favorites))
+	for i, f := range favorites {
+		ids[i] = f.ListingID
+	}
+
+	var listings []models.Listing
+	if err := withMedia(r.DB).Where("id IN ?", ids).Find(&listings).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*models.Listing, len(listings))
+	for i := range listings {
+		byID[listings[i].ID] = &listings[i]
+	}
+
+	result := make([]models.FavoriteListing, len(favorites))
+	for i, f := range favorites {
+		listing := byID[f.ListingID]
+		if listing != nil && !listing.IsPublic() {
+			// Listings under review, rejected or hidden are not shown to
+			// anyone but their owner.
+			listing = nil
+		}
+		result[i] = models.FavoriteListing{
+			ListingID:         f.ListingID,
+			FavoritedAt:       f.CreatedAt,
+			SavedPrice:        f.SavedPrice,
+			PriceDropped:      listing != nil && f.SavedPrice > 0 && listing.Price < f.SavedPrice,
+			Listing:           listing,
+			NoLongerAvailable: listing == nil || listing.Status != models.ListingStatusActive,
+		}
+	}
+	return result, nil
+}
+
+func (r *GormFavoriteRepository) CountFavorites(listingIDs []int) (map[int]int64, error) {
+	var rows []struct {
+		ListingID int
+		Count     int64
+	}
+	err := r.DB.Model(&models.Favorite{}).
+		Select("listing_id, COUNT(*) AS count").
+		Where("listing_id IN ?", listingIDs).
+		Group("listing_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int64, len(listingIDs))
+	for _, id := range listingIDs {
+		counts[id] = 0
+	}
+	for _, row := range rows {
+		counts[row.ListingID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormInquiryRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormInquiryRepository(db *gorm.DB) *GormInquiryRepository {
+	return &GormInquiryRepository{DB: db}
+}
+
+// inquiryEvent is the outbox payload. Contact details and the message stay
+// out of the event streams, which partners can subscribe to.
+type inquiryEvent struct {
+	ID        int64  `json:"id"`
+	TenantID  string `json:"tenant_id"`
+	ListingID int    `json:"listing_id"`
+	OwnerID   int    `json:"owner_id"`
+	BuyerID   int    `json:"buyer_id"`
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+	SourceRef string `json:"source_ref,omitempty"`
+}
+
+func newInquiryEvent(inquiry *models.Inquiry) inquiryEvent {
+	return inquiryEvent{
+		ID:        inquiry.ID,
+		TenantID:  inquiry.TenantID,
+		ListingID: inquiry.ListingID,
+		OwnerID:   inquiry.OwnerID,
+		BuyerID:   inquiry.BuyerID,
+		Status:    inquiry.Status,
+		Source:    inquiry.Source,
+		SourceRef: inquiry.SourceRef,
+	}
+}
+
+func (r *GormInquiryRepository) CreateInquiry(inquiry *models.Inquiry) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(inquiry).Error; err != nil {
+			return err
+		}
+		return writeOutboxFor(tx, events.InquiryCreated, events.AggregateInquiry, int(inquiry.ID), newInquiryEvent(inquiry))
+	})
+}
+
+func (r *GormInquiryRepository) GetInquiry(id int64) (*models.Inquiry, error) {
+	var inquiry models.Inquiry
+	if err := r.DB.First(&inquiry, id).Error; err != nil {
+		return nil, err
+	}
+	return &inquiry, nil
+}
+
+func (r *GormInquiryRepository) GetInquiries(filter models.InquiryFilter, page, size int) ([]models.Inquiry, error) {
+	db := r.DB.Where("owner_id = ?", filter.OwnerID)
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	inquiries := []models.Inquiry{}
+	err := db.Order("created_at desc").Offset((page - 1) * size).Limit(size).Find(&inquiries).Error
+	return inquiries, err
+}
+
+func (r *GormInquiryRepository) UpdateInquiryStatus(inquiry *models.Inquiry, status string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		inquiry.Status = status
+		inquiry.UpdatedAt = time.Now().UnixMicro()
+		err := tx.Model(inquiry).Updates(map[string]interface{}{
+			"status":     inquiry.Status,
+			"updated_at": inquiry.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return writeOutboxFor(tx, events.InquiryStatusChanged, events.AggregateInquiry, int(inquiry.ID), newInquiryEvent(inquiry))
+	})
+}
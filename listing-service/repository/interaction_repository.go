@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+type GormInteractionRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormInteractionRepository(db *gorm.DB) *GormInteractionRepository {
+	return &GormInteractionRepository{DB: db}
+}
+
+// GetCompletedInteractions lists the interactions userID finished with
+// counterpartID as of now, latest first.
+func (r *GormInteractionRepository) GetCompletedInteractions(userID, counterpartID int, now int64) ([]models.Interaction, error) {
+	interactions := []models.Interaction{}
+
+	var inquiries []models.Inquiry
+	err := r.DB.Where("buyer_id = ? AND owner_id = ? AND status = ?", userID, counterpartID, models.InquiryStatusClosed).
+		Find(&inquiries).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, inquiry := range inquiries {
+		interactions = append(interactions, models.Interaction{
+			Type: models.InteractionInquiry, ID: inquiry.ID, ListingID: inquiry.ListingID, OccurredAt: inquiry.UpdatedAt,
+		})
+	}
+
+	var viewings []models.Viewing
+	err = r.DB.Where("buyer_id = ? AND agent_id = ? AND status = ? AND ends_at <= ?", userID, counterpartID, models.ViewingStatusBooked, now).
+		Find(&viewings).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, viewing := range viewings {
+		interactions = append(interactions, models.Interaction{
+			Type: models.InteractionViewing, ID: viewing.ID, ListingID: viewing.ListingID, OccurredAt: viewing.EndsAt,
+		})
+	}
+
+	var leases []models.Lease
+	err = r.DB.Where("tenant_id = ? AND landlord_id = ?", userID, counterpartID).Find(&leases).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, lease := range leases {
+		interactions = append(interactions, models.Interaction{
+			Type: models.InteractionLease, ID: lease.ID, ListingID: lease.ListingID, OccurredAt: lease.StartsAt,
+		})
+	}
+
+	sort.SliceStable(interactions, func(i, j int) bool {
+		return interactions[i].OccurredAt > interactions[j].OccurredAt
+	})
+	return interactions, nil
+}
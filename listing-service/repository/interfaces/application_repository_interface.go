@@ -0,0 +1,14 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type ApplicationRepository interface {
+	CreateApplication(app *models.Application) error
+	GetApplication(id int64) (*models.Application, error)
+	GetApplications(filter models.ApplicationFilter) ([]models.Application, error)
+	HasOpenApplication(listingID, applicantID int) (bool, error)
+	UpdateApplicationStatus(app *models.Application, status, note string) error
+	ApproveApplication(app *models.Application, note string) error
+	GetScreeningRules(landlordID int) (*models.ScreeningRules, error)
+	SaveScreeningRules(rules *models.ScreeningRules) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type DuplicateRepository interface {
+	GetCandidates(listing *models.Listing, radius float64, limit int) ([]models.Listing, error)
+	GetListingsToScan(afterID, limit int) ([]models.Listing, error)
+	SaveDuplicatePairs(pairs []models.DuplicatePair) error
+	GetDuplicatePairs(filter models.DuplicateFilter, page, size int) ([]models.DuplicatePair, error)
+	GetDuplicatePair(id int64) (*models.DuplicatePair, error)
+	MergeDuplicatePair(pair *models.DuplicatePair, keepID, adminID int) error
+	DismissDuplicatePair(pair *models.DuplicatePair, adminID int) error
+}
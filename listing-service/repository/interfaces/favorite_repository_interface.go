@@ -0,0 +1,10 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type FavoriteRepository interface {
+	AddFavorite(favorite *models.Favorite) (bool, error)
+	RemoveFavorite(userID, listingID int) error
+	GetFavorites(userID int) ([]models.FavoriteListing, error)
+	CountFavorites(listingIDs []int) (map[int]int64, error)
+}
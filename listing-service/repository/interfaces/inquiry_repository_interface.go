@@ -0,0 +1,10 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type InquiryRepository interface {
+	CreateInquiry(inquiry *models.Inquiry) error
+	GetInquiry(id int64) (*models.Inquiry, error)
+	GetInquiries(filter models.InquiryFilter, page, size int) ([]models.Inquiry, error)
+	UpdateInquiryStatus(inquiry *models.Inquiry, status string) error
+}
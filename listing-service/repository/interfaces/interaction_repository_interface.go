@@ -0,0 +1,7 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type InteractionRepository interface {
+	GetCompletedInteractions(userID, counterpartID int, now int64) ([]models.Interaction, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type LeaseRepository interface {
+	CreateLease(lease *models.Lease, charges []models.RentCharge) error
+	GetLease(id int64) (*models.Lease, error)
+	GetLeases(filter models.LeaseFilter) ([]models.Lease, error)
+	GetRentCharges(leaseID int64) ([]models.RentCharge, error)
+	RenewLease(lease, renewal *models.Lease, charges []models.RentCharge) error
+	TerminateLease(lease *models.Lease, endsAt int64, reason string) error
+	ExpiringLeases(before int64, limit int) ([]models.Lease, error)
+	FlagExpiring(lease *models.Lease) error
+	EndedLeases(now int64, limit int) ([]models.Lease, error)
+	EndLease(lease *models.Lease) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type LedgerRepository interface {
+	UnpostedRentCharges(now int64, limit int) ([]models.RentCharge, error)
+	PostRentCharge(charge *models.RentCharge, txn *models.LedgerTransaction) error
+	LateFeeCandidates(now int64, limit int) ([]models.RentCharge, error)
+	ApplyLateFee(lease *models.Lease, charge *models.RentCharge, txn *models.LedgerTransaction) error
+	GetTransactions(leaseID int64) ([]models.LedgerTransaction, error)
+	CreatePayment(payment *models.Payment) (bool, error)
+	CompletePayment(payment *models.Payment, txn *models.LedgerTransaction) error
+	GetPayments(leaseID int64) ([]models.Payment, error)
+	GetLateFeeRule(landlordID int) (*models.LateFeeRule, error)
+	SaveLateFeeRule(rule *models.LateFeeRule) error
+}
@@ -2,7 +2,14 @@ package interfaces
 
 import "real-estate-system/listing-service/models"
 
+// ListingRepository only sees the listings of one tenant; ForTenant
+// returns the repository of another.
 type ListingRepository interface {
+	ForTenant(tenantID string) ListingRepository
 	CreateListing(*models.Listing) error
-	GetListings(page, size int) ([]models.Listing, error)
+	GetListings(filter models.ListingFilter, page, size int) ([]models.Listing, error)
+	GetListing(id int) (*models.Listing, error)
+	UpdateListingStatus(listing *models.Listing, status string) error
+	UpdateListingPrice(listing *models.Listing, price int) error
+	GetPriceHistory(listingID int) ([]models.ListingPriceChange, error)
 }
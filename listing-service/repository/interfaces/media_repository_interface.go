@@ -0,0 +1,12 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type MediaRepository interface {
+	AddMedia(item *models.ListingMedia) error
+	GetMedia(filter models.MediaFilter) ([]models.ListingMedia, error)
+	GetMediaItem(id int64) (*models.ListingMedia, error)
+	DeleteMedia(item *models.ListingMedia) error
+	ReorderPhotos(listingID int, ids []int64) ([]models.ListingMedia, error)
+	SetCover(photo *models.ListingMedia) error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type ModerationRepository interface {
+	GetModerationStats(listing *models.Listing, since int64) (models.ModerationStats, error)
+	SubmitListing(listing *models.Listing, review *models.ListingReview) error
+	GetReviews(filter models.ReviewFilter, page, size int) ([]models.ListingReview, error)
+	GetReview(id int64) (*models.ListingReview, error)
+	ApproveListing(review *models.ListingReview, adminID int, note string) error
+	RejectListing(review *models.ListingReview, adminID int, note string) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type OfferRepository interface {
+	CreateOffer(offer *models.Offer, event *models.OfferEvent) error
+	GetOffer(id int64) (*models.Offer, error)
+	GetOffers(filter models.OfferFilter) ([]models.Offer, error)
+	GetOfferEvents(offerID int64) ([]models.OfferEvent, error)
+	HasPendingOffer(listingID, buyerID int) (bool, error)
+	UpdateOffer(offer *models.Offer, event *models.OfferEvent) error
+	AcceptOffer(offer *models.Offer, event *models.OfferEvent) error
+	ExpireOffers(now int64, limit int) (int, error)
+}
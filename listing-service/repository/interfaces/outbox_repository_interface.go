@@ -0,0 +1,9 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type OutboxRepository interface {
+	FetchPending(limit int) ([]models.OutboxEvent, error)
+	MarkPublished(id int64, publishedAt int64) error
+	MarkFailed(id int64, reason string) error
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type ReportRepository interface {
+	CreateReport(report *models.Report, hideAfter int) (bool, error)
+	GetReports(filter models.ReportFilter, page, size int) ([]models.Report, error)
+	GetReport(id int64) (*models.Report, error)
+	ResolveReports(report *models.Report, outcome string, adminID int, note string) ([]models.Report, error)
+	GetTrustRecord(userID int) (*models.TrustRecord, error)
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type StatsRepository interface {
+	GetAreaStats(query models.AreaStatsQuery) ([]models.AreaStats, error)
+	RefreshViews() error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type ThreadRepository interface {
+	GetOrCreateThread(thread *models.Thread) (bool, error)
+	GetThread(id int64) (*models.Thread, error)
+	GetThreads(userID int) ([]models.ThreadSummary, error)
+	GetMessages(threadID, before int64, limit int) ([]models.Message, error)
+	PostMessage(thread *models.Thread, message *models.Message) error
+	MarkRead(thread *models.Thread, userID int, messageID int64) error
+}
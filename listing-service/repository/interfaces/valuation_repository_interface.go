@@ -0,0 +1,9 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type ValuationRepository interface {
+	GetComparables(subject *models.Listing, radius float64, since int64, limit int) ([]models.Listing, error)
+	GetListingsToValue(afterID, limit int) ([]models.Listing, error)
+	SaveEstimate(listing *models.Listing, valuation *models.Valuation, now int64) error
+}
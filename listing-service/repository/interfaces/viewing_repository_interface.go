@@ -0,0 +1,20 @@
+package interfaces
+
+import "real-estate-system/listing-service/models"
+
+type ViewingRepository interface {
+	CreateSlot(slot *models.ViewingSlot) error
+	GetSlot(id int64) (*models.ViewingSlot, error)
+	GetSlots(agentID, listingID int, from int64) ([]models.ViewingSlot, error)
+	DeleteSlot(id int64) error
+
+	BookViewing(viewing *models.Viewing, slot *models.ViewingSlot) error
+	RescheduleViewing(viewing *models.Viewing, slot *models.ViewingSlot, startsAt, endsAt int64) error
+	CancelViewing(viewing *models.Viewing, reason string) error
+	GetViewing(id int64) (*models.Viewing, error)
+	GetViewings(userID int, from int64) ([]models.Viewing, error)
+	GetAgentViewings(agentID int, from int64) ([]models.Viewing, error)
+
+	DueReminders(now int64, limit int) ([]models.Viewing, error)
+	MarkReminded(viewing *models.Viewing) error
+}
@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormLeaseRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormLeaseRepository(db *gorm.DB) *GormLeaseRepository {
+	return &GormLeaseRepository{DB: db}
+}
+
+// leaseEvent is the outbox payload; rent and deposit stay in the database.
+type leaseEvent struct {
+	ID                int64  `json:"id"`
+	ListingID         int    `json:"listing_id"`
+	LandlordID        int    `json:"landlord_id"`
+	TenantID          int    `json:"tenant_id"`
+	Status            string `json:"status"`
+	StartsAt          int64  `json:"starts_at"`
+	EndsAt            int64  `json:"ends_at"`
+	RenewedFromID     int64  `json:"renewed_from_id,omitempty"`
+	RenewedToID       int64  `json:"renewed_to_id,omitempty"`
+	TerminationReason string `json:"termination_reason,omitempty"`
+}
+
+func writeLeaseEvent(tx *gorm.DB, eventType string, lease *models.Lease) error {
+	return writeOutboxFor(tx, eventType, events.AggregateLease, int(lease.ID), leaseEvent{
+		ID:                lease.ID,
+		ListingID:         lease.ListingID,
+		LandlordID:        lease.LandlordID,
+		TenantID:          lease.TenantID,
+		Status:            lease.Status,
+		StartsAt:          lease.StartsAt,
+		EndsAt:            lease.EndsAt,
+		RenewedFromID:     lease.RenewedFromID,
+		RenewedToID:       lease.RenewedToID,
+		TerminationReason: lease.TerminationReason,
+	})
+}
+
+// insertLease checks the lease does not overlap another active lease of the
+// listing, then saves it with its rent schedule.
+func insertLease(tx *gorm.DB, lease *models.Lease, charges []models.RentCharge) error {
+	var overlapping int64
+	err := tx.Model(&models.Lease{}).
+		Where("listing_id = ? AND status = ? AND starts_at < ? AND ends_at > ?",
+			lease.ListingID, models.LeaseStatusActive, lease.EndsAt, lease.StartsAt).
+		Count(&overlapping).Error
+	if err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return models.ErrLeaseOverlap
+	}
+
+	if err := tx.Create(lease).Error; err != nil {
+		return err
+	}
+	for i := range charges {
+		charges[i].LeaseID = lease.ID
+	}
+	if len(charges) > 0 {
+		if err := tx.Create(&charges).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateLease saves the lease and its schedule and marks an active listing
+// rented. Listings already rented, e.g. by an approved application, stay so.
+func (r *GormLeaseRepository) CreateLease(lease *models.Lease, charges []models.RentCharge) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockListing(tx, lease.ListingID)
+		if err != nil {
+			return err
+		}
+		if listing.Status != models.ListingStatusActive && listing.Status != models.ListingStatusRented {
+			return models.ErrListingUnavailable
+		}
+
+		if err := insertLease(tx, lease, charges); err != nil {
+			return err
+		}
+		if err := writeLeaseEvent(tx, events.LeaseCreated, lease); err != nil {
+			return err
+		}
+
+		if listing.Status == models.ListingStatusActive {
+			return setListingStatus(tx, listing, models.ListingStatusRented, lease.CreatedAt)
+		}
+		return nil
+	})
+}
+
+func (r *GormLeaseRepository) GetLease(id int64) (*models.Lease, error) {
+	var lease models.Lease
+	if err := r.DB.First(&lease, id).Error; err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+func (r *GormLeaseRepository) GetLeases(filter models.LeaseFilter) ([]models.Lease, error) {
+	db := r.DB.Where("landlord_id = ? OR tenant_id = ?", filter.UserID, filter.UserID)
+	if filter.ListingID > 0 {
+		db = db.Where("listing_id = ?", filter.ListingID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.EndingBefore > 0 {
+		db = db.Where("ends_at <= ?", filter.EndingBefore)
+	}
+
+	leases := []models.Lease{}
+	err := db.Order("starts_at desc").Find(&leases).Error
+	return leases, err
+}
+
+func (r *GormLeaseRepository) GetRentCharges(leaseID int64) ([]models.RentCharge, error) {
+	charges := []models.RentCharge{}
+	err := r.DB.Where("lease_id = ?", leaseID).Order("period_start").Find(&charges).Error
+	return charges, err
+}
+
+// RenewLease saves renewal, which starts when lease ends, and links the two.
+func (r *GormLeaseRepository) RenewLease(lease, renewal *models.Lease, charges []models.RentCharge) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockListing(tx, lease.ListingID); err != nil {
+			return err
+		}
+		if err := insertLease(tx, renewal, charges); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Lease{}).
+			Where("id = ? AND renewed_to_id = 0 AND termination_reason = ''", lease.ID).
+			Updates(map[string]interface{}{"renewed_to_id": renewal.ID, "updated_at": renewal.CreatedAt})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrLeaseRenewed
+		}
+		lease.RenewedToID = renewal.ID
+		lease.UpdatedAt = renewal.CreatedAt
+
+		return writeLeaseEvent(tx, events.LeaseRenewed, lease)
+	})
+}
+
+// TerminateLease brings the lease end forward to endsAt and cancels the rent
+// due for periods starting from then. The lease ends right away if endsAt
+// has passed, otherwise when the end job reaches it.
+func (r *GormLeaseRepository) TerminateLease(lease *models.Lease, endsAt int64, reason string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMicro()
+		result := tx.Model(&models.Lease{}).
+			Where("id = ? AND renewed_to_id = 0", lease.ID).
+			Updates(map[string]interface{}{"ends_at": endsAt, "termination_reason": reason, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrLeaseRenewed
+		}
+		lease.EndsAt = endsAt
+		lease.TerminationReason = reason
+		lease.UpdatedAt = now
+
+		err := tx.Model(&models.RentCharge{}).
+			Where("lease_id = ? AND period_start >= ?", lease.ID, endsAt).
+			Update("status", models.RentChargeCancelled).Error
+		if err != nil {
+			return err
+		}
+		if err := writeLeaseEvent(tx, events.LeaseTerminated, lease); err != nil {
+			return err
+		}
+
+		if endsAt <= now {
+			return endLease(tx, lease, now)
+		}
+		return nil
+	})
+}
+
+// ExpiringLeases returns active leases ending before the given time that
+// will not continue, and whose parties have not been told yet.
+func (r *GormLeaseRepository) ExpiringLeases(before int64, limit int) ([]models.Lease, error) {
+	leases := []models.Lease{}
+	err := r.DB.Where("status = ? AND ends_at <= ? AND expiry_notified_at = 0 AND renewed_to_id = 0 AND termination_reason = ''",
+		models.LeaseStatusActive, before).
+		Order("ends_at").
+		Limit(limit).
+		Find(&leases).Error
+	return leases, err
+}
+
+// FlagExpiring records that the lease is about to end and emits
+// lease.expiring.
+func (r *GormLeaseRepository) FlagExpiring(lease *models.Lease) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMicro()
+		result := tx.Model(&models.Lease{}).
+			Where("id = ? AND expiry_notified_at = 0", lease.ID).
+			Updates(map[string]interface{}{"expiry_notified_at": now, "updated_at": now})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		lease.ExpiryNotifiedAt = now
+		lease.UpdatedAt = now
+		return writeLeaseEvent(tx, events.LeaseExpiring, lease)
+	})
+}
+
+func (r *GormLeaseRepository) EndedLeases(now int64, limit int) ([]models.Lease, error) {
+	leases := []models.Lease{}
+	err := r.DB.Where("status = ? AND ends_at <= ?", models.LeaseStatusActive, now).
+		Order("ends_at").
+		Limit(limit).
+		Find(&leases).Error
+	return leases, err
+}
+
+func (r *GormLeaseRepository) EndLease(lease *models.Lease) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return endLease(tx, lease, time.Now().UnixMicro())
+	})
+}
+
+// endLease closes the lease and, unless another lease follows, makes the
+// listing available again.
+func endLease(tx *gorm.DB, lease *models.Lease, now int64) error {
+	status := models.LeaseStatusEnded
+	if lease.TerminationReason != "" {
+		status = models.LeaseStatusTerminated
+	}
+
+	result := tx.Model(&models.Lease{}).
+		Where("id = ? AND status = ?", lease.ID, models.LeaseStatusActive).
+		Updates(map[string]interface{}{"status": status, "updated_at": now})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	lease.Status = status
+	lease.UpdatedAt = now
+	if err := writeLeaseEvent(tx, events.LeaseEnded, lease); err != nil {
+		return err
+	}
+
+	// A renewal or a later lease keeps the listing rented.
+	var following int64
+	err := tx.Model(&models.Lease{}).
+		Where("listing_id = ? AND status = ?", lease.ListingID, models.LeaseStatusActive).
+		Count(&following).Error
+	if err != nil || following > 0 {
+		return err
+	}
+	listing, err := lockListing(tx, lease.ListingID)
+	if errors.Is(err, models.ErrListingUnavailable) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if listing.Status != models.ListingStatusRented {
+		return nil
+	}
+	return setListingStatus(tx, listing, models.ListingStatusActive, now)
+}
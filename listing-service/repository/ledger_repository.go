@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/ledger"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormLedgerRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormLedgerRepository(db *gorm.DB) *GormLedgerRepository {
+	return &GormLedgerRepository{DB: db}
+}
+
+// postTransaction writes a balanced transaction and its entries. It returns
+// false without error if the reference was already posted.
+func postTransaction(tx *gorm.DB, txn *models.LedgerTransaction) (bool, error) {
+	if !ledger.Balanced(*txn) {
+		return false, models.ErrUnbalanced
+	}
+
+	txn.CreatedAt = time.Now().UnixMicro()
+	result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "reference"}}, DoNothing: true}).
+		Omit(clause.Associations).
+		Create(txn)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	for i := range txn.Entries {
+		txn.Entries[i].TransactionID = txn.ID
+	}
+	return true, tx.Create(&txn.Entries).Error
+}
+
+// UnpostedRentCharges returns scheduled charges that are due but not yet in
+// the ledger.
+func (r *GormLedgerRepository) UnpostedRentCharges(now int64, limit int) ([]models.RentCharge, error) {
+	charges := []models.RentCharge{}
+	err := r.DB.Where("status = ? AND posted_at = 0 AND due_at <= ?", models.RentChargeScheduled, now).
+		Order("due_at").
+		Limit(limit).
+		Find(&charges).Error
+	return charges, err
+}
+
+func (r *GormLedgerRepository) PostRentCharge(charge *models.RentCharge, txn *models.LedgerTransaction) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := postTransaction(tx, txn); err != nil {
+			return err
+		}
+		charge.PostedAt = txn.CreatedAt
+		return tx.Model(charge).Update("posted_at", charge.PostedAt).Error
+	})
+}
+
+// LateFeeCandidates returns posted charges whose landlord's grace period has
+// passed and that have not been checked for a late fee yet.
+func (r *GormLedgerRepository) LateFeeCandidates(now int64, limit int) ([]models.RentCharge, error) {
+	charges := []models.RentCharge{}
+	err := r.DB.Model(&models.RentCharge{}).
+		Select("rent_charges.*").
+		Joins("JOIN leases ON leases.id = rent_charges.lease_id").
+		Joins("LEFT JOIN late_fee_rules ON late_fee_rules.landlord_id = leases.landlord_id").
+		Where("rent_charges.status = ? AND rent_charges.posted_at > 0 AND rent_charges.late_fee_at = 0", models.RentChargeScheduled).
+		Where("rent_charges.due_at + COALESCE(late_fee_rules.grace_days, ?) * ? <= ?",
+			models.DefaultLateFeeRule(0).GraceDays, (24 * time.Hour).Microseconds(), now).
+		Order("rent_charges.due_at").
+		Limit(limit).
+		Find(&charges).Error
+	return charges, err
+}
+
+// ApplyLateFee marks the charge as checked and posts txn, the late fee, if
+// there is one.
+func (r *GormLedgerRepository) ApplyLateFee(lease *models.Lease, charge *models.RentCharge, txn *models.LedgerTransaction) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		charge.LateFeeAt = time.Now().UnixMicro()
+		if err := tx.Model(charge).Update("late_fee_at", charge.LateFeeAt).Error; err != nil {
+			return err
+		}
+		if txn == nil {
+			return nil
+		}
+
+		posted, err := postTransaction(tx, txn)
+		if err != nil || !posted {
+			return err
+		}
+		return writeOutboxFor(tx, events.LateFeeApplied, events.AggregateLease, int(lease.ID), map[string]interface{}{
+			"lease_id":    lease.ID,
+			"landlord_id": lease.LandlordID,
+			"tenant_id":   lease.TenantID,
+			"charge_id":   charge.ID,
+			"amount":      txn.Amount,
+			"currency":    txn.Currency,
+		})
+	})
+}
+
+func (r *GormLedgerRepository) GetTransactions(leaseID int64) ([]models.LedgerTransaction, error) {
+	txns := []models.LedgerTransaction{}
+	err := r.DB.Preload("Entries").Where("lease_id = ?", leaseID).Order("effective_at, id").Find(&txns).Error
+	return txns, err
+}
+
+// CreatePayment saves a pending payment. If one with the same idempotency
+// key exists, payment is replaced by it and false is returned.
+func (r *GormLedgerRepository) CreatePayment(payment *models.Payment) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).
+		Create(payment)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+	return false, r.DB.Where("idempotency_key = ?", payment.IdempotencyKey).First(payment).Error
+}
+
+// CompletePayment records the provider's outcome, as of payment.UpdatedAt,
+// and for successful payments posts txn.
+func (r *GormLedgerRepository) CompletePayment(payment *models.Payment, txn *models.LedgerTransaction) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(payment).Updates(map[string]interface{}{
+			"status":         payment.Status,
+			"provider_ref":   payment.ProviderRef,
+			"failure_reason": payment.FailureReason,
+			"updated_at":     payment.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		eventType := events.PaymentFailed
+		if payment.Status == models.PaymentStatusSucceeded {
+			eventType = events.PaymentSucceeded
+			if _, err := postTransaction(tx, txn); err != nil {
+				return err
+			}
+		}
+		return writeOutboxFor(tx, eventType, events.AggregateLease, int(payment.LeaseID), payment)
+	})
+}
+
+func (r *GormLedgerRepository) GetPayments(leaseID int64) ([]models.Payment, error) {
+	payments := []models.Payment{}
+	err := r.DB.Where("lease_id = ?", leaseID).Order("created_at desc").Find(&payments).Error
+	return payments, err
+}
+
+// GetLateFeeRule returns the landlord's rule, or the default if they have
+// not set one.
+func (r *GormLedgerRepository) GetLateFeeRule(landlordID int) (*models.LateFeeRule, error) {
+	var rule models.LateFeeRule
+	err := r.DB.First(&rule, landlordID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		rule = models.DefaultLateFeeRule(landlordID)
+		return &rule, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *GormLedgerRepository) SaveLateFeeRule(rule *models.LateFeeRule) error {
+	return r.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(rule).Error
+}
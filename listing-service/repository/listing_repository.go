@@ -1,25 +1,239 @@
 package repository
 
 import (
+	"errors"
+	"real-estate-system/listing-service/events"
 	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// GormListingRepository reads and writes the listings of one tenant, the
+// default one unless it comes from ForTenant.
 type GormListingRepository struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	TenantID string
 }
 
 func NewGormListingRepository(db *gorm.DB) *GormListingRepository {
-	return &GormListingRepository{DB: db}
+	return &GormListingRepository{DB: db, TenantID: models.DefaultTenant}
+}
+
+func (r *GormListingRepository) ForTenant(tenantID string) interfaces.ListingRepository {
+	return &GormListingRepository{DB: r.DB, TenantID: tenantID}
+}
+
+// scoped limits db to the tenant's listings.
+func (r *GormListingRepository) scoped(db *gorm.DB) *gorm.DB {
+	return db.Where("tenant_id = ?", r.TenantID)
 }
 
 func (r *GormListingRepository) CreateListing(listing *models.Listing) error {
-	return r.DB.Create(listing).Error
+	listing.TenantID = r.TenantID
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(listing).Error; err != nil {
+			return err
+		}
+		return writeListingEvent(tx, events.ListingCreated, listing)
+	})
 }
 
-func (r *GormListingRepository) GetListings(page, size int) ([]models.Listing, error) {
+func (r *GormListingRepository) GetListings(filter models.ListingFilter, page, size int) ([]models.Listing, error) {
 	var listings []models.Listing
-	err := r.DB.Order("created_at desc").Limit(size).Find(&listings).Error
+	err := withMedia(applyListingFilter(r.scoped(r.DB), filter)).
+		Order("created_at desc").Limit(size).Find(&listings).Error
 	return listings, err
 }
+
+func (r *GormListingRepository) GetListing(id int) (*models.Listing, error) {
+	var listing models.Listing
+	if err := withMedia(r.scoped(r.DB)).First(&listing, id).Error; err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+func (r *GormListingRepository) UpdateListingStatus(listing *models.Listing, status string) error {
+	if listing.TenantID != r.TenantID {
+		return models.ErrListingUnavailable
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if status == models.ListingStatusArchived {
+			return archiveListing(tx, listing, time.Now().UnixMicro(), "The listing was archived")
+		}
+		return setListingStatus(tx, listing, status, time.Now().UnixMicro())
+	})
+}
+
+// archiveListing archives the listing. Conversations, negotiations and
+// applications end with it, giving reason.
+func archiveListing(tx *gorm.DB, listing *models.Listing, now int64, reason string) error {
+	if err := setListingStatus(tx, listing, models.ListingStatusArchived, now); err != nil {
+		return err
+	}
+	if err := closeThreads(tx, listing.ID); err != nil {
+		return err
+	}
+	if err := declineOffers(tx, listing.ID, 0, reason); err != nil {
+		return err
+	}
+	return rejectApplications(tx, listing.ID, 0, reason)
+}
+
+// UpdateListingPrice changes the asking price, keeping the old one in the
+// price history. Every change emits listing.price_changed and drops also
+// emit listing.price_dropped.
+func (r *GormListingRepository) UpdateListingPrice(listing *models.Listing, price int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		locked, err := lockListing(r.scoped(tx), listing.ID)
+		if err != nil {
+			return err
+		}
+		*listing = *locked
+		if listing.Price == price {
+			return nil
+		}
+
+		now := time.Now().UnixMicro()
+		change := models.ListingPriceChange{
+			ListingID: listing.ID,
+			OldPrice:  listing.Price,
+			NewPrice:  price,
+			Currency:  listing.Currency,
+			ChangedAt: now,
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return err
+		}
+
+		listing.PreviousPrice = listing.Price
+		listing.Price = price
+		listing.PriceChangedAt = now
+		listing.PriceChangePct = models.PriceChangePct(listing.PreviousPrice, price)
+		listing.UpdatedAt = now
+		err = tx.Model(listing).Updates(map[string]interface{}{
+			"price":            listing.Price,
+			"previous_price":   listing.PreviousPrice,
+			"price_changed_at": listing.PriceChangedAt,
+			"price_change_pct": listing.PriceChangePct,
+			"updated_at":       listing.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := writeListingEvent(tx, events.ListingPriceChanged, listing); err != nil {
+			return err
+		}
+		if price < listing.PreviousPrice {
+			return writeListingEvent(tx, events.ListingPriceDropped, listing)
+		}
+		return nil
+	})
+}
+
+func (r *GormListingRepository) GetPriceHistory(listingID int) ([]models.ListingPriceChange, error) {
+	history := []models.ListingPriceChange{}
+	tenantListings := r.scoped(r.DB.Model(&models.Listing{})).Select("id")
+	err := r.DB.Where("listing_id = ? AND listing_id IN (?)", listingID, tenantListings).
+		Order("changed_at, id").Find(&history).Error
+	return history, err
+}
+
+// lockListing loads the listing for update, so changes that depend on its
+// status are made one at a time.
+func lockListing(tx *gorm.DB, listingID int) (*models.Listing, error) {
+	var listing models.Listing
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&listing, listingID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrListingUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// setListingStatus changes the listing's status, noting when it leaves the
+// market and when it comes back for days-on-market statistics. A public
+// listing that is hidden still publishes the change, so subscribers drop it.
+func setListingStatus(tx *gorm.DB, listing *models.Listing, status string, now int64) error {
+	wasPublic := listing.IsPublic()
+	updates := map[string]interface{}{"status": status, "updated_at": now}
+	switch {
+	case models.IsOffMarket(status) && listing.OffMarketAt == 0:
+		listing.OffMarketAt = now
+		updates["off_market_at"] = now
+	case status == models.ListingStatusActive && listing.OffMarketAt != 0:
+		listing.ListedAt = now
+		listing.OffMarketAt = 0
+		updates["listed_at"] = now
+		updates["off_market_at"] = 0
+	}
+
+	listing.Status = status
+	listing.UpdatedAt = now
+	err := tx.Model(listing).Updates(updates).Error
+	if err != nil {
+		return err
+	}
+	if wasPublic && !listing.IsPublic() {
+		return writeOutboxFor(tx, events.ListingStatusChanged, events.AggregateListing, listing.ID, listing)
+	}
+	return writeListingEvent(tx, events.ListingStatusChanged, listing)
+}
+
+// monthlyPrice is a listing's price per month for yearly rents and its
+// price otherwise.
+const monthlyPrice = "CASE WHEN rent_period = 'year' THEN price / 12.0 ELSE price END"
+
+// priceRangesCondition matches listings whose price falls in the range for
+// their currency.
+func priceRangesCondition(db *gorm.DB, ranges []models.PriceRange) *gorm.DB {
+	cond := db.Session(&gorm.Session{NewDB: true})
+	for _, r := range ranges {
+		match := db.Session(&gorm.Session{NewDB: true}).Where("currency = ?", r.Currency)
+		if r.Min > 0 {
+			match = match.Where(monthlyPrice+" >= ?", r.Min)
+		}
+		if r.Max > 0 {
+			match = match.Where(monthlyPrice+" <= ?", r.Max)
+		}
+		cond = cond.Or(match)
+	}
+	return cond
+}
+
+func applyListingFilter(db *gorm.DB, filter models.ListingFilter) *gorm.DB {
+	if !filter.IncludeUnreviewed {
+		db = db.Where("status NOT IN ?", models.NonPublicStatuses)
+	}
+	if filter.UserID > 0 {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ListingType != "" {
+		db = db.Where("listing_type = ?", filter.ListingType)
+	}
+	if len(filter.PriceRanges) > 0 {
+		db = db.Where(priceRangesCondition(db, filter.PriceRanges))
+	} else {
+		if filter.MinPrice > 0 {
+			db = db.Where("price >= ?", filter.MinPrice)
+		}
+		if filter.MaxPrice > 0 {
+			db = db.Where("price <= ?", filter.MaxPrice)
+		}
+	}
+	if filter.PriceDroppedSince > 0 {
+		// The first change since then holds the price at that time.
+		db = db.Where("price < (SELECT h.old_price FROM listing_price_history h WHERE h.listing_id = listings.id AND h.changed_at >= ? ORDER BY h.changed_at, h.id LIMIT 1)",
+			filter.PriceDroppedSince)
+	}
+	if filter.Area != "" {
+		db = db.Where("LOWER(city) = LOWER(?) OR LOWER(district) = LOWER(?)", filter.Area, filter.Area)
+	}
+	return db
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+
+	"gorm.io/gorm"
+)
+
+type GormMediaRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormMediaRepository(db *gorm.DB) *GormMediaRepository {
+	return &GormMediaRepository{DB: db}
+}
+
+// orderPhotos loads a listing's photos in display order.
+func orderPhotos(db *gorm.DB) *gorm.DB {
+	return db.Where("type = ?", models.MediaPhoto).Order("position, id")
+}
+
+// publicAttachments loads a listing's other public attachments by type, in
+// display order.
+func publicAttachments(db *gorm.DB) *gorm.DB {
+	return db.Where("type <> ? AND private = ?", models.MediaPhoto, false).Order("type, position, id")
+}
+
+// withMedia loads listings with their photos and public attachments.
+func withMedia(db *gorm.DB) *gorm.DB {
+	return db.Preload("Photos", orderPhotos).Preload("Attachments", publicAttachments)
+}
+
+// AddMedia appends the attachment to the listing's attachments of its type.
+// The first photo becomes the cover.
+func (r *GormMediaRepository) AddMedia(item *models.ListingMedia) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockListing(tx, item.ListingID); err != nil {
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&models.ListingMedia{}).
+			Where("listing_id = ? AND type = ?", item.ListingID, item.Type).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= int64(models.MediaRules[item.Type].MaxPerListing) {
+			return models.ErrTooManyMedia
+		}
+		item.Position = int(count)
+		item.IsCover = item.Type == models.MediaPhoto && count == 0
+
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return listingUpdated(tx, item.ListingID)
+	})
+}
+
+func (r *GormMediaRepository) GetMedia(filter models.MediaFilter) ([]models.ListingMedia, error) {
+	query := r.DB.Where("listing_id = ?", filter.ListingID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if !filter.IncludePrivate {
+		query = query.Where("private = ?", false)
+	}
+
+	items := []models.ListingMedia{}
+	err := query.Order("type, position, id").Find(&items).Error
+	return items, err
+}
+
+func (r *GormMediaRepository) GetMediaItem(id int64) (*models.ListingMedia, error) {
+	var item models.ListingMedia
+	if err := r.DB.First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// DeleteMedia removes the attachment and closes the gap it leaves among its
+// type. When it was the cover, the new first photo becomes the cover.
+func (r *GormMediaRepository) DeleteMedia(item *models.ListingMedia) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockListing(tx, item.ListingID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.ListingMedia{}, item.ID).Error; err != nil {
+			return err
+		}
+
+		var rest []models.ListingMedia
+		err := tx.Where("listing_id = ? AND type = ?", item.ListingID, item.Type).
+			Order("position, id").Find(&rest).Error
+		if err != nil {
+			return err
+		}
+		ids := make([]int64, len(rest))
+		cover := int64(0)
+		for i, m := range rest {
+			ids[i] = m.ID
+			if m.IsCover {
+				cover = m.ID
+			}
+		}
+		if cover == 0 && len(ids) > 0 && item.Type == models.MediaPhoto {
+			cover = ids[0]
+		}
+		if err := arrangeMedia(tx, rest, ids, cover); err != nil {
+			return err
+		}
+		return listingUpdated(tx, item.ListingID)
+	})
+}
+
+// ReorderPhotos puts the listing's photos in the order of ids, which must
+// name each of them once.
+func (r *GormMediaRepository) ReorderPhotos(listingID int, ids []int64) ([]models.ListingMedia, error) {
+	var photos []models.ListingMedia
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockListing(tx, listingID); err != nil {
+			return err
+		}
+		if err := orderPhotos(tx.Where("listing_id = ?", listingID)).Find(&photos).Error; err != nil {
+			return err
+		}
+		if len(ids) != len(photos) {
+			return models.ErrPhotoOrder
+		}
+		cover := int64(0)
+		seen := make(map[int64]bool, len(photos))
+		for _, p := range photos {
+			seen[p.ID] = false
+			if p.IsCover {
+				cover = p.ID
+			}
+		}
+		for _, id := range ids {
+			if done, ok := seen[id]; !ok || done {
+				return models.ErrPhotoOrder
+			}
+			seen[id] = true
+		}
+
+		if err := arrangeMedia(tx, photos, ids, cover); err != nil {
+			return err
+		}
+		return listingUpdated(tx, listingID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetMedia(models.MediaFilter{ListingID: listingID, Type: models.MediaPhoto})
+}
+
+// SetCover makes the photo the cover of its listing.
+func (r *GormMediaRepository) SetCover(photo *models.ListingMedia) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockListing(tx, photo.ListingID); err != nil {
+			return err
+		}
+		err := tx.Model(&models.ListingMedia{}).Where("listing_id = ? AND type = ?", photo.ListingID, models.MediaPhoto).
+			Update("is_cover", gorm.Expr("id = ?", photo.ID)).Error
+		if err != nil {
+			return err
+		}
+		photo.IsCover = true
+		return listingUpdated(tx, photo.ListingID)
+	})
+}
+
+// arrangeMedia saves the position and cover flag of each attachment whose
+// place in ids or cover status changed.
+func arrangeMedia(tx *gorm.DB, items []models.ListingMedia, ids []int64, cover int64) error {
+	position := make(map[int64]int, len(ids))
+	for i, id := range ids {
+		position[id] = i
+	}
+	for _, m := range items {
+		if m.Position == position[m.ID] && m.IsCover == (m.ID == cover) {
+			continue
+		}
+		err := tx.Model(&models.ListingMedia{}).Where("id = ?", m.ID).Updates(map[string]interface{}{
+			"position": position[m.ID],
+			"is_cover": m.ID == cover,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listingUpdated tells subscribers that the listing's attachments changed.
+func listingUpdated(tx *gorm.DB, listingID int) error {
+	var listing models.Listing
+	if err := withMedia(tx).First(&listing, listingID).Error; err != nil {
+		return err
+	}
+	return writeListingEvent(tx, events.ListingUpdated, &listing)
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ApplicationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ApplicationRepositoryMock) CreateApplication(app *models.Application) error {
+	args := m.Called(app)
+	return args.Error(0)
+}
+
+func (m *ApplicationRepositoryMock) GetApplication(id int64) (*models.Application, error) {
+	args := m.Called(id)
+	var app *models.Application
+	if args.Get(0) != nil {
+		app = args.Get(0).(*models.Application)
+	}
+	return app, args.Error(1)
+}
+
+func (m *ApplicationRepositoryMock) GetApplications(filter models.ApplicationFilter) ([]models.Application, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.Application), args.Error(1)
+}
+
+func (m *ApplicationRepositoryMock) HasOpenApplication(listingID, applicantID int) (bool, error) {
+	args := m.Called(listingID, applicantID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *ApplicationRepositoryMock) UpdateApplicationStatus(app *models.Application, status, note string) error {
+	args := m.Called(app, status, note)
+	return args.Error(0)
+}
+
+func (m *ApplicationRepositoryMock) ApproveApplication(app *models.Application, note string) error {
+	args := m.Called(app, note)
+	return args.Error(0)
+}
+
+func (m *ApplicationRepositoryMock) GetScreeningRules(landlordID int) (*models.ScreeningRules, error) {
+	args := m.Called(landlordID)
+	var rules *models.ScreeningRules
+	if args.Get(0) != nil {
+		rules = args.Get(0).(*models.ScreeningRules)
+	}
+	return rules, args.Error(1)
+}
+
+func (m *ApplicationRepositoryMock) SaveScreeningRules(rules *models.ScreeningRules) error {
+	args := m.Called(rules)
+	return args.Error(0)
+}
@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type DuplicateRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *DuplicateRepositoryMock) GetCandidates(listing *models.Listing, radius float64, limit int) ([]models.Listing, error) {
+	args := m.Called(listing, radius, limit)
+	var listings []models.Listing
+	if args.Get(0) != nil {
+		listings = args.Get(0).([]models.Listing)
+	}
+	return listings, args.Error(1)
+}
+
+func (m *DuplicateRepositoryMock) GetListingsToScan(afterID, limit int) ([]models.Listing, error) {
+	args := m.Called(afterID, limit)
+	var listings []models.Listing
+	if args.Get(0) != nil {
+		listings = args.Get(0).([]models.Listing)
+	}
+	return listings, args.Error(1)
+}
+
+func (m *DuplicateRepositoryMock) SaveDuplicatePairs(pairs []models.DuplicatePair) error {
+	args := m.Called(pairs)
+	return args.Error(0)
+}
+
+func (m *DuplicateRepositoryMock) GetDuplicatePairs(filter models.DuplicateFilter, page, size int) ([]models.DuplicatePair, error) {
+	args := m.Called(filter, page, size)
+	var pairs []models.DuplicatePair
+	if args.Get(0) != nil {
+		pairs = args.Get(0).([]models.DuplicatePair)
+	}
+	return pairs, args.Error(1)
+}
+
+func (m *DuplicateRepositoryMock) GetDuplicatePair(id int64) (*models.DuplicatePair, error) {
+	args := m.Called(id)
+	var pair *models.DuplicatePair
+	if args.Get(0) != nil {
+		pair = args.Get(0).(*models.DuplicatePair)
+	}
+	return pair, args.Error(1)
+}
+
+func (m *DuplicateRepositoryMock) MergeDuplicatePair(pair *models.DuplicatePair, keepID, adminID int) error {
+	args := m.Called(pair, keepID, adminID)
+	return args.Error(0)
+}
+
+func (m *DuplicateRepositoryMock) DismissDuplicatePair(pair *models.DuplicatePair, adminID int) error {
+	args := m.Called(pair, adminID)
+	return args.Error(0)
+}
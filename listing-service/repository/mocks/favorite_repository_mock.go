@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type FavoriteRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *FavoriteRepositoryMock) AddFavorite(favorite *models.Favorite) (bool, error) {
+	args := m.Called(favorite)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *FavoriteRepositoryMock) RemoveFavorite(userID, listingID int) error {
+	args := m.Called(userID, listingID)
+	return args.Error(0)
+}
+
+func (m *FavoriteRepositoryMock) GetFavorites(userID int) ([]models.FavoriteListing, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.FavoriteListing), args.Error(1)
+}
+
+func (m *FavoriteRepositoryMock) CountFavorites(listingIDs []int) (map[int]int64, error) {
+	args := m.Called(listingIDs)
+	return args.Get(0).(map[int]int64), args.Error(1)
+}
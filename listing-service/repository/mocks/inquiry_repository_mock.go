@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type InquiryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *InquiryRepositoryMock) CreateInquiry(inquiry *models.Inquiry) error {
+	args := m.Called(inquiry)
+	return args.Error(0)
+}
+
+func (m *InquiryRepositoryMock) GetInquiry(id int64) (*models.Inquiry, error) {
+	args := m.Called(id)
+	var inquiry *models.Inquiry
+	if args.Get(0) != nil {
+		inquiry = args.Get(0).(*models.Inquiry)
+	}
+	return inquiry, args.Error(1)
+}
+
+func (m *InquiryRepositoryMock) GetInquiries(filter models.InquiryFilter, page, size int) ([]models.Inquiry, error) {
+	args := m.Called(filter, page, size)
+	return args.Get(0).([]models.Inquiry), args.Error(1)
+}
+
+func (m *InquiryRepositoryMock) UpdateInquiryStatus(inquiry *models.Inquiry, status string) error {
+	args := m.Called(inquiry, status)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type InteractionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *InteractionRepositoryMock) GetCompletedInteractions(userID, counterpartID int, now int64) ([]models.Interaction, error) {
+	args := m.Called(userID, counterpartID, now)
+	var interactions []models.Interaction
+	if args.Get(0) != nil {
+		interactions = args.Get(0).([]models.Interaction)
+	}
+	return interactions, args.Error(1)
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LeaseRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *LeaseRepositoryMock) CreateLease(lease *models.Lease, charges []models.RentCharge) error {
+	args := m.Called(lease, charges)
+	return args.Error(0)
+}
+
+func (m *LeaseRepositoryMock) GetLease(id int64) (*models.Lease, error) {
+	args := m.Called(id)
+	var lease *models.Lease
+	if args.Get(0) != nil {
+		lease = args.Get(0).(*models.Lease)
+	}
+	return lease, args.Error(1)
+}
+
+func (m *LeaseRepositoryMock) GetLeases(filter models.LeaseFilter) ([]models.Lease, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.Lease), args.Error(1)
+}
+
+func (m *LeaseRepositoryMock) GetRentCharges(leaseID int64) ([]models.RentCharge, error) {
+	args := m.Called(leaseID)
+	return args.Get(0).([]models.RentCharge), args.Error(1)
+}
+
+func (m *LeaseRepositoryMock) RenewLease(lease, renewal *models.Lease, charges []models.RentCharge) error {
+	args := m.Called(lease, renewal, charges)
+	return args.Error(0)
+}
+
+func (m *LeaseRepositoryMock) TerminateLease(lease *models.Lease, endsAt int64, reason string) error {
+	args := m.Called(lease, endsAt, reason)
+	return args.Error(0)
+}
+
+func (m *LeaseRepositoryMock) ExpiringLeases(before int64, limit int) ([]models.Lease, error) {
+	args := m.Called(before, limit)
+	return args.Get(0).([]models.Lease), args.Error(1)
+}
+
+func (m *LeaseRepositoryMock) FlagExpiring(lease *models.Lease) error {
+	args := m.Called(lease)
+	return args.Error(0)
+}
+
+func (m *LeaseRepositoryMock) EndedLeases(now int64, limit int) ([]models.Lease, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.Lease), args.Error(1)
+}
+
+func (m *LeaseRepositoryMock) EndLease(lease *models.Lease) error {
+	args := m.Called(lease)
+	return args.Error(0)
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LedgerRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *LedgerRepositoryMock) UnpostedRentCharges(now int64, limit int) ([]models.RentCharge, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.RentCharge), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) PostRentCharge(charge *models.RentCharge, txn *models.LedgerTransaction) error {
+	args := m.Called(charge, txn)
+	return args.Error(0)
+}
+
+func (m *LedgerRepositoryMock) LateFeeCandidates(now int64, limit int) ([]models.RentCharge, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.RentCharge), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) ApplyLateFee(lease *models.Lease, charge *models.RentCharge, txn *models.LedgerTransaction) error {
+	args := m.Called(lease, charge, txn)
+	return args.Error(0)
+}
+
+func (m *LedgerRepositoryMock) GetTransactions(leaseID int64) ([]models.LedgerTransaction, error) {
+	args := m.Called(leaseID)
+	return args.Get(0).([]models.LedgerTransaction), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) CreatePayment(payment *models.Payment) (bool, error) {
+	args := m.Called(payment)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) CompletePayment(payment *models.Payment, txn *models.LedgerTransaction) error {
+	args := m.Called(payment, txn)
+	return args.Error(0)
+}
+
+func (m *LedgerRepositoryMock) GetPayments(leaseID int64) ([]models.Payment, error) {
+	args := m.Called(leaseID)
+	return args.Get(0).([]models.Payment), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) GetLateFeeRule(landlordID int) (*models.LateFeeRule, error) {
+	args := m.Called(landlordID)
+	var rule *models.LateFeeRule
+	if args.Get(0) != nil {
+		rule = args.Get(0).(*models.LateFeeRule)
+	}
+	return rule, args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) SaveLateFeeRule(rule *models.LateFeeRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
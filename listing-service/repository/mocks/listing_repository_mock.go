@@ -2,12 +2,21 @@ package mocks
 
 import (
 	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
 
 	"github.com/stretchr/testify/mock"
 )
 
+// ListingRepositoryMock records the tenant last passed to ForTenant and
+// serves every tenant itself.
 type ListingRepositoryMock struct {
 	mock.Mock
+	TenantID string
+}
+
+func (m *ListingRepositoryMock) ForTenant(tenantID string) interfaces.ListingRepository {
+	m.TenantID = tenantID
+	return m
 }
 
 func (m *ListingRepositoryMock) CreateListing(listing *models.Listing) error {
@@ -15,7 +24,34 @@ func (m *ListingRepositoryMock) CreateListing(listing *models.Listing) error {
 	return args.Error(0)
 }
 
-func (m *ListingRepositoryMock) GetListings(page, size int) ([]models.Listing, error) {
-	args := m.Called(page, size)
+func (m *ListingRepositoryMock) GetListings(filter models.ListingFilter, page, size int) ([]models.Listing, error) {
+	args := m.Called(filter, page, size)
 	return args.Get(0).([]models.Listing), args.Error(1)
 }
+
+func (m *ListingRepositoryMock) GetListing(id int) (*models.Listing, error) {
+	args := m.Called(id)
+	var listing *models.Listing
+	if args.Get(0) != nil {
+		listing = args.Get(0).(*models.Listing)
+	}
+	return listing, args.Error(1)
+}
+
+func (m *ListingRepositoryMock) UpdateListingStatus(listing *models.Listing, status string) error {
+	args := m.Called(listing, status)
+	if args.Error(0) == nil {
+		listing.Status = status
+	}
+	return args.Error(0)
+}
+
+func (m *ListingRepositoryMock) UpdateListingPrice(listing *models.Listing, price int) error {
+	args := m.Called(listing, price)
+	return args.Error(0)
+}
+
+func (m *ListingRepositoryMock) GetPriceHistory(listingID int) ([]models.ListingPriceChange, error) {
+	args := m.Called(listingID)
+	return args.Get(0).([]models.ListingPriceChange), args.Error(1)
+}
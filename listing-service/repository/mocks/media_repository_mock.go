@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MediaRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *MediaRepositoryMock) AddMedia(item *models.ListingMedia) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MediaRepositoryMock) GetMedia(filter models.MediaFilter) ([]models.ListingMedia, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.ListingMedia), args.Error(1)
+}
+
+func (m *MediaRepositoryMock) GetMediaItem(id int64) (*models.ListingMedia, error) {
+	args := m.Called(id)
+	var item *models.ListingMedia
+	if args.Get(0) != nil {
+		item = args.Get(0).(*models.ListingMedia)
+	}
+	return item, args.Error(1)
+}
+
+func (m *MediaRepositoryMock) DeleteMedia(item *models.ListingMedia) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MediaRepositoryMock) ReorderPhotos(listingID int, ids []int64) ([]models.ListingMedia, error) {
+	args := m.Called(listingID, ids)
+	var photos []models.ListingMedia
+	if args.Get(0) != nil {
+		photos = args.Get(0).([]models.ListingMedia)
+	}
+	return photos, args.Error(1)
+}
+
+func (m *MediaRepositoryMock) SetCover(photo *models.ListingMedia) error {
+	args := m.Called(photo)
+	return args.Error(0)
+}
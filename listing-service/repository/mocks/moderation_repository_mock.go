@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ModerationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ModerationRepositoryMock) GetModerationStats(listing *models.Listing, since int64) (models.ModerationStats, error) {
+	args := m.Called(listing, since)
+	return args.Get(0).(models.ModerationStats), args.Error(1)
+}
+
+func (m *ModerationRepositoryMock) SubmitListing(listing *models.Listing, review *models.ListingReview) error {
+	args := m.Called(listing, review)
+	return args.Error(0)
+}
+
+func (m *ModerationRepositoryMock) GetReviews(filter models.ReviewFilter, page, size int) ([]models.ListingReview, error) {
+	args := m.Called(filter, page, size)
+	var reviews []models.ListingReview
+	if args.Get(0) != nil {
+		reviews = args.Get(0).([]models.ListingReview)
+	}
+	return reviews, args.Error(1)
+}
+
+func (m *ModerationRepositoryMock) GetReview(id int64) (*models.ListingReview, error) {
+	args := m.Called(id)
+	var review *models.ListingReview
+	if args.Get(0) != nil {
+		review = args.Get(0).(*models.ListingReview)
+	}
+	return review, args.Error(1)
+}
+
+func (m *ModerationRepositoryMock) ApproveListing(review *models.ListingReview, adminID int, note string) error {
+	args := m.Called(review, adminID, note)
+	return args.Error(0)
+}
+
+func (m *ModerationRepositoryMock) RejectListing(review *models.ListingReview, adminID int, note string) error {
+	args := m.Called(review, adminID, note)
+	return args.Error(0)
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type OfferRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *OfferRepositoryMock) CreateOffer(offer *models.Offer, event *models.OfferEvent) error {
+	args := m.Called(offer, event)
+	return args.Error(0)
+}
+
+func (m *OfferRepositoryMock) GetOffer(id int64) (*models.Offer, error) {
+	args := m.Called(id)
+	var offer *models.Offer
+	if args.Get(0) != nil {
+		offer = args.Get(0).(*models.Offer)
+	}
+	return offer, args.Error(1)
+}
+
+func (m *OfferRepositoryMock) GetOffers(filter models.OfferFilter) ([]models.Offer, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.Offer), args.Error(1)
+}
+
+func (m *OfferRepositoryMock) GetOfferEvents(offerID int64) ([]models.OfferEvent, error) {
+	args := m.Called(offerID)
+	return args.Get(0).([]models.OfferEvent), args.Error(1)
+}
+
+func (m *OfferRepositoryMock) HasPendingOffer(listingID, buyerID int) (bool, error) {
+	args := m.Called(listingID, buyerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *OfferRepositoryMock) UpdateOffer(offer *models.Offer, event *models.OfferEvent) error {
+	args := m.Called(offer, event)
+	return args.Error(0)
+}
+
+func (m *OfferRepositoryMock) AcceptOffer(offer *models.Offer, event *models.OfferEvent) error {
+	args := m.Called(offer, event)
+	return args.Error(0)
+}
+
+func (m *OfferRepositoryMock) ExpireOffers(now int64, limit int) (int, error) {
+	args := m.Called(now, limit)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *OutboxRepositoryMock) FetchPending(limit int) ([]models.OutboxEvent, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]models.OutboxEvent), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) MarkPublished(id int64, publishedAt int64) error {
+	args := m.Called(id, publishedAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkFailed(id int64, reason string) error {
+	args := m.Called(id, reason)
+	return args.Error(0)
+}
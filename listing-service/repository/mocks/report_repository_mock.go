@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReportRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReportRepositoryMock) CreateReport(report *models.Report, hideAfter int) (bool, error) {
+	args := m.Called(report, hideAfter)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *ReportRepositoryMock) GetReports(filter models.ReportFilter, page, size int) ([]models.Report, error) {
+	args := m.Called(filter, page, size)
+	var reports []models.Report
+	if args.Get(0) != nil {
+		reports = args.Get(0).([]models.Report)
+	}
+	return reports, args.Error(1)
+}
+
+func (m *ReportRepositoryMock) GetReport(id int64) (*models.Report, error) {
+	args := m.Called(id)
+	var report *models.Report
+	if args.Get(0) != nil {
+		report = args.Get(0).(*models.Report)
+	}
+	return report, args.Error(1)
+}
+
+func (m *ReportRepositoryMock) ResolveReports(report *models.Report, outcome string, adminID int, note string) ([]models.Report, error) {
+	args := m.Called(report, outcome, adminID, note)
+	var reports []models.Report
+	if args.Get(0) != nil {
+		reports = args.Get(0).([]models.Report)
+	}
+	return reports, args.Error(1)
+}
+
+func (m *ReportRepositoryMock) GetTrustRecord(userID int) (*models.TrustRecord, error) {
+	args := m.Called(userID)
+	var record *models.TrustRecord
+	if args.Get(0) != nil {
+		record = args.Get(0).(*models.TrustRecord)
+	}
+	return record, args.Error(1)
+}
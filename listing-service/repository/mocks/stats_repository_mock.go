@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StatsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *StatsRepositoryMock) GetAreaStats(query models.AreaStatsQuery) ([]models.AreaStats, error) {
+	args := m.Called(query)
+	var stats []models.AreaStats
+	if args.Get(0) != nil {
+		stats = args.Get(0).([]models.AreaStats)
+	}
+	return stats, args.Error(1)
+}
+
+func (m *StatsRepositoryMock) RefreshViews() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ThreadRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ThreadRepositoryMock) GetOrCreateThread(thread *models.Thread) (bool, error) {
+	args := m.Called(thread)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *ThreadRepositoryMock) GetThread(id int64) (*models.Thread, error) {
+	args := m.Called(id)
+	var thread *models.Thread
+	if args.Get(0) != nil {
+		thread = args.Get(0).(*models.Thread)
+	}
+	return thread, args.Error(1)
+}
+
+func (m *ThreadRepositoryMock) GetThreads(userID int) ([]models.ThreadSummary, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.ThreadSummary), args.Error(1)
+}
+
+func (m *ThreadRepositoryMock) GetMessages(threadID, before int64, limit int) ([]models.Message, error) {
+	args := m.Called(threadID, before, limit)
+	return args.Get(0).([]models.Message), args.Error(1)
+}
+
+func (m *ThreadRepositoryMock) PostMessage(thread *models.Thread, message *models.Message) error {
+	args := m.Called(thread, message)
+	return args.Error(0)
+}
+
+func (m *ThreadRepositoryMock) MarkRead(thread *models.Thread, userID int, messageID int64) error {
+	args := m.Called(thread, userID, messageID)
+	return args.Error(0)
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ValuationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ValuationRepositoryMock) GetComparables(subject *models.Listing, radius float64, since int64, limit int) ([]models.Listing, error) {
+	args := m.Called(subject, radius, since, limit)
+	var listings []models.Listing
+	if args.Get(0) != nil {
+		listings = args.Get(0).([]models.Listing)
+	}
+	return listings, args.Error(1)
+}
+
+func (m *ValuationRepositoryMock) GetListingsToValue(afterID, limit int) ([]models.Listing, error) {
+	args := m.Called(afterID, limit)
+	var listings []models.Listing
+	if args.Get(0) != nil {
+		listings = args.Get(0).([]models.Listing)
+	}
+	return listings, args.Error(1)
+}
+
+func (m *ValuationRepositoryMock) SaveEstimate(listing *models.Listing, valuation *models.Valuation, now int64) error {
+	args := m.Called(listing, valuation, now)
+	return args.Error(0)
+}
@@ -0,0 +1,79 @@
+package mocks
+
+import (
+	"real-estate-system/listing-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ViewingRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ViewingRepositoryMock) CreateSlot(slot *models.ViewingSlot) error {
+	args := m.Called(slot)
+	return args.Error(0)
+}
+
+func (m *ViewingRepositoryMock) GetSlot(id int64) (*models.ViewingSlot, error) {
+	args := m.Called(id)
+	var slot *models.ViewingSlot
+	if args.Get(0) != nil {
+		slot = args.Get(0).(*models.ViewingSlot)
+	}
+	return slot, args.Error(1)
+}
+
+func (m *ViewingRepositoryMock) GetSlots(agentID, listingID int, from int64) ([]models.ViewingSlot, error) {
+	args := m.Called(agentID, listingID, from)
+	return args.Get(0).([]models.ViewingSlot), args.Error(1)
+}
+
+func (m *ViewingRepositoryMock) DeleteSlot(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *ViewingRepositoryMock) BookViewing(viewing *models.Viewing, slot *models.ViewingSlot) error {
+	args := m.Called(viewing, slot)
+	return args.Error(0)
+}
+
+func (m *ViewingRepositoryMock) RescheduleViewing(viewing *models.Viewing, slot *models.ViewingSlot, startsAt, endsAt int64) error {
+	args := m.Called(viewing, slot, startsAt, endsAt)
+	return args.Error(0)
+}
+
+func (m *ViewingRepositoryMock) CancelViewing(viewing *models.Viewing, reason string) error {
+	args := m.Called(viewing, reason)
+	return args.Error(0)
+}
+
+func (m *ViewingRepositoryMock) GetViewing(id int64) (*models.Viewing, error) {
+	args := m.Called(id)
+	var viewing *models.Viewing
+	if args.Get(0) != nil {
+		viewing = args.Get(0).(*models.Viewing)
+	}
+	return viewing, args.Error(1)
+}
+
+func (m *ViewingRepositoryMock) GetViewings(userID int, from int64) ([]models.Viewing, error) {
+	args := m.Called(userID, from)
+	return args.Get(0).([]models.Viewing), args.Error(1)
+}
+
+func (m *ViewingRepositoryMock) GetAgentViewings(agentID int, from int64) ([]models.Viewing, error) {
+	args := m.Called(agentID, from)
+	return args.Get(0).([]models.Viewing), args.Error(1)
+}
+
+func (m *ViewingRepositoryMock) DueReminders(now int64, limit int) ([]models.Viewing, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.Viewing), args.Error(1)
+}
+
+func (m *ViewingRepositoryMock) MarkReminded(viewing *models.Viewing) error {
+	args := m.Called(viewing)
+	return args.Error(0)
+}
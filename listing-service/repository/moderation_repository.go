@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormModerationRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormModerationRepository(db *gorm.DB) *GormModerationRepository {
+	return &GormModerationRepository{DB: db}
+}
+
+// GetModerationStats returns the median monthly price of live listings of
+// the same type and currency in the listing's city and tenant, and how many listings
+// its owner created since then.
+func (r *GormModerationRepository) GetModerationStats(listing *models.Listing, since int64) (models.ModerationStats, error) {
+	var stats models.ModerationStats
+	if listing.City != "" {
+		var area struct {
+			Samples int
+			Median  float64
+		}
+		err := r.DB.Model(&models.Listing{}).
+			Select("count(*) AS samples, coalesce(percentile_cont(0.5) WITHIN GROUP (ORDER BY "+monthlyPrice+"), 0) AS median").
+			Where("tenant_id = ? AND lower(city) = lower(?) AND listing_type = ? AND currency = ?", listing.TenantID, listing.City, listing.ListingType, listing.Currency).
+			Where("status IN ?", []string{models.ListingStatusActive, models.ListingStatusUnderOffer, models.ListingStatusRented}).
+			Scan(&area).Error
+		if err != nil {
+			return stats, err
+		}
+		stats.AreaSamples, stats.AreaMedian = area.Samples, area.Median
+	}
+
+	var recent int64
+	err := r.DB.Model(&models.Listing{}).Where("user_id = ? AND created_at >= ?", listing.UserID, since).Count(&recent).Error
+	stats.RecentListings = int(recent)
+	return stats, err
+}
+
+// SubmitListing creates the listing held back for review. It is announced
+// as listing.created only once approved.
+func (r *GormModerationRepository) SubmitListing(listing *models.Listing, review *models.ListingReview) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		listing.Status = models.ListingStatusPendingReview
+		if err := tx.Create(listing).Error; err != nil {
+			return err
+		}
+		review.ListingID = listing.ID
+		review.OwnerID = listing.UserID
+		review.Status = models.ReviewPending
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		review.Listing = listing
+		return writeOutboxFor(tx, events.ModerationPendingReview, events.AggregateModeration, listing.ID, review)
+	})
+}
+
+// GetReviews returns reviews with their listings, oldest first, so the
+// queue is worked in order.
+func (r *GormModerationRepository) GetReviews(filter models.ReviewFilter, page, size int) ([]models.ListingReview, error) {
+	db := r.DB.Preload("Listing.Photos", orderPhotos)
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	reviews := []models.ListingReview{}
+	err := db.Order("created_at, id").Offset((page - 1) * size).Limit(size).Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *GormModerationRepository) GetReview(id int64) (*models.ListingReview, error) {
+	var review models.ListingReview
+	if err := r.DB.Preload("Listing").First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ApproveListing publishes the listing, emitting listing.created as if it
+// had just been created.
+func (r *GormModerationRepository) ApproveListing(review *models.ListingReview, adminID int, note string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockReviewedListing(tx, review)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UnixMicro()
+		listing.Status = models.ListingStatusActive
+		listing.UpdatedAt = now
+		err = tx.Model(listing).Updates(map[string]interface{}{
+			"status":     listing.Status,
+			"updated_at": listing.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := writeListingEvent(tx, events.ListingCreated, listing); err != nil {
+			return err
+		}
+		return closeReview(tx, review, listing, models.ReviewApproved, adminID, note, now)
+	})
+}
+
+// RejectListing keeps the listing hidden for good. The note tells the
+// lister why.
+func (r *GormModerationRepository) RejectListing(review *models.ListingReview, adminID int, note string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockReviewedListing(tx, review)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UnixMicro()
+		listing.Status = models.ListingStatusRejected
+		listing.UpdatedAt = now
+		err = tx.Model(listing).Updates(map[string]interface{}{
+			"status":     listing.Status,
+			"updated_at": listing.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return closeReview(tx, review, listing, models.ReviewRejected, adminID, note, now)
+	})
+}
+
+// lockReviewedListing locks the review and its listing, which must both
+// still be waiting for a decision.
+func lockReviewedListing(tx *gorm.DB, review *models.ListingReview) (*models.Listing, error) {
+	var locked models.ListingReview
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, review.ID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, models.ErrReviewClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+	if locked.Status != models.ReviewPending {
+		return nil, models.ErrReviewClosed
+	}
+
+	listing, err := lockListing(tx, review.ListingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status != models.ListingStatusPendingReview {
+		return nil, models.ErrReviewClosed
+	}
+	return listing, nil
+}
+
+func closeReview(tx *gorm.DB, review *models.ListingReview, listing *models.Listing, status string, adminID int, note string, now int64) error {
+	review.Status = status
+	review.Note = note
+	review.ReviewedBy = adminID
+	review.ReviewedAt = now
+	err := tx.Model(review).Updates(map[string]interface{}{
+		"status":      review.Status,
+		"note":        review.Note,
+		"reviewed_by": review.ReviewedBy,
+		"reviewed_at": review.ReviewedAt,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	review.Listing = listing
+	eventType := events.ModerationApproved
+	if status == models.ReviewRejected {
+		eventType = events.ModerationRejected
+	}
+	return writeOutboxFor(tx, eventType, events.AggregateModeration, listing.ID, review)
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormOfferRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormOfferRepository(db *gorm.DB) *GormOfferRepository {
+	return &GormOfferRepository{DB: db}
+}
+
+// writeOfferEvent appends event to the offer's history and publishes the
+// offer's new state as "offer.<action>".
+func writeOfferEvent(tx *gorm.DB, offer *models.Offer, event *models.OfferEvent) error {
+	event.OfferID = offer.ID
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+	return writeOutboxFor(tx, events.OfferEventPrefix+event.Action, events.AggregateOffer, int(offer.ID), offer)
+}
+
+// saveOffer writes the offer's current terms if nobody changed it since it
+// was loaded, and bumps its version.
+func saveOffer(tx *gorm.DB, offer *models.Offer) error {
+	result := tx.Model(&models.Offer{}).
+		Where("id = ? AND version = ?", offer.ID, offer.Version).
+		Updates(map[string]interface{}{
+			"amount":      offer.Amount,
+			"conditions":  offer.Conditions,
+			"status":      offer.Status,
+			"awaiting_id": offer.AwaitingID,
+			"expires_at":  offer.ExpiresAt,
+			"version":     offer.Version + 1,
+			"updated_at":  offer.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrOfferChanged
+	}
+	offer.Version++
+	return nil
+}
+
+// closeOffer ends a pending offer without a party acting on it.
+func closeOffer(tx *gorm.DB, offer *models.Offer, status, action, note string) error {
+	now := time.Now().UnixMicro()
+	offer.Status = status
+	offer.AwaitingID = 0
+	offer.UpdatedAt = now
+	if err := saveOffer(tx, offer); err != nil {
+		return err
+	}
+	return writeOfferEvent(tx, offer, &models.OfferEvent{
+		Action:     action,
+		Amount:     offer.Amount,
+		Conditions: offer.Conditions,
+		ExpiresAt:  offer.ExpiresAt,
+		Note:       note,
+		CreatedAt:  now,
+	})
+}
+
+// declineOffers declines the listing's pending offers except exceptID.
+func declineOffers(tx *gorm.DB, listingID int, exceptID int64, note string) error {
+	var offers []models.Offer
+	err := tx.Where("listing_id = ? AND status = ? AND id <> ?", listingID, models.OfferStatusPending, exceptID).
+		Find(&offers).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range offers {
+		if err := closeOffer(tx, &offers[i], models.OfferStatusDeclined, models.OfferActionDecline, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormOfferRepository) CreateOffer(offer *models.Offer, event *models.OfferEvent) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(offer).Error; err != nil {
+			return err
+		}
+		return writeOfferEvent(tx, offer, event)
+	})
+}
+
+func (r *GormOfferRepository) GetOffer(id int64) (*models.Offer, error) {
+	var offer models.Offer
+	if err := r.DB.First(&offer, id).Error; err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+func (r *GormOfferRepository) GetOffers(filter models.OfferFilter) ([]models.Offer, error) {
+	db := r.DB.Where("buyer_id = ? OR seller_id = ?", filter.UserID, filter.UserID)
+	if filter.ListingID > 0 {
+		db = db.Where("listing_id = ?", filter.ListingID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	offers := []models.Offer{}
+	err := db.Order("updated_at desc").Find(&offers).Error
+	return offers, err
+}
+
+// GetOfferEvents returns the negotiation history, oldest first.
+func (r *GormOfferRepository) GetOfferEvents(offerID int64) ([]models.OfferEvent, error) {
+	history := []models.OfferEvent{}
+	err := r.DB.Where("offer_id = ?", offerID).Order("id").Find(&history).Error
+	return history, err
+}
+
+func (r *GormOfferRepository) HasPendingOffer(listingID, buyerID int) (bool, error) {
+	var count int64
+	err := r.DB.Model(&models.Offer{}).
+		Where("listing_id = ? AND buyer_id = ? AND status = ?", listingID, buyerID, models.OfferStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateOffer saves a counter, rejection or withdrawal together with its
+// history entry.
+func (r *GormOfferRepository) UpdateOffer(offer *models.Offer, event *models.OfferEvent) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveOffer(tx, offer); err != nil {
+			return err
+		}
+		return writeOfferEvent(tx, offer, event)
+	})
+}
+
+// AcceptOffer accepts the offer, puts the listing under offer and declines
+// the competing offers. The listing row is locked so two offers cannot be
+// accepted at once.
+func (r *GormOfferRepository) AcceptOffer(offer *models.Offer, event *models.OfferEvent) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockListing(tx, offer.ListingID)
+		if err != nil {
+			return err
+		}
+		if listing.Status != models.ListingStatusActive {
+			return models.ErrListingUnavailable
+		}
+
+		if err := saveOffer(tx, offer); err != nil {
+			return err
+		}
+		if err := writeOfferEvent(tx, offer, event); err != nil {
+			return err
+		}
+		if err := setListingStatus(tx, listing, models.ListingStatusUnderOffer, offer.UpdatedAt); err != nil {
+			return err
+		}
+
+		return declineOffers(tx, listing.ID, offer.ID, "Another offer was accepted")
+	})
+}
+
+// ExpireOffers expires up to limit pending offers past their deadline and
+// returns how many it expired. Offers changed concurrently are skipped.
+func (r *GormOfferRepository) ExpireOffers(now int64, limit int) (int, error) {
+	var due []models.Offer
+	err := r.DB.Where("status = ? AND expires_at <= ?", models.OfferStatusPending, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range due {
+		err := r.DB.Transaction(func(tx *gorm.DB) error {
+			return closeOffer(tx, &due[i], models.OfferStatusExpired, models.OfferActionExpire, "")
+		})
+		if errors.Is(err, models.ErrOfferChanged) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+
+	"gorm.io/gorm"
+)
+
+type GormOutboxRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormOutboxRepository(db *gorm.DB) *GormOutboxRepository {
+	return &GormOutboxRepository{DB: db}
+}
+
+func (r *GormOutboxRepository) FetchPending(limit int) ([]models.OutboxEvent, error) {
+	var pending []models.OutboxEvent
+	err := r.DB.Where("published_at = ?", 0).Order("id asc").Limit(limit).Find(&pending).Error
+	return pending, err
+}
+
+func (r *GormOutboxRepository) MarkPublished(id int64, publishedAt int64) error {
+	return r.DB.Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Update("published_at", publishedAt).Error
+}
+
+func (r *GormOutboxRepository) MarkFailed(id int64, reason string) error {
+	return r.DB.Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}).Error
+}
+
+// writeListingEvent records a listing event on tx so it commits or rolls
+// back together with the mutation it describes. Listings pending review,
+// rejected or hidden are not public, so their changes are not published;
+// subscribers learn of a listing when it is approved.
+func writeListingEvent(tx *gorm.DB, eventType string, listing *models.Listing) error {
+	if !listing.IsPublic() {
+		return nil
+	}
+	return writeOutboxFor(tx, eventType, events.AggregateListing, listing.ID, listing)
+}
+
+// writeOutboxFor records an event of any aggregate on tx.
+func writeOutboxFor(tx *gorm.DB, eventType, aggregateType string, aggregateID int, payload interface{}) error {
+	event, err := events.NewOutboxEvent(eventType, aggregateType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
+}
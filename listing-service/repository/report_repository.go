@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormReportRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormReportRepository(db *gorm.DB) *GormReportRepository {
+	return &GormReportRepository{DB: db}
+}
+
+// CreateReport records the report, or loads the reporter's earlier report
+// of the same target and returns false. An active listing is hidden once
+// hideAfter users have open reports about it; zero never hides.
+func (r *GormReportRepository) CreateReport(report *models.Report, hideAfter int) (bool, error) {
+	created := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var listing *models.Listing
+		if report.TargetType == models.ReportTargetListing {
+			// Serializes reports of the listing, so it is hidden once.
+			locked, err := lockListing(tx, report.TargetID)
+			if err != nil {
+				return err
+			}
+			listing = locked
+		}
+
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "target_type"}, {Name: "target_id"}, {Name: "reporter_id"}},
+			DoNothing: true,
+		}).Create(report)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Where("target_type = ? AND target_id = ? AND reporter_id = ?", report.TargetType, report.TargetID, report.ReporterID).
+				First(report).Error
+		}
+		created = true
+
+		if err := addToTrustRecord(tx, report.SubjectID, report.CreatedAt, models.TrustRecord{ReportsReceived: 1}); err != nil {
+			return err
+		}
+
+		if listing == nil || hideAfter <= 0 || listing.Status != models.ListingStatusActive {
+			return nil
+		}
+		var open int64
+		err := tx.Model(&models.Report{}).
+			Where("target_type = ? AND target_id = ? AND status = ?", models.ReportTargetListing, listing.ID, models.ReportOpen).
+			Count(&open).Error
+		if err != nil || open < int64(hideAfter) {
+			return err
+		}
+		return setListingStatus(tx, listing, models.ListingStatusHidden, report.CreatedAt)
+	})
+	return created, err
+}
+
+// GetReports returns reports oldest first, so the queue is worked in order.
+func (r *GormReportRepository) GetReports(filter models.ReportFilter, page, size int) ([]models.Report, error) {
+	db := r.DB
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		db = db.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		db = db.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.SubjectID != 0 {
+		db = db.Where("subject_id = ?", filter.SubjectID)
+	}
+
+	reports := []models.Report{}
+	err := db.Order("created_at, id").Offset((page - 1) * size).Limit(size).Find(&reports).Error
+	return reports, err
+}
+
+func (r *GormReportRepository) GetReport(id int64) (*models.Report, error) {
+	var report models.Report
+	if err := r.DB.First(&report, id).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ResolveReports resolves every open report about the report's target with
+// the same outcome and records it on the subject's trust record. Upheld
+// listing reports archive the listing; dismissing them shows a hidden
+// listing again.
+func (r *GormReportRepository) ResolveReports(report *models.Report, outcome string, adminID int, note string) ([]models.Report, error) {
+	var resolved []models.Report
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenReport(tx, report); err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("target_type = ? AND target_id = ? AND status = ?", report.TargetType, report.TargetID, models.ReportOpen).
+			Order("id").Find(&resolved).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UnixMicro()
+		ids := make([]int64, len(resolved))
+		for i := range resolved {
+			resolved[i].Status = outcome
+			resolved[i].Note = note
+			resolved[i].ResolvedBy = adminID
+			resolved[i].ResolvedAt = now
+			resolved[i].UpdatedAt = now
+			ids[i] = resolved[i].ID
+		}
+		err = tx.Model(&models.Report{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":      outcome,
+			"note":        note,
+			"resolved_by": adminID,
+			"resolved_at": now,
+			"updated_at":  now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		delta := models.TrustRecord{ReportsDismissed: len(resolved)}
+		if outcome == models.ReportUpheld {
+			delta = models.TrustRecord{ReportsUpheld: len(resolved), LastUpheldAt: now}
+		}
+
+		if report.TargetType == models.ReportTargetListing {
+			listing, err := lockListing(tx, report.TargetID)
+			if err != nil {
+				return err
+			}
+			switch {
+			case outcome == models.ReportUpheld && listing.Status != models.ListingStatusArchived:
+				if err := archiveListing(tx, listing, now, "The listing was removed after reports from other users"); err != nil {
+					return err
+				}
+				delta.ListingsRemoved = 1
+			case outcome == models.ReportDismissed && listing.Status == models.ListingStatusHidden:
+				if err := setListingStatus(tx, listing, models.ListingStatusActive, now); err != nil {
+					return err
+				}
+			}
+		}
+		return addToTrustRecord(tx, report.SubjectID, now, delta)
+	})
+	return resolved, err
+}
+
+func lockOpenReport(tx *gorm.DB, report *models.Report) error {
+	var locked models.Report
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, report.ID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ErrReportResolved
+	}
+	if err != nil {
+		return err
+	}
+	if locked.Status != models.ReportOpen {
+		return models.ErrReportResolved
+	}
+	return nil
+}
+
+// GetTrustRecord returns the user's trust record, all zeros for users never
+// reported.
+func (r *GormReportRepository) GetTrustRecord(userID int) (*models.TrustRecord, error) {
+	record := models.TrustRecord{UserID: userID}
+	err := r.DB.Where("user_id = ?", userID).Limit(1).Find(&record).Error
+	return &record, err
+}
+
+// addToTrustRecord adds the counts in delta to the user's trust record,
+// creating it if the user was never reported.
+func addToTrustRecord(tx *gorm.DB, userID int, now int64, delta models.TrustRecord) error {
+	delta.UserID = userID
+	delta.UpdatedAt = now
+	changes := map[string]interface{}{
+		"reports_received":  gorm.Expr("trust_records.reports_received + ?", delta.ReportsReceived),
+		"reports_upheld":    gorm.Expr("trust_records.reports_upheld + ?", delta.ReportsUpheld),
+		"reports_dismissed": gorm.Expr("trust_records.reports_dismissed + ?", delta.ReportsDismissed),
+		"listings_removed":  gorm.Expr("trust_records.listings_removed + ?", delta.ListingsRemoved),
+		"updated_at":        now,
+	}
+	if delta.LastUpheldAt > 0 {
+		changes["last_upheld_at"] = delta.LastUpheldAt
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(changes),
+	}).Create(&delta).Error
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+
+	"gorm.io/gorm"
+)
+
+const (
+	areaStatsView       = "listing_area_stats"
+	areaStatsWeeklyView = "listing_area_stats_weekly"
+
+	// statsListings are the public listings with their monthly price and
+	// days on market, counted to now for listings still on the market.
+	statsListings = `SELECT tenant_id, COALESCE(city, '') AS city, COALESCE(district, '') AS district,
+	COALESCE(property_type, '') AS property_type, COALESCE(listing_type, '') AS listing_type,
+	COALESCE(NULLIF(currency, ''), '` + money.DefaultCurrency + `') AS currency, status, created_at, floor_area,
+	` + monthlyPrice + ` AS monthly_price,
+	(COALESCE(NULLIF(off_market_at, 0), EXTRACT(EPOCH FROM now()) * 1000000) - COALESCE(NULLIF(listed_at, 0), created_at)) / 86400000000.0 AS days_on_market
+FROM listings WHERE status NOT IN ('pending_review', 'rejected', 'hidden')`
+
+	// The views hold every combination of city, district and property type
+	// (CUBE), told apart by grouping_set, so any grouping is a lookup.
+	statsGroups = `tenant_id, listing_type, currency, GROUPING(city, district, property_type) AS grouping_set,
+	COALESCE(city, '') AS city, COALESCE(district, '') AS district, COALESCE(property_type, '') AS property_type`
+
+	createAreaStatsView = `CREATE MATERIALIZED VIEW IF NOT EXISTS ` + areaStatsView + ` AS
+SELECT ` + statsGroups + `,
+	COUNT(*) FILTER (WHERE status = 'active') AS active_listings,
+	percentile_cont(0.25) WITHIN GROUP (ORDER BY monthly_price) FILTER (WHERE status = 'active') AS price_p25,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY monthly_price) FILTER (WHERE status = 'active') AS price_median,
+	percentile_cont(0.75) WITHIN GROUP (ORDER BY monthly_price) FILTER (WHERE status = 'active') AS price_p75,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY monthly_price / floor_area) FILTER (WHERE status = 'active' AND floor_area > 0) AS price_per_m2_median,
+	AVG(days_on_market) AS avg_days_on_market,
+	(EXTRACT(EPOCH FROM now()) * 1000000)::bigint AS refreshed_at
+FROM (` + statsListings + `) l
+GROUP BY tenant_id, listing_type, currency, CUBE(city, district, property_type)`
+
+	createAreaStatsWeeklyView = `CREATE MATERIALIZED VIEW IF NOT EXISTS ` + areaStatsWeeklyView + ` AS
+SELECT ` + statsGroups + `, week_start,
+	COUNT(*) AS new_listings,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY monthly_price) AS median_price
+FROM (SELECT s.*, (EXTRACT(EPOCH FROM date_trunc('week', to_timestamp(created_at / 1000000.0) AT TIME ZONE 'UTC')) * 1000000)::bigint AS week_start
+	FROM (` + statsListings + `) s) l
+GROUP BY tenant_id, listing_type, currency, week_start, CUBE(city, district, property_type)`
+)
+
+// cubeDimensions are the dimensions of the CUBE, in GROUPING order.
+var cubeDimensions = []string{"city", "district", "property_type"}
+
+type GormStatsRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormStatsRepository(db *gorm.DB) *GormStatsRepository {
+	return &GormStatsRepository{DB: db}
+}
+
+// CreateViews creates the area statistics views, filled from the current
+// listings, unless they exist. Their unique indexes let RefreshViews run
+// without blocking readers.
+func (r *GormStatsRepository) CreateViews() error {
+	statements := []string{
+		createAreaStatsView,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_area_stats_group ON ` + areaStatsView +
+			` (tenant_id, grouping_set, listing_type, currency, city, district, property_type)`,
+		createAreaStatsWeeklyView,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_area_stats_weekly_group ON ` + areaStatsWeeklyView +
+			` (tenant_id, grouping_set, listing_type, currency, city, district, property_type, week_start)`,
+	}
+	for _, statement := range statements {
+		if err := r.DB.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormStatsRepository) RefreshViews() error {
+	for _, view := range []string{areaStatsView, areaStatsWeeklyView} {
+		if err := r.DB.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY " + view).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type areaStatsRow struct {
+	City             string
+	District         string
+	PropertyType     string
+	ListingType      string
+	Currency         string
+	ActiveListings   int
+	PriceP25         *float64
+	PriceMedian      *float64
+	PriceP75         *float64
+	PricePerM2Median *float64
+	AvgDaysOnMarket  *float64
+	RefreshedAt      int64
+}
+
+type areaStatsWeekRow struct {
+	City         string
+	District     string
+	PropertyType string
+	ListingType  string
+	Currency     string
+	WeekStart    int64
+	NewListings  int
+	MedianPrice  *float64
+}
+
+func areaStatsKey(city, district, propertyType, listingType, currency string) string {
+	return city + "\x00" + district + "\x00" + propertyType + "\x00" + listingType + "\x00" + currency
+}
+
+// GetAreaStats reads the statistics of each group as of the last refresh,
+// largest inventory first, with their weekly series.
+func (r *GormStatsRepository) GetAreaStats(query models.AreaStatsQuery) ([]models.AreaStats, error) {
+	grouped := statsGrouping(query)
+
+	var rows []areaStatsRow
+	err := areaStatsScope(r.DB.Table(areaStatsView), query, grouped).
+		Order("active_listings DESC, city, district, property_type, listing_type, currency").
+		Limit(1000).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var weeks []areaStatsWeekRow
+	err = areaStatsScope(r.DB.Table(areaStatsWeeklyView), query, grouped).
+		Where("week_start >= ?", query.SeriesSince).Order("week_start").Scan(&weeks).Error
+	if err != nil {
+		return nil, err
+	}
+	series := map[string][]models.AreaStatsWeek{}
+	for _, week := range weeks {
+		key := areaStatsKey(week.City, week.District, week.PropertyType, week.ListingType, week.Currency)
+		series[key] = append(series[key], models.AreaStatsWeek{
+			WeekStart:   week.WeekStart,
+			NewListings: week.NewListings,
+			MedianPrice: week.MedianPrice,
+		})
+	}
+
+	stats := make([]models.AreaStats, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		group := models.AreaStats{
+			ListingType:      row.ListingType,
+			Currency:         row.Currency,
+			ActiveListings:   row.ActiveListings,
+			PriceP25:         row.PriceP25,
+			PriceMedian:      row.PriceMedian,
+			PriceP75:         row.PriceP75,
+			PricePerM2Median: row.PricePerM2Median,
+			AvgDaysOnMarket:  row.AvgDaysOnMarket,
+			Series:           series[areaStatsKey(row.City, row.District, row.PropertyType, row.ListingType, row.Currency)],
+			RefreshedAt:      row.RefreshedAt,
+		}
+		if grouped["city"] {
+			group.City = &row.City
+		}
+		if grouped["district"] {
+			group.District = &row.District
+		}
+		if grouped["property_type"] {
+			group.PropertyType = &row.PropertyType
+		}
+		if group.Series == nil {
+			group.Series = []models.AreaStatsWeek{}
+		}
+		newListings := 0
+		for _, week := range group.Series {
+			newListings += week.NewListings
+		}
+		if query.Weeks > 0 {
+			group.NewListingsPerWeek = float64(newListings) / float64(query.Weeks)
+		}
+		stats = append(stats, group)
+	}
+	return stats, nil
+}
+
+// statsGrouping returns the cube dimensions the query groups by, including
+// those it filters on.
+func statsGrouping(query models.AreaStatsQuery) map[string]bool {
+	grouped := map[string]bool{
+		"city":          query.City != "",
+		"district":      query.District != "",
+		"property_type": query.PropertyType != "",
+	}
+	for _, dimension := range query.GroupBy {
+		if _, ok := grouped[dimension]; ok {
+			grouped[dimension] = true
+		}
+	}
+	return grouped
+}
+
+func areaStatsScope(db *gorm.DB, query models.AreaStatsQuery, grouped map[string]bool) *gorm.DB {
+	// GROUPING sets the bit of every dimension that is rolled up, the first
+	// dimension being the most significant.
+	groupingSet := 0
+	for _, dimension := range cubeDimensions {
+		groupingSet <<= 1
+		if !grouped[dimension] {
+			groupingSet |= 1
+		}
+	}
+
+	db = db.Where("tenant_id = ? AND grouping_set = ?", query.TenantID, groupingSet)
+	if query.City != "" {
+		db = db.Where("LOWER(city) = LOWER(?)", query.City)
+	}
+	if query.District != "" {
+		db = db.Where("LOWER(district) = LOWER(?)", query.District)
+	}
+	if query.PropertyType != "" {
+		db = db.Where("property_type = ?", query.PropertyType)
+	}
+	if query.ListingType != "" {
+		db = db.Where("listing_type = ?", query.ListingType)
+	}
+	if query.Currency != "" {
+		db = db.Where("currency = ?", query.Currency)
+	}
+	return db
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproveApplication_RentsListingAndRejectsOthers(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormApplicationRepository(db)
+
+	app := &models.Application{ID: 4, ListingID: 7, ApplicantID: 5, LandlordID: 2, Status: "reviewing"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(7, 2, "active"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "applications" SET "decision_note"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs("", "approved", sqlmock.AnyArg(), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("application", 4, "application.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "off_market_at"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(sqlmock.AnyArg(), "rented", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 7, "listing.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "applications" WHERE listing_id = $1 AND status IN ($2,$3) AND id <> $4`)).
+		WithArgs(7, "submitted", "reviewing", int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "applicant_id", "status"}).AddRow(6, 7, 8, "submitted"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "applications" SET "decision_note"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs("Another applicant was approved", "rejected", sqlmock.AnyArg(), int64(6)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("application", 6, "application.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.ApproveApplication(app, ""))
+	assert.Equal(t, "approved", app.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetScreeningRules_DefaultsWhenUnset(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormApplicationRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "screening_rules" WHERE "screening_rules"."landlord_id" = $1`)).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"landlord_id"}))
+
+	rules, err := repo.GetScreeningRules(2)
+	assert.NoError(t, err)
+	assert.Equal(t, models.DefaultScreeningRules(2), *rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCandidates_SameCityOrNearby(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormDuplicateRepository(db)
+
+	listing := &models.Listing{ID: 9, TenantID: "acme", ListingType: "sale", City: "Jakarta", Latitude: -6.2607, Longitude: 106.8137}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE (tenant_id = $1 AND id <> $2 AND listing_type = $3 AND status <> $4) AND (lower(city) = lower($5) OR (latitude BETWEEN $6 AND $7 AND longitude BETWEEN $8 AND $9)) ORDER BY id desc LIMIT $10`)).
+		WithArgs("acme", 9, "sale", "archived", "Jakarta", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 200).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE "listing_media"."listing_id" = $1 AND type = $2 ORDER BY position, id`)).
+		WithArgs(4, "photo").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id"}))
+
+	listings, err := repo.GetCandidates(listing, 500, 200)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveDuplicatePairs_KeepsResolvedPairs(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormDuplicateRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "duplicate_pairs" ("listing_id","duplicate_id","score","signals","status","kept_listing_id","resolved_by","resolved_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) ON CONFLICT ("listing_id","duplicate_id") DO UPDATE SET "score"="excluded"."score","signals"="excluded"."signals","updated_at"="excluded"."updated_at" WHERE "duplicate_pairs"."status" = $11 RETURNING "id"`)).
+		WithArgs(4, 9, 0.9, `{"geo":1}`, "pending", 0, 0, 0, 100, 100, "pending").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := repo.SaveDuplicatePairs([]models.DuplicatePair{{
+		ListingID: 4, DuplicateID: 9, Score: 0.9, Signals: map[string]float64{"geo": 1},
+		Status: models.DuplicatePending, CreatedAt: 100, UpdatedAt: 100,
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.SaveDuplicatePairs(nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMergeDuplicatePair_ArchivesTheOtherListing(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormDuplicateRepository(db)
+
+	pair := &models.DuplicatePair{ID: 3, ListingID: 4, DuplicateID: 9, Status: models.DuplicatePending}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "duplicate_pairs" WHERE "duplicate_pairs"."id" = $1 ORDER BY "duplicate_pairs"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(int64(3), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "duplicate_id", "status"}).AddRow(3, 4, 9, "pending"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(9, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(9, "active"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "off_market_at"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(sqlmock.AnyArg(), "archived", sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 9, "listing.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "threads"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "offers"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "applications"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "duplicate_pairs" SET "kept_listing_id"=$1,"resolved_at"=$2,"resolved_by"=$3,"status"=$4,"updated_at"=$5 WHERE "id" = $6`)).
+		WithArgs(4, sqlmock.AnyArg(), 1, "merged", sqlmock.AnyArg(), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.MergeDuplicatePair(pair, 4, 1))
+	assert.Equal(t, models.DuplicateMerged, pair.Status)
+	assert.Equal(t, 4, pair.KeptListingID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDismissDuplicatePair_AlreadyResolved(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormDuplicateRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "duplicate_pairs"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(3, "merged"))
+	mock.ExpectRollback()
+
+	err := repo.DismissDuplicatePair(&models.DuplicatePair{ID: 3}, 1)
+	assert.ErrorIs(t, err, models.ErrDuplicateResolved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
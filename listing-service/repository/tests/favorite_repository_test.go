@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddFavorite_Idempotent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormFavoriteRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "favorites" ("user_id","listing_id","saved_price","created_at") VALUES ($1,$2,$3,$4) ON CONFLICT DO NOTHING RETURNING "id"`)).
+		WithArgs(5, 7, 3000, int64(100)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	created, err := repo.AddFavorite(&models.Favorite{UserID: 5, ListingID: 7, SavedPrice: 3000, CreatedAt: 100})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFavorites_FlagsUnavailable(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormFavoriteRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "favorites" WHERE user_id = $1 ORDER BY created_at desc`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "listing_id", "saved_price", "created_at"}).
+			AddRow(1, 5, 7, 3500, 300).
+			AddRow(2, 5, 8, 4000, 200).
+			AddRow(3, 5, 9, 0, 100).
+			AddRow(4, 5, 10, 5000, 50))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE id IN ($1,$2,$3,$4)`)).
+		WithArgs(7, 8, 9, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price", "status"}).
+			AddRow(7, 3000, "active").
+			AddRow(8, 4000, "archived").
+			AddRow(10, 4500, "hidden"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE "listing_media"."listing_id" IN ($1,$2,$3) AND (type <> $4 AND private = $5) ORDER BY type, position, id`)).
+		WithArgs(7, 8, 10, "photo", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE "listing_media"."listing_id" IN ($1,$2,$3) AND type = $4 ORDER BY position, id`)).
+		WithArgs(7, 8, 10, "photo").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id"}))
+
+	favorites, err := repo.GetFavorites(5)
+	assert.NoError(t, err)
+	assert.Len(t, favorites, 4)
+	assert.False(t, favorites[0].NoLongerAvailable)
+	assert.True(t, favorites[0].PriceDropped)
+	assert.True(t, favorites[1].NoLongerAvailable)
+	assert.False(t, favorites[1].PriceDropped)
+	assert.True(t, favorites[2].NoLongerAvailable) // deleted
+	assert.Nil(t, favorites[2].Listing)
+	assert.True(t, favorites[3].NoLongerAvailable) // hidden
+	assert.Nil(t, favorites[3].Listing)
+	assert.False(t, favorites[3].PriceDropped)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountFavorites(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormFavoriteRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT listing_id, COUNT(*) AS count FROM "favorites" WHERE listing_id IN ($1,$2) GROUP BY "listing_id"`)).
+		WithArgs(7, 8).
+		WillReturnRows(sqlmock.NewRows([]string{"listing_id", "count"}).AddRow(7, 4))
+
+	counts, err := repo.CountFavorites([]int{7, 8})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), counts[7])
+	assert.Equal(t, int64(0), counts[8])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
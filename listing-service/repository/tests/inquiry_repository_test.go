@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateInquiry_WritesOutboxWithoutContactDetails(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormInquiryRepository(db)
+
+	inquiry := &models.Inquiry{
+		TenantID: "acme", ListingID: 7, OwnerID: 2, BuyerID: 5, Status: "new",
+		Email: "buyer@example.com", Message: "Is it still available?",
+		PreferredContact: "email", Source: "web", CreatedAt: 100, UpdatedAt: 100,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "inquiries"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("inquiry", 3, "inquiry.created",
+			`{"id":3,"tenant_id":"acme","listing_id":7,"owner_id":2,"buyer_id":5,"status":"new","source":"web"}`,
+			0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.CreateInquiry(inquiry))
+	assert.Equal(t, int64(3), inquiry.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInquiries_FiltersByStatusAndPages(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormInquiryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inquiries" WHERE owner_id = $1 AND status = $2 ORDER BY created_at desc LIMIT $3 OFFSET $4`)).
+		WithArgs(2, "new", 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id", "status"}).AddRow(3, 2, "new"))
+
+	inquiries, err := repo.GetInquiries(models.InquiryFilter{OwnerID: 2, Status: "new"}, 2, 10)
+	assert.NoError(t, err)
+	assert.Len(t, inquiries, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
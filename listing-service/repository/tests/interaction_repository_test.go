@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCompletedInteractions_LatestFirst(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormInteractionRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "inquiries" WHERE buyer_id = $1 AND owner_id = $2 AND status = $3`)).
+		WithArgs(6, 5, "closed").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "updated_at"}).AddRow(4, 7, 100))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "viewings" WHERE buyer_id = $1 AND agent_id = $2 AND status = $3 AND ends_at <= $4`)).
+		WithArgs(6, 5, "booked", int64(1000)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "ends_at"}).AddRow(2, 7, 300))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "leases" WHERE tenant_id = $1 AND landlord_id = $2`)).
+		WithArgs(6, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "starts_at"}).AddRow(9, 8, 200))
+
+	interactions, err := repo.GetCompletedInteractions(6, 5, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Interaction{
+		{Type: models.InteractionViewing, ID: 2, ListingID: 7, OccurredAt: 300},
+		{Type: models.InteractionLease, ID: 9, ListingID: 8, OccurredAt: 200},
+		{Type: models.InteractionInquiry, ID: 4, ListingID: 7, OccurredAt: 100},
+	}, interactions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
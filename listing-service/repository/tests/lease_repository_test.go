@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLease_Overlap(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormLeaseRepository(db)
+
+	lease := &models.Lease{ListingID: 7, StartsAt: 100, EndsAt: 200}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, "rented"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "leases" WHERE listing_id = $1 AND status = $2 AND starts_at < $3 AND ends_at > $4`)).
+		WithArgs(7, "active", int64(200), int64(100)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err := repo.CreateLease(lease, nil)
+	assert.ErrorIs(t, err, models.ErrLeaseOverlap)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEndLease_FreesListing(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormLeaseRepository(db)
+
+	lease := &models.Lease{ID: 3, ListingID: 7, Status: "active"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "leases" SET "status"=$1,"updated_at"=$2 WHERE id = $3 AND status = $4`)).
+		WithArgs("ended", sqlmock.AnyArg(), int64(3), "active").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("lease", 3, "lease.ended", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "leases" WHERE listing_id = $1 AND status = $2`)).
+		WithArgs(7, "active").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, "rented"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "status"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs("active", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 7, "listing.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.EndLease(lease))
+	assert.Equal(t, "ended", lease.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/ledger"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostRentCharge_AlreadyPosted(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormLedgerRepository(db)
+
+	charge := &models.RentCharge{ID: 4, LeaseID: 3, Amount: 5000}
+	txn := ledger.RentCharge(models.Lease{ID: 3, Currency: "IDR"}, *charge)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ledger_transactions"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "rent_charges" SET "posted_at"=$1 WHERE "id" = $2`)).
+		WithArgs(sqlmock.AnyArg(), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.PostRentCharge(charge, &txn))
+	assert.NotZero(t, charge.PostedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostRentCharge_RejectsUnbalanced(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormLedgerRepository(db)
+
+	txn := ledger.Transaction(3, models.LedgerRentCharge, "rent_charge:4", "Rent", money.Amount{Minor: 500, Currency: "IDR"}, 0,
+		models.AccountReceivable, models.AccountRentIncome)
+	txn.Entries[1].Amount = -400
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err := repo.PostRentCharge(&models.RentCharge{ID: 4}, &txn)
+	assert.ErrorIs(t, err, models.ErrUnbalanced)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePayment_ReturnsExistingForKey(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormLedgerRepository(db)
+
+	payment := &models.Payment{LeaseID: 3, Amount: 500, IdempotencyKey: "3:abc"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "payments"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payments" WHERE idempotency_key = $1`)).
+		WithArgs("3:abc", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "lease_id", "amount", "status"}).AddRow(9, 3, 500, "succeeded"))
+
+	created, err := repo.CreatePayment(payment)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, int64(9), payment.ID)
+	assert.Equal(t, "succeeded", payment.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
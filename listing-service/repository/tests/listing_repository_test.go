@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"errors"
 	"real-estate-system/listing-service/models"
 	"real-estate-system/listing-service/repository"
 	"regexp"
@@ -33,15 +34,21 @@ func TestCreateListing(t *testing.T) {
 	listing := &models.Listing{
 		UserID:      1,
 		Price:       500000,
+		Currency:    "IDR",
+		RentPeriod:  "month",
 		ListingType: "rent",
+		Status:      "active",
 		CreatedAt:   123456789,
 		UpdatedAt:   123456789,
 	}
 
 	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "listings" ("user_id","price","listing_type","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
-		WithArgs(listing.UserID, listing.Price, listing.ListingType, listing.CreatedAt, listing.UpdatedAt).
+		`INSERT INTO "listings" ("tenant_id","user_id","price","currency","rent_period","listing_type","property_type","city","district","description","address","latitude","longitude","bedrooms","bathrooms","floor_area","status","listed_at","off_market_at","previous_price","price_changed_at","price_change_pct","estimated_value","estimate_confidence","estimated_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27) RETURNING "id"`)).
+		WithArgs("default", listing.UserID, listing.Price, listing.Currency, listing.RentPeriod, listing.ListingType, "", listing.City, listing.District, "", "", 0.0, 0.0, 0, 0, 0, listing.Status, int64(0), int64(0), 0, 0, 0.0, 0, 0, int64(0), listing.CreatedAt, listing.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 1, "listing.created", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
 
@@ -58,14 +65,288 @@ func TestGetListings(t *testing.T) {
 		AddRow(1, 1, 100000, "sale", 123, 123).
 		AddRow(2, 2, 200000, "rent", 123, 123)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" ORDER BY created_at desc LIMIT $1`)).
-		WithArgs(2).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND status NOT IN ($2,$3,$4) ORDER BY created_at desc LIMIT $5`)).
+		WithArgs("default", "pending_review", "rejected", "hidden", 2).
 		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE "listing_media"."listing_id" IN ($1,$2) AND (type <> $3 AND private = $4) ORDER BY type, position, id`)).
+		WithArgs(1, 2, "photo", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "type", "url"}).
+			AddRow(13, 2, "virtual_tour_url", "https://tour.example.com/2"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE "listing_media"."listing_id" IN ($1,$2) AND type = $3 ORDER BY position, id`)).
+		WithArgs(1, 2, "photo").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "type", "position", "is_cover", "url"}).
+			AddRow(11, 1, "photo", 0, true, "/media/a.jpg").
+			AddRow(12, 1, "photo", 1, false, "/media/b.jpg"))
 
-	listings, err := repo.GetListings(1, 2)
+	listings, err := repo.GetListings(models.ListingFilter{}, 1, 2)
 	assert.NoError(t, err)
 	assert.Len(t, listings, 2)
 	assert.Equal(t, "sale", listings[0].ListingType)
 	assert.Equal(t, "rent", listings[1].ListingType)
+	assert.Len(t, listings[0].Photos, 2)
+	assert.True(t, listings[0].Photos[0].IsCover)
+	assert.Empty(t, listings[1].Photos)
+	assert.Len(t, listings[1].Attachments, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateListing_OutboxFailureRollsBack(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	listing := &models.Listing{UserID: 1, Price: 500000, ListingType: "rent"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WillReturnError(errors.New("outbox error"))
+	mock.ExpectRollback()
+
+	err := repo.CreateListing(listing)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetListings_WithFilter(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "price", "listing_type", "city", "district", "created_at", "updated_at"}).
+		AddRow(1, 1, 3500, "rent", "Jakarta Selatan", "Kebayoran Baru", 123, 123)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND status NOT IN ($2,$3,$4) AND listing_type = $5 AND price <= $6 AND (LOWER(city) = LOWER($7) OR LOWER(district) = LOWER($8)) ORDER BY created_at desc LIMIT $9`)).
+		WithArgs("default", "pending_review", "rejected", "hidden", "rent", 4000, "jakarta selatan", "jakarta selatan", 10).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	listings, err := repo.GetListings(models.ListingFilter{ListingType: "rent", MaxPrice: 4000, Area: "jakarta selatan"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+	assert.Equal(t, "Kebayoran Baru", listings[0].District)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetListings_PriceRangesPerCurrency(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	monthly := "CASE WHEN rent_period = 'year' THEN price / 12.0 ELSE price END"
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND status NOT IN ($2,$3,$4) AND listing_type = $5 AND ((currency = $6 AND `+monthly+` <= $7) OR (currency = $8 AND `+monthly+` >= $9 AND `+monthly+` <= $10)) ORDER BY created_at desc LIMIT $11`)).
+		WithArgs("default", "pending_review", "rejected", "hidden", "rent", "IDR", 32000000, "USD", 100, 2000, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.GetListings(models.ListingFilter{ListingType: "rent", MaxPrice: 2000, PriceRanges: []models.PriceRange{
+		{Currency: "IDR", Max: 32000000},
+		{Currency: "USD", Min: 100, Max: 2000},
+	}}, 1, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetListing(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND "listings"."id" = $2 ORDER BY "listings"."id" LIMIT $3`)).
+		WithArgs("default", 7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, "active"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE "listing_media"."listing_id" = $1 AND (type <> $2 AND private = $3) ORDER BY type, position, id`)).
+		WithArgs(7, "photo", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE "listing_media"."listing_id" = $1 AND type = $2 ORDER BY position, id`)).
+		WithArgs(7, "photo").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id"}))
+
+	listing, err := repo.GetListing(7)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, listing.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateListingStatus_ArchiveClosesThreads(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	listing := &models.Listing{ID: 7, TenantID: "default", Status: "active"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "off_market_at"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(sqlmock.AnyArg(), "archived", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 7, "listing.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "threads" WHERE listing_id = $1 AND status = $2`)).
+		WithArgs(7, "open").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "buyer_id", "owner_id", "status"}).AddRow(4, 7, 5, 2, "open"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "threads" SET "status"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs("closed", sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("thread", 4, "thread.closed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "offers" WHERE listing_id = $1 AND status = $2 AND id <> $3`)).
+		WithArgs(7, "pending", int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "applications" WHERE listing_id = $1 AND status IN ($2,$3) AND id <> $4`)).
+		WithArgs(7, "submitted", "reviewing", int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	err := repo.UpdateListingStatus(listing, "archived")
+	assert.NoError(t, err)
+	assert.Equal(t, "archived", listing.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateListingStatus_RelistingRestartsDaysOnMarket(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	listing := &models.Listing{ID: 7, TenantID: "default", Status: "rented", ListedAt: 100, OffMarketAt: 200}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "listed_at"=$1,"off_market_at"=$2,"status"=$3,"updated_at"=$4 WHERE "id" = $5`)).
+		WithArgs(sqlmock.AnyArg(), 0, "active", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 7, "listing.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := repo.UpdateListingStatus(listing, "active")
+	assert.NoError(t, err)
+	assert.Greater(t, listing.ListedAt, int64(200))
+	assert.Zero(t, listing.OffMarketAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateListingPrice_DropRecordsHistoryAndEvents(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	listing := &models.Listing{ID: 7}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND "listings"."id" = $2 ORDER BY "listings"."id" LIMIT $3 FOR UPDATE`)).
+		WithArgs("default", 7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price", "currency", "status"}).AddRow(7, 4000, "IDR", "active"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "listing_price_history" ("listing_id","old_price","new_price","currency","changed_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+		WithArgs(7, 4000, 3600, "IDR", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "previous_price"=$1,"price"=$2,"price_change_pct"=$3,"price_changed_at"=$4,"updated_at"=$5 WHERE "id" = $6`)).
+		WithArgs(4000, 3600, -10.0, sqlmock.AnyArg(), sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 7, "listing.price_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 7, "listing.price_dropped", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.UpdateListingPrice(listing, 3600))
+	assert.Equal(t, 3600, listing.Price)
+	assert.Equal(t, 4000, listing.PreviousPrice)
+	assert.Equal(t, -10.0, listing.PriceChangePct)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateListingPrice_HiddenListingPublishesNothing(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	listing := &models.Listing{ID: 7}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND "listings"."id" = $2 ORDER BY "listings"."id" LIMIT $3 FOR UPDATE`)).
+		WithArgs("default", 7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price", "currency", "status"}).AddRow(7, 4000, "IDR", "hidden"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "listing_price_history"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.UpdateListingPrice(listing, 3600))
+	assert.Equal(t, 3600, listing.Price)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetListings_PriceDroppedSince(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND status NOT IN ($2,$3,$4) AND (price < (SELECT h.old_price FROM listing_price_history h WHERE h.listing_id = listings.id AND h.changed_at >= $5 ORDER BY h.changed_at, h.id LIMIT 1)) ORDER BY created_at desc LIMIT $6`)).
+		WithArgs("default", "pending_review", "rejected", "hidden", int64(1000), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	listings, err := repo.GetListings(models.ListingFilter{PriceDroppedSince: 1000}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetListings_IncludeUnreviewed(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND user_id = $2 ORDER BY created_at desc LIMIT $3`)).
+		WithArgs("default", 3, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, "pending_review"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	listings, err := repo.GetListings(models.ListingFilter{UserID: 3, IncludeUnreviewed: true}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+	assert.Equal(t, "pending_review", listings[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetListing_OtherTenantNotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db).ForTenant("acme")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE tenant_id = $1 AND "listings"."id" = $2 ORDER BY "listings"."id" LIMIT $3`)).
+		WithArgs("acme", 7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	listing, err := repo.GetListing(7)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, listing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateListingStatus_OtherTenantUnavailable(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db).ForTenant("acme")
+
+	err := repo.UpdateListingStatus(&models.Listing{ID: 7, TenantID: "default", Status: "active"}, "archived")
+	assert.ErrorIs(t, err, models.ErrListingUnavailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPriceHistory_OnlyTenantListings(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormListingRepository(db).ForTenant("acme")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_price_history" WHERE listing_id = $1 AND listing_id IN (SELECT "id" FROM "listings" WHERE tenant_id = $2) ORDER BY changed_at, id`)).
+		WithArgs(7, "acme").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	history, err := repo.GetPriceHistory(7)
+	assert.NoError(t, err)
+	assert.Empty(t, history)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectListingUpdated expects the listing.updated event with the listing
+// and its media.
+func expectListingUpdated(mock sqlmock.Sqlmock, listingID int) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1`)).
+		WithArgs(listingID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(listingID))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", listingID, "listing.updated", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func TestAddMedia_FirstPhotoIsCover(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormMediaRepository(db)
+
+	photo := &models.ListingMedia{ListingID: 7, Type: models.MediaPhoto, URL: "/media/a.jpg"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "listing_media" WHERE listing_id = $1 AND type = $2`)).
+		WithArgs(7, "photo").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	expectListingUpdated(mock, 7)
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.AddMedia(photo))
+	assert.True(t, photo.IsCover)
+	assert.Equal(t, 0, photo.Position)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddMedia_FloorPlanIsNeverCover(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormMediaRepository(db)
+
+	plan := &models.ListingMedia{ListingID: 7, Type: models.MediaFloorPlan}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "listing_media" WHERE listing_id = $1 AND type = $2`)).
+		WithArgs(7, "floor_plan").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "listing_media"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	expectListingUpdated(mock, 7)
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.AddMedia(plan))
+	assert.False(t, plan.IsCover)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddMedia_LimitPerType(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormMediaRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "listing_media"`)).
+		WithArgs(7, "virtual_tour_url").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(models.MediaRules[models.MediaVirtualTourURL].MaxPerListing))
+	mock.ExpectRollback()
+
+	err := repo.AddMedia(&models.ListingMedia{ListingID: 7, Type: models.MediaVirtualTourURL})
+	assert.ErrorIs(t, err, models.ErrTooManyMedia)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMedia_HidesPrivateByDefault(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormMediaRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE listing_id = $1 AND type = $2 AND private = $3 ORDER BY type, position, id`)).
+		WithArgs(7, "document", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE listing_id = $1 ORDER BY type, position, id`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "private"}).AddRow(3, true))
+
+	items, err := repo.GetMedia(models.MediaFilter{ListingID: 7, Type: models.MediaDocument})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	items, err = repo.GetMedia(models.MediaFilter{ListingID: 7, IncludePrivate: true})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReorderPhotos_RequiresEveryPhoto(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormMediaRepository(db)
+
+	for _, ids := range [][]int64{{4, 3}, {4, 4, 3}, {4, 9, 3}} {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE listing_id = $1 AND type = $2 ORDER BY position, id`)).
+			WithArgs(7, "photo").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "position"}).
+				AddRow(3, 7, 0).AddRow(4, 7, 1).AddRow(5, 7, 2))
+		mock.ExpectRollback()
+
+		_, err := repo.ReorderPhotos(7, ids)
+		assert.ErrorIs(t, err, models.ErrPhotoOrder)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReorderPhotos_SavesMovedPhotos(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormMediaRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE listing_id = $1 AND type = $2 ORDER BY position, id`)).
+		WithArgs(7, "photo").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "position", "is_cover"}).
+			AddRow(3, 7, 0, true).AddRow(4, 7, 1, false).AddRow(5, 7, 2, false))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listing_media" SET "is_cover"=$1,"position"=$2 WHERE id = $3`)).
+		WithArgs(true, 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listing_media" SET "is_cover"=$1,"position"=$2 WHERE id = $3`)).
+		WithArgs(false, 0, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectListingUpdated(mock, 7)
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_media" WHERE listing_id = $1 AND type = $2 AND private = $3 ORDER BY type, position, id`)).
+		WithArgs(7, "photo", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "position"}).AddRow(4, 0).AddRow(3, 1).AddRow(5, 2))
+
+	photos, err := repo.ReorderPhotos(7, []int64{4, 3, 5})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), photos[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetCover(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormMediaRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listing_media" SET "is_cover"=id = $1 WHERE listing_id = $2 AND type = $3`)).
+		WithArgs(int64(5), 7, "photo").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	expectListingUpdated(mock, 7)
+	mock.ExpectCommit()
+
+	photo := &models.ListingMedia{ID: 5, ListingID: 7, Type: models.MediaPhoto}
+	assert.NoError(t, repo.SetCover(photo))
+	assert.True(t, photo.IsCover)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetModerationStats(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormModerationRepository(db)
+
+	listing := &models.Listing{TenantID: "default", UserID: 3, City: "Jakarta", ListingType: "rent", Currency: "IDR"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) AS samples, coalesce(percentile_cont(0.5) WITHIN GROUP (ORDER BY CASE WHEN rent_period = 'year' THEN price / 12.0 ELSE price END), 0) AS median FROM "listings" WHERE (tenant_id = $1 AND lower(city) = lower($2) AND listing_type = $3 AND currency = $4) AND status IN ($5,$6,$7)`)).
+		WithArgs("default", "Jakarta", "rent", "IDR", "active", "under_offer", "rented").
+		WillReturnRows(sqlmock.NewRows([]string{"samples", "median"}).AddRow(12, 6500000.0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "listings" WHERE user_id = $1 AND created_at >= $2`)).
+		WithArgs(3, int64(1000)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	stats, err := repo.GetModerationStats(listing, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ModerationStats{AreaMedian: 6500000, AreaSamples: 12, RecentListings: 2}, stats)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmitListing_HoldsListingForReview(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormModerationRepository(db)
+
+	listing := &models.Listing{UserID: 3, Price: 100, ListingType: "rent", Status: models.ListingStatusActive}
+	review := &models.ListingReview{Reasons: []models.ModerationReason{{Rule: models.RulePhoneNumber}}, CreatedAt: 100}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "listing_reviews" ("listing_id","owner_id","reasons","status","note","reviewed_by","reviewed_at","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING "id"`)).
+		WithArgs(9, 3, `[{"rule":"phone_number","detail":""}]`, "pending", "", 0, 0, int64(100)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("moderation", 9, "moderation.pending_review", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.SubmitListing(listing, review))
+	assert.Equal(t, models.ListingStatusPendingReview, listing.Status)
+	assert.Equal(t, 9, review.ListingID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestApproveListing_PublishesListing(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormModerationRepository(db)
+
+	review := &models.ListingReview{ID: 5, ListingID: 9, OwnerID: 3, Status: models.ReviewPending}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_reviews" WHERE "listing_reviews"."id" = $1 ORDER BY "listing_reviews"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(int64(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "status"}).AddRow(5, 9, "pending"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(9, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(9, "pending_review"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "status"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs("active", sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 9, "listing.created", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listing_reviews" SET "note"=$1,"reviewed_at"=$2,"reviewed_by"=$3,"status"=$4 WHERE "id" = $5`)).
+		WithArgs("Looks fine", sqlmock.AnyArg(), 1, "approved", int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("moderation", 9, "moderation.approved", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.ApproveListing(review, 1, "Looks fine"))
+	assert.Equal(t, models.ReviewApproved, review.Status)
+	assert.Equal(t, models.ListingStatusActive, review.Listing.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRejectListing_AlreadyReviewed(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormModerationRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_reviews"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(5, "approved"))
+	mock.ExpectRollback()
+
+	err := repo.RejectListing(&models.ListingReview{ID: 5, ListingID: 9}, 1, "Scam")
+	assert.ErrorIs(t, err, models.ErrReviewClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateOffer_StaleVersion(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormOfferRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "offers" SET`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "rejected", sqlmock.AnyArg(), 3, int64(4), 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	offer := &models.Offer{ID: 4, Status: "rejected", Version: 2}
+	err := repo.UpdateOffer(offer, &models.OfferEvent{Action: "rejected"})
+	assert.ErrorIs(t, err, models.ErrOfferChanged)
+	assert.Equal(t, 2, offer.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcceptOffer_ListingNotActive(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormOfferRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, "under_offer"))
+	mock.ExpectRollback()
+
+	err := repo.AcceptOffer(&models.Offer{ID: 4, ListingID: 7, Version: 1}, &models.OfferEvent{Action: "accepted"})
+	assert.ErrorIs(t, err, models.ErrListingUnavailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcceptOffer_DeclinesCompetingOffers(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormOfferRepository(db)
+
+	offer := &models.Offer{ID: 4, ListingID: 7, BuyerID: 5, SellerID: 2, Amount: 900, Status: "accepted", Version: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(7, 2, "active"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "offers" SET`)).
+		WithArgs(900, 0, "", int64(0), "accepted", sqlmock.AnyArg(), 4, int64(4), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "offer_events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("offer", 4, "offer.accepted", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "off_market_at"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(sqlmock.AnyArg(), "under_offer", sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 7, "listing.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "offers" WHERE listing_id = $1 AND status = $2 AND id <> $3`)).
+		WithArgs(7, "pending", int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "buyer_id", "amount", "status", "version"}).AddRow(6, 7, 8, 850, "pending", 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "offers" SET`)).
+		WithArgs(850, 0, "", int64(0), "declined", sqlmock.AnyArg(), 2, int64(6), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "offer_events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("offer", 6, "offer.declined", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.AcceptOffer(offer, &models.OfferEvent{ActorID: 2, Action: "accepted"}))
+	assert.Equal(t, 4, offer.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
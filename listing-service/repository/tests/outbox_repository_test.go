@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchPendingOutbox(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormOutboxRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "aggregate_type", "aggregate_id", "event_type", "payload", "created_at", "published_at"}).
+		AddRow(1, "listing", 7, "listing.created", `{"id":7}`, 123, 0).
+		AddRow(2, "listing", 7, "listing.updated", `{"id":7}`, 124, 0)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "outbox_events" WHERE published_at = $1 ORDER BY id asc LIMIT $2`)).
+		WithArgs(0, 50).
+		WillReturnRows(rows)
+
+	pending, err := repo.FetchPending(50)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, "listing.created", pending[0].EventType)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkOutboxPublished(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormOutboxRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_events" SET "published_at"=$1 WHERE id = $2`)).
+		WithArgs(int64(999), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.MarkPublished(1, 999)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkOutboxFailed(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormOutboxRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "outbox_events" SET "attempts"=attempts + 1,"last_error"=$1 WHERE id = $2`)).
+		WithArgs("redis down", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.MarkFailed(1, "redis down")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReport_HidesListingAtThreshold(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReportRepository(db)
+
+	report := &models.Report{TargetType: "listing", TargetID: 9, ReporterID: 5, SubjectID: 3, Reason: "scam", Status: "open", CreatedAt: 100, UpdatedAt: 100}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(9, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(9, 3, "active"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reports" ("target_type","target_id","reporter_id","subject_id","reason","details","status","note","resolved_by","resolved_at","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) ON CONFLICT ("target_type","target_id","reporter_id") DO NOTHING RETURNING "id"`)).
+		WithArgs("listing", 9, 5, 3, "scam", "", "open", "", 0, 0, int64(100), int64(100)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "trust_records" ("user_id","reports_received","reports_upheld","reports_dismissed","listings_removed","last_upheld_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("user_id") DO UPDATE SET "listings_removed"=trust_records.listings_removed + $8,"reports_dismissed"=trust_records.reports_dismissed + $9,"reports_received"=trust_records.reports_received + $10,"reports_upheld"=trust_records.reports_upheld + $11,"updated_at"=$12`)).
+		WithArgs(3, 1, 0, 0, 0, 0, int64(100), 0, 0, 1, 0, int64(100)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "reports" WHERE target_type = $1 AND target_id = $2 AND status = $3`)).
+		WithArgs("listing", 9, "open").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "status"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs("hidden", int64(100), 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("listing", 9, "listing.status_changed", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	created, err := repo.CreateReport(report, 3)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, int64(12), report.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateReport_SameReporterAgain(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReportRepository(db)
+
+	report := &models.Report{TargetType: "user", TargetID: 3, ReporterID: 5, SubjectID: 3, Reason: "spam", Status: "open"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reports"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reports" WHERE target_type = $1 AND target_id = $2 AND reporter_id = $3 ORDER BY "reports"."id" LIMIT $4`)).
+		WithArgs("user", 3, 5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reason", "status"}).AddRow(4, "harassment", "open"))
+	mock.ExpectCommit()
+
+	created, err := repo.CreateReport(report, 3)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, int64(4), report.ID)
+	assert.Equal(t, "harassment", report.Reason)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveReports_UpheldArchivesListing(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReportRepository(db)
+
+	report := &models.Report{ID: 12, TargetType: "listing", TargetID: 9, SubjectID: 3, Status: "open"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reports" WHERE "reports"."id" = $1 ORDER BY "reports"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(int64(12), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(12, "open"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reports" WHERE target_type = $1 AND target_id = $2 AND status = $3 ORDER BY id FOR UPDATE`)).
+		WithArgs("listing", 9, "open").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reporter_id", "status"}).AddRow(10, 4, "open").AddRow(12, 5, "open"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reports" SET "note"=$1,"resolved_at"=$2,"resolved_by"=$3,"status"=$4,"updated_at"=$5 WHERE id IN ($6,$7)`)).
+		WithArgs("Deposit scam", sqlmock.AnyArg(), 1, "upheld", sqlmock.AnyArg(), int64(10), int64(12)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE "listings"."id" = $1 ORDER BY "listings"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(9, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(9, "hidden"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "off_market_at"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(sqlmock.AnyArg(), "archived", sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "threads"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "offers"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "applications"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "trust_records"`)).
+		WithArgs(3, 0, 2, 0, 1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 0, 0, 2, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	resolved, err := repo.ResolveReports(report, models.ReportUpheld, 1, "Deposit scam")
+	assert.NoError(t, err)
+	assert.Len(t, resolved, 2)
+	assert.Equal(t, models.ReportUpheld, resolved[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveReports_AlreadyResolved(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReportRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reports"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(12, "dismissed"))
+	mock.ExpectRollback()
+
+	_, err := repo.ResolveReports(&models.Report{ID: 12}, models.ReportUpheld, 1, "")
+	assert.ErrorIs(t, err, models.ErrReportResolved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
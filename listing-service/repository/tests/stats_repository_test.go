@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAreaStats_ByDistrictWithinCity(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormStatsRepository(db)
+
+	// city and district are grouped, property_type is rolled up.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_area_stats" WHERE (tenant_id = $1 AND grouping_set = $2) AND LOWER(city) = LOWER($3) ORDER BY active_listings DESC`)).
+		WithArgs("", 1, "Berlin", 1000).
+		WillReturnRows(sqlmock.NewRows([]string{"city", "district", "property_type", "listing_type", "currency", "active_listings", "price_median", "refreshed_at"}).
+			AddRow("Berlin", "Mitte", "", "rent", "EUR", 12, 1500.0, 99).
+			AddRow("Berlin", "Pankow", "", "rent", "EUR", 4, nil, 99))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listing_area_stats_weekly" WHERE (tenant_id = $1 AND grouping_set = $2) AND LOWER(city) = LOWER($3) AND week_start >= $4 ORDER BY week_start`)).
+		WithArgs("", 1, "Berlin", int64(500)).
+		WillReturnRows(sqlmock.NewRows([]string{"city", "district", "property_type", "listing_type", "currency", "week_start", "new_listings", "median_price"}).
+			AddRow("Berlin", "Mitte", "", "rent", "EUR", 500, 3, 1400.0).
+			AddRow("Berlin", "Mitte", "", "rent", "EUR", 600, 5, 1600.0))
+
+	stats, err := repo.GetAreaStats(models.AreaStatsQuery{
+		GroupBy:     []string{"district"},
+		City:        "Berlin",
+		SeriesSince: 500,
+		Weeks:       2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	mitte := stats[0]
+	assert.Equal(t, "Berlin", *mitte.City)
+	assert.Equal(t, "Mitte", *mitte.District)
+	assert.Nil(t, mitte.PropertyType)
+	assert.Equal(t, 1500.0, *mitte.PriceMedian)
+	assert.Len(t, mitte.Series, 2)
+	assert.Equal(t, 4.0, mitte.NewListingsPerWeek)
+
+	pankow := stats[1]
+	assert.Nil(t, pankow.PriceMedian)
+	assert.Empty(t, pankow.Series)
+	assert.Zero(t, pankow.NewListingsPerWeek)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshViews_Concurrently(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormStatsRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta(`REFRESH MATERIALIZED VIEW CONCURRENTLY listing_area_stats`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`REFRESH MATERIALIZED VIEW CONCURRENTLY listing_area_stats_weekly`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.RefreshViews())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetThreads_UnreadCounts(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormThreadRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "threads" WHERE buyer_id = $1 OR owner_id = $2 ORDER BY last_message_at desc`)).
+		WithArgs(5, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "listing_id", "buyer_id", "owner_id"}).
+			AddRow(1, 7, 5, 2).
+			AddRow(2, 8, 9, 5))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT messages.thread_id, COUNT(*) AS unread FROM "messages" JOIN threads ON threads.id = messages.thread_id WHERE messages.sender_id <> $1`)).
+		WithArgs(5, 5, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"thread_id", "unread"}).AddRow(2, 3))
+
+	threads, err := repo.GetThreads(5)
+	assert.NoError(t, err)
+	assert.Len(t, threads, 2)
+	assert.Equal(t, int64(0), threads[0].UnreadCount)
+	assert.Equal(t, int64(3), threads[1].UnreadCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostMessage_MovesSenderReceipt(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormThreadRepository(db)
+
+	thread := &models.Thread{ID: 4, ListingID: 7, BuyerID: 5, OwnerID: 2, Status: "open"}
+	message := &models.Message{SenderID: 2, Body: "Hello", Attachments: []models.MessageAttachment{}, CreatedAt: 100}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "messages" ("thread_id","sender_id","body","attachments","created_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+		WithArgs(int64(4), 2, "Hello", "[]", int64(100)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "threads" SET "last_message_at"=$1,"owner_last_read_id"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(int64(100), int64(11), int64(100), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("thread", 4, "message.created", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.PostMessage(thread, message))
+	assert.Equal(t, int64(11), thread.OwnerLastReadID)
+	assert.Equal(t, int64(0), thread.BuyerLastReadID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkRead_ClampsToLatestMessage(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormThreadRepository(db)
+
+	thread := &models.Thread{ID: 4, ListingID: 7, BuyerID: 5, OwnerID: 2, BuyerLastReadID: 3}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(id), 0) FROM "messages" WHERE thread_id = $1`)).
+		WithArgs(int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(11))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "threads" SET "buyer_last_read_id"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs(int64(11), sqlmock.AnyArg(), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("thread", 4, "thread.read", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.MarkRead(thread, 5, 999))
+	assert.Equal(t, int64(11), thread.BuyerLastReadID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
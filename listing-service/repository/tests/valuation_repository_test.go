@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetComparables_SameTypeNearby(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormValuationRepository(db)
+
+	subject := &models.Listing{TenantID: "default", ListingType: "sale", PropertyType: "house", City: "Jakarta"}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "listings" WHERE (tenant_id = $1 AND id <> $2 AND listing_type = $3 AND status IN ($4,$5,$6)) AND (status = $7 OR off_market_at >= $8) AND lower(city) = lower($9) AND property_type = $10 ORDER BY id desc LIMIT $11`)).
+		WithArgs("default", 0, "sale", "active", "under_offer", "rented", "active", int64(500), "Jakarta", "house", 200).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).AddRow(3, 1000))
+
+	listings, err := repo.GetComparables(subject, 2000, 500, 200)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveEstimate_KeepsUpdatedAt(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormValuationRepository(db)
+
+	listing := &models.Listing{ID: 7}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "listings" SET "estimate_confidence"=$1,"estimated_at"=$2,"estimated_value"=$3 WHERE "id" = $4`)).
+		WithArgs(64, int64(900), 2000, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.SaveEstimate(listing, &models.Valuation{EstimatedValue: 2000, Confidence: 64}, 900)
+	assert.NoError(t, err)
+	assert.Equal(t, 2000, listing.EstimatedValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookViewing_ConflictWithinBuffer(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormViewingRepository(db)
+
+	slot := &models.ViewingSlot{ID: 3, AgentID: 2, BufferMinutes: 15}
+	viewing := &models.Viewing{ListingID: 7, AgentID: 2, BuyerID: 5, StartsAt: 1_000_000_000, EndsAt: 1_000_000_000 + (30 * time.Minute).Microseconds()}
+	buffer := (15 * time.Minute).Microseconds()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "viewings" WHERE (status = $1 AND id <> $2) AND ((agent_id = $3 AND starts_at < $4 AND ends_at > $5) OR (buyer_id = $6 AND starts_at < $7 AND ends_at > $8))`)).
+		WithArgs("booked", int64(0), 2, viewing.EndsAt+buffer, viewing.StartsAt-buffer, 5, viewing.EndsAt, viewing.StartsAt).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err := repo.BookViewing(viewing, slot)
+	assert.ErrorIs(t, err, models.ErrViewingConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBookViewing_SchedulesReminderAndWritesOutbox(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormViewingRepository(db)
+
+	now := time.Now().UnixMicro()
+	start := now + (48 * time.Hour).Microseconds()
+	slot := &models.ViewingSlot{ID: 3, AgentID: 2}
+	viewing := &models.Viewing{ListingID: 7, AgentID: 2, BuyerID: 5, StartsAt: start, EndsAt: start + 1800000000, Status: "booked", CreatedAt: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "viewings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "viewings"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("viewing", 9, "viewing.booked", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.BookViewing(viewing, slot))
+	assert.Equal(t, int64(3), viewing.SlotID)
+	assert.Equal(t, start-(24*time.Hour).Microseconds(), viewing.RemindAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkReminded_SkipsRescheduledReminder(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormViewingRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "viewings" SET "remind_at"=$1,"updated_at"=$2 WHERE id = $3 AND remind_at = $4`)).
+		WithArgs(0, sqlmock.AnyArg(), int64(9), int64(500)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	viewing := &models.Viewing{ID: 9, RemindAt: 500}
+	assert.NoError(t, repo.MarkReminded(viewing))
+	assert.Equal(t, int64(500), viewing.RemindAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
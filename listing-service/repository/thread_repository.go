@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormThreadRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormThreadRepository(db *gorm.DB) *GormThreadRepository {
+	return &GormThreadRepository{DB: db}
+}
+
+// threadEvent is the payload of every thread event. It names both
+// participants so real-time consumers can route it to each of them.
+type threadEvent struct {
+	ThreadID   int64           `json:"thread_id"`
+	ListingID  int             `json:"listing_id"`
+	BuyerID    int             `json:"buyer_id"`
+	OwnerID    int             `json:"owner_id"`
+	Status     string          `json:"status"`
+	Message    *models.Message `json:"message,omitempty"`
+	ReaderID   int             `json:"reader_id,omitempty"`
+	LastReadID int64           `json:"last_read_id,omitempty"`
+}
+
+func newThreadEvent(thread *models.Thread) threadEvent {
+	return threadEvent{
+		ThreadID:  thread.ID,
+		ListingID: thread.ListingID,
+		BuyerID:   thread.BuyerID,
+		OwnerID:   thread.OwnerID,
+		Status:    thread.Status,
+	}
+}
+
+func writeThreadEvent(tx *gorm.DB, eventType string, event threadEvent) error {
+	return writeOutboxFor(tx, eventType, events.AggregateThread, int(event.ThreadID), event)
+}
+
+// GetOrCreateThread loads the thread for the listing, buyer and owner in
+// thread, creating it first if there is none. It reports whether it was
+// created.
+func (r *GormThreadRepository) GetOrCreateThread(thread *models.Thread) (bool, error) {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(thread)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	err := r.DB.Where("listing_id = ? AND buyer_id = ? AND owner_id = ?", thread.ListingID, thread.BuyerID, thread.OwnerID).
+		First(thread).Error
+	return false, err
+}
+
+func (r *GormThreadRepository) GetThread(id int64) (*models.Thread, error) {
+	var thread models.Thread
+	if err := r.DB.First(&thread, id).Error; err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+// GetThreads returns the user's threads, most recently active first, with
+// the number of messages from the other side the user has not read.
+func (r *GormThreadRepository) GetThreads(userID int) ([]models.ThreadSummary, error) {
+	var threads []models.Thread
+	err := r.DB.Where("buyer_id = ? OR owner_id = ?", userID, userID).
+		Order("last_message_at desc").
+		Find(&threads).Error
+	if err != nil || len(threads) == 0 {
+		return []models.ThreadSummary{}, err
+	}
+
+	var rows []struct {
+		ThreadID int64
+		Unread   int64
+	}
+	err = r.DB.Table("messages").
+		Select("messages.thread_id, COUNT(*) AS unread").
+		Joins("JOIN threads ON threads.id = messages.thread_id").
+		Where("messages.sender_id <> ?", userID).
+		Where("(threads.buyer_id = ? AND messages.id > threads.buyer_last_read_id) OR (threads.owner_id = ? AND messages.id > threads.owner_last_read_id)", userID, userID).
+		Group("messages.thread_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	unread := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		unread[row.ThreadID] = row.Unread
+	}
+
+	summaries := make([]models.ThreadSummary, len(threads))
+	for i, thread := range threads {
+		summaries[i] = models.ThreadSummary{Thread: thread, UnreadCount: unread[thread.ID]}
+	}
+	return summaries, nil
+}
+
+// GetMessages pages backwards through a thread: up to limit messages older
+// than the message ID before, newest first. before 0 starts at the latest.
+func (r *GormThreadRepository) GetMessages(threadID, before int64, limit int) ([]models.Message, error) {
+	db := r.DB.Where("thread_id = ?", threadID)
+	if before > 0 {
+		db = db.Where("id < ?", before)
+	}
+
+	messages := []models.Message{}
+	err := db.Order("id desc").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// PostMessage stores a message and moves the sender's read receipt to it.
+func (r *GormThreadRepository) PostMessage(thread *models.Thread, message *models.Message) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		message.ThreadID = thread.ID
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+
+		thread.LastMessageAt = message.CreatedAt
+		thread.UpdatedAt = message.CreatedAt
+		updates := map[string]interface{}{
+			"last_message_at": thread.LastMessageAt,
+			"updated_at":      thread.UpdatedAt,
+		}
+		if message.SenderID == thread.OwnerID {
+			thread.OwnerLastReadID = message.ID
+			updates["owner_last_read_id"] = message.ID
+		} else {
+			thread.BuyerLastReadID = message.ID
+			updates["buyer_last_read_id"] = message.ID
+		}
+		if err := tx.Model(thread).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		event := newThreadEvent(thread)
+		event.Message = message
+		return writeThreadEvent(tx, events.MessageCreated, event)
+	})
+}
+
+// MarkRead moves the user's read receipt forward to messageID, or to the
+// latest message when messageID is 0. Receipts never move backwards or
+// past the last message.
+func (r *GormThreadRepository) MarkRead(thread *models.Thread, userID int, messageID int64) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var latest int64
+		err := tx.Model(&models.Message{}).Where("thread_id = ?", thread.ID).
+			Select("COALESCE(MAX(id), 0)").Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		if messageID == 0 || messageID > latest {
+			messageID = latest
+		}
+		if messageID <= thread.LastReadID(userID) {
+			return nil
+		}
+
+		column := "buyer_last_read_id"
+		if userID == thread.OwnerID {
+			column = "owner_last_read_id"
+			thread.OwnerLastReadID = messageID
+		} else {
+			thread.BuyerLastReadID = messageID
+		}
+		thread.UpdatedAt = time.Now().UnixMicro()
+		err = tx.Model(thread).Updates(map[string]interface{}{
+			column:       messageID,
+			"updated_at": thread.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		event := newThreadEvent(thread)
+		event.ReaderID = userID
+		event.LastReadID = messageID
+		return writeThreadEvent(tx, events.ThreadRead, event)
+	})
+}
+
+// closeThreads closes the open threads of a listing on tx.
+func closeThreads(tx *gorm.DB, listingID int) error {
+	var threads []models.Thread
+	err := tx.Where("listing_id = ? AND status = ?", listingID, models.ThreadStatusOpen).Find(&threads).Error
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMicro()
+	for i := range threads {
+		threads[i].Status = models.ThreadStatusClosed
+		threads[i].UpdatedAt = now
+		err := tx.Model(&threads[i]).Updates(map[string]interface{}{
+			"status":     threads[i].Status,
+			"updated_at": threads[i].UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := writeThreadEvent(tx, events.ThreadClosed, newThreadEvent(&threads[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"math"
+	"real-estate-system/listing-service/models"
+
+	"gorm.io/gorm"
+)
+
+// comparableStatuses are the statuses of listings whose price says what a
+// property is worth: asked for now, or agreed on.
+var comparableStatuses = []string{models.ListingStatusActive, models.ListingStatusUnderOffer, models.ListingStatusRented}
+
+type GormValuationRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormValuationRepository(db *gorm.DB) *GormValuationRepository {
+	return &GormValuationRepository{DB: db}
+}
+
+// GetComparables returns the listings of the subject's tenant, listing type
+// and property type, if it has one, in its city or within radius metres,
+// newest first. They are either active or under offer or rented since
+// since.
+func (r *GormValuationRepository) GetComparables(subject *models.Listing, radius float64, since int64, limit int) ([]models.Listing, error) {
+	near := r.DB.Session(&gorm.Session{NewDB: true})
+	if subject.City != "" {
+		near = near.Or("lower(city) = lower(?)", subject.City)
+	}
+	if subject.HasLocation() {
+		dLat := radius / metresPerDegree
+		dLon := radius / (metresPerDegree * math.Max(math.Cos(subject.Latitude*math.Pi/180), 0.01))
+		near = near.Or("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+			subject.Latitude-dLat, subject.Latitude+dLat, subject.Longitude-dLon, subject.Longitude+dLon)
+	}
+
+	query := r.DB.Where("tenant_id = ? AND id <> ? AND listing_type = ? AND status IN ?", subject.TenantID, subject.ID, subject.ListingType, comparableStatuses).
+		Where("status = ? OR off_market_at >= ?", models.ListingStatusActive, since).
+		Where(near)
+	if subject.PropertyType != "" {
+		query = query.Where("property_type = ?", subject.PropertyType)
+	}
+
+	var listings []models.Listing
+	err := query.Order("id desc").Limit(limit).Find(&listings).Error
+	return listings, err
+}
+
+// GetListingsToValue returns the active listings after afterID in ID order.
+func (r *GormValuationRepository) GetListingsToValue(afterID, limit int) ([]models.Listing, error) {
+	var listings []models.Listing
+	err := r.DB.Where("id > ? AND status = ?", afterID, models.ListingStatusActive).
+		Order("id").Limit(limit).Find(&listings).Error
+	return listings, err
+}
+
+// SaveEstimate records the listing's valuation, or clears it when valuation
+// is nil. It is not a change by the owner, so updated_at is left alone and
+// no event is emitted.
+func (r *GormValuationRepository) SaveEstimate(listing *models.Listing, valuation *models.Valuation, now int64) error {
+	listing.EstimatedValue, listing.Confidence, listing.EstimatedAt = 0, 0, now
+	if valuation != nil {
+		listing.EstimatedValue, listing.Confidence = valuation.EstimatedValue, valuation.Confidence
+	}
+	return r.DB.Model(listing).UpdateColumns(map[string]interface{}{
+		"estimated_value":     listing.EstimatedValue,
+		"estimate_confidence": listing.Confidence,
+		"estimated_at":        listing.EstimatedAt,
+	}).Error
+}
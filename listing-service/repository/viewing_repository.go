@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"real-estate-system/listing-service/events"
+	"real-estate-system/listing-service/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	reminderLead     = 24 * time.Hour
+	lateReminderLead = time.Hour
+)
+
+type GormViewingRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormViewingRepository(db *gorm.DB) *GormViewingRepository {
+	return &GormViewingRepository{DB: db}
+}
+
+// reminderAt schedules the reminder a day ahead, or an hour ahead for short
+// notice bookings. It returns 0 when even that has passed.
+func reminderAt(startsAt, now int64) int64 {
+	for _, lead := range []time.Duration{reminderLead, lateReminderLead} {
+		if at := startsAt - lead.Microseconds(); at > now {
+			return at
+		}
+	}
+	return 0
+}
+
+func writeViewingEvent(tx *gorm.DB, eventType string, viewing *models.Viewing) error {
+	return writeOutboxFor(tx, eventType, events.AggregateViewing, int(viewing.ID), viewing)
+}
+
+func (r *GormViewingRepository) CreateSlot(slot *models.ViewingSlot) error {
+	return r.DB.Create(slot).Error
+}
+
+func (r *GormViewingRepository) GetSlot(id int64) (*models.ViewingSlot, error) {
+	var slot models.ViewingSlot
+	if err := r.DB.First(&slot, id).Error; err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// GetSlots returns the agent's slots ending after from. With a listingID
+// only slots for that listing or for all of the agent's listings are kept.
+func (r *GormViewingRepository) GetSlots(agentID, listingID int, from int64) ([]models.ViewingSlot, error) {
+	db := r.DB.Where("agent_id = ? AND ends_at > ?", agentID, from)
+	if listingID > 0 {
+		db = db.Where("listing_id IN (0, ?)", listingID)
+	}
+
+	slots := []models.ViewingSlot{}
+	err := db.Order("starts_at").Find(&slots).Error
+	return slots, err
+}
+
+func (r *GormViewingRepository) DeleteSlot(id int64) error {
+	return r.DB.Delete(&models.ViewingSlot{}, id).Error
+}
+
+// lockAgent serializes bookings for one agent until tx ends, so two buyers
+// cannot both pass the conflict check for the same time.
+func lockAgent(tx *gorm.DB, agentID int) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", agentID).Error
+}
+
+// checkAvailability fails with ErrViewingConflict if the agent has another
+// booking within the slot's buffer of [startsAt, endsAt), or the buyer has
+// one overlapping it.
+func checkAvailability(tx *gorm.DB, viewing *models.Viewing, slot *models.ViewingSlot, startsAt, endsAt int64) error {
+	buffer := slot.Buffer()
+
+	var conflicts int64
+	err := tx.Model(&models.Viewing{}).
+		Where("status = ? AND id <> ?", models.ViewingStatusBooked, viewing.ID).
+		Where("(agent_id = ? AND starts_at < ? AND ends_at > ?) OR (buyer_id = ? AND starts_at < ? AND ends_at > ?)",
+			viewing.AgentID, endsAt+buffer, startsAt-buffer,
+			viewing.BuyerID, endsAt, startsAt).
+		Count(&conflicts).Error
+	if err != nil {
+		return err
+	}
+	if conflicts > 0 {
+		return models.ErrViewingConflict
+	}
+	return nil
+}
+
+func (r *GormViewingRepository) BookViewing(viewing *models.Viewing, slot *models.ViewingSlot) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockAgent(tx, viewing.AgentID); err != nil {
+			return err
+		}
+		if err := checkAvailability(tx, viewing, slot, viewing.StartsAt, viewing.EndsAt); err != nil {
+			return err
+		}
+
+		viewing.SlotID = slot.ID
+		viewing.RemindAt = reminderAt(viewing.StartsAt, viewing.CreatedAt)
+		if err := tx.Create(viewing).Error; err != nil {
+			return err
+		}
+		return writeViewingEvent(tx, events.ViewingBooked, viewing)
+	})
+}
+
+func (r *GormViewingRepository) RescheduleViewing(viewing *models.Viewing, slot *models.ViewingSlot, startsAt, endsAt int64) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockAgent(tx, viewing.AgentID); err != nil {
+			return err
+		}
+		if err := checkAvailability(tx, viewing, slot, startsAt, endsAt); err != nil {
+			return err
+		}
+
+		now := time.Now().UnixMicro()
+		viewing.SlotID = slot.ID
+		viewing.StartsAt = startsAt
+		viewing.EndsAt = endsAt
+		viewing.Sequence++
+		viewing.RemindAt = reminderAt(startsAt, now)
+		viewing.UpdatedAt = now
+		err := tx.Model(viewing).Updates(map[string]interface{}{
+			"slot_id":    viewing.SlotID,
+			"starts_at":  viewing.StartsAt,
+			"ends_at":    viewing.EndsAt,
+			"sequence":   viewing.Sequence,
+			"remind_at":  viewing.RemindAt,
+			"updated_at": viewing.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return writeViewingEvent(tx, events.ViewingRescheduled, viewing)
+	})
+}
+
+func (r *GormViewingRepository) CancelViewing(viewing *models.Viewing, reason string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		viewing.Status = models.ViewingStatusCancelled
+		viewing.CancelReason = reason
+		viewing.Sequence++
+		viewing.RemindAt = 0
+		viewing.UpdatedAt = time.Now().UnixMicro()
+		err := tx.Model(viewing).Updates(map[string]interface{}{
+			"status":        viewing.Status,
+			"cancel_reason": viewing.CancelReason,
+			"sequence":      viewing.Sequence,
+			"remind_at":     viewing.RemindAt,
+			"updated_at":    viewing.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return writeViewingEvent(tx, events.ViewingCancelled, viewing)
+	})
+}
+
+func (r *GormViewingRepository) GetViewing(id int64) (*models.Viewing, error) {
+	var viewing models.Viewing
+	if err := r.DB.First(&viewing, id).Error; err != nil {
+		return nil, err
+	}
+	return &viewing, nil
+}
+
+// GetViewings returns the viewings the user books or hosts that end after
+// from, soonest first.
+func (r *GormViewingRepository) GetViewings(userID int, from int64) ([]models.Viewing, error) {
+	viewings := []models.Viewing{}
+	err := r.DB.Where("buyer_id = ? OR agent_id = ?", userID, userID).
+		Where("ends_at > ?", from).
+		Order("starts_at").
+		Find(&viewings).Error
+	return viewings, err
+}
+
+// GetAgentViewings returns every viewing the agent hosts starting after
+// from, cancelled ones included so calendars can drop them.
+func (r *GormViewingRepository) GetAgentViewings(agentID int, from int64) ([]models.Viewing, error) {
+	viewings := []models.Viewing{}
+	err := r.DB.Where("agent_id = ? AND starts_at > ?", agentID, from).
+		Order("starts_at").
+		Find(&viewings).Error
+	return viewings, err
+}
+
+func (r *GormViewingRepository) DueReminders(now int64, limit int) ([]models.Viewing, error) {
+	viewings := []models.Viewing{}
+	err := r.DB.Where("status = ? AND remind_at > 0 AND remind_at <= ?", models.ViewingStatusBooked, now).
+		Order("remind_at").
+		Limit(limit).
+		Find(&viewings).Error
+	return viewings, err
+}
+
+// MarkReminded clears the reminder and emits viewing.reminder. A reminder
+// that was rescheduled in the meantime is left alone.
+func (r *GormViewingRepository) MarkReminded(viewing *models.Viewing) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMicro()
+		result := tx.Model(&models.Viewing{}).
+			Where("id = ? AND remind_at = ?", viewing.ID, viewing.RemindAt).
+			Updates(map[string]interface{}{"remind_at": 0, "updated_at": now})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		viewing.RemindAt = 0
+		viewing.UpdatedAt = now
+		return writeViewingEvent(tx, events.ViewingReminder, viewing)
+	})
+}
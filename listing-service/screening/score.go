@@ -0,0 +1,129 @@
+package screening
+
+import (
+	"fmt"
+	"math"
+	"real-estate-system/listing-service/models"
+)
+
+// Check is the outcome of one rule. Points is out of Weight.
+type Check struct {
+	Rule   string  `json:"rule"`
+	Passed bool    `json:"passed"`
+	Points float64 `json:"points"`
+	Weight int     `json:"weight"`
+	Detail string  `json:"detail"`
+}
+
+// Result is an application's score from 0 to 100 and how it was reached.
+type Result struct {
+	Score  int     `json:"score"`
+	Checks []Check `json:"checks"`
+}
+
+// Score rates app against rules for a listing renting at rent. Rules with
+// weight 0 are skipped; rules that fall short earn partial points.
+func Score(rules models.ScreeningRules, app models.Application, rent int) Result {
+	result := Result{Checks: []Check{}}
+	if rules.IncomeWeight > 0 {
+		result.Checks = append(result.Checks, incomeCheck(rules, app, rent))
+	}
+	if rules.EmploymentWeight > 0 {
+		result.Checks = append(result.Checks, employmentCheck(rules, app))
+	}
+	if rules.ReferencesWeight > 0 {
+		result.Checks = append(result.Checks, referencesCheck(rules, app))
+	}
+	if rules.DocumentsWeight > 0 {
+		result.Checks = append(result.Checks, documentsCheck(rules, app))
+	}
+
+	var points float64
+	var total int
+	for _, check := range result.Checks {
+		points += check.Points
+		total += check.Weight
+	}
+	if total > 0 {
+		result.Score = int(math.Round(points * 100 / float64(total)))
+	}
+	return result
+}
+
+// fraction is min(have/want, 1), or 1 when nothing is wanted.
+func fraction(have, want float64) float64 {
+	if want <= 0 {
+		return 1
+	}
+	return math.Min(have/want, 1)
+}
+
+func incomeCheck(rules models.ScreeningRules, app models.Application, rent int) Check {
+	ratio := 0.0
+	if rent > 0 {
+		ratio = float64(app.MonthlyIncome) / float64(rent)
+	}
+	share := fraction(ratio, rules.MinIncomeRatio)
+	return Check{
+		Rule:   "rent_to_income",
+		Passed: share == 1,
+		Points: share * float64(rules.IncomeWeight),
+		Weight: rules.IncomeWeight,
+		Detail: fmt.Sprintf("income is %.1fx rent, %.1fx required", ratio, rules.MinIncomeRatio),
+	}
+}
+
+// employmentCheck gives full points for steady work or a pension and half
+// for work shorter than the minimum tenure.
+func employmentCheck(rules models.ScreeningRules, app models.Application) Check {
+	share := 0.0
+	switch app.EmploymentStatus {
+	case models.EmploymentEmployed, models.EmploymentSelfEmployed:
+		share = 0.5
+		if app.EmploymentMonths >= rules.MinEmploymentMonths {
+			share = 1
+		}
+	case models.EmploymentRetired:
+		share = 1
+	}
+	return Check{
+		Rule:   "employment",
+		Passed: share == 1,
+		Points: share * float64(rules.EmploymentWeight),
+		Weight: rules.EmploymentWeight,
+		Detail: fmt.Sprintf("%s for %d months, %d required", app.EmploymentStatus, app.EmploymentMonths, rules.MinEmploymentMonths),
+	}
+}
+
+func referencesCheck(rules models.ScreeningRules, app models.Application) Check {
+	share := fraction(float64(len(app.References)), float64(rules.MinReferences))
+	return Check{
+		Rule:   "references",
+		Passed: share == 1,
+		Points: share * float64(rules.ReferencesWeight),
+		Weight: rules.ReferencesWeight,
+		Detail: fmt.Sprintf("%d of %d references", len(app.References), rules.MinReferences),
+	}
+}
+
+func documentsCheck(rules models.ScreeningRules, app models.Application) Check {
+	provided := map[string]bool{}
+	for _, doc := range app.Documents {
+		provided[doc.Kind] = true
+	}
+	have := 0
+	for _, kind := range rules.RequiredDocuments {
+		if provided[kind] {
+			have++
+		}
+	}
+
+	share := fraction(float64(have), float64(len(rules.RequiredDocuments)))
+	return Check{
+		Rule:   "documents",
+		Passed: share == 1,
+		Points: share * float64(rules.DocumentsWeight),
+		Weight: rules.DocumentsWeight,
+		Detail: fmt.Sprintf("%d of %d required documents", have, len(rules.RequiredDocuments)),
+	}
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/screening"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore_FullMarks(t *testing.T) {
+	app := models.Application{
+		MonthlyIncome:    3000,
+		EmploymentStatus: models.EmploymentEmployed,
+		EmploymentMonths: 24,
+		References:       []models.ApplicationReference{{Name: "A"}, {Name: "B"}},
+		Documents:        []models.ApplicationDocument{{Kind: models.DocumentIdentity}, {Kind: models.DocumentPayslip}},
+	}
+
+	result := screening.Score(models.DefaultScreeningRules(2), app, 1000)
+	assert.Equal(t, 100, result.Score)
+	assert.Len(t, result.Checks, 4)
+	for _, check := range result.Checks {
+		assert.True(t, check.Passed, check.Rule)
+	}
+}
+
+func TestScore_PartialPoints(t *testing.T) {
+	app := models.Application{
+		MonthlyIncome:    1500, // half the required 3x
+		EmploymentStatus: models.EmploymentEmployed,
+		EmploymentMonths: 2, // short tenure, half points
+		References:       []models.ApplicationReference{{Name: "A"}},
+		Documents:        []models.ApplicationDocument{{Kind: models.DocumentOther}},
+	}
+
+	// 50*0.5 + 20*0.5 + 15*0.5 + 15*0 = 42.5 of 100
+	result := screening.Score(models.DefaultScreeningRules(2), app, 1000)
+	assert.Equal(t, 43, result.Score)
+	assert.False(t, result.Checks[0].Passed)
+}
+
+func TestScore_IgnoresZeroWeightRules(t *testing.T) {
+	rules := models.ScreeningRules{MinIncomeRatio: 2, IncomeWeight: 1}
+	app := models.Application{MonthlyIncome: 2000, EmploymentStatus: models.EmploymentUnemployed}
+
+	result := screening.Score(rules, app, 1000)
+	assert.Equal(t, 100, result.Score)
+	assert.Len(t, result.Checks, 1)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand"
 	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
 	"time"
 
 	"gorm.io/gorm"
@@ -15,11 +16,35 @@ func randomListingType() string {
 	return listingTypes[rand.Intn(len(listingTypes))]
 }
 
-func randomPrice() int {
+// randomPrice returns an IDR price for the listing type and, for rents, the
+// rent period.
+func randomPrice(listingType string) (int, string) {
+	if listingType == "sale" {
+		return (rand.Intn(95) + 5) * 100_000_000, "" // harga dummy sale: Rp 500 juta–9,9 miliar
+	}
+	monthly := (rand.Intn(23) + 3) * 1_000_000 // harga dummy rent: Rp 3–25 juta per bulan
 	if rand.Intn(2) == 0 {
-		return rand.Intn(3000) + 2000 // harga dummy rent: 2000–4999
+		return monthly * 12, models.RentPerYear
+	}
+	return monthly, models.RentPerMonth
+}
+
+// Dummy areas as city -> districts
+var areas = map[string][]string{
+	"Jakarta Selatan": {"Kebayoran Baru", "Setiabudi", "Cilandak", "Tebet"},
+	"Jakarta Barat":   {"Kebon Jeruk", "Grogol Petamburan", "Palmerah"},
+	"Tangerang":       {"Ciledug", "Karawaci", "Cipondoh"},
+	"Bandung":         {"Coblong", "Sukajadi", "Buahbatu"},
+}
+
+func randomArea() (string, string) {
+	cities := make([]string, 0, len(areas))
+	for city := range areas {
+		cities = append(cities, city)
 	}
-	return rand.Intn(90000) + 10000 // harga dummy sale: 10000–99999
+	city := cities[rand.Intn(len(cities))]
+	districts := areas[city]
+	return city, districts[rand.Intn(len(districts))]
 }
 
 func randomUserID() int {
@@ -43,10 +68,18 @@ func SeedListings(db *gorm.DB) {
 	now := time.Now().UnixMicro()
 
 	for i := 0; i < 10; i++ {
+		city, district := randomArea()
+		listingType := randomListingType()
+		price, rentPeriod := randomPrice(listingType)
 		listing := models.Listing{
 			UserID:      randomUserID(),
-			Price:       randomPrice(),
-			ListingType: randomListingType(),
+			Price:       price,
+			Currency:    money.DefaultCurrency,
+			RentPeriod:  rentPeriod,
+			ListingType: listingType,
+			City:        city,
+			District:    district,
+			Status:      models.ListingStatusActive,
 			CreatedAt:   now,
 			UpdatedAt:   now,
 		}
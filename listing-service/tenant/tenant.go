@@ -0,0 +1,51 @@
+// Package tenant reads the tenant a request is made for. The public API
+// resolves it from the host or API key and passes it on in X-Tenant-ID;
+// requests without one belong to the default tenant.
+//
+// The services are separate modules, each built on its own, so user-service
+// has a copy of this package. Change both together.
+package tenant
+
+import (
+	"net/http"
+	"real-estate-system/listing-service/models"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	Header     = "X-Tenant-ID"
+	contextKey = "tenant_id"
+)
+
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,39}$`)
+
+// Middleware stores the X-Tenant-ID of the request for ID, rejecting
+// malformed ones.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(Header)
+			if id == "" {
+				id = models.DefaultTenant
+			}
+			if !validID.MatchString(id) {
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid "+Header)
+			}
+			c.Set(contextKey, id)
+			return next(c)
+		}
+	}
+}
+
+// ID returns the tenant of the request.
+func ID(c echo.Context) string {
+	if id, ok := c.Get(contextKey).(string); ok {
+		return id
+	}
+	if id := c.Request().Header.Get(Header); validID.MatchString(id) {
+		return id
+	}
+	return models.DefaultTenant
+}
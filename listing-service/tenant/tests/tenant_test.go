@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/listing-service/tenant"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(header string) (*httptest.ResponseRecorder, string) {
+	var seen string
+	e := echo.New()
+	e.Use(tenant.Middleware())
+	e.GET("/", func(c echo.Context) error {
+		seen = tenant.ID(c)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(tenant.Header, header)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec, seen
+}
+
+func TestMiddleware_DefaultTenant(t *testing.T) {
+	rec, id := serve("")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "default", id)
+}
+
+func TestMiddleware_HeaderTenant(t *testing.T) {
+	rec, id := serve("acme-homes")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme-homes", id)
+}
+
+func TestMiddleware_RejectsMalformedTenant(t *testing.T) {
+	for _, header := range []string{"Acme", "a", "acme;drop", "-acme"} {
+		rec, _ := serve(header)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, header)
+	}
+}
@@ -0,0 +1,37 @@
+package valuation
+
+import (
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/repository/interfaces"
+	"time"
+)
+
+const (
+	// maxCandidates bounds the listings a valuation is based on.
+	maxCandidates = 200
+
+	// maxAge is how long ago comparables may have left the market.
+	maxAge = 2 * 365 * 24 * time.Hour
+)
+
+// Estimator values properties from the listings around them.
+type Estimator struct {
+	Repo interfaces.ValuationRepository
+	FX   *fx.Service
+}
+
+func NewEstimator(repo interfaces.ValuationRepository, rates *fx.Service) *Estimator {
+	return &Estimator{Repo: repo, FX: rates}
+}
+
+// Estimate values subject from the listings of the same type nearby that
+// are on the market or left it within the last two years.
+func (e *Estimator) Estimate(subject *models.Listing) (*models.Valuation, error) {
+	now := time.Now()
+	candidates, err := e.Repo.GetComparables(subject, SearchRadius, now.Add(-maxAge).UnixMicro(), maxCandidates)
+	if err != nil {
+		return nil, err
+	}
+	return Estimate(subject, candidates, e.FX.Rates(), now.UnixMicro())
+}
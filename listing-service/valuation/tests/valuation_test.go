@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/valuation"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var rates, _ = fx.Parse([]byte(`{"base": "USD", "rates": {"IDR": "16000", "SGD": "1.25"}}`))
+
+const now = int64(400 * 24 * 60 * 60 * 1000000)
+
+func TestEstimate_ScalesByFloorArea(t *testing.T) {
+	subject := models.Listing{ListingType: "sale", Currency: "IDR", FloorArea: 100, Latitude: -6.26, Longitude: 106.81}
+	at := func(id, price, area int) models.Listing {
+		return models.Listing{ID: id, ListingType: "sale", Status: "active", Price: price, Currency: "IDR",
+			FloorArea: area, Latitude: -6.26, Longitude: 106.81, CreatedAt: now}
+	}
+	candidates := []models.Listing{
+		at(1, 2000, 100),
+		at(2, 1100, 50),
+		at(3, 3600, 200),
+		at(4, 1000, 40), // too small
+		at(5, 5000, 0),  // size unknown
+	}
+
+	estimate, err := valuation.Estimate(&subject, candidates, rates, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1800, estimate.Low)
+	assert.Equal(t, 2000, estimate.EstimatedValue)
+	assert.Equal(t, 2000, estimate.High)
+	assert.Equal(t, 20, estimate.PricePerM2)
+	assert.Len(t, estimate.Comparables, 3)
+	assert.Equal(t, 1, estimate.Comparables[0].ListingID)
+	assert.Equal(t, 0.5, estimate.Comparables[0].Weight)
+	assert.ElementsMatch(t, []int{2200, 1800}, []int{estimate.Comparables[1].AdjustedPrice, estimate.Comparables[2].AdjustedPrice})
+	assert.Equal(t, 0, *estimate.Comparables[0].DistanceM)
+}
+
+func TestEstimate_RecentAndCloseCountMore(t *testing.T) {
+	subject := models.Listing{ListingType: "sale", Currency: "IDR", City: "Jakarta", District: "Kemang", Bedrooms: 3}
+	candidates := []models.Listing{
+		{ID: 1, ListingType: "sale", Status: "active", Price: 1000, City: "Jakarta", District: "Kemang", Bedrooms: 3, CreatedAt: now},
+		{ID: 2, ListingType: "sale", Status: "rented", Price: 1000, City: "Jakarta", District: "Kemang", Bedrooms: 3, OffMarketAt: now - 180*24*60*60*1000000},
+		{ID: 3, ListingType: "sale", Status: "active", Price: 1000, City: "Jakarta", District: "Cikini", Bedrooms: 3, CreatedAt: now},
+		{ID: 4, ListingType: "sale", Status: "active", Price: 1000, City: "Jakarta", District: "Kemang", Bedrooms: 1, CreatedAt: now},
+	}
+
+	estimate, err := valuation.Estimate(&subject, candidates, rates, now)
+	assert.NoError(t, err)
+	weights := map[int]float64{}
+	for _, c := range estimate.Comparables {
+		weights[c.ListingID] = c.Weight
+	}
+	assert.InDelta(t, weights[1]/2, weights[2], 0.001)
+	assert.Less(t, weights[3], weights[1])
+	assert.Less(t, weights[4], weights[1])
+}
+
+func TestEstimate_ConvertsCurrencyAndRentPeriod(t *testing.T) {
+	subject := models.Listing{ListingType: "rent", RentPeriod: "year", Currency: "IDR", City: "Jakarta"}
+	rent := models.Listing{ListingType: "rent", RentPeriod: "month", Status: "active", Price: 1, Currency: "USD", City: "Jakarta", CreatedAt: now}
+	candidates := []models.Listing{rent, rent, rent}
+	for i := range candidates {
+		candidates[i].ID = i + 1
+	}
+
+	estimate, err := valuation.Estimate(&subject, candidates, rates, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 192000, estimate.EstimatedValue)
+	assert.Equal(t, "year", estimate.RentPeriod)
+	// Few comparables known only by their city make a weak estimate.
+	assert.Less(t, estimate.Confidence, 50)
+}
+
+func TestEstimate_NotEnoughComparables(t *testing.T) {
+	subject := models.Listing{ID: 1, ListingType: "sale", City: "Jakarta"}
+	candidates := []models.Listing{
+		{ID: 1, ListingType: "sale", Price: 1000, City: "Jakarta"}, // the listing itself
+		{ID: 2, ListingType: "sale", Price: 1000, City: "Jakarta"},
+		{ID: 3, ListingType: "sale", Price: 1000, City: "Jakarta"},
+	}
+
+	_, err := valuation.Estimate(&subject, candidates, rates, now)
+	assert.ErrorIs(t, err, models.ErrNotEnoughComparables)
+}
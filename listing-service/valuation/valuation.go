@@ -0,0 +1,203 @@
+package valuation
+
+import (
+	"math"
+	"real-estate-system/listing-service/dedup"
+	"real-estate-system/listing-service/fx"
+	"real-estate-system/listing-service/models"
+	"real-estate-system/listing-service/money"
+	"sort"
+	"strings"
+)
+
+const (
+	// SearchRadius bounds the comparables by location, in metres.
+	SearchRadius = 2000
+
+	// MinComparables is how many comparables an estimate takes.
+	MinComparables = 3
+
+	// shown is how many comparables a valuation lists, the weightiest.
+	shown = 10
+
+	day = 24 * 60 * 60 * 1000000
+
+	// halfLife is the age in days at which a comparable's price counts half.
+	halfLife = 180
+
+	// minSizeRatio is how much smaller or larger than the property valued
+	// a comparable may be.
+	minSizeRatio = 0.5
+)
+
+type weighted struct {
+	comparable models.Comparable
+	price      float64
+}
+
+// Estimate values subject, a property described like a listing, from the
+// candidates: listings of the same type and property type nearby. Each
+// candidate's price is converted to the subject's currency and, for rents,
+// rent period, and scaled by floor area when both have one. It counts
+// according to its distance, how recently it was priced and how alike the
+// two are in size and bedrooms. Candidates of very different size are left
+// out.
+func Estimate(subject *models.Listing, candidates []models.Listing, rates *fx.Rates, now int64) (*models.Valuation, error) {
+	currency := subject.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	var comparables []weighted
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.ID == subject.ID && subject.ID != 0 || candidate.Price <= 0 {
+			continue
+		}
+		converted, err := rates.Convert(candidate.PriceAmount(), currency)
+		if err != nil {
+			continue
+		}
+		price := float64(converted.Minor) / math.Pow10(money.Exponent(currency))
+		if candidate.RentPeriod == models.RentPerYear {
+			price /= 12
+		}
+
+		weight := proximity(subject, candidate) * recency(candidate, now) * bedroomLikeness(subject, candidate)
+		if subject.FloorArea > 0 {
+			if candidate.FloorArea <= 0 {
+				continue
+			}
+			ratio := float64(min(subject.FloorArea, candidate.FloorArea)) / float64(max(subject.FloorArea, candidate.FloorArea))
+			if ratio < minSizeRatio {
+				continue
+			}
+			weight *= ratio
+			price = price / float64(candidate.FloorArea) * float64(subject.FloorArea)
+		}
+		if subject.RentPeriod == models.RentPerYear {
+			price *= 12
+		}
+
+		comparable := models.Comparable{
+			ListingID:     candidate.ID,
+			Status:        candidate.Status,
+			Price:         candidate.Price,
+			Currency:      candidate.Currency,
+			RentPeriod:    candidate.RentPeriod,
+			AdjustedPrice: int(math.Round(price)),
+			FloorArea:     candidate.FloorArea,
+			Bedrooms:      candidate.Bedrooms,
+			District:      candidate.District,
+			PricedAt:      pricedAt(candidate),
+			Weight:        weight,
+		}
+		if subject.HasLocation() && candidate.HasLocation() {
+			metres := int(math.Round(dedup.Distance(subject.Latitude, subject.Longitude, candidate.Latitude, candidate.Longitude)))
+			comparable.DistanceM = &metres
+		}
+		comparables = append(comparables, weighted{comparable: comparable, price: price})
+	}
+	if len(comparables) < MinComparables {
+		return nil, models.ErrNotEnoughComparables
+	}
+
+	var total, squares float64
+	for _, c := range comparables {
+		total += c.comparable.Weight
+		squares += c.comparable.Weight * c.comparable.Weight
+	}
+	sort.Slice(comparables, func(i, j int) bool { return comparables[i].price < comparables[j].price })
+	low := quantile(comparables, total, 0.25)
+	value := quantile(comparables, total, 0.5)
+	high := quantile(comparables, total, 0.75)
+
+	valuation := &models.Valuation{
+		ListingType:    subject.ListingType,
+		Currency:       currency,
+		RentPeriod:     subject.RentPeriod,
+		Low:            int(math.Round(low)),
+		EstimatedValue: int(math.Round(value)),
+		High:           int(math.Round(high)),
+		Confidence:     confidence(total, squares, low, value, high),
+	}
+	if subject.FloorArea > 0 {
+		valuation.PricePerM2 = int(math.Round(value / float64(subject.FloorArea)))
+	}
+
+	sort.SliceStable(comparables, func(i, j int) bool {
+		return comparables[i].comparable.Weight > comparables[j].comparable.Weight
+	})
+	for _, c := range comparables[:min(len(comparables), shown)] {
+		c.comparable.Weight = math.Round(c.comparable.Weight/total*1000) / 1000
+		valuation.Comparables = append(valuation.Comparables, c.comparable)
+	}
+	return valuation, nil
+}
+
+// quantile is the price at which the comparables, sorted by price, reach q
+// of the total weight.
+func quantile(comparables []weighted, total, q float64) float64 {
+	var sum float64
+	for _, c := range comparables {
+		sum += c.comparable.Weight
+		if sum >= q*total {
+			return c.price
+		}
+	}
+	return comparables[len(comparables)-1].price
+}
+
+// confidence grows with the effective number of comparables, up to 8, and
+// their total weight, up to 3 close, recent and alike ones, and falls as
+// the interquartile range widens relative to the estimate.
+func confidence(total, squares, low, value, high float64) int {
+	if value <= 0 {
+		return 0
+	}
+	count := math.Min(total*total/squares/8, 1)
+	strength := math.Min(total/3, 1)
+	agreement := math.Max(0, 1-(high-low)/value)
+	return int(math.Round(100 * math.Sqrt(count*strength) * agreement))
+}
+
+// proximity is 1 at the same spot, halving 500 m away. Without coordinates
+// on both, sharing a district counts as 1 km away and a city as 2 km.
+func proximity(subject, candidate *models.Listing) float64 {
+	metres := 4.0 * SearchRadius
+	switch {
+	case subject.HasLocation() && candidate.HasLocation():
+		metres = dedup.Distance(subject.Latitude, subject.Longitude, candidate.Latitude, candidate.Longitude)
+	case subject.District != "" && strings.EqualFold(subject.District, candidate.District):
+		metres = 1000
+	case subject.City != "" && strings.EqualFold(subject.City, candidate.City):
+		metres = SearchRadius
+	}
+	return 1 / (1 + metres/500)
+}
+
+// recency halves every halfLife days since the candidate was priced.
+func recency(candidate *models.Listing, now int64) float64 {
+	age := float64(max(now-pricedAt(candidate), 0)) / day
+	return math.Pow(0.5, age/halfLife)
+}
+
+// bedroomLikeness is 0.8 to the power of the difference in bedrooms, or 1
+// when either is unknown.
+func bedroomLikeness(subject, candidate *models.Listing) float64 {
+	if subject.Bedrooms == 0 || candidate.Bedrooms == 0 {
+		return 1
+	}
+	diff := subject.Bedrooms - candidate.Bedrooms
+	if diff < 0 {
+		diff = -diff
+	}
+	return math.Pow(0.8, float64(diff))
+}
+
+func pricedAt(listing *models.Listing) int64 {
+	if models.IsOffMarket(listing.Status) && listing.OffMarketAt != 0 {
+		return listing.OffMarketAt
+	}
+	return max(listing.ListedAt, listing.PriceChangedAt, listing.CreatedAt)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myAgentURL(c echo.Context) string {
+	return UserServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int))
+}
+
+func agencyURL(c echo.Context) string {
+	return UserServiceURL + "/agencies/" + url.PathEscape(c.Param("id"))
+}
+
+func asCurrentUser(c echo.Context) url.Values {
+	return url.Values{"user_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))}}
+}
+
+func GetMyAgentProfile(c echo.Context) error {
+	return forward(c, http.MethodGet, myAgentURL(c)+"/agent-profile", "User service")
+}
+
+// SaveMyAgentProfile creates or replaces the current user's agent profile
+// (JSON license_number, service_areas, languages, bio).
+func SaveMyAgentProfile(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPut, myAgentURL(c)+"/agent-profile", "User service")
+}
+
+// GetMyAgency returns the current user's agency and open invitations.
+func GetMyAgency(c echo.Context) error {
+	return forward(c, http.MethodGet, myAgentURL(c)+"/agency", "User service")
+}
+
+func AcceptAgencyInvitation(c echo.Context) error {
+	target := myAgentURL(c) + "/agency-invitations/" + url.PathEscape(c.Param("agency_id")) + "/accept"
+	return forwardAsForm(c, http.MethodPost, target, "User service")
+}
+
+// CreateAgency creates an agency (JSON name) run by the current user.
+func CreateAgency(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, UserServiceURL+"/agencies", "User service", asCurrentUser(c))
+}
+
+// GetAgency returns an agency with its agents.
+func GetAgency(c echo.Context) error {
+	return forward(c, http.MethodGet, agencyURL(c), "User service")
+}
+
+// InviteAgencyMember invites a user (JSON member_id, role) to the agency.
+func InviteAgencyMember(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, agencyURL(c)+"/members", "User service", asCurrentUser(c))
+}
+
+// RemoveAgencyMember removes a member as agency admin, or leaves the agency.
+func RemoveAgencyMember(c echo.Context) error {
+	target := agencyURL(c) + "/members/" + url.PathEscape(c.Param("member_id")) + "?" + asCurrentUser(c).Encode()
+	req, err := newRequest(c, http.MethodDelete, target, nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "User service")
+}
+
+// managedAgents returns the users whose listings userID manages as an
+// agency admin.
+func managedAgents(c echo.Context, userID int) (map[int]bool, error) {
+	resp, err := get(c, UserServiceURL+"/users/"+strconv.Itoa(userID)+"/managed-agents")
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, "User service unavailable")
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Result   bool  `json:"result"`
+		AgentIDs []int `json:"agent_ids"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &payload); err != nil || !payload.Result {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, "User service unavailable")
+	}
+
+	agents := make(map[int]bool, len(payload.AgentIDs))
+	for _, id := range payload.AgentIDs {
+		agents[id] = true
+	}
+	return agents, nil
+}
+
+// managedListingOwner returns the owner of the :listing_id listing if the
+// current user manages their listings.
+func managedListingOwner(c echo.Context) (url.Values, error) {
+	resp, err := get(c, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("listing_id")))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Listing struct {
+			UserID int `json:"user_id"`
+		} `json:"listing"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || json.Unmarshal(body, &payload) != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+
+	agents, err := managedAgents(c, c.Get(middleware.ContextUserID).(int))
+	if err != nil {
+		return nil, err
+	}
+	if !agents[payload.Listing.UserID] {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Only admins of the lister's agency can do this")
+	}
+	return url.Values{"user_id": {strconv.Itoa(payload.Listing.UserID)}}, nil
+}
+
+// GetAgencyListings returns the listings of one of the agents (agent_id)
+// the current user manages, including those under review.
+func GetAgencyListings(c echo.Context) error {
+	agentID, err := strconv.Atoi(c.QueryParam("agent_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid agent_id")
+	}
+	agents, err := managedAgents(c, c.Get(middleware.ContextUserID).(int))
+	if err != nil {
+		return err
+	}
+	if !agents[agentID] {
+		return echo.NewHTTPError(http.StatusForbidden, "Only admins of the agent's agency can do this")
+	}
+
+	query := c.Request().URL.Query()
+	query.Del("agent_id")
+	query.Set("user_id", strconv.Itoa(agentID))
+	query.Set("viewer_id", strconv.Itoa(agentID))
+	req, err := newRequest(c, http.MethodGet, ListingServiceURL+"/listings?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
+
+// UpdateAgencyListingPrice changes the price of an agent's listing (JSON
+// price) on their behalf.
+func UpdateAgencyListingPrice(c echo.Context) error {
+	owner, err := managedListingOwner(c)
+	if err != nil {
+		return err
+	}
+	target := ListingServiceURL + "/listings/" + url.PathEscape(c.Param("listing_id")) + "/price"
+	return forwardAsFormWith(c, http.MethodPatch, target, "Listing service", owner)
+}
+
+// UpdateAgencyListingStatus activates or archives an agent's listing (JSON
+// status) on their behalf.
+func UpdateAgencyListingStatus(c echo.Context) error {
+	owner, err := managedListingOwner(c)
+	if err != nil {
+		return err
+	}
+	target := ListingServiceURL + "/listings/" + url.PathEscape(c.Param("listing_id")) + "/status"
+	return forwardAsFormWith(c, http.MethodPatch, target, "Listing service", owner)
+}
+
+// GetAgentVerifications lists agent license details waiting for
+// verification, longest waiting first.
+func GetAgentVerifications(c echo.Context) error {
+	query := c.Request().URL.Query()
+	for k, v := range asAdmin(c) {
+		query[k] = v
+	}
+
+	req, err := newRequest(c, http.MethodGet, UserServiceURL+"/agent-profiles?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "User service")
+}
+
+// ApproveAgentProfile verifies an agent's license details, which gives
+// their listings the verified agent badge.
+func ApproveAgentProfile(c echo.Context) error {
+	target := UserServiceURL + "/agent-profiles/" + url.PathEscape(c.Param("user_id")) + "/approve"
+	return forwardAsFormWith(c, http.MethodPost, target, "User service", asAdmin(c))
+}
+
+// RejectAgentProfile rejects an agent's license details. The JSON note,
+// explaining why, is required.
+func RejectAgentProfile(c echo.Context) error {
+	target := UserServiceURL + "/agent-profiles/" + url.PathEscape(c.Param("user_id")) + "/reject"
+	return forwardAsFormWith(c, http.MethodPost, target, "User service", asAdmin(c))
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myApplicationsURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int))
+}
+
+// ApplyForListing submits a rental application as the current user.
+func ApplyForListing(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/applications", "Listing service", url.Values{
+		"applicant_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))},
+	})
+}
+
+// GetApplications lists the applications the current user has made.
+func GetApplications(c echo.Context) error {
+	return forward(c, http.MethodGet, myApplicationsURL(c)+"/applications", "Listing service")
+}
+
+// GetReceivedApplications lists applications for the current user's
+// listings with their screening scores.
+func GetReceivedApplications(c echo.Context) error {
+	return forward(c, http.MethodGet, myApplicationsURL(c)+"/received-applications", "Listing service")
+}
+
+func GetApplication(c echo.Context) error {
+	return forward(c, http.MethodGet, myApplicationsURL(c)+"/applications/"+url.PathEscape(c.Param("application_id")), "Listing service")
+}
+
+func UpdateApplicationStatus(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPatch, myApplicationsURL(c)+"/applications/"+url.PathEscape(c.Param("application_id"))+"/status", "Listing service")
+}
+
+func GetScreeningRules(c echo.Context) error {
+	return forward(c, http.MethodGet, myApplicationsURL(c)+"/screening-rules", "Listing service")
+}
+
+func UpdateScreeningRules(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPut, myApplicationsURL(c)+"/screening-rules", "Listing service")
+}
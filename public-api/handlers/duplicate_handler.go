@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// asAdmin identifies the current user to the listing service as an
+// administrator. Routes using it sit behind RequireAdmin.
+func asAdmin(c echo.Context) url.Values {
+	return url.Values{
+		"user_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))},
+		"role":    {middleware.RoleAdmin},
+	}
+}
+
+func duplicateURL(c echo.Context, action string) string {
+	return ListingServiceURL + "/duplicates/" + url.PathEscape(c.Param("pair_id")) + action
+}
+
+// GetDuplicates lists listings suspected to be the same property.
+func GetDuplicates(c echo.Context) error {
+	query := c.Request().URL.Query()
+	for k, v := range asAdmin(c) {
+		query[k] = v
+	}
+
+	req, err := newRequest(c, http.MethodGet, ListingServiceURL+"/duplicates?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
+
+// MergeDuplicate keeps one listing of a pair (JSON keep_listing_id) and
+// archives the other.
+func MergeDuplicate(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, duplicateURL(c, "/merge"), "Listing service", asAdmin(c))
+}
+
+// DismissDuplicate marks a pair as different properties.
+func DismissDuplicate(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, duplicateURL(c, "/dismiss"), "Listing service", asAdmin(c))
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myFavoritesURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) + "/favorites"
+}
+
+// AddFavorite bookmarks a listing for the current user
+func AddFavorite(c echo.Context) error {
+	return forward(c, http.MethodPut, myFavoritesURL(c)+"/"+url.PathEscape(c.Param("listing_id")), "Listing service")
+}
+
+// RemoveFavorite removes a bookmark of the current user
+func RemoveFavorite(c echo.Context) error {
+	return forward(c, http.MethodDelete, myFavoritesURL(c)+"/"+url.PathEscape(c.Param("listing_id")), "Listing service")
+}
+
+// GetFavorites returns the current user's favorites as listing cards
+// enriched with the listing owner.
+func GetFavorites(c echo.Context) error {
+	resp, err := get(c, myFavoritesURL(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Result    bool `json:"result"`
+		Favorites []struct {
+			ListingID         int                    `json:"listing_id"`
+			FavoritedAt       int64                  `json:"favorited_at"`
+			SavedPrice        int                    `json:"saved_price"`
+			PriceDropped      bool                   `json:"price_dropped"`
+			NoLongerAvailable bool                   `json:"no_longer_available"`
+			Listing           map[string]interface{} `json:"listing"`
+		} `json:"favorites"`
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to decode favorites")
+	}
+
+	for _, favorite := range payload.Favorites {
+		if favorite.Listing == nil {
+			continue
+		}
+		if user := fetchUser(c, int(toFloat(favorite.Listing["user_id"]))); user != nil {
+			favorite.Listing["user"] = user
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":    true,
+		"favorites": payload.Favorites,
+	})
+}
+
+// GetMyListings returns the current user's listings, including those under
+// review, with how many users favorited each one.
+func GetMyListings(c echo.Context) error {
+	userID := strconv.Itoa(c.Get(middleware.ContextUserID).(int))
+	query := c.Request().URL.Query()
+	query.Set("user_id", userID)
+	query.Set("viewer_id", userID)
+
+	resp, err := get(c, ListingServiceURL+"/listings?"+query.Encode())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Result   bool                     `json:"result"`
+		Listings []map[string]interface{} `json:"listings"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to decode listings")
+	}
+
+	if len(payload.Listings) > 0 {
+		ids := make([]string, len(payload.Listings))
+		for i, listing := range payload.Listings {
+			ids[i] = ToString(listing["id"])
+		}
+
+		counts := fetchFavoriteCounts(c, strings.Join(ids, ","))
+		for i, listing := range payload.Listings {
+			payload.Listings[i]["favorite_count"] = counts[ToString(listing["id"])]
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":   true,
+		"listings": payload.Listings,
+	})
+}
+
+// fetchFavoriteCounts returns counts keyed by listing id; missing counts
+// read as zero.
+func fetchFavoriteCounts(c echo.Context, ids string) map[string]int64 {
+	counts := map[string]int64{}
+
+	resp, err := get(c, ListingServiceURL+"/listings/favorite-counts?ids="+url.QueryEscape(ids))
+	if err != nil {
+		return counts
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Counts map[string]int64 `json:"counts"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Counts != nil {
+		counts = payload.Counts
+	}
+	return counts
+}
+
+func toFloat(value interface{}) float64 {
+	v, _ := value.(float64)
+	return v
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func inquiriesURL(c echo.Context) string {
+	return ListingServiceURL + "/listings/" + url.PathEscape(c.Param("id")) + "/inquiries"
+}
+
+func myInquiriesURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) + "/inquiries"
+}
+
+// CreateInquiry sends an inquiry from the current user to a listing owner.
+func CreateInquiry(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, inquiriesURL(c), "Listing service", url.Values{
+		"buyer_id":   {strconv.Itoa(c.Get(middleware.ContextUserID).(int))},
+		"source":     {"web"},
+		"source_ref": {""},
+	})
+}
+
+// CreatePartnerInquiry records a lead collected by a partner, attributed to
+// the partner's key fingerprint.
+func CreatePartnerInquiry(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, inquiriesURL(c), "Listing service", url.Values{
+		"buyer_id":   {""},
+		"source":     {"partner"},
+		"source_ref": {middleware.APIKeyFingerprint(c.Get(middleware.ContextAPIKey).(string))},
+	})
+}
+
+// GetInquiries returns the current user's inquiry inbox.
+func GetInquiries(c echo.Context) error {
+	return forward(c, http.MethodGet, myInquiriesURL(c), "Listing service")
+}
+
+func UpdateInquiryStatus(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPatch, myInquiriesURL(c)+"/"+url.PathEscape(c.Param("inquiry_id"))+"/status", "Listing service")
+}
+
+// InquirySender keys inquiry throttling by the signed-in user or partner,
+// falling back to the client IP.
+func InquirySender(c echo.Context) string {
+	if userID, ok := c.Get(middleware.ContextUserID).(int); ok {
+		return "user:" + strconv.Itoa(userID)
+	}
+	if key, ok := c.Get(middleware.ContextAPIKey).(string); ok {
+		return "partner:" + middleware.APIKeyFingerprint(key)
+	}
+	return "ip:" + c.RealIP()
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myLeasesURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) + "/leases"
+}
+
+func myLeaseURL(c echo.Context, action string) string {
+	return myLeasesURL(c) + "/" + url.PathEscape(c.Param("lease_id")) + action
+}
+
+// CreateLease records a lease of one of the current user's listings.
+func CreateLease(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myLeasesURL(c), "Listing service")
+}
+
+// GetLeases lists the current user's leases as landlord or tenant.
+func GetLeases(c echo.Context) error {
+	return forward(c, http.MethodGet, myLeasesURL(c), "Listing service")
+}
+
+// GetLease returns a lease with its rent schedule.
+func GetLease(c echo.Context) error {
+	return forward(c, http.MethodGet, myLeaseURL(c, ""), "Listing service")
+}
+
+func RenewLease(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myLeaseURL(c, "/renew"), "Listing service")
+}
+
+func TerminateLease(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myLeaseURL(c, "/terminate"), "Listing service")
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxUploadBytes is the largest file the listing service accepts.
+const maxUploadBytes = 10 << 20
+
+func myListingPhotosURL(c echo.Context, action string) string {
+	return ListingServiceURL + "/listings/" + url.PathEscape(c.Param("listing_id")) + "/photos" + action
+}
+
+func myListingAttachmentsURL(c echo.Context, action string) string {
+	return ListingServiceURL + "/listings/" + url.PathEscape(c.Param("listing_id")) + "/attachments" + action
+}
+
+// GetListingPhotos returns a listing's photos in display order.
+func GetListingPhotos(c echo.Context) error {
+	return forward(c, http.MethodGet, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/photos", "Listing service")
+}
+
+// UploadListingPhoto adds the multipart file "photo" to one of the current
+// user's listings.
+func UploadListingPhoto(c echo.Context) error {
+	return forwardUpload(c, myListingPhotosURL(c, ""), "photo", nil)
+}
+
+// forwardUpload posts the multipart file in field, with fields, to target
+// as the current user. Without a file only the fields are sent.
+func forwardUpload(c echo.Context, target, field string, fields []string) error {
+	header, err := c.FormFile(field)
+	if err != nil && fields == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, field+" file is required")
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("user_id", strconv.Itoa(c.Get(middleware.ContextUserID).(int))); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, name := range fields {
+		if value := c.FormValue(name); value != "" {
+			if err := writer.WriteField(name, value); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+		}
+	}
+	if header != nil {
+		if header.Size > maxUploadBytes {
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "file is larger than 10 MB")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, field+" could not be read")
+		}
+		defer file.Close()
+		part, err := writer.CreateFormFile(field, header.Filename)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, field+" could not be read")
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	req, err := newRequest(c, http.MethodPost, target, &body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+	return relay(c, req, "Listing service")
+}
+
+// ReorderListingPhotos sets the display order of a listing's photos.
+func ReorderListingPhotos(c echo.Context) error {
+	overrides := url.Values{"user_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))}}
+	return forwardAsFormWith(c, http.MethodPut, myListingPhotosURL(c, "/order"), "Listing service", overrides)
+}
+
+// SetListingCoverPhoto makes a photo the cover of its listing.
+func SetListingCoverPhoto(c echo.Context) error {
+	overrides := url.Values{"user_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))}}
+	return forwardAsFormWith(c, http.MethodPost, myListingPhotosURL(c, "/"+url.PathEscape(c.Param("photo_id"))+"/cover"), "Listing service", overrides)
+}
+
+// DeleteListingPhoto removes a photo from one of the current user's listings.
+func DeleteListingPhoto(c echo.Context) error {
+	return deleteAsCurrentUser(c, myListingPhotosURL(c, "/"+url.PathEscape(c.Param("photo_id"))))
+}
+
+// deleteAsCurrentUser sends a DELETE to target with the current user in the
+// query, as the listing service does not read DELETE bodies.
+func deleteAsCurrentUser(c echo.Context, target string) error {
+	query := url.Values{"user_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))}}
+	req, err := newRequest(c, http.MethodDelete, target+"?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
+
+// GetListingAttachments returns a listing's public attachments: floor plans,
+// virtual tours and other files, optionally of one type.
+func GetListingAttachments(c echo.Context) error {
+	return getAttachments(c, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/attachments", url.Values{})
+}
+
+// GetMyListingAttachments returns every attachment of a listing the current
+// user owns, or of any listing for administrators. Private documents come
+// with links that expire.
+func GetMyListingAttachments(c echo.Context) error {
+	query := url.Values{"user_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))}}
+	if role, _ := c.Get(middleware.ContextUserRole).(string); role != "" {
+		query.Set("role", role)
+	}
+	return getAttachments(c, myListingAttachmentsURL(c, ""), query)
+}
+
+// getAttachments lists attachments with query and the type asked for. The
+// rest of the client's query is dropped so it cannot claim a user or role.
+func getAttachments(c echo.Context, target string, query url.Values) error {
+	if mediaType := c.QueryParam("type"); mediaType != "" {
+		query.Set("type", mediaType)
+	}
+	req, err := newRequest(c, http.MethodGet, target+"?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
+
+// CreateListingAttachment adds a floor plan, document, photo or virtual
+// tour link to one of the current user's listings.
+func CreateListingAttachment(c echo.Context) error {
+	return forwardUpload(c, myListingAttachmentsURL(c, ""), "file", []string{"type", "title", "url"})
+}
+
+// DeleteListingAttachment removes an attachment from one of the current
+// user's listings.
+func DeleteListingAttachment(c echo.Context) error {
+	return deleteAsCurrentUser(c, myListingAttachmentsURL(c, "/"+url.PathEscape(c.Param("attachment_id"))))
+}
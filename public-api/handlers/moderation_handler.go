@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+)
+
+func reviewURL(c echo.Context, action string) string {
+	return ListingServiceURL + "/moderation/reviews/" + url.PathEscape(c.Param("review_id")) + action
+}
+
+// GetModerationQueue lists listings held back by the moderation rules,
+// oldest first.
+func GetModerationQueue(c echo.Context) error {
+	query := c.Request().URL.Query()
+	for k, v := range asAdmin(c) {
+		query[k] = v
+	}
+
+	req, err := newRequest(c, http.MethodGet, ListingServiceURL+"/moderation/reviews?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
+
+// ApproveListing publishes a listing under review, with an optional JSON
+// note to the lister.
+func ApproveListing(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, reviewURL(c, "/approve"), "Listing service", asAdmin(c))
+}
+
+// RejectListing keeps a listing under review hidden. The JSON note,
+// explaining why to the lister, is required.
+func RejectListing(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, reviewURL(c, "/reject"), "Listing service", asAdmin(c))
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetMortgageSchedule returns the installments of a home loan, or its
+// amortization table as CSV with format=csv.
+func GetMortgageSchedule(c echo.Context) error {
+	return forward(c, http.MethodGet, ListingServiceURL+"/mortgage/schedule", "Listing service")
+}
+
+// GetMortgageAffordability returns the highest price a buyer can afford on
+// their income.
+func GetMortgageAffordability(c echo.Context) error {
+	return forward(c, http.MethodGet, ListingServiceURL+"/mortgage/affordability", "Listing service")
+}
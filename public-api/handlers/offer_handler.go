@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myOffersURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) + "/offers"
+}
+
+func myOfferURL(c echo.Context, action string) string {
+	return myOffersURL(c) + "/" + url.PathEscape(c.Param("offer_id")) + action
+}
+
+// MakeOffer submits an offer on a sale listing as the current user.
+func MakeOffer(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/offers", "Listing service", url.Values{
+		"buyer_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))},
+	})
+}
+
+// GetOffers lists the offers the current user made or received.
+func GetOffers(c echo.Context) error {
+	return forward(c, http.MethodGet, myOffersURL(c), "Listing service")
+}
+
+// GetOffer returns an offer with its negotiation history.
+func GetOffer(c echo.Context) error {
+	return forward(c, http.MethodGet, myOfferURL(c, ""), "Listing service")
+}
+
+func CounterOffer(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myOfferURL(c, "/counter"), "Listing service")
+}
+
+func AcceptOffer(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myOfferURL(c, "/accept"), "Listing service")
+}
+
+func RejectOffer(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myOfferURL(c, "/reject"), "Listing service")
+}
+
+func WithdrawOffer(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myOfferURL(c, "/withdraw"), "Listing service")
+}
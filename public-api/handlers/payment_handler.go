@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myUserURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int))
+}
+
+// PayRent pays towards a lease as tenant, or records a payment as landlord.
+// An Idempotency-Key header is passed on as idempotency_key.
+func PayRent(c echo.Context) error {
+	overrides := url.Values{}
+	if key := c.Request().Header.Get("Idempotency-Key"); key != "" {
+		overrides.Set("idempotency_key", key)
+	}
+	return forwardAsFormWith(c, http.MethodPost, myLeaseURL(c, "/payments"), "Listing service", overrides)
+}
+
+func GetRentPayments(c echo.Context) error {
+	return forward(c, http.MethodGet, myLeaseURL(c, "/payments"), "Listing service")
+}
+
+// GetRentStatement returns the tenant's account for a lease.
+func GetRentStatement(c echo.Context) error {
+	return forward(c, http.MethodGet, myLeaseURL(c, "/statement"), "Listing service")
+}
+
+// GetRentRoll lists the current user's leases as landlord with balances.
+func GetRentRoll(c echo.Context) error {
+	return forward(c, http.MethodGet, myUserURL(c)+"/rent-roll", "Listing service")
+}
+
+func GetLateFeeRule(c echo.Context) error {
+	return forward(c, http.MethodGet, myUserURL(c)+"/late-fee-rule", "Listing service")
+}
+
+func UpdateLateFeeRule(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPut, myUserURL(c)+"/late-fee-rule", "Listing service")
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetPriceHistory returns every price change of a public listing. The
+// client's query is dropped so it cannot claim a user or role.
+func GetPriceHistory(c echo.Context) error {
+	req, err := newRequest(c, http.MethodGet, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/price-history", nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
+
+// UpdateListingPrice changes the price of one of the current user's listings.
+func UpdateListingPrice(c echo.Context) error {
+	overrides := url.Values{"user_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))}}
+	return forwardAsFormWith(c, http.MethodPatch, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("listing_id"))+"/price", "Listing service", overrides)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// forwardAsForm converts a JSON body to form values, which is what the
+// internal services accept, and relays the upstream response.
+func forwardAsForm(c echo.Context, method, target, service string) error {
+	return forwardAsFormWith(c, method, target, service, nil)
+}
+
+// forwardAsFormWith is forwardAsForm with values the caller must not be
+// able to set themselves; they replace any sent in the body.
+func forwardAsFormWith(c echo.Context, method, target, service string, overrides url.Values) error {
+	form := url.Values{}
+	if c.Request().ContentLength != 0 {
+		var data map[string]interface{}
+		if err := c.Bind(&data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON body")
+		}
+		for k, v := range data {
+			form.Set(k, ToString(v))
+		}
+	}
+	for k, v := range overrides {
+		form[k] = v
+	}
+
+	req, err := newRequest(c, method, target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+	return relay(c, req, service)
+}
+
+// forward relays a body-less request, keeping the query string.
+func forward(c echo.Context, method, target, service string) error {
+	if query := c.Request().URL.RawQuery; query != "" {
+		target += "?" + query
+	}
+
+	req, err := newRequest(c, method, target, nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return relay(c, req, service)
+}
+
+// newRequest builds a request to an internal service for the tenant of c.
+func newRequest(c echo.Context, method, target string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(middleware.HeaderTenantID, middleware.TenantID(c))
+	return req, nil
+}
+
+// get fetches target from an internal service for the tenant of c.
+func get(c echo.Context, target string) (*http.Response, error) {
+	req, err := newRequest(c, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
+func relay(c echo.Context, req *http.Request, service string) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, service+" unavailable")
+	}
+	defer resp.Body.Close()
+
+	contentType := resp.Header.Get(echo.HeaderContentType)
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if disposition := resp.Header.Get(echo.HeaderContentDisposition); disposition != "" {
+		c.Response().Header().Set(echo.HeaderContentDisposition, disposition)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	return c.Blob(resp.StatusCode, contentType, body)
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -23,14 +25,12 @@ func CreateUser(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid body")
 	}
 
-	resp, err := http.Post(UserServiceURL+"/users", "application/json", bytes.NewBuffer(reqBody))
+	req, err := newRequest(c, http.MethodPost, UserServiceURL+"/users", bytes.NewBuffer(reqBody))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadGateway, "User service unavailable")
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	return c.Blob(resp.StatusCode, "application/json", body)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	return relay(c, req, "User service")
 }
 
 // CreateListing forwards request to listing-service
@@ -41,25 +41,24 @@ func CreateListing(c echo.Context) error {
 	}
 
 	// Convert JSON to form-urlencoded
-	form := make(map[string][]string)
+	form := url.Values{}
 	for k, v := range data {
-		form[k] = []string{ToString(v)}
+		form.Set(k, ToString(v))
 	}
-	resp, err := http.PostForm(ListingServiceURL+"/listings", form)
+	req, err := newRequest(c, http.MethodPost, ListingServiceURL+"/listings", strings.NewReader(form.Encode()))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	return c.Blob(resp.StatusCode, "application/json", body)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	return relay(c, req, "Listing service")
 }
 
 // GetListings fetches from listing-service and enriches with user-service
 func GetListings(c echo.Context) error {
-	// Forward query params
-	query := c.Request().URL.RawQuery
-	listingResp, err := http.Get(ListingServiceURL + "/listings?" + query)
+	// Forward query params. Only GetMyListings shows listings under review.
+	query := c.Request().URL.Query()
+	query.Del("viewer_id")
+	listingResp, err := get(c, ListingServiceURL+"/listings?"+query.Encode())
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
 	}
@@ -68,13 +67,35 @@ func GetListings(c echo.Context) error {
 	var listingPayload struct {
 		Result   bool `json:"result"`
 		Listings []struct {
-			ID          int    `json:"id"`
-			UserID      int    `json:"user_id"`
-			ListingType string `json:"listing_type"`
-			Price       int    `json:"price"`
-			CreatedAt   int64  `json:"created_at"`
-			UpdatedAt   int64  `json:"updated_at"`
-			User        any    `json:"user,omitempty"` // Will be filled later
+			ID             int      `json:"id"`
+			UserID         int      `json:"user_id"`
+			ListingType    string   `json:"listing_type"`
+			PropertyType   string   `json:"property_type,omitempty"`
+			Price          int      `json:"price"`
+			Currency       string   `json:"currency"`
+			RentPeriod     string   `json:"rent_period,omitempty"`
+			DisplayPrice   any      `json:"display_price,omitempty"`
+			PreviousPrice  int      `json:"previous_price,omitempty"`
+			PriceChangedAt int64    `json:"price_changed_at,omitempty"`
+			PriceChangePct float64  `json:"price_change_pct,omitempty"`
+			EstimatedValue int      `json:"estimated_value,omitempty"`
+			Confidence     int      `json:"estimate_confidence,omitempty"`
+			City           string   `json:"city"`
+			District       string   `json:"district"`
+			Description    string   `json:"description,omitempty"`
+			Address        string   `json:"address,omitempty"`
+			Latitude       float64  `json:"latitude,omitempty"`
+			Longitude      float64  `json:"longitude,omitempty"`
+			Bedrooms       int      `json:"bedrooms,omitempty"`
+			Bathrooms      int      `json:"bathrooms,omitempty"`
+			FloorArea      int      `json:"floor_area,omitempty"`
+			ListedAt       int64    `json:"listed_at,omitempty"`
+			CreatedAt      int64    `json:"created_at"`
+			UpdatedAt      int64    `json:"updated_at"`
+			Photos         any      `json:"photos,omitempty"`
+			Attachments    any      `json:"attachments,omitempty"`
+			User           any      `json:"user,omitempty"` // Will be filled later
+			Badges         []string `json:"badges,omitempty"`
 		} `json:"listings"`
 	}
 
@@ -85,19 +106,9 @@ func GetListings(c echo.Context) error {
 
 	// Fetch and embed user data per listing
 	for i, listing := range listingPayload.Listings {
-		userResp, err := http.Get(UserServiceURL + "/users/" + ToString(listing.UserID))
-		if err != nil {
-			continue
-		}
-		defer userResp.Body.Close()
-
-		var userPayload struct {
-			Result bool        `json:"result"`
-			User   interface{} `json:"user"`
-		}
-		uBody, _ := io.ReadAll(userResp.Body)
-		if err := json.Unmarshal(uBody, &userPayload); err == nil && userPayload.Result {
-			listingPayload.Listings[i].User = userPayload.User
+		if user := fetchUser(c, listing.UserID); user != nil {
+			listingPayload.Listings[i].User = user
+			listingPayload.Listings[i].Badges = userBadges(user)
 		}
 	}
 
@@ -108,6 +119,83 @@ func GetListings(c echo.Context) error {
 	})
 }
 
+// GetListing fetches a public listing from listing-service with its lister
+// and, for sale listings, the mortgage estimate. Only the display currency
+// is forwarded, so the client cannot claim a user or role.
+func GetListing(c echo.Context) error {
+	query := url.Values{}
+	if currency := c.QueryParam("currency"); currency != "" {
+		query.Set("currency", currency)
+	}
+	listingResp, err := get(c, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"?"+query.Encode())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
+	}
+	defer listingResp.Body.Close()
+
+	body, _ := io.ReadAll(listingResp.Body)
+	if listingResp.StatusCode != http.StatusOK {
+		return c.Blob(listingResp.StatusCode, echo.MIMEApplicationJSON, body)
+	}
+
+	var listingPayload struct {
+		Listing          map[string]interface{} `json:"listing"`
+		MortgageEstimate interface{}            `json:"mortgage_estimate"`
+	}
+	if err := json.Unmarshal(body, &listingPayload); err != nil || listingPayload.Listing == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to decode listing")
+	}
+
+	listing := listingPayload.Listing
+	userID, _ := listing["user_id"].(float64)
+	if user := fetchUser(c, int(userID)); user != nil {
+		listing["user"] = user
+		if badges := userBadges(user); badges != nil {
+			listing["badges"] = badges
+		}
+	}
+
+	response := map[string]interface{}{
+		"result":  true,
+		"listing": listing,
+	}
+	if listingPayload.MortgageEstimate != nil {
+		response["mortgage_estimate"] = listingPayload.MortgageEstimate
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// fetchUser returns the user-service representation of a user, or nil if
+// it cannot be loaded.
+func fetchUser(c echo.Context, userID int) interface{} {
+	userResp, err := get(c, UserServiceURL+"/users/"+ToString(userID))
+	if err != nil {
+		return nil
+	}
+	defer userResp.Body.Close()
+
+	var userPayload struct {
+		Result bool        `json:"result"`
+		User   interface{} `json:"user"`
+	}
+	uBody, _ := io.ReadAll(userResp.Body)
+	if err := json.Unmarshal(uBody, &userPayload); err != nil || !userPayload.Result {
+		return nil
+	}
+	return userPayload.User
+}
+
+// userBadges returns the badges a lister earns, "verified_agent" for agents
+// whose license was verified.
+func userBadges(user interface{}) []string {
+	fields, _ := user.(map[string]interface{})
+	agent, _ := fields["agent"].(map[string]interface{})
+	if verified, _ := agent["verified"].(bool); verified {
+		return []string{"verified_agent"}
+	}
+	return nil
+}
+
 // Converts any number/string/float to string
 func ToString(value interface{}) string {
 	switch v := value.(type) {
@@ -116,7 +204,18 @@ func ToString(value interface{}) string {
 	case int:
 		return strconv.Itoa(v)
 	case float64:
-		return strconv.Itoa(int(v))
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				// Lists of objects, such as attachments, stay JSON.
+				body, _ := json.Marshal(v)
+				return string(body)
+			}
+			parts[i] = ToString(item)
+		}
+		return strings.Join(parts, ",")
 	default:
 		return ""
 	}
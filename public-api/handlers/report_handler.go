@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func asReporter(c echo.Context) url.Values {
+	return url.Values{"reporter_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))}}
+}
+
+// ReportListing reports a listing as the current user (JSON reason and
+// details).
+func ReportListing(c echo.Context) error {
+	target := ListingServiceURL + "/listings/" + url.PathEscape(c.Param("id")) + "/reports"
+	return forwardAsFormWith(c, http.MethodPost, target, "Listing service", asReporter(c))
+}
+
+// ReportUser reports another user as the current user (JSON reason and
+// details).
+func ReportUser(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || fetchUser(c, userID) == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+	target := ListingServiceURL + "/users/" + strconv.Itoa(userID) + "/reports"
+	return forwardAsFormWith(c, http.MethodPost, target, "Listing service", asReporter(c))
+}
+
+// GetReports lists reports for triage.
+func GetReports(c echo.Context) error {
+	query := c.Request().URL.Query()
+	for k, v := range asAdmin(c) {
+		query[k] = v
+	}
+
+	req, err := newRequest(c, http.MethodGet, ListingServiceURL+"/reports?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
+
+// ResolveReport upholds or dismisses the reports about a target (JSON
+// outcome and note).
+func ResolveReport(c echo.Context) error {
+	target := ListingServiceURL + "/reports/" + url.PathEscape(c.Param("report_id")) + "/resolve"
+	return forwardAsFormWith(c, http.MethodPost, target, "Listing service", asAdmin(c))
+}
+
+// GetTrustRecord returns how reports about a user were resolved.
+func GetTrustRecord(c echo.Context) error {
+	query := asAdmin(c)
+	req, err := newRequest(c, http.MethodGet, ListingServiceURL+"/trust-records/"+url.PathEscape(c.Param("id"))+"?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "Listing service")
+}
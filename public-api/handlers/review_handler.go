@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myReviewsURL(c echo.Context) string {
+	return UserServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) + "/reviews"
+}
+
+type interaction struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+// latestInteraction returns the last closed inquiry, past viewing or lease
+// the current user had with revieweeID, or nil if there is none.
+func latestInteraction(c echo.Context, revieweeID int) (*interaction, error) {
+	target := ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) +
+		"/interactions?with=" + strconv.Itoa(revieweeID)
+	resp, err := get(c, target)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		Result       bool          `json:"result"`
+		Interactions []interaction `json:"interactions"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &payload); err != nil || !payload.Result {
+		return nil, echo.NewHTTPError(http.StatusBadGateway, "Listing service unavailable")
+	}
+	if len(payload.Interactions) == 0 {
+		return nil, nil
+	}
+	return &payload.Interactions[0], nil
+}
+
+// CreateReview reviews an agent or landlord as the current user (JSON
+// rating and text). It takes a closed inquiry, a viewing that took place or
+// a lease with them.
+func CreateReview(c echo.Context) error {
+	revieweeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+	latest, err := latestInteraction(c, revieweeID)
+	if err != nil {
+		return err
+	}
+	if latest == nil {
+		return echo.NewHTTPError(http.StatusForbidden, "Only users with a closed inquiry, a viewing or a lease with this user can review them")
+	}
+
+	overrides := url.Values{
+		"reviewer_id":      {strconv.Itoa(c.Get(middleware.ContextUserID).(int))},
+		"interaction_type": {latest.Type},
+		"interaction_id":   {strconv.FormatInt(latest.ID, 10)},
+	}
+	target := UserServiceURL + "/users/" + strconv.Itoa(revieweeID) + "/reviews"
+	return forwardAsFormWith(c, http.MethodPost, target, "User service", overrides)
+}
+
+// GetReviews returns a user's published reviews with their rating.
+func GetReviews(c echo.Context) error {
+	return forward(c, http.MethodGet, UserServiceURL+"/users/"+url.PathEscape(c.Param("id"))+"/reviews", "User service")
+}
+
+// GetMyReviews returns the published reviews of the current user.
+func GetMyReviews(c echo.Context) error {
+	return forward(c, http.MethodGet, myReviewsURL(c), "User service")
+}
+
+// ReplyToReview sets the current user's reply to a review of them (JSON
+// reply).
+func ReplyToReview(c echo.Context) error {
+	target := myReviewsURL(c) + "/" + url.PathEscape(c.Param("review_id")) + "/reply"
+	return forwardAsForm(c, http.MethodPost, target, "User service")
+}
+
+// GetReviewModerationQueue lists reviews for moderators, optionally by
+// status.
+func GetReviewModerationQueue(c echo.Context) error {
+	query := c.Request().URL.Query()
+	for k, v := range asAdmin(c) {
+		query[k] = v
+	}
+
+	req, err := newRequest(c, http.MethodGet, UserServiceURL+"/reviews?"+query.Encode(), nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return relay(c, req, "User service")
+}
+
+// HideReview takes a review down (JSON note, required).
+func HideReview(c echo.Context) error {
+	target := UserServiceURL + "/reviews/" + url.PathEscape(c.Param("review_id")) + "/hide"
+	return forwardAsFormWith(c, http.MethodPost, target, "User service", asAdmin(c))
+}
+
+// RestoreReview publishes a hidden review again (JSON note).
+func RestoreReview(c echo.Context) error {
+	target := UserServiceURL + "/reviews/" + url.PathEscape(c.Param("review_id")) + "/restore"
+	return forwardAsFormWith(c, http.MethodPost, target, "User service", asAdmin(c))
+}
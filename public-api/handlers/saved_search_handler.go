@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func mySavedSearchesURL(c echo.Context) string {
+	return UserServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) + "/saved-searches"
+}
+
+func myAlertsURL(c echo.Context) string {
+	return UserServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) + "/alerts"
+}
+
+// CreateSavedSearch saves a JSON search for the current user
+func CreateSavedSearch(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, mySavedSearchesURL(c), "User service")
+}
+
+func GetSavedSearches(c echo.Context) error {
+	return forward(c, http.MethodGet, mySavedSearchesURL(c), "User service")
+}
+
+func UpdateSavedSearch(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPut, mySavedSearchesURL(c)+"/"+url.PathEscape(c.Param("search_id")), "User service")
+}
+
+func DeleteSavedSearch(c echo.Context) error {
+	return forward(c, http.MethodDelete, mySavedSearchesURL(c)+"/"+url.PathEscape(c.Param("search_id")), "User service")
+}
+
+// GetAlerts returns the current user's unread saved search alerts
+func GetAlerts(c echo.Context) error {
+	return forward(c, http.MethodGet, myAlertsURL(c), "User service")
+}
+
+// MarkAlertsRead accepts {"ids": [1, 2]}, or an empty body for all alerts
+func MarkAlertsRead(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myAlertsURL(c)+"/read", "User service")
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetAreaStats returns market statistics by area, property type and listing
+// type: inventory, price quartiles, price per m², days on market and weekly
+// new listings.
+func GetAreaStats(c echo.Context) error {
+	return forward(c, http.MethodGet, ListingServiceURL+"/listings/stats", "Listing service")
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"real-estate-system/public-api/middleware"
+	"real-estate-system/public-api/stream"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type StreamHandler struct {
+	Broker    *stream.Broker
+	Heartbeat time.Duration
+}
+
+func NewStreamHandler(broker *stream.Broker) *StreamHandler {
+	return &StreamHandler{Broker: broker, Heartbeat: 15 * time.Second}
+}
+
+// StreamListings is an SSE feed of listing created/updated/status changes.
+// It accepts the same filters as GetListings and resumes from Last-Event-ID.
+func (h *StreamHandler) StreamListings(c echo.Context) error {
+	filter, err := parseStreamFilter(c)
+	if err != nil {
+		return err
+	}
+
+	client := c.RealIP()
+	if key, ok := c.Get(middleware.ContextAPIKey).(string); ok && key != "" {
+		client = "key:" + key
+	}
+
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+
+	sub, replay, err := h.Broker.Subscribe(client, lastEventID)
+	if err == stream.ErrTooManyStreams {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "Too many concurrent streams")
+	}
+	defer h.Broker.Unsubscribe(sub)
+
+	res := openEventStream(c)
+
+	for _, event := range replay {
+		if filter.Matches(event) {
+			writeEvent(res, event)
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			fmt.Fprint(res, ": heartbeat\n\n")
+			res.Flush()
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind; the client resumes from its last ID.
+				return nil
+			}
+			if filter.Matches(event) {
+				writeEvent(res, event)
+				res.Flush()
+			}
+		}
+	}
+}
+
+// openEventStream writes the SSE response headers and reconnect delay.
+func openEventStream(c echo.Context) *echo.Response {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprint(res, "retry: 3000\n\n")
+	res.Flush()
+	return res
+}
+
+func writeEvent(res *echo.Response, event stream.Event) {
+	fmt.Fprintf(res, "id: %s\nevent: %s\ndata: {\"type\":%q,\"listing\":%s}\n\n", event.ID, event.Type, event.Type, event.Listing)
+}
+
+func parseStreamFilter(c echo.Context) (stream.Filter, error) {
+	filter := stream.Filter{
+		Tenant:      middleware.TenantID(c),
+		ListingType: c.QueryParam("listing_type"),
+		Area:        strings.TrimSpace(c.QueryParam("area")),
+	}
+
+	if filter.ListingType != "" && filter.ListingType != "rent" && filter.ListingType != "sale" {
+		return filter, echo.NewHTTPError(http.StatusBadRequest, "listing_type must be 'rent' or 'sale'")
+	}
+
+	for name, dst := range map[string]*int{"min_price": &filter.MinPrice, "max_price": &filter.MaxPrice} {
+		raw := c.QueryParam(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+		}
+		*dst = v
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository/interfaces"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxTenantName = 200
+
+var (
+	// Tenant IDs are passed to the internal services, which accept the
+	// same pattern.
+	validTenantID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,39}$`)
+	validHost     = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+)
+
+type TenantHandler struct {
+	Repo interfaces.TenantRepository
+}
+
+func NewTenantHandler(repo interfaces.TenantRepository) *TenantHandler {
+	return &TenantHandler{Repo: repo}
+}
+
+type tenantRequest struct {
+	ID        string   `json:"id"`
+	Name      *string  `json:"name"`
+	Hosts     []string `json:"hosts"`
+	RateLimit *int     `json:"rate_limit"`
+}
+
+// apply validates the request and copies its fields onto tenant.
+func (req tenantRequest) apply(tenant *models.Tenant) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > maxTenantName {
+			return echo.NewHTTPError(http.StatusBadRequest, "name is required and at most 200 characters")
+		}
+		tenant.Name = name
+	}
+	if req.Hosts != nil {
+		hosts := make([]string, 0, len(req.Hosts))
+		for _, host := range req.Hosts {
+			host = strings.ToLower(strings.TrimSpace(host))
+			if !validHost.MatchString(host) {
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid host "+host)
+			}
+			hosts = append(hosts, host)
+		}
+		tenant.Hosts = hosts
+	}
+	if req.RateLimit != nil {
+		if *req.RateLimit < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "rate_limit must not be negative")
+		}
+		tenant.RateLimit = *req.RateLimit
+	}
+	return nil
+}
+
+// newTenantAPIKey returns a fresh key and stores its hash on tenant.
+func newTenantAPIKey(tenant *models.Tenant) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	key := "tk_" + hex.EncodeToString(buf)
+	tenant.APIKeyHash = models.HashAPIKey(key)
+	return key, nil
+}
+
+func (h *TenantHandler) tenant(c echo.Context) (*models.Tenant, error) {
+	tenant, err := h.Repo.GetTenant(c.Param("id"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if tenant == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Tenant not found")
+	}
+	return tenant, nil
+}
+
+func saveTenantError(err error) error {
+	if errors.Is(err, models.ErrTenantExists) || errors.Is(err, models.ErrHostTaken) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
+
+// CreateTenant sets up a white-label tenant. Its API key is only returned
+// in this response.
+func (h *TenantHandler) CreateTenant(c echo.Context) error {
+	var req tenantRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON body")
+	}
+	if !validTenantID.MatchString(req.ID) || req.ID == models.DefaultTenant {
+		return echo.NewHTTPError(http.StatusBadRequest, "id must be 2-40 lowercase letters, digits or dashes")
+	}
+	if req.Name == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+
+	now := time.Now().UnixMicro()
+	tenant := models.Tenant{ID: req.ID, Hosts: []string{}, CreatedAt: now, UpdatedAt: now}
+	if err := req.apply(&tenant); err != nil {
+		return err
+	}
+	key, err := newTenantAPIKey(&tenant)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	if err := h.Repo.CreateTenant(&tenant); err != nil {
+		return saveTenantError(err)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":  true,
+		"tenant":  tenant,
+		"api_key": key,
+	})
+}
+
+func (h *TenantHandler) GetTenants(c echo.Context) error {
+	tenants, err := h.Repo.ListTenants()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"tenants": tenants,
+	})
+}
+
+func (h *TenantHandler) GetTenant(c echo.Context) error {
+	tenant, err := h.tenant(c)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"tenant": tenant,
+	})
+}
+
+// UpdateTenant changes the name, hosts or rate limit of a tenant.
+func (h *TenantHandler) UpdateTenant(c echo.Context) error {
+	tenant, err := h.tenant(c)
+	if err != nil {
+		return err
+	}
+
+	var req tenantRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON body")
+	}
+	if err := req.apply(tenant); err != nil {
+		return err
+	}
+	tenant.UpdatedAt = time.Now().UnixMicro()
+
+	if err := h.Repo.UpdateTenant(tenant); err != nil {
+		return saveTenantError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+		"tenant": tenant,
+	})
+}
+
+// RotateTenantAPIKey replaces the API key of a tenant; the old one stops
+// working at once.
+func (h *TenantHandler) RotateTenantAPIKey(c echo.Context) error {
+	tenant, err := h.tenant(c)
+	if err != nil {
+		return err
+	}
+
+	key, err := newTenantAPIKey(tenant)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	tenant.UpdatedAt = time.Now().UnixMicro()
+
+	if err := h.Repo.UpdateTenant(tenant); err != nil {
+		return saveTenantError(err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"tenant":  tenant,
+		"api_key": key,
+	})
+}
+
+// DeleteTenant removes a tenant's configuration. Its hosts then serve the
+// default tenant; its users and listings are kept.
+func (h *TenantHandler) DeleteTenant(c echo.Context) error {
+	tenant, err := h.tenant(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteTenant(tenant); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+	})
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAgencyServices(t *testing.T, listingOwner string, onForward http.HandlerFunc) func() {
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/managed-agents", r.URL.Path)
+		w.Write([]byte(`{"result":true,"agent_ids":[5,6]}`))
+	}))
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/listings/9" {
+			w.Write([]byte(`{"result":true,"listing":{"id":9,"user_id":` + listingOwner + `}}`))
+			return
+		}
+		onForward(w, r)
+	}))
+	handlers.UserServiceURL = mockUserService.URL
+	handlers.ListingServiceURL = mockListingService.URL
+	return func() {
+		mockUserService.Close()
+		mockListingService.Close()
+	}
+}
+
+func newAgencyListingContext(body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPatch, "/public-api/users/me/agency/listings/9/price", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("listing_id")
+	c.SetParamValues("9")
+	c.Set(middleware.ContextUserID, 5)
+	return c, rec
+}
+
+func TestUpdateAgencyListingPrice_AsTheAgent(t *testing.T) {
+	forwarded := false
+	defer newAgencyServices(t, "6", func(w http.ResponseWriter, r *http.Request) {
+		forwarded = true
+		assert.Equal(t, "/listings/9/price", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "6", r.FormValue("user_id"))
+		assert.Equal(t, "4500", r.FormValue("price"))
+		w.Write([]byte(`{"result":true}`))
+	})()
+
+	c, rec := newAgencyListingContext(`{"price": 4500, "user_id": 5}`)
+
+	assert.NoError(t, handlers.UpdateAgencyListingPrice(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, forwarded)
+}
+
+func TestUpdateAgencyListingPrice_OtherAgencysListing(t *testing.T) {
+	defer newAgencyServices(t, "7", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})()
+
+	c, _ := newAgencyListingContext(`{"price": 4500}`)
+
+	err := handlers.UpdateAgencyListingPrice(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+}
+
+func TestGetAgencyListings_ShowsAgentsListingsUnderReview(t *testing.T) {
+	defer newAgencyServices(t, "6", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings", r.URL.Path)
+		assert.Equal(t, "6", r.URL.Query().Get("user_id"))
+		assert.Equal(t, "6", r.URL.Query().Get("viewer_id"))
+		assert.Empty(t, r.URL.Query().Get("agent_id"))
+		w.Write([]byte(`{"result":true,"listings":[]}`))
+	})()
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/public-api/users/me/agency/listings?agent_id=6&viewer_id=1", nil), rec)
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.GetAgencyListings(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
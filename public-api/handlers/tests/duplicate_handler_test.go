@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDuplicate_ForwardsAsAdmin(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/duplicates/3/merge", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "5", r.FormValue("user_id"))
+		assert.Equal(t, "admin", r.FormValue("role"))
+		assert.Equal(t, "9", r.FormValue("keep_listing_id"))
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/admin/duplicates/3/merge", strings.NewReader(`{"keep_listing_id": 9, "user_id": 1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middleware.HeaderUserID, "5")
+	req.Header.Set(middleware.HeaderUserRole, "admin")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("pair_id")
+	c.SetParamValues("3")
+
+	handler := middleware.RequireUser()(middleware.RequireAdmin()(handlers.MergeDuplicate))
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetDuplicates_RequiresAdmin(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/public-api/admin/duplicates?role=admin", nil)
+	req.Header.Set(middleware.HeaderUserID, "5")
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	handler := middleware.RequireUser()(middleware.RequireAdmin()(handlers.GetDuplicates))
+	err := handler(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireUser_RejectsMissingHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/public-api/users/me/favorites", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := middleware.RequireUser()(func(c echo.Context) error { return nil })(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+}
+
+func TestAddFavorite_ForwardsAsPut(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/users/5/favorites/9", r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/me/favorites/9", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+	c.SetParamNames("listing_id")
+	c.SetParamValues("9")
+
+	assert.NoError(t, handlers.AddFavorite(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestGetFavorites_EmbedsOwner(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/favorites", r.URL.Path)
+		w.Write([]byte(`{"result":true,"favorites":[
+			{"listing_id":9,"favorited_at":1,"saved_price":4000,"price_dropped":true,"no_longer_available":false,"listing":{"id":9,"user_id":2}},
+			{"listing_id":10,"favorited_at":2,"no_longer_available":true,"listing":null}]}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/2", r.URL.Path)
+		w.Write([]byte(`{"result":true,"user":{"id":2,"name":"Alice"}}`))
+	}))
+	defer mockUserService.Close()
+	handlers.UserServiceURL = mockUserService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/users/me/favorites", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.GetFavorites(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Favorites []struct {
+			SavedPrice        int                    `json:"saved_price"`
+			PriceDropped      bool                   `json:"price_dropped"`
+			NoLongerAvailable bool                   `json:"no_longer_available"`
+			Listing           map[string]interface{} `json:"listing"`
+		} `json:"favorites"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Len(t, resp.Favorites, 2)
+	assert.Equal(t, 4000, resp.Favorites[0].SavedPrice)
+	assert.True(t, resp.Favorites[0].PriceDropped)
+	assert.Equal(t, "Alice", resp.Favorites[0].Listing["user"].(map[string]interface{})["name"])
+	assert.True(t, resp.Favorites[1].NoLongerAvailable)
+}
+
+func TestGetMyListings_AttachesFavoriteCounts(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/listings":
+			assert.Equal(t, "5", r.URL.Query().Get("user_id"))
+			assert.Equal(t, "5", r.URL.Query().Get("viewer_id"))
+			w.Write([]byte(`{"result":true,"listings":[{"id":9},{"id":10}]}`))
+		case "/listings/favorite-counts":
+			assert.Equal(t, "9,10", r.URL.Query().Get("ids"))
+			w.Write([]byte(`{"result":true,"counts":{"9":3}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/users/me/listings", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.GetMyListings(c))
+
+	var resp struct {
+		Listings []map[string]interface{} `json:"listings"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, float64(3), resp.Listings[0]["favorite_count"])
+	assert.Equal(t, float64(0), resp.Listings[1]["favorite_count"])
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateInquiry_OverridesSourceAndBuyer(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7/inquiries", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "5", r.FormValue("buyer_id"))
+		assert.Equal(t, "web", r.FormValue("source"))
+		assert.Equal(t, "", r.FormValue("source_ref"))
+		assert.Equal(t, "Still available?", r.FormValue("message"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true,"inquiry":{"id":1}}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	body := `{"message":"Still available?","email":"a@example.com","buyer_id":99,"source":"partner","source_ref":"spoofed"}`
+	req := httptest.NewRequest(http.MethodPost, "/public-api/listings/7/inquiries", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, handlers.CreateInquiry(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestCreatePartnerInquiry_AttributesPartner(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "partner", r.FormValue("source"))
+		assert.Equal(t, middleware.APIKeyFingerprint("key-a"), r.FormValue("source_ref"))
+		assert.NotContains(t, r.Form.Encode(), "key-a")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/partner/listings/7/inquiries", strings.NewReader(`{"message":"Hi","email":"lead@example.com"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextAPIKey, "key-a")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, handlers.CreatePartnerInquiry(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestInquiryLimiter_ThrottlesPerSender(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	limiter := middleware.NewKeyedRateLimiter(rdb, "ratelimit:inquiry", 2, time.Hour, handlers.InquirySender)
+	ok := limiter(func(c echo.Context) error { return c.NoContent(http.StatusCreated) })
+
+	send := func(userID int) int {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+		c.Set(middleware.ContextUserID, userID)
+		assert.NoError(t, ok(c))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, send(5))
+	assert.Equal(t, http.StatusCreated, send(5))
+	assert.Equal(t, http.StatusTooManyRequests, send(5))
+	assert.Equal(t, http.StatusCreated, send(6))
+	assert.True(t, mr.Exists("ratelimit:inquiry:user:5"))
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadListingPhoto_ForwardsFileAsCurrentUser(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7/photos", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "5", r.FormValue("user_id"))
+		file, header, err := r.FormFile("photo")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "kitchen.jpg", header.Filename)
+		assert.Equal(t, "jpeg bytes", string(data))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true,"photo":{"id":3}}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("user_id", "1")
+	part, _ := writer.CreateFormFile("photo", "kitchen.jpg")
+	part.Write([]byte("jpeg bytes"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/me/listings/7/photos", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("listing_id")
+	c.SetParamValues("7")
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.UploadListingPhoto(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestDeleteListingPhoto_PassesUserInQuery(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/listings/7/photos/3", r.URL.Path)
+		assert.Equal(t, "5", r.URL.Query().Get("user_id"))
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodDelete, "/public-api/users/me/listings/7/photos/3?user_id=1", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("listing_id", "photo_id")
+	c.SetParamValues("7", "3")
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.DeleteListingPhoto(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCreateListingAttachment_ForwardsTypeAndFile(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7/attachments", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "5", r.FormValue("user_id"))
+		assert.Equal(t, "floor_plan", r.FormValue("type"))
+		assert.Equal(t, "Ground floor", r.FormValue("title"))
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "%PDF-1.7", string(data))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true,"attachment":{"id":4}}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("type", "floor_plan")
+	writer.WriteField("title", "Ground floor")
+	part, _ := writer.CreateFormFile("file", "plan.pdf")
+	part.Write([]byte("%PDF-1.7"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/me/listings/7/attachments", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("listing_id")
+	c.SetParamValues("7")
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.CreateListingAttachment(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestGetMyListingAttachments_PassesUserAndRole(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7/attachments", r.URL.Path)
+		assert.Equal(t, "5", r.URL.Query().Get("user_id"))
+		assert.Equal(t, "admin", r.URL.Query().Get("role"))
+		assert.Equal(t, "document", r.URL.Query().Get("type"))
+		w.Write([]byte(`{"result":true,"attachments":[]}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/users/me/listings/7/attachments?type=document&role=user", nil)
+	req.Header.Set(middleware.HeaderUserID, "5")
+	req.Header.Set(middleware.HeaderUserRole, "admin")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("listing_id")
+	c.SetParamValues("7")
+
+	handler := middleware.RequireUser()(handlers.GetMyListingAttachments)
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
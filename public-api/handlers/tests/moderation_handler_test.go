@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectListing_ForwardsAsAdmin(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderation/reviews/5/reject", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "7", r.FormValue("user_id"))
+		assert.Equal(t, "admin", r.FormValue("role"))
+		assert.Equal(t, "Asks for a deposit by wire transfer", r.FormValue("note"))
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/admin/moderation/reviews/5/reject", strings.NewReader(`{"note": "Asks for a deposit by wire transfer"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(middleware.HeaderUserID, "7")
+	req.Header.Set(middleware.HeaderUserRole, "admin")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("review_id")
+	c.SetParamValues("5")
+
+	handler := middleware.RequireUser()(middleware.RequireAdmin()(handlers.RejectListing))
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGetModerationQueue_KeepsFilters(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/moderation/reviews", r.URL.Path)
+		assert.Equal(t, "rejected", r.URL.Query().Get("status"))
+		assert.Equal(t, []string{"7"}, r.URL.Query()["user_id"])
+		w.Write([]byte(`{"result":true,"reviews":[]}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/admin/moderation/reviews?status=rejected&user_id=1", nil)
+	req.Header.Set(middleware.HeaderUserID, "7")
+	req.Header.Set(middleware.HeaderUserRole, "admin")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	handler := middleware.RequireUser()(middleware.RequireAdmin()(handlers.GetModerationQueue))
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
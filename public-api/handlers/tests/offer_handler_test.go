@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeOffer_UsesCurrentUserAsBuyer(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7/offers", r.URL.Path)
+		r.ParseForm()
+		assert.Equal(t, "5", r.FormValue("buyer_id"))
+		assert.Equal(t, "900", r.FormValue("amount"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true,"offer":{"id":4}}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/listings/7/offers", strings.NewReader(`{"amount":900,"buyer_id":99}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.MakeOffer(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
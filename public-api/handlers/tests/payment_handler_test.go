@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayRent_PassesIdempotencyKeyHeader(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/leases/3/payments", r.URL.Path)
+		r.ParseForm()
+		assert.Equal(t, "500000", r.FormValue("amount"))
+		assert.Equal(t, "retry-1", r.FormValue("idempotency_key"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true,"payment":{"id":9}}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/me/leases/3/payments", strings.NewReader(`{"amount":500000,"payment_method":"card_123"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Idempotency-Key", "retry-1")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("lease_id")
+	c.SetParamValues("3")
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.PayRent(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPriceHistory_DropsClaimedUserAndRole(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7/price-history", r.URL.Path)
+		assert.Empty(t, r.URL.RawQuery)
+		w.Write([]byte(`{"result":true,"listing_id":7,"price_history":[]}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/listings/7/price-history?user_id=3&role=admin", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, handlers.GetPriceHistory(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
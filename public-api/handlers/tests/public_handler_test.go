@@ -147,6 +147,72 @@ func TestGetListings_Success(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), "Alice")
 }
 
+func TestGetListings_VerifiedAgentBadge(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":true,"listings":[{"id":1,"user_id":2},{"id":2,"user_id":3}]}`))
+	}))
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/2" {
+			w.Write([]byte(`{"result":true,"user":{"id":2,"name":"Alice","agent":{"verified":true,"license_number":"AREBI-1"}}}`))
+			return
+		}
+		w.Write([]byte(`{"result":true,"user":{"id":3,"name":"Bob","agent":{"verified":false}}}`))
+	}))
+	defer mockListingService.Close()
+	defer mockUserService.Close()
+
+	handlers.ListingServiceURL = mockListingService.URL
+	handlers.UserServiceURL = mockUserService.URL
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/listings", nil), rec)
+
+	assert.NoError(t, handlers.GetListings(c))
+
+	var response struct {
+		Listings []struct {
+			ID     int      `json:"id"`
+			Badges []string `json:"badges"`
+		} `json:"listings"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []string{"verified_agent"}, response.Listings[0].Badges)
+	assert.Empty(t, response.Listings[1].Badges)
+}
+
+func TestGetListings_OwnerRating(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":true,"listings":[{"id":1,"user_id":2}]}`))
+	}))
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":true,"user":{"id":2,"name":"Alice","rating":{"average":4.5,"count":12}}}`))
+	}))
+	defer mockListingService.Close()
+	defer mockUserService.Close()
+
+	handlers.ListingServiceURL = mockListingService.URL
+	handlers.UserServiceURL = mockUserService.URL
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/listings", nil), rec)
+
+	assert.NoError(t, handlers.GetListings(c))
+
+	var response struct {
+		Listings []struct {
+			User struct {
+				Rating struct {
+					Average float64 `json:"average"`
+					Count   int     `json:"count"`
+				} `json:"rating"`
+			} `json:"user"`
+		} `json:"listings"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 4.5, response.Listings[0].User.Rating.Average)
+	assert.Equal(t, 12, response.Listings[0].User.Rating.Count)
+}
+
 func TestGetListings_UserEnrichFailSafe(t *testing.T) {
 	e := echo.New()
 
@@ -257,6 +323,23 @@ func (b *badReader) Read(p []byte) (int, error) {
 	return 0, io.ErrUnexpectedEOF
 }
 
+func TestGetListings_DropsViewerID(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "3", r.URL.Query().Get("user_id"))
+		assert.Empty(t, r.URL.Query().Get("viewer_id"))
+		w.Write([]byte(`{"result":true,"listings":[]}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/listings?user_id=3&viewer_id=3", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, handlers.GetListings(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
 func TestCreateListing_InvalidJSON(t *testing.T) {
 	e := echo.New()
 	badJSON := `{"user_id": 1, "listing_type": "rent", "price": ` // broken JSON
@@ -302,7 +385,69 @@ func TestGetListings_UserGetFails_Completely(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), "listings")
 }
 
+func TestToString_KeepsDecimals(t *testing.T) {
+	assert.Equal(t, "-6.2607", handlers.ToString(-6.2607))
+	assert.Equal(t, "2000000000", handlers.ToString(float64(2000000000)))
+}
+
 func TestToString_UnsupportedType(t *testing.T) {
 	result := handlers.ToString(true) // bool is not handled in switch
 	assert.Equal(t, "", result)
 }
+
+func TestGetListing_WithListerAndMortgageEstimate(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7", r.URL.Path)
+		assert.Equal(t, "currency=USD", r.URL.RawQuery)
+		w.Write([]byte(`{"result":true,"listing":{"id":7,"user_id":3,"listing_type":"sale"},"mortgage_estimate":{"loan":80000000000}}`))
+	}))
+	defer mockListingService.Close()
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/3", r.URL.Path)
+		w.Write([]byte(`{"result":true,"user":{"id":3,"name":"Jane","agent":{"verified":true}}}`))
+	}))
+	defer mockUserService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+	handlers.UserServiceURL = mockUserService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/listings/7?currency=USD&user_id=3&role=admin", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, handlers.GetListing(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Listing struct {
+			User   map[string]interface{} `json:"user"`
+			Badges []string               `json:"badges"`
+		} `json:"listing"`
+		MortgageEstimate struct {
+			Loan int64 `json:"loan"`
+		} `json:"mortgage_estimate"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Jane", response.Listing.User["name"])
+	assert.Equal(t, []string{"verified_agent"}, response.Listing.Badges)
+	assert.Equal(t, int64(80000000000), response.MortgageEstimate.Loan)
+}
+
+func TestGetListing_NotFound(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Listing not found"}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/listings/9", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("9")
+
+	assert.NoError(t, handlers.GetListing(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
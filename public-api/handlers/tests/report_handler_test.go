@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportListing_AsCurrentUser(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/9/reports", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "5", r.FormValue("reporter_id"))
+		assert.Equal(t, "deposit_first", r.FormValue("reason"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/listings/9/reports", strings.NewReader(`{"reason": "deposit_first", "reporter_id": 1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("9")
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.ReportListing(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestReportUser_UnknownUser(t *testing.T) {
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"User not found"}`))
+	}))
+	defer mockUserService.Close()
+	handlers.UserServiceURL = mockUserService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/42/reports", strings.NewReader(`{"reason": "spam"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+	c.Set(middleware.ContextUserID, 5)
+
+	err := handlers.ReportUser(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestGetTrustRecord_AsAdmin(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/trust-records/3", r.URL.Path)
+		assert.Equal(t, "7", r.URL.Query().Get("user_id"))
+		assert.Equal(t, "admin", r.URL.Query().Get("role"))
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/admin/users/3/trust", nil)
+	req.Header.Set(middleware.HeaderUserID, "7")
+	req.Header.Set(middleware.HeaderUserRole, "admin")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+
+	handler := middleware.RequireUser()(middleware.RequireAdmin()(handlers.GetTrustRecord))
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReviewServices(t *testing.T, interactions string, onReview http.HandlerFunc) func() {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/interactions", r.URL.Path)
+		assert.Equal(t, "8", r.URL.Query().Get("with"))
+		w.Write([]byte(`{"result":true,"interactions":` + interactions + `}`))
+	}))
+	mockUserService := httptest.NewServer(onReview)
+	handlers.ListingServiceURL = mockListingService.URL
+	handlers.UserServiceURL = mockUserService.URL
+	return func() {
+		mockListingService.Close()
+		mockUserService.Close()
+	}
+}
+
+func newReviewContext(body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/8/reviews", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("8")
+	c.Set(middleware.ContextUserID, 5)
+	return c, rec
+}
+
+func TestCreateReview_CitesLatestInteraction(t *testing.T) {
+	forwarded := false
+	defer newReviewServices(t, `[{"type":"viewing","id":2,"listing_id":7},{"type":"lease","id":9,"listing_id":8}]`,
+		func(w http.ResponseWriter, r *http.Request) {
+			forwarded = true
+			assert.Equal(t, "/users/8/reviews", r.URL.Path)
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "5", r.FormValue("reviewer_id"))
+			assert.Equal(t, "viewing", r.FormValue("interaction_type"))
+			assert.Equal(t, "2", r.FormValue("interaction_id"))
+			assert.Equal(t, "4", r.FormValue("rating"))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"result":true}`))
+		})()
+
+	c, rec := newReviewContext(`{"rating": 4, "text": "Showed up on time", "reviewer_id": 8, "interaction_id": 1}`)
+
+	assert.NoError(t, handlers.CreateReview(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.True(t, forwarded)
+}
+
+func TestCreateReview_WithoutInteraction(t *testing.T) {
+	defer newReviewServices(t, `[]`, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	})()
+
+	c, _ := newReviewContext(`{"rating": 1}`)
+
+	err := handlers.CreateReview(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSavedSearch_ForwardsAsForm(t *testing.T) {
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/saved-searches", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "rent", r.FormValue("listing_type"))
+		assert.Equal(t, "4000", r.FormValue("max_price"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true,"saved_search":{"id":1}}`))
+	}))
+	defer mockUserService.Close()
+	handlers.UserServiceURL = mockUserService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/me/saved-searches", strings.NewReader(`{"name":"Cheap","listing_type":"rent","max_price":4000}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+
+	err := handlers.CreateSavedSearch(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestGetAlerts_ForwardsQuery(t *testing.T) {
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/alerts", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("all"))
+		w.Write([]byte(`{"result":true,"alerts":[]}`))
+	}))
+	defer mockUserService.Close()
+	handlers.UserServiceURL = mockUserService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/users/me/alerts?all=true", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.GetAlerts(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestMarkAlertsRead_JoinsIDs(t *testing.T) {
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/alerts/read", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "1,2", r.FormValue("ids"))
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockUserService.Close()
+	handlers.UserServiceURL = mockUserService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/me/alerts/read", strings.NewReader(`{"ids":[1,2]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+
+	assert.NoError(t, handlers.MarkAlertsRead(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestDeleteSavedSearch_ServiceUnavailable(t *testing.T) {
+	handlers.UserServiceURL = "http://localhost:9999"
+
+	req := httptest.NewRequest(http.MethodDelete, "/public-api/users/me/saved-searches/1", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+	c.SetParamNames("search_id")
+	c.SetParamValues("1")
+
+	err := handlers.DeleteSavedSearch(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, err.(*echo.HTTPError).Code)
+}
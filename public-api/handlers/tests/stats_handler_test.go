@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAreaStats_ForwardsQuery(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/stats", r.URL.Path)
+		assert.Equal(t, "district", r.URL.Query().Get("group_by"))
+		assert.Equal(t, "Berlin", r.URL.Query().Get("city"))
+		w.Write([]byte(`{"result":true,"stats":[]}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/listings/stats?group_by=district&city=Berlin", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, handlers.GetAreaStats(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"result":true,"stats":[]}`, rec.Body.String())
+}
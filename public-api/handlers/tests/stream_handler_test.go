@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"real-estate-system/public-api/stream"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamListings_ReplaysAndFilters(t *testing.T) {
+	broker := stream.NewBroker(10, 3)
+	broker.Publish(stream.Event{ID: "1-0", Type: "listing.created", Listing: []byte(`{"id":1,"listing_type":"rent"}`)})
+	broker.Publish(stream.Event{ID: "2-0", Type: "listing.created", Listing: []byte(`{"id":2,"listing_type":"sale"}`)})
+	broker.Publish(stream.Event{ID: "3-0", Type: "listing.updated", Listing: []byte(`{"id":3,"listing_type":"rent"}`)})
+
+	h := handlers.NewStreamHandler(broker)
+	h.Heartbeat = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/listings/stream?listing_type=rent", nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "1-0")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := h.StreamListings(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "id: 3-0\nevent: listing.updated\n")
+	assert.NotContains(t, body, "id: 1-0")
+	assert.NotContains(t, body, "id: 2-0")
+	assert.Contains(t, body, ": heartbeat")
+}
+
+func TestStreamListings_TooManyStreams(t *testing.T) {
+	broker := stream.NewBroker(10, 1)
+	_, _, err := broker.Subscribe("192.0.2.1", "")
+	assert.NoError(t, err)
+
+	h := handlers.NewStreamHandler(broker)
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/listings/stream", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err = h.StreamListings(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+}
+
+func TestStreamListings_CapsKnownAPIKeysApartFromTheirIP(t *testing.T) {
+	broker := stream.NewBroker(10, 1)
+	_, _, err := broker.Subscribe("192.0.2.1", "")
+	assert.NoError(t, err)
+
+	h := handlers.NewStreamHandler(broker)
+	streamListings := middleware.IdentifyAPIKey([]string{"partner-key"})(h.StreamListings)
+
+	open := func(apiKey string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/public-api/listings/stream", nil).WithContext(ctx)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set(middleware.HeaderAPIKey, apiKey)
+		return streamListings(echo.New().NewContext(req, httptest.NewRecorder()))
+	}
+
+	assert.NoError(t, open("partner-key"))
+
+	err = open("made-up-key")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, err.(*echo.HTTPError).Code)
+}
+
+func TestStreamListings_InvalidFilter(t *testing.T) {
+	h := handlers.NewStreamHandler(stream.NewBroker(10, 1))
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/listings/stream?max_price=abc", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := h.StreamListings(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
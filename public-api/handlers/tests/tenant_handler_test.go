@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	custommiddleware "real-estate-system/public-api/middleware"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository/mocks"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTenantContext(method, target, body string, id string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	return c, rec
+}
+
+func TestCreateTenant_ReturnsAPIKeyOnce(t *testing.T) {
+	repo := new(mocks.TenantRepositoryMock)
+	h := handlers.NewTenantHandler(repo)
+
+	var created *models.Tenant
+	repo.On("CreateTenant", mock.MatchedBy(func(tenant *models.Tenant) bool {
+		created = tenant
+		return tenant.ID == "acme" && tenant.Name == "Acme Homes" && tenant.RateLimit == 600 &&
+			len(tenant.Hosts) == 1 && tenant.Hosts[0] == "homes.acme.test"
+	})).Return(nil)
+
+	c, rec := newTenantContext(http.MethodPost, "/public-api/admin/tenants",
+		`{"id":"acme","name":"Acme Homes","hosts":["Homes.Acme.test"],"rate_limit":600}`, "")
+
+	assert.NoError(t, h.CreateTenant(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"api_key":"tk_`)
+	assert.NotContains(t, rec.Body.String(), created.APIKeyHash)
+	assert.NotEmpty(t, created.APIKeyHash)
+}
+
+func TestCreateTenant_InvalidOrReservedID(t *testing.T) {
+	h := handlers.NewTenantHandler(new(mocks.TenantRepositoryMock))
+
+	for _, id := range []string{"default", "Acme", "a", "acme/../x"} {
+		c, _ := newTenantContext(http.MethodPost, "/public-api/admin/tenants", `{"id":"`+id+`","name":"Acme"}`, "")
+		err := h.CreateTenant(c)
+		assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code, id)
+	}
+}
+
+func TestCreateTenant_HostTaken(t *testing.T) {
+	repo := new(mocks.TenantRepositoryMock)
+	h := handlers.NewTenantHandler(repo)
+	repo.On("CreateTenant", mock.Anything).Return(models.ErrHostTaken)
+
+	c, _ := newTenantContext(http.MethodPost, "/public-api/admin/tenants", `{"id":"acme","name":"Acme","hosts":["acme.test"]}`, "")
+
+	err := h.CreateTenant(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateTenant_ChangesRateLimit(t *testing.T) {
+	repo := new(mocks.TenantRepositoryMock)
+	h := handlers.NewTenantHandler(repo)
+	repo.On("GetTenant", "acme").Return(&models.Tenant{ID: "acme", Name: "Acme", Hosts: []string{"acme.test"}}, nil)
+	repo.On("UpdateTenant", mock.MatchedBy(func(tenant *models.Tenant) bool {
+		return tenant.RateLimit == 100 && tenant.Name == "Acme" && len(tenant.Hosts) == 1
+	})).Return(nil)
+
+	c, rec := newTenantContext(http.MethodPut, "/public-api/admin/tenants/acme", `{"rate_limit":100}`, "acme")
+
+	assert.NoError(t, h.UpdateTenant(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestRotateTenantAPIKey_ReplacesHash(t *testing.T) {
+	repo := new(mocks.TenantRepositoryMock)
+	h := handlers.NewTenantHandler(repo)
+	old := models.HashAPIKey("tk_old")
+	repo.On("GetTenant", "acme").Return(&models.Tenant{ID: "acme", APIKeyHash: old}, nil)
+	repo.On("UpdateTenant", mock.MatchedBy(func(tenant *models.Tenant) bool {
+		return tenant.APIKeyHash != "" && tenant.APIKeyHash != old
+	})).Return(nil)
+
+	c, rec := newTenantContext(http.MethodPost, "/public-api/admin/tenants/acme/api-key", "", "acme")
+
+	assert.NoError(t, h.RotateTenantAPIKey(c))
+	assert.Contains(t, rec.Body.String(), `"api_key":"tk_`)
+	repo.AssertExpectations(t)
+}
+
+func TestGetTenant_NotFound(t *testing.T) {
+	repo := new(mocks.TenantRepositoryMock)
+	h := handlers.NewTenantHandler(repo)
+	repo.On("GetTenant", "nope").Return(nil, nil)
+
+	c, _ := newTenantContext(http.MethodGet, "/public-api/admin/tenants/nope", "", "nope")
+
+	err := h.GetTenant(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+// The services scope every read to X-Tenant-ID, so the public API must send
+// the resolved tenant and never the one a client claims.
+func TestGetListings_SendsResolvedTenantOnly(t *testing.T) {
+	var listingTenant, userTenant string
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listingTenant = r.Header.Get(custommiddleware.HeaderTenantID)
+		w.Write([]byte(`{"result":true,"listings":[{"id":1,"user_id":2}]}`))
+	}))
+	mockUserService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userTenant = r.Header.Get(custommiddleware.HeaderTenantID)
+		w.Write([]byte(`{"result":true,"user":{"id":2}}`))
+	}))
+	defer mockListingService.Close()
+	defer mockUserService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+	handlers.UserServiceURL = mockUserService.URL
+
+	tenants := new(mocks.TenantRepositoryMock)
+	tenants.On("GetTenantByHost", "homes.acme.test").Return(&models.Tenant{ID: "acme"}, nil)
+	tenants.On("GetTenantByHost", "example.test").Return(nil, nil)
+
+	e := echo.New()
+	e.Use(custommiddleware.ResolveTenant(tenants))
+	e.GET("/public-api/listings", handlers.GetListings)
+
+	for host, want := range map[string]string{"homes.acme.test": "acme", "example.test": models.DefaultTenant} {
+		req := httptest.NewRequest(http.MethodGet, "/public-api/listings", nil)
+		req.Host = host
+		req.Header.Set(custommiddleware.HeaderTenantID, "other")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, want, listingTenant, host)
+		assert.Equal(t, want, userTenant, host)
+	}
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostMessage_KeepsAttachmentsAsJSON(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/5/threads/4/messages", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "See attached", r.FormValue("body"))
+		assert.JSONEq(t, `[{"name":"plan.pdf","url":"https://files.example.com/plan.pdf","size":10}]`, r.FormValue("attachments"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	body := `{"body":"See attached","attachments":[{"name":"plan.pdf","url":"https://files.example.com/plan.pdf","size":10}]}`
+	req := httptest.NewRequest(http.MethodPost, "/public-api/users/me/threads/4/messages", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+	c.SetParamNames("thread_id")
+	c.SetParamValues("4")
+
+	assert.NoError(t, handlers.PostMessage(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestStartThread_UsesCurrentUserAsBuyer(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/7/threads", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "5", r.FormValue("buyer_id"))
+		w.Write([]byte(`{"result":true}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodPost, "/public-api/listings/7/threads", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 5)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	assert.NoError(t, handlers.StartThread(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateValue_ForwardsAsForm(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/listings/valuation", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "sale", r.FormValue("listing_type"))
+		assert.Equal(t, "120", r.FormValue("floor_area"))
+		w.Write([]byte(`{"result":true,"valuation":{"estimated_value":2000}}`))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	body := `{"listing_type":"sale","city":"Jakarta","floor_area":120}`
+	req := httptest.NewRequest(http.MethodPost, "/public-api/listings/valuation", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, handlers.EstimateValue(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
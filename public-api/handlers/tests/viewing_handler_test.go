@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/middleware"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportViewings_KeepsCalendarContentType(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/2/viewings.ics", r.URL.Path)
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="viewings.ics"`)
+		w.Write([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/users/me/viewings.ics", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(middleware.ContextUserID, 2)
+
+	assert.NoError(t, handlers.ExportViewings(c))
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "viewings.ics")
+	assert.Contains(t, rec.Body.String(), "BEGIN:VCALENDAR")
+}
+
+func TestGetViewingsCalendarFeed_ForwardsSignature(t *testing.T) {
+	mockListingService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/calendar/viewings/2", r.URL.Path)
+		assert.Equal(t, "123", r.URL.Query().Get("expires"))
+		assert.Equal(t, "abc", r.URL.Query().Get("signature"))
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Write([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	}))
+	defer mockListingService.Close()
+	handlers.ListingServiceURL = mockListingService.URL
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/calendar/viewings/2?expires=123&signature=abc", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("user_id")
+	c.SetParamValues("2")
+
+	assert.NoError(t, handlers.GetViewingsCalendarFeed(c))
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "BEGIN:VCALENDAR")
+}
@@ -0,0 +1,209 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/handlers"
+	custommiddleware "real-estate-system/public-api/middleware"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository/mocks"
+	"real-estate-system/public-api/webhooks"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newWebhookContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set(custommiddleware.ContextAPIKey, "partner-a")
+	return c, rec
+}
+
+func TestCreateWebhook_Success(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	repo.On("CreateSubscription", mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+		return s.APIKey == "partner-a" && s.Active && strings.HasPrefix(s.Secret, "whsec_")
+	})).Return(nil)
+
+	c, rec := newWebhookContext(http.MethodPost, "/public-api/webhooks", `{"url":"https://partner.example/hook","event_types":["listing.*"]}`)
+
+	err := h.CreateWebhook(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), "whsec_")
+	repo.AssertExpectations(t)
+}
+
+func TestGetWebhook_OtherTenantNotFound(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+	repo.On("GetSubscription", int64(1)).Return(&models.WebhookSubscription{ID: 1, APIKey: "partner-a", TenantID: "acme"}, nil)
+
+	c, _ := newWebhookContext(http.MethodGet, "/public-api/webhooks/1", "")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := h.GetWebhook(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestCreateWebhook_InvalidURL(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	c, _ := newWebhookContext(http.MethodPost, "/public-api/webhooks", `{"url":"ftp://nope"}`)
+
+	err := h.CreateWebhook(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
+
+func TestCreateWebhook_PrivateHost(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	c, _ := newWebhookContext(http.MethodPost, "/public-api/webhooks", `{"url":"https://169.254.169.254/latest/meta-data"}`)
+
+	err := h.CreateWebhook(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "CreateSubscription", mock.Anything)
+}
+
+func TestGetWebhooks_HidesSecrets(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	repo.On("ListSubscriptions", "partner-a").Return([]models.WebhookSubscription{
+		{ID: 1, URL: "https://partner.example/hook", Secret: "whsec_abc"},
+	}, nil)
+
+	c, rec := newWebhookContext(http.MethodGet, "/public-api/webhooks", "")
+
+	err := h.GetWebhooks(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "whsec_abc")
+}
+
+func TestGetWebhook_OtherPartnerIsNotFound(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	repo.On("GetSubscription", int64(1)).Return(&models.WebhookSubscription{ID: 1, APIKey: "partner-b"}, nil)
+
+	c, _ := newWebhookContext(http.MethodGet, "/public-api/webhooks/1", "")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := h.GetWebhook(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateWebhook_Deactivate(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	repo.On("GetSubscription", int64(1)).Return(&models.WebhookSubscription{ID: 1, APIKey: "partner-a", Active: true}, nil)
+	repo.On("UpdateSubscription", mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+		return !s.Active
+	})).Return(nil)
+
+	c, rec := newWebhookContext(http.MethodPut, "/public-api/webhooks/1", `{"active":false}`)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := h.UpdateWebhook(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestDeleteWebhook_RepoError(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	sub := &models.WebhookSubscription{ID: 1, APIKey: "partner-a"}
+	repo.On("GetSubscription", int64(1)).Return(sub, nil)
+	repo.On("DeleteSubscription", sub).Return(errors.New("redis down"))
+
+	c, _ := newWebhookContext(http.MethodDelete, "/public-api/webhooks/1", "")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := h.DeleteWebhook(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, err.(*echo.HTTPError).Code)
+}
+
+func TestGetDeliveries_Success(t *testing.T) {
+	repo := new(mocks.WebhookRepositoryMock)
+	h := handlers.NewWebhookHandler(repo, webhooks.NewDispatcher(repo))
+
+	repo.On("GetSubscription", int64(1)).Return(&models.WebhookSubscription{ID: 1, APIKey: "partner-a"}, nil)
+	repo.On("ListDeliveries", int64(1), 50).Return([]models.WebhookDelivery{{ID: 9, Status: models.DeliveryDead}}, nil)
+
+	c, rec := newWebhookContext(http.MethodGet, "/public-api/webhooks/1/deliveries", "")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := h.GetDeliveries(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"dead"`)
+}
+
+func TestRedeliver_Success(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := new(mocks.WebhookRepositoryMock)
+	dispatcher := webhooks.NewDispatcher(repo)
+	dispatcher.Client = receiver.Client()
+	h := handlers.NewWebhookHandler(repo, dispatcher)
+
+	sub := &models.WebhookSubscription{ID: 1, APIKey: "partner-a", URL: receiver.URL}
+	repo.On("GetSubscription", int64(1)).Return(sub, nil)
+	repo.On("GetDelivery", int64(9)).Return(&models.WebhookDelivery{ID: 9, SubscriptionID: 1, Status: models.DeliveryDead, Attempts: 8}, nil)
+	repo.On("SaveDelivery", mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.Status == models.DeliverySucceeded
+	})).Return(nil)
+
+	c, rec := newWebhookContext(http.MethodPost, "/public-api/webhooks/1/deliveries/9/redeliver", "")
+	c.SetParamNames("id", "delivery_id")
+	c.SetParamValues("1", "9")
+
+	err := h.Redeliver(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	e := echo.New()
+	mw := custommiddleware.RequireAPIKey([]string{"partner-a"})
+	next := mw(func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Get(custommiddleware.ContextAPIKey).(string))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/public-api/webhooks", nil)
+	rec := httptest.NewRecorder()
+	err := next(e.NewContext(req, rec))
+	assert.Equal(t, http.StatusUnauthorized, err.(*echo.HTTPError).Code)
+
+	req.Header.Set(custommiddleware.HeaderAPIKey, "partner-a")
+	rec = httptest.NewRecorder()
+	assert.NoError(t, next(e.NewContext(req, rec)))
+	assert.Equal(t, "partner-a", rec.Body.String())
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"real-estate-system/public-api/stream"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myThreadURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int)) +
+		"/threads/" + url.PathEscape(c.Param("thread_id"))
+}
+
+// StartThread opens (or returns) the current user's conversation with the
+// owner of a listing.
+func StartThread(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/threads", "Listing service", url.Values{
+		"buyer_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))},
+	})
+}
+
+// GetThreads lists the current user's threads with unread counts.
+func GetThreads(c echo.Context) error {
+	return forward(c, http.MethodGet, ListingServiceURL+"/users/"+strconv.Itoa(c.Get(middleware.ContextUserID).(int))+"/threads", "Listing service")
+}
+
+// GetMessages pages through a thread with the before cursor.
+func GetMessages(c echo.Context) error {
+	return forward(c, http.MethodGet, myThreadURL(c)+"/messages", "Listing service")
+}
+
+// PostMessage sends body and optional attachments metadata to a thread.
+func PostMessage(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myThreadURL(c)+"/messages", "Listing service")
+}
+
+// MarkThreadRead records a read receipt up to message_id, or the latest.
+func MarkThreadRead(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myThreadURL(c)+"/read", "Listing service")
+}
+
+type InboxHandler struct {
+	Inbox     *stream.Inbox
+	Heartbeat time.Duration
+}
+
+func NewInboxHandler(inbox *stream.Inbox) *InboxHandler {
+	return &InboxHandler{Inbox: inbox, Heartbeat: 15 * time.Second}
+}
+
+// StreamMessages is an SSE feed of new messages, read receipts and closed
+// threads for the current user.
+func (h *InboxHandler) StreamMessages(c echo.Context) error {
+	sub, err := h.Inbox.Subscribe(c.Get(middleware.ContextUserID).(int))
+	if err == stream.ErrTooManyStreams {
+		return echo.NewHTTPError(http.StatusTooManyRequests, "Too many concurrent streams")
+	}
+	defer h.Inbox.Unsubscribe(sub)
+
+	res := openEventStream(c)
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			fmt.Fprint(res, ": heartbeat\n\n")
+			res.Flush()
+		case event, ok := <-sub.Events:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+			res.Flush()
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EstimateValue estimates a property's price range from comparable listings
+// (JSON listing_type, property_type, city, district, latitude, longitude,
+// bedrooms, bathrooms, floor_area, currency, rent_period).
+func EstimateValue(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, ListingServiceURL+"/listings/valuation", "Listing service")
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/middleware"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func myViewingsURL(c echo.Context) string {
+	return ListingServiceURL + "/users/" + strconv.Itoa(c.Get(middleware.ContextUserID).(int))
+}
+
+// GetViewingSlots returns a listing's open viewing slots and taken times.
+func GetViewingSlots(c echo.Context) error {
+	return forward(c, http.MethodGet, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/viewing-slots", "Listing service")
+}
+
+// BookViewing books a viewing for the current user.
+func BookViewing(c echo.Context) error {
+	return forwardAsFormWith(c, http.MethodPost, ListingServiceURL+"/listings/"+url.PathEscape(c.Param("id"))+"/viewings", "Listing service", url.Values{
+		"buyer_id": {strconv.Itoa(c.Get(middleware.ContextUserID).(int))},
+	})
+}
+
+func CreateViewingSlot(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myViewingsURL(c)+"/viewing-slots", "Listing service")
+}
+
+func DeleteViewingSlot(c echo.Context) error {
+	return forward(c, http.MethodDelete, myViewingsURL(c)+"/viewing-slots/"+url.PathEscape(c.Param("slot_id")), "Listing service")
+}
+
+// GetViewings lists the current user's upcoming viewings as buyer or agent.
+func GetViewings(c echo.Context) error {
+	return forward(c, http.MethodGet, myViewingsURL(c)+"/viewings", "Listing service")
+}
+
+// ExportViewings serves the viewings the current user hosts as iCalendar.
+func ExportViewings(c echo.Context) error {
+	return forward(c, http.MethodGet, myViewingsURL(c)+"/viewings.ics", "Listing service")
+}
+
+// GetViewingsCalendarLink returns a signed, expiring link to the current
+// user's viewings as iCalendar, for calendar apps to subscribe to.
+func GetViewingsCalendarLink(c echo.Context) error {
+	return forward(c, http.MethodGet, myViewingsURL(c)+"/viewings/calendar-link", "Listing service")
+}
+
+// GetViewingsCalendarFeed serves an agent's viewings as iCalendar through a
+// link from GetViewingsCalendarLink. The signature stands in for the user.
+func GetViewingsCalendarFeed(c echo.Context) error {
+	return forward(c, http.MethodGet, ListingServiceURL+"/calendar/viewings/"+url.PathEscape(c.Param("user_id")), "Listing service")
+}
+
+func RescheduleViewing(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPatch, myViewingsURL(c)+"/viewings/"+url.PathEscape(c.Param("viewing_id")), "Listing service")
+}
+
+func CancelViewing(c echo.Context) error {
+	return forwardAsForm(c, http.MethodPost, myViewingsURL(c)+"/viewings/"+url.PathEscape(c.Param("viewing_id"))+"/cancel", "Listing service")
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"real-estate-system/public-api/middleware"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository/interfaces"
+	"real-estate-system/public-api/webhooks"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	Repo       interfaces.WebhookRepository
+	Dispatcher *webhooks.Dispatcher
+}
+
+func NewWebhookHandler(repo interfaces.WebhookRepository, dispatcher *webhooks.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{Repo: repo, Dispatcher: dispatcher}
+}
+
+type webhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// ownSubscription loads a subscription and hides other partners' ones and
+// those made for other tenants.
+func (h *WebhookHandler) ownSubscription(c echo.Context) (*models.WebhookSubscription, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	sub, err := h.Repo.GetSubscription(id)
+	if err != nil || sub == nil || sub.APIKey != c.Get(middleware.ContextAPIKey) || models.TenantOrDefault(sub.TenantID) != middleware.TenantID(c) {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Webhook not found")
+	}
+	return sub, nil
+}
+
+// CreateWebhook registers a subscription. The signing secret is only
+// returned in this response.
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON body")
+	}
+	if err := webhooks.ValidateURL(req.URL); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	now := time.Now().UnixMicro()
+	sub := models.WebhookSubscription{
+		APIKey:     c.Get(middleware.ContextAPIKey).(string),
+		TenantID:   middleware.TenantID(c),
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		Active:     req.Active == nil || *req.Active,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := h.Repo.CreateSubscription(&sub); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"result":  true,
+		"webhook": sub,
+	})
+}
+
+func (h *WebhookHandler) GetWebhooks(c echo.Context) error {
+	subs, err := h.Repo.ListSubscriptions(c.Get(middleware.ContextAPIKey).(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	tenant := middleware.TenantID(c)
+	own := subs[:0]
+	for _, sub := range subs {
+		if models.TenantOrDefault(sub.TenantID) == tenant {
+			sub.Secret = ""
+			own = append(own, sub)
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":   true,
+		"webhooks": own,
+	})
+}
+
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	sub, err := h.ownSubscription(c)
+	if err != nil {
+		return err
+	}
+	sub.Secret = ""
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"webhook": sub,
+	})
+}
+
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	sub, err := h.ownSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid JSON body")
+	}
+	if req.URL != "" {
+		if err := webhooks.ValidateURL(req.URL); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		sub.URL = req.URL
+	}
+	if req.EventTypes != nil {
+		sub.EventTypes = req.EventTypes
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	sub.UpdatedAt = time.Now().UnixMicro()
+
+	if err := h.Repo.UpdateSubscription(sub); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	sub.Secret = ""
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":  true,
+		"webhook": sub,
+	})
+}
+
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	sub, err := h.ownSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteSubscription(sub); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result": true,
+	})
+}
+
+// GetDeliveries returns the delivery log, newest first.
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	sub, err := h.ownSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	deliveries, err := h.Repo.ListDeliveries(sub.ID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":     true,
+		"deliveries": deliveries,
+	})
+}
+
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	sub, err := h.ownSubscription(c)
+	if err != nil {
+		return err
+	}
+
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid delivery ID")
+	}
+
+	delivery, err := h.Repo.GetDelivery(deliveryID)
+	if err != nil || delivery == nil || delivery.SubscriptionID != sub.ID {
+		return echo.NewHTTPError(http.StatusNotFound, "Delivery not found")
+	}
+
+	if err := h.Dispatcher.Redeliver(delivery); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"result":   true,
+		"delivery": delivery,
+	})
+}
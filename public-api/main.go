@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"os"
 	"real-estate-system/public-api/handlers"
+	"real-estate-system/public-api/repository"
+	"real-estate-system/public-api/stream"
+	"real-estate-system/public-api/webhooks"
 	"time"
 
 	custommiddleware "real-estate-system/public-api/middleware"
@@ -23,12 +27,177 @@ func main() {
 		Addr: redisAddr(),
 	})
 
+	// Requests are for the tenant owning the API key or host, and count
+	// against its limit as well as the per-IP one
+	tenantRepo := repository.NewRedisTenantRepository(rdb)
+	e.Use(custommiddleware.ResolveTenant(tenantRepo))
 	e.Use(custommiddleware.NewRedisRateLimiter(rdb, 5, time.Minute))
+	e.Use(custommiddleware.NewTenantRateLimiter(rdb, time.Minute))
+
+	// Live listing feed
+	broker := stream.NewBroker(1000, 3)
+	go broker.Run(context.Background(), rdb)
+	sh := handlers.NewStreamHandler(broker)
+
+	// Live conversation feed for thread participants
+	inbox := stream.NewInbox(3)
+	go inbox.Run(context.Background(), rdb)
+	ih := handlers.NewInboxHandler(inbox)
 
 	// Public APIs
 	e.POST("/public-api/users", handlers.CreateUser)
 	e.POST("/public-api/listings", handlers.CreateListing)
 	e.GET("/public-api/listings", handlers.GetListings)
+	e.GET("/public-api/listings/stats", handlers.GetAreaStats)
+	e.POST("/public-api/listings/valuation", handlers.EstimateValue)
+	e.GET("/public-api/mortgage/schedule", handlers.GetMortgageSchedule)
+	e.GET("/public-api/mortgage/affordability", handlers.GetMortgageAffordability)
+	e.GET("/public-api/listings/:id", handlers.GetListing)
+	e.GET("/public-api/listings/:id/price-history", handlers.GetPriceHistory)
+	e.GET("/public-api/listings/:id/photos", handlers.GetListingPhotos)
+	e.GET("/public-api/listings/:id/attachments", handlers.GetListingAttachments)
+
+	requireUser := custommiddleware.RequireUser()
+	partnerKeys := custommiddleware.ParseAPIKeys(os.Getenv("PARTNER_API_KEYS"))
+	requirePartner := custommiddleware.RequireAPIKey(partnerKeys)
+
+	// Streams are capped per partner key when a known one is sent, per IP
+	// otherwise
+	e.GET("/public-api/listings/stream", sh.StreamListings, custommiddleware.IdentifyAPIKey(partnerKeys))
+
+	// Inquiries, throttled per sender on top of the per-IP limit
+	inquiryLimiter := custommiddleware.NewKeyedRateLimiter(rdb, "ratelimit:inquiry", 10, time.Hour, handlers.InquirySender)
+	e.POST("/public-api/listings/:id/inquiries", handlers.CreateInquiry, requireUser, inquiryLimiter)
+	e.POST("/public-api/partner/listings/:id/inquiries", handlers.CreatePartnerInquiry, requirePartner, inquiryLimiter)
+
+	e.POST("/public-api/listings/:id/threads", handlers.StartThread, requireUser)
+	e.POST("/public-api/listings/:id/reports", handlers.ReportListing, requireUser)
+	e.POST("/public-api/users/:id/reports", handlers.ReportUser, requireUser)
+	e.GET("/public-api/users/:id/reviews", handlers.GetReviews)
+	e.POST("/public-api/users/:id/reviews", handlers.CreateReview, requireUser)
+	e.GET("/public-api/agencies/:id", handlers.GetAgency)
+	e.GET("/public-api/listings/:id/viewing-slots", handlers.GetViewingSlots)
+	e.GET("/public-api/calendar/viewings/:user_id", handlers.GetViewingsCalendarFeed)
+	e.POST("/public-api/listings/:id/viewings", handlers.BookViewing, requireUser)
+	e.POST("/public-api/listings/:id/offers", handlers.MakeOffer, requireUser)
+	e.POST("/public-api/listings/:id/applications", handlers.ApplyForListing, requireUser)
+
+	// Current user's favorites, listings, inquiries, threads, viewings, offers,
+	// rental applications, leases, rent payments, agent profile, reviews, saved
+	// searches and alerts
+	me := e.Group("/public-api/users/me", requireUser)
+	me.GET("/favorites", handlers.GetFavorites)
+	me.POST("/favorites/:listing_id", handlers.AddFavorite)
+	me.DELETE("/favorites/:listing_id", handlers.RemoveFavorite)
+	me.GET("/listings", handlers.GetMyListings)
+	me.PATCH("/listings/:listing_id/price", handlers.UpdateListingPrice)
+	me.POST("/listings/:listing_id/photos", handlers.UploadListingPhoto)
+	me.PUT("/listings/:listing_id/photos/order", handlers.ReorderListingPhotos)
+	me.POST("/listings/:listing_id/photos/:photo_id/cover", handlers.SetListingCoverPhoto)
+	me.DELETE("/listings/:listing_id/photos/:photo_id", handlers.DeleteListingPhoto)
+	me.GET("/listings/:listing_id/attachments", handlers.GetMyListingAttachments)
+	me.POST("/listings/:listing_id/attachments", handlers.CreateListingAttachment)
+	me.DELETE("/listings/:listing_id/attachments/:attachment_id", handlers.DeleteListingAttachment)
+	me.GET("/inquiries", handlers.GetInquiries)
+	me.PATCH("/inquiries/:inquiry_id", handlers.UpdateInquiryStatus)
+	me.GET("/threads", handlers.GetThreads)
+	me.GET("/threads/:thread_id/messages", handlers.GetMessages)
+	me.POST("/threads/:thread_id/messages", handlers.PostMessage)
+	me.POST("/threads/:thread_id/read", handlers.MarkThreadRead)
+	me.GET("/messages/stream", ih.StreamMessages)
+	me.POST("/viewing-slots", handlers.CreateViewingSlot)
+	me.DELETE("/viewing-slots/:slot_id", handlers.DeleteViewingSlot)
+	me.GET("/viewings", handlers.GetViewings)
+	me.GET("/viewings.ics", handlers.ExportViewings)
+	me.GET("/viewings/calendar-link", handlers.GetViewingsCalendarLink)
+	me.PATCH("/viewings/:viewing_id", handlers.RescheduleViewing)
+	me.POST("/viewings/:viewing_id/cancel", handlers.CancelViewing)
+	me.GET("/offers", handlers.GetOffers)
+	me.GET("/offers/:offer_id", handlers.GetOffer)
+	me.POST("/offers/:offer_id/counter", handlers.CounterOffer)
+	me.POST("/offers/:offer_id/accept", handlers.AcceptOffer)
+	me.POST("/offers/:offer_id/reject", handlers.RejectOffer)
+	me.POST("/offers/:offer_id/withdraw", handlers.WithdrawOffer)
+	me.GET("/applications", handlers.GetApplications)
+	me.GET("/applications/:application_id", handlers.GetApplication)
+	me.PATCH("/applications/:application_id/status", handlers.UpdateApplicationStatus)
+	me.GET("/received-applications", handlers.GetReceivedApplications)
+	me.GET("/screening-rules", handlers.GetScreeningRules)
+	me.PUT("/screening-rules", handlers.UpdateScreeningRules)
+	me.POST("/leases", handlers.CreateLease)
+	me.GET("/leases", handlers.GetLeases)
+	me.GET("/leases/:lease_id", handlers.GetLease)
+	me.POST("/leases/:lease_id/renew", handlers.RenewLease)
+	me.POST("/leases/:lease_id/terminate", handlers.TerminateLease)
+	me.POST("/leases/:lease_id/payments", handlers.PayRent)
+	me.GET("/leases/:lease_id/payments", handlers.GetRentPayments)
+	me.GET("/leases/:lease_id/statement", handlers.GetRentStatement)
+	me.GET("/rent-roll", handlers.GetRentRoll)
+	me.GET("/late-fee-rule", handlers.GetLateFeeRule)
+	me.PUT("/late-fee-rule", handlers.UpdateLateFeeRule)
+	me.GET("/agent-profile", handlers.GetMyAgentProfile)
+	me.PUT("/agent-profile", handlers.SaveMyAgentProfile)
+	me.GET("/agency", handlers.GetMyAgency)
+	me.POST("/agency-invitations/:agency_id/accept", handlers.AcceptAgencyInvitation)
+	me.GET("/reviews", handlers.GetMyReviews)
+	me.POST("/reviews/:review_id/reply", handlers.ReplyToReview)
+	me.GET("/agency/listings", handlers.GetAgencyListings)
+	me.PATCH("/agency/listings/:listing_id/price", handlers.UpdateAgencyListingPrice)
+	me.PATCH("/agency/listings/:listing_id/status", handlers.UpdateAgencyListingStatus)
+	me.POST("/saved-searches", handlers.CreateSavedSearch)
+	me.GET("/saved-searches", handlers.GetSavedSearches)
+	me.PUT("/saved-searches/:search_id", handlers.UpdateSavedSearch)
+	me.DELETE("/saved-searches/:search_id", handlers.DeleteSavedSearch)
+	me.GET("/alerts", handlers.GetAlerts)
+	me.POST("/alerts/read", handlers.MarkAlertsRead)
+
+	// Agencies; their admins manage their agents' listings under
+	// /users/me/agency/listings
+	agency := e.Group("/public-api/agencies", requireUser)
+	agency.POST("", handlers.CreateAgency)
+	agency.POST("/:id/members", handlers.InviteAgencyMember)
+	agency.DELETE("/:id/members/:member_id", handlers.RemoveAgencyMember)
+
+	// Administration of the whole platform, from the default tenant only
+	admin := e.Group("/public-api/admin", custommiddleware.RequireDefaultTenant(), requireUser, custommiddleware.RequireAdmin())
+	admin.GET("/duplicates", handlers.GetDuplicates)
+	admin.POST("/duplicates/:pair_id/merge", handlers.MergeDuplicate)
+	admin.POST("/duplicates/:pair_id/dismiss", handlers.DismissDuplicate)
+	admin.GET("/moderation/reviews", handlers.GetModerationQueue)
+	admin.POST("/moderation/reviews/:review_id/approve", handlers.ApproveListing)
+	admin.POST("/moderation/reviews/:review_id/reject", handlers.RejectListing)
+	admin.GET("/reports", handlers.GetReports)
+	admin.POST("/reports/:report_id/resolve", handlers.ResolveReport)
+	admin.GET("/users/:id/trust", handlers.GetTrustRecord)
+	admin.GET("/agent-profiles", handlers.GetAgentVerifications)
+	admin.POST("/agent-profiles/:user_id/approve", handlers.ApproveAgentProfile)
+	admin.POST("/agent-profiles/:user_id/reject", handlers.RejectAgentProfile)
+	admin.GET("/reviews", handlers.GetReviewModerationQueue)
+	admin.POST("/reviews/:review_id/hide", handlers.HideReview)
+	admin.POST("/reviews/:review_id/restore", handlers.RestoreReview)
+
+	th := handlers.NewTenantHandler(tenantRepo)
+	admin.POST("/tenants", th.CreateTenant)
+	admin.GET("/tenants", th.GetTenants)
+	admin.GET("/tenants/:id", th.GetTenant)
+	admin.PUT("/tenants/:id", th.UpdateTenant)
+	admin.DELETE("/tenants/:id", th.DeleteTenant)
+	admin.POST("/tenants/:id/api-key", th.RotateTenantAPIKey)
+
+	// Partner webhooks
+	webhookRepo := repository.NewRedisWebhookRepository(rdb)
+	dispatcher := webhooks.NewDispatcher(webhookRepo)
+	go dispatcher.Run(context.Background(), rdb, hostname())
+
+	wh := handlers.NewWebhookHandler(webhookRepo, dispatcher)
+	partner := e.Group("/public-api/webhooks", requirePartner)
+	partner.POST("", wh.CreateWebhook)
+	partner.GET("", wh.GetWebhooks)
+	partner.GET("/:id", wh.GetWebhook)
+	partner.PUT("/:id", wh.UpdateWebhook)
+	partner.DELETE("/:id", wh.DeleteWebhook)
+	partner.GET("/:id/deliveries", wh.GetDeliveries)
+	partner.POST("/:id/deliveries/:delivery_id/redeliver", wh.Redeliver)
 
 	e.Logger.Fatal(e.Start(":6002"))
 }
@@ -44,3 +213,11 @@ func redisAddr() string {
 	}
 	return host + ":" + port
 }
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "public-api"
+	}
+	return name
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderAPIKey  = "X-API-Key"
+	ContextAPIKey = "api_key"
+)
+
+// ParseAPIKeys splits a comma separated list such as PARTNER_API_KEYS.
+func ParseAPIKeys(raw string) []string {
+	var keys []string
+	for _, key := range strings.Split(raw, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// RequireAPIKey rejects requests without a known partner key and stores the
+// key in the context under ContextAPIKey.
+func RequireAPIKey(keys []string) echo.MiddlewareFunc {
+	allowed := keySet(keys)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(HeaderAPIKey)
+			if key == "" || !allowed[key] {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid API key")
+			}
+
+			c.Set(ContextAPIKey, key)
+			return next(c)
+		}
+	}
+}
+
+// IdentifyAPIKey stores a known partner key in the context under
+// ContextAPIKey like RequireAPIKey, but lets requests without one through.
+func IdentifyAPIKey(keys []string) echo.MiddlewareFunc {
+	allowed := keySet(keys)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if key := c.Request().Header.Get(HeaderAPIKey); key != "" && allowed[key] {
+				c.Set(ContextAPIKey, key)
+			}
+			return next(c)
+		}
+	}
+}
+
+func keySet(keys []string) map[string]bool {
+	allowed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		allowed[key] = true
+	}
+	return allowed
+}
+
+// APIKeyFingerprint identifies a partner in stored data without keeping the
+// key itself.
+func APIKeyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderUserID carries the authenticated user, set by the auth proxy in
+	// front of the public API.
+	HeaderUserID  = "X-User-ID"
+	ContextUserID = "user_id"
+
+	// HeaderUserRole carries the user's role, "admin" for administrators,
+	// set by the same proxy.
+	HeaderUserRole  = "X-User-Role"
+	ContextUserRole = "user_role"
+
+	RoleAdmin = "admin"
+)
+
+// RequireUser rejects requests without a valid X-User-ID and stores the ID
+// in the context under ContextUserID, and the role under ContextUserRole.
+func RequireUser() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, err := strconv.Atoi(c.Request().Header.Get(HeaderUserID))
+			if err != nil || userID <= 0 {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Missing or invalid "+HeaderUserID)
+			}
+
+			c.Set(ContextUserID, userID)
+			c.Set(ContextUserRole, strings.TrimSpace(c.Request().Header.Get(HeaderUserRole)))
+			return next(c)
+		}
+	}
+}
+
+// RequireAdmin rejects users without the admin role. It runs after
+// RequireUser.
+func RequireAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if role, _ := c.Get(ContextUserRole).(string); role != RoleAdmin {
+				return echo.NewHTTPError(http.StatusForbidden, "Administrators only")
+			}
+			return next(c)
+		}
+	}
+}
@@ -11,28 +11,40 @@ import (
 )
 
 func NewRedisRateLimiter(rdb *redis.Client, limit int, window time.Duration) echo.MiddlewareFunc {
+	return NewKeyedRateLimiter(rdb, "ratelimit", limit, window, func(c echo.Context) string {
+		return c.RealIP()
+	})
+}
+
+// NewKeyedRateLimiter counts requests per key returned by keyFunc in a fixed
+// window, under its own Redis prefix so limits do not share counters.
+func NewKeyedRateLimiter(rdb *redis.Client, prefix string, limit int, window time.Duration, keyFunc func(echo.Context) string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			ip := c.RealIP()
-			key := fmt.Sprintf("ratelimit:%s", ip)
-			ctx := context.Background()
-
-			// Increment count and set expiration if it's a new key
-			count, err := rdb.Incr(ctx, key).Result()
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, "Rate limiter error")
-			}
-			if count == 1 {
-				rdb.Expire(ctx, key, window)
-			}
-
-			if int(count) > limit {
-				return c.JSON(http.StatusTooManyRequests, map[string]string{
-					"message": "Rate limit exceeded",
-				})
-			}
-
-			return next(c)
+			return throttle(c, next, rdb, fmt.Sprintf("%s:%s", prefix, keyFunc(c)), limit, window)
 		}
 	}
 }
+
+// throttle counts the request under key and calls next unless the count in
+// the current window is over limit.
+func throttle(c echo.Context, next echo.HandlerFunc, rdb *redis.Client, key string, limit int, window time.Duration) error {
+	ctx := context.Background()
+
+	// Increment count and set expiration if it's a new key
+	count, err := rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Rate limiter error")
+	}
+	if count == 1 {
+		rdb.Expire(ctx, key, window)
+	}
+
+	if int(count) > limit {
+		return c.JSON(http.StatusTooManyRequests, map[string]string{
+			"message": "Rate limit exceeded",
+		})
+	}
+
+	return next(c)
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository/interfaces"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// HeaderTenantID tells the internal services which tenant a request
+	// is for. It is always set by the public API, never passed through.
+	HeaderTenantID = "X-Tenant-ID"
+	ContextTenant  = "tenant"
+)
+
+// ResolveTenant stores the tenant of the request under ContextTenant: the
+// one whose API key is in X-API-Key, else the one owning the Host, else the
+// default tenant.
+func ResolveTenant(tenants interfaces.TenantRepository) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var tenant *models.Tenant
+			var err error
+			if key := c.Request().Header.Get(HeaderAPIKey); key != "" {
+				tenant, err = tenants.GetTenantByAPIKey(key)
+			}
+			if err == nil && tenant == nil {
+				tenant, err = tenants.GetTenantByHost(RequestHost(c))
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Tenant lookup failed")
+			}
+			if tenant == nil {
+				tenant = &models.Tenant{ID: models.DefaultTenant}
+			}
+
+			c.Set(ContextTenant, tenant)
+			return next(c)
+		}
+	}
+}
+
+// RequestHost returns the lower-cased Host of the request without its port.
+func RequestHost(c echo.Context) string {
+	host := c.Request().Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// TenantID returns the tenant resolved for the request, the default tenant
+// outside ResolveTenant.
+func TenantID(c echo.Context) string {
+	if tenant, ok := c.Get(ContextTenant).(*models.Tenant); ok {
+		return tenant.ID
+	}
+	return models.DefaultTenant
+}
+
+// RequireDefaultTenant hides routes that administer the whole platform
+// from the tenants' portals.
+func RequireDefaultTenant() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if TenantID(c) != models.DefaultTenant {
+				return echo.NewHTTPError(http.StatusNotFound, "Not found")
+			}
+			return next(c)
+		}
+	}
+}
+
+// NewTenantRateLimiter caps the requests of each tenant per window at the
+// tenant's RateLimit. It runs after ResolveTenant.
+func NewTenantRateLimiter(rdb *redis.Client, window time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tenant, ok := c.Get(ContextTenant).(*models.Tenant)
+			if !ok || tenant.RateLimit <= 0 {
+				return next(c)
+			}
+			return throttle(c, next, rdb, "ratelimit:tenant:"+tenant.ID, tenant.RateLimit, window)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/public-api/middleware"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+var acme = &models.Tenant{ID: "acme", Hosts: []string{"homes.acme.test"}, RateLimit: 2}
+
+func newTenantServer(tenants *mocks.TenantRepositoryMock, extra ...echo.MiddlewareFunc) *echo.Echo {
+	e := echo.New()
+	e.Use(middleware.ResolveTenant(tenants))
+	e.Use(extra...)
+	e.GET("/tenant", func(c echo.Context) error {
+		return c.String(http.StatusOK, middleware.TenantID(c))
+	})
+	return e
+}
+
+func get(e *echo.Echo, host, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+	req.Host = host
+	if apiKey != "" {
+		req.Header.Set(middleware.HeaderAPIKey, apiKey)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestResolveTenant_ByAPIKey(t *testing.T) {
+	tenants := new(mocks.TenantRepositoryMock)
+	tenants.On("GetTenantByAPIKey", "tk_acme").Return(acme, nil)
+
+	rec := get(newTenantServer(tenants), "api.example.test", "tk_acme")
+	assert.Equal(t, "acme", rec.Body.String())
+	tenants.AssertNotCalled(t, "GetTenantByHost", "api.example.test")
+}
+
+func TestResolveTenant_ByHostWithoutPort(t *testing.T) {
+	tenants := new(mocks.TenantRepositoryMock)
+	tenants.On("GetTenantByAPIKey", "partner-key").Return(nil, nil)
+	tenants.On("GetTenantByHost", "homes.acme.test").Return(acme, nil)
+
+	rec := get(newTenantServer(tenants), "Homes.Acme.test:8443", "partner-key")
+	assert.Equal(t, "acme", rec.Body.String())
+}
+
+func TestResolveTenant_DefaultForUnknownHost(t *testing.T) {
+	tenants := new(mocks.TenantRepositoryMock)
+	tenants.On("GetTenantByHost", "example.test").Return(nil, nil)
+
+	rec := get(newTenantServer(tenants), "example.test", "")
+	assert.Equal(t, models.DefaultTenant, rec.Body.String())
+}
+
+func TestRequireDefaultTenant_HidesPlatformRoutesFromTenants(t *testing.T) {
+	tenants := new(mocks.TenantRepositoryMock)
+	tenants.On("GetTenantByHost", "homes.acme.test").Return(acme, nil)
+	tenants.On("GetTenantByHost", "example.test").Return(nil, nil)
+	e := newTenantServer(tenants, middleware.RequireDefaultTenant())
+
+	assert.Equal(t, http.StatusNotFound, get(e, "homes.acme.test", "").Code)
+	assert.Equal(t, http.StatusOK, get(e, "example.test", "").Code)
+}
+
+func TestTenantRateLimiter_CountsWholeTenant(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	tenants := new(mocks.TenantRepositoryMock)
+	tenants.On("GetTenantByHost", "homes.acme.test").Return(acme, nil)
+	tenants.On("GetTenantByHost", "example.test").Return(nil, nil)
+	e := newTenantServer(tenants, middleware.NewTenantRateLimiter(rdb, time.Minute))
+
+	assert.Equal(t, http.StatusOK, get(e, "homes.acme.test", "").Code)
+	assert.Equal(t, http.StatusOK, get(e, "homes.acme.test", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(e, "homes.acme.test", "").Code)
+
+	// The default tenant has no tenant-wide limit.
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, get(e, "example.test", "").Code)
+	}
+}
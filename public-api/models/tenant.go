@@ -0,0 +1,44 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// DefaultTenant serves the main portal and every host no tenant claims.
+const DefaultTenant = "default"
+
+var (
+	ErrTenantExists = errors.New("tenant already exists")
+	ErrHostTaken    = errors.New("host belongs to another tenant")
+)
+
+// Tenant is a white-label portal of an agency. Requests are resolved to it
+// by one of its Hosts or by its API key, of which only a hash is kept.
+// RateLimit caps the requests per minute across the tenant; 0 leaves it
+// to the per-client limits.
+type Tenant struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Hosts      []string `json:"hosts"`
+	RateLimit  int      `json:"rate_limit"`
+	APIKeyHash string   `json:"-"`
+	CreatedAt  int64    `json:"created_at"`
+	UpdatedAt  int64    `json:"updated_at"`
+}
+
+// TenantOrDefault returns id, or the default tenant for data from before
+// tenants existed, which has none.
+func TenantOrDefault(id string) string {
+	if id == "" {
+		return DefaultTenant
+	}
+	return id
+}
+
+// HashAPIKey returns the hash a tenant API key is stored and looked up by.
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
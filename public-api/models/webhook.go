@@ -0,0 +1,35 @@
+package models
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryRetrying  = "retrying"
+	DeliveryDead      = "dead"
+)
+
+type WebhookSubscription struct {
+	ID         int64    `json:"id"`
+	APIKey     string   `json:"-"`
+	TenantID   string   `json:"tenant_id"` // only events of this tenant are delivered
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"` // empty matches every event
+	Secret     string   `json:"secret,omitempty"`
+	Active     bool     `json:"active"`
+	CreatedAt  int64    `json:"created_at"`
+	UpdatedAt  int64    `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64  `json:"id"`
+	SubscriptionID int64  `json:"subscription_id"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	Payload        string `json:"payload"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	LastStatusCode int    `json:"last_status_code"`
+	LastError      string `json:"last_error"`
+	NextAttemptAt  int64  `json:"next_attempt_at"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
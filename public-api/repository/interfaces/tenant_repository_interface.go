@@ -0,0 +1,15 @@
+package interfaces
+
+import "real-estate-system/public-api/models"
+
+// TenantRepository lookups return nil without an error for unknown tenants,
+// hosts and keys.
+type TenantRepository interface {
+	CreateTenant(tenant *models.Tenant) error
+	GetTenant(id string) (*models.Tenant, error)
+	GetTenantByHost(host string) (*models.Tenant, error)
+	GetTenantByAPIKey(apiKey string) (*models.Tenant, error)
+	ListTenants() ([]models.Tenant, error)
+	UpdateTenant(tenant *models.Tenant) error
+	DeleteTenant(tenant *models.Tenant) error
+}
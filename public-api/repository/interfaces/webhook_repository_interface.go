@@ -0,0 +1,18 @@
+package interfaces
+
+import "real-estate-system/public-api/models"
+
+type WebhookRepository interface {
+	CreateSubscription(sub *models.WebhookSubscription) error
+	GetSubscription(id int64) (*models.WebhookSubscription, error)
+	ListSubscriptions(apiKey string) ([]models.WebhookSubscription, error)
+	ListActiveSubscriptions() ([]models.WebhookSubscription, error)
+	UpdateSubscription(sub *models.WebhookSubscription) error
+	DeleteSubscription(sub *models.WebhookSubscription) error
+
+	CreateDelivery(delivery *models.WebhookDelivery) (bool, error)
+	SaveDelivery(delivery *models.WebhookDelivery) error
+	GetDelivery(id int64) (*models.WebhookDelivery, error)
+	ListDeliveries(subscriptionID int64, limit int) ([]models.WebhookDelivery, error)
+	DueDeliveries(now int64, limit int) ([]models.WebhookDelivery, error)
+}
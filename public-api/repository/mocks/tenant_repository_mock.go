@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"real-estate-system/public-api/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TenantRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *TenantRepositoryMock) CreateTenant(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *TenantRepositoryMock) GetTenant(id string) (*models.Tenant, error) {
+	args := m.Called(id)
+	return tenantResult(args)
+}
+
+func (m *TenantRepositoryMock) GetTenantByHost(host string) (*models.Tenant, error) {
+	args := m.Called(host)
+	return tenantResult(args)
+}
+
+func (m *TenantRepositoryMock) GetTenantByAPIKey(apiKey string) (*models.Tenant, error) {
+	args := m.Called(apiKey)
+	return tenantResult(args)
+}
+
+func tenantResult(args mock.Arguments) (*models.Tenant, error) {
+	var tenant *models.Tenant
+	if args.Get(0) != nil {
+		tenant = args.Get(0).(*models.Tenant)
+	}
+	return tenant, args.Error(1)
+}
+
+func (m *TenantRepositoryMock) ListTenants() ([]models.Tenant, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Tenant), args.Error(1)
+}
+
+func (m *TenantRepositoryMock) UpdateTenant(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *TenantRepositoryMock) DeleteTenant(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
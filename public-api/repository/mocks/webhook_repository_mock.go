@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	"real-estate-system/public-api/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *WebhookRepositoryMock) CreateSubscription(sub *models.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) GetSubscription(id int64) (*models.WebhookSubscription, error) {
+	args := m.Called(id)
+	var sub *models.WebhookSubscription
+	if args.Get(0) != nil {
+		sub = args.Get(0).(*models.WebhookSubscription)
+	}
+	return sub, args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) ListSubscriptions(apiKey string) ([]models.WebhookSubscription, error) {
+	args := m.Called(apiKey)
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) ListActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	args := m.Called()
+	return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) UpdateSubscription(sub *models.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) DeleteSubscription(sub *models.WebhookSubscription) error {
+	args := m.Called(sub)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) CreateDelivery(delivery *models.WebhookDelivery) (bool, error) {
+	args := m.Called(delivery)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) SaveDelivery(delivery *models.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *WebhookRepositoryMock) GetDelivery(id int64) (*models.WebhookDelivery, error) {
+	args := m.Called(id)
+	var delivery *models.WebhookDelivery
+	if args.Get(0) != nil {
+		delivery = args.Get(0).(*models.WebhookDelivery)
+	}
+	return delivery, args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) ListDeliveries(subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(subscriptionID, limit)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *WebhookRepositoryMock) DueDeliveries(now int64, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(now, limit)
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
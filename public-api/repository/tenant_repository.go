@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"real-estate-system/public-api/models"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// storedTenant persists the API key hash, which models hide from JSON.
+type storedTenant struct {
+	models.Tenant
+	APIKeyHash string `json:"api_key_hash"`
+}
+
+// RedisTenantRepository keeps tenants as JSON values, with their hosts and
+// API key hash indexed to the tenant ID.
+type RedisTenantRepository struct {
+	Client *redis.Client
+}
+
+func NewRedisTenantRepository(client *redis.Client) *RedisTenantRepository {
+	return &RedisTenantRepository{Client: client}
+}
+
+func tenantKey(id string) string {
+	return "tenant:" + id
+}
+
+func tenantByHost(host string) string {
+	return "tenant:host:" + host
+}
+
+func tenantByAPIKey(hash string) string {
+	return "tenant:key:" + hash
+}
+
+const allTenants = "tenants"
+
+func (r *RedisTenantRepository) CreateTenant(tenant *models.Tenant) error {
+	ctx := context.Background()
+	exists, err := r.Client.Exists(ctx, tenantKey(tenant.ID)).Result()
+	if err != nil {
+		return err
+	}
+	if exists > 0 {
+		return models.ErrTenantExists
+	}
+	if err := r.checkHosts(ctx, tenant); err != nil {
+		return err
+	}
+	return r.save(ctx, tenant, nil)
+}
+
+func (r *RedisTenantRepository) GetTenant(id string) (*models.Tenant, error) {
+	body, err := r.Client.Get(context.Background(), tenantKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stored storedTenant
+	if err := json.Unmarshal(body, &stored); err != nil {
+		return nil, err
+	}
+	tenant := stored.Tenant
+	tenant.APIKeyHash = stored.APIKeyHash
+	return &tenant, nil
+}
+
+func (r *RedisTenantRepository) GetTenantByHost(host string) (*models.Tenant, error) {
+	return r.lookup(tenantByHost(host))
+}
+
+func (r *RedisTenantRepository) GetTenantByAPIKey(apiKey string) (*models.Tenant, error) {
+	return r.lookup(tenantByAPIKey(models.HashAPIKey(apiKey)))
+}
+
+func (r *RedisTenantRepository) lookup(index string) (*models.Tenant, error) {
+	id, err := r.Client.Get(context.Background(), index).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetTenant(id)
+}
+
+// ListTenants returns every tenant ordered by ID.
+func (r *RedisTenantRepository) ListTenants() ([]models.Tenant, error) {
+	ids, err := r.Client.SMembers(context.Background(), allTenants).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	tenants := make([]models.Tenant, 0, len(ids))
+	for _, id := range ids {
+		tenant, err := r.GetTenant(id)
+		if err != nil {
+			return nil, err
+		}
+		if tenant != nil {
+			tenants = append(tenants, *tenant)
+		}
+	}
+	return tenants, nil
+}
+
+// UpdateTenant saves the tenant and moves its host and API key indexes to
+// the new values.
+func (r *RedisTenantRepository) UpdateTenant(tenant *models.Tenant) error {
+	ctx := context.Background()
+	previous, err := r.GetTenant(tenant.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.checkHosts(ctx, tenant); err != nil {
+		return err
+	}
+	return r.save(ctx, tenant, previous)
+}
+
+func (r *RedisTenantRepository) DeleteTenant(tenant *models.Tenant) error {
+	ctx := context.Background()
+	_, err := r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		unindex(ctx, pipe, tenant)
+		pipe.Del(ctx, tenantKey(tenant.ID))
+		pipe.SRem(ctx, allTenants, tenant.ID)
+		return nil
+	})
+	return err
+}
+
+// checkHosts fails with ErrHostTaken if another tenant has one of the
+// tenant's hosts.
+func (r *RedisTenantRepository) checkHosts(ctx context.Context, tenant *models.Tenant) error {
+	for _, host := range tenant.Hosts {
+		owner, err := r.Client.Get(ctx, tenantByHost(host)).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if owner != tenant.ID {
+			return models.ErrHostTaken
+		}
+	}
+	return nil
+}
+
+func (r *RedisTenantRepository) save(ctx context.Context, tenant *models.Tenant, previous *models.Tenant) error {
+	body, err := json.Marshal(storedTenant{*tenant, tenant.APIKeyHash})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != nil {
+			unindex(ctx, pipe, previous)
+		}
+		pipe.Set(ctx, tenantKey(tenant.ID), body, 0)
+		pipe.SAdd(ctx, allTenants, tenant.ID)
+		for _, host := range tenant.Hosts {
+			pipe.Set(ctx, tenantByHost(host), tenant.ID, 0)
+		}
+		if tenant.APIKeyHash != "" {
+			pipe.Set(ctx, tenantByAPIKey(tenant.APIKeyHash), tenant.ID, 0)
+		}
+		return nil
+	})
+	return err
+}
+
+func unindex(ctx context.Context, pipe redis.Pipeliner, tenant *models.Tenant) {
+	for _, host := range tenant.Hosts {
+		pipe.Del(ctx, tenantByHost(host))
+	}
+	if tenant.APIKeyHash != "" {
+		pipe.Del(ctx, tenantByAPIKey(tenant.APIKeyHash))
+	}
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantLifecycle(t *testing.T) {
+	repo := repository.NewRedisTenantRepository(setupRedis(t))
+
+	acme := &models.Tenant{ID: "acme", Name: "Acme Homes", Hosts: []string{"homes.acme.test"}, RateLimit: 600, APIKeyHash: models.HashAPIKey("tk_one")}
+	assert.NoError(t, repo.CreateTenant(acme))
+	assert.ErrorIs(t, repo.CreateTenant(&models.Tenant{ID: "acme"}), models.ErrTenantExists)
+	assert.ErrorIs(t, repo.CreateTenant(&models.Tenant{ID: "other", Hosts: []string{"homes.acme.test"}}), models.ErrHostTaken)
+
+	got, err := repo.GetTenantByHost("homes.acme.test")
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme Homes", got.Name)
+	got, err = repo.GetTenantByAPIKey("tk_one")
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", got.ID)
+	assert.Equal(t, 600, got.RateLimit)
+
+	got.Hosts = []string{"acme.test"}
+	got.APIKeyHash = models.HashAPIKey("tk_two")
+	assert.NoError(t, repo.UpdateTenant(got))
+	for _, stale := range []func() (*models.Tenant, error){
+		func() (*models.Tenant, error) { return repo.GetTenantByHost("homes.acme.test") },
+		func() (*models.Tenant, error) { return repo.GetTenantByAPIKey("tk_one") },
+	} {
+		tenant, err := stale()
+		assert.NoError(t, err)
+		assert.Nil(t, tenant)
+	}
+	got, _ = repo.GetTenantByAPIKey("tk_two")
+	assert.Equal(t, []string{"acme.test"}, got.Hosts)
+
+	tenants, err := repo.ListTenants()
+	assert.NoError(t, err)
+	assert.Len(t, tenants, 1)
+
+	assert.NoError(t, repo.DeleteTenant(got))
+	got, err = repo.GetTenant("acme")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	got, _ = repo.GetTenantByHost("acme.test")
+	assert.Nil(t, got)
+}
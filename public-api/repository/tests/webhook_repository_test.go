@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRedis(t *testing.T) *redis.Client {
+	mr := miniredis.RunT(t)
+	return redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+func TestWebhookSubscriptionLifecycle(t *testing.T) {
+	repo := repository.NewRedisWebhookRepository(setupRedis(t))
+
+	sub := &models.WebhookSubscription{APIKey: "partner-a", URL: "http://example.com/hook", Secret: "s", Active: true}
+	assert.NoError(t, repo.CreateSubscription(sub))
+	assert.Equal(t, int64(1), sub.ID)
+	assert.NoError(t, repo.CreateSubscription(&models.WebhookSubscription{APIKey: "partner-b", URL: "http://b.example.com", Active: false}))
+
+	got, err := repo.GetSubscription(sub.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "partner-a", got.APIKey)
+	assert.Equal(t, "s", got.Secret)
+
+	own, err := repo.ListSubscriptions("partner-a")
+	assert.NoError(t, err)
+	assert.Len(t, own, 1)
+
+	active, err := repo.ListActiveSubscriptions()
+	assert.NoError(t, err)
+	assert.Len(t, active, 1)
+
+	got.URL = "http://example.com/v2"
+	assert.NoError(t, repo.UpdateSubscription(got))
+	got, _ = repo.GetSubscription(sub.ID)
+	assert.Equal(t, "http://example.com/v2", got.URL)
+	assert.Equal(t, "partner-a", got.APIKey)
+
+	assert.NoError(t, repo.DeleteSubscription(got))
+	_, err = repo.GetSubscription(sub.ID)
+	assert.Error(t, err)
+	own, _ = repo.ListSubscriptions("partner-a")
+	assert.Empty(t, own)
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	repo := repository.NewRedisWebhookRepository(setupRedis(t))
+
+	first := &models.WebhookDelivery{SubscriptionID: 1, Status: models.DeliverySucceeded}
+	retrying := &models.WebhookDelivery{SubscriptionID: 1, Status: models.DeliveryRetrying, NextAttemptAt: 100}
+	later := &models.WebhookDelivery{SubscriptionID: 1, Status: models.DeliveryRetrying, NextAttemptAt: 500}
+	for _, d := range []*models.WebhookDelivery{first, retrying, later} {
+		assert.NoError(t, repo.SaveDelivery(d))
+	}
+
+	log, err := repo.ListDeliveries(1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, log, 3)
+	assert.Equal(t, later.ID, log[0].ID) // newest first
+
+	due, err := repo.DueDeliveries(200, 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, retrying.ID, due[0].ID)
+
+	retrying.Status = models.DeliverySucceeded
+	assert.NoError(t, repo.SaveDelivery(retrying))
+	due, _ = repo.DueDeliveries(200, 10)
+	assert.Empty(t, due)
+}
+
+func TestCreateDelivery_OncePerSubscriptionAndEvent(t *testing.T) {
+	repo := repository.NewRedisWebhookRepository(setupRedis(t))
+
+	first := &models.WebhookDelivery{SubscriptionID: 1, EventID: "listing-1", Status: models.DeliveryPending, NextAttemptAt: 100}
+	created, err := repo.CreateDelivery(first)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = repo.CreateDelivery(&models.WebhookDelivery{SubscriptionID: 1, EventID: "listing-1", Status: models.DeliveryPending})
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	created, err = repo.CreateDelivery(&models.WebhookDelivery{SubscriptionID: 2, EventID: "listing-1", Status: models.DeliveryPending})
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	log, _ := repo.ListDeliveries(1, 10)
+	assert.Len(t, log, 1)
+	due, _ := repo.DueDeliveries(100, 10)
+	assert.Len(t, due, 2)
+}
+
+func TestDeleteSubscription_RemovesDeliveries(t *testing.T) {
+	rdb := setupRedis(t)
+	repo := repository.NewRedisWebhookRepository(rdb)
+
+	sub := &models.WebhookSubscription{APIKey: "partner-a", URL: "https://example.com/hook", Active: true}
+	assert.NoError(t, repo.CreateSubscription(sub))
+	delivery := &models.WebhookDelivery{SubscriptionID: sub.ID, EventID: "listing-1", Status: models.DeliveryRetrying, NextAttemptAt: 100}
+	assert.NoError(t, repo.SaveDelivery(delivery))
+
+	ttl, err := rdb.TTL(context.Background(), "webhook:delivery:1").Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+
+	assert.NoError(t, repo.DeleteSubscription(sub))
+	_, err = repo.GetDelivery(delivery.ID)
+	assert.Error(t, err)
+	due, _ := repo.DueDeliveries(200, 10)
+	assert.Empty(t, due)
+}
+
+func TestSaveDelivery_RemovesTrimmedDeliveries(t *testing.T) {
+	repo := repository.NewRedisWebhookRepository(setupRedis(t))
+
+	oldest := &models.WebhookDelivery{SubscriptionID: 1, Status: models.DeliveryRetrying, NextAttemptAt: 100}
+	assert.NoError(t, repo.SaveDelivery(oldest))
+	for i := 1; i < 500; i++ {
+		assert.NoError(t, repo.SaveDelivery(&models.WebhookDelivery{SubscriptionID: 1, Status: models.DeliverySucceeded}))
+	}
+	_, err := repo.GetDelivery(oldest.ID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.SaveDelivery(&models.WebhookDelivery{SubscriptionID: 1, Status: models.DeliverySucceeded}))
+	_, err = repo.GetDelivery(oldest.ID)
+	assert.Error(t, err)
+	due, _ := repo.DueDeliveries(200, 10)
+	assert.Empty(t, due)
+}
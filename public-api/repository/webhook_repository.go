@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"real-estate-system/public-api/models"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	maxDeliveriesPerSubscription = 500
+
+	// deliveryRetention is how long a delivery is kept after it last
+	// changed, and a subscription remembers that it was sent an event.
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+// storedSubscription persists the owning API key, which models hide from JSON.
+type storedSubscription struct {
+	models.WebhookSubscription
+	APIKey string `json:"api_key"`
+}
+
+// RedisWebhookRepository keeps subscriptions and deliveries as JSON values.
+// Deliveries waiting for an attempt are indexed in a sorted set scored by
+// their next attempt time.
+type RedisWebhookRepository struct {
+	Client *redis.Client
+}
+
+func NewRedisWebhookRepository(client *redis.Client) *RedisWebhookRepository {
+	return &RedisWebhookRepository{Client: client}
+}
+
+func subscriptionKey(id int64) string {
+	return fmt.Sprintf("webhook:sub:%d", id)
+}
+
+func subscriptionsByKey(apiKey string) string {
+	return "webhook:subs:" + apiKey
+}
+
+func deliveryKey(id int64) string {
+	return fmt.Sprintf("webhook:delivery:%d", id)
+}
+
+func deliveryByEvent(subscriptionID int64, eventID string) string {
+	return fmt.Sprintf("webhook:delivery:event:%d:%s", subscriptionID, eventID)
+}
+
+func deliveriesBySubscription(subscriptionID int64) string {
+	return fmt.Sprintf("webhook:deliveries:%d", subscriptionID)
+}
+
+const (
+	allSubscriptions = "webhook:subs:all"
+	retrySchedule    = "webhook:retry"
+)
+
+func (r *RedisWebhookRepository) CreateSubscription(sub *models.WebhookSubscription) error {
+	ctx := context.Background()
+
+	id, err := r.Client.Incr(ctx, "webhook:sub:seq").Result()
+	if err != nil {
+		return err
+	}
+	sub.ID = id
+
+	body, err := json.Marshal(storedSubscription{*sub, sub.APIKey})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, subscriptionKey(id), body, 0)
+		pipe.SAdd(ctx, subscriptionsByKey(sub.APIKey), id)
+		pipe.SAdd(ctx, allSubscriptions, id)
+		return nil
+	})
+	return err
+}
+
+func (r *RedisWebhookRepository) GetSubscription(id int64) (*models.WebhookSubscription, error) {
+	body, err := r.Client.Get(context.Background(), subscriptionKey(id)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var stored storedSubscription
+	if err := json.Unmarshal(body, &stored); err != nil {
+		return nil, err
+	}
+	sub := stored.WebhookSubscription
+	sub.APIKey = stored.APIKey
+	return &sub, nil
+}
+
+func (r *RedisWebhookRepository) ListSubscriptions(apiKey string) ([]models.WebhookSubscription, error) {
+	return r.loadSubscriptions(subscriptionsByKey(apiKey))
+}
+
+func (r *RedisWebhookRepository) ListActiveSubscriptions() ([]models.WebhookSubscription, error) {
+	subs, err := r.loadSubscriptions(allSubscriptions)
+	if err != nil {
+		return nil, err
+	}
+
+	active := subs[:0]
+	for _, sub := range subs {
+		if sub.Active {
+			active = append(active, sub)
+		}
+	}
+	return active, nil
+}
+
+func (r *RedisWebhookRepository) loadSubscriptions(setKey string) ([]models.WebhookSubscription, error) {
+	ids, err := r.Client.SMembers(context.Background(), setKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]models.WebhookSubscription, 0, len(ids))
+	for _, raw := range ids {
+		id, _ := strconv.ParseInt(raw, 10, 64)
+		sub, err := r.GetSubscription(id)
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, nil
+}
+
+func (r *RedisWebhookRepository) UpdateSubscription(sub *models.WebhookSubscription) error {
+	body, err := json.Marshal(storedSubscription{*sub, sub.APIKey})
+	if err != nil {
+		return err
+	}
+	return r.Client.Set(context.Background(), subscriptionKey(sub.ID), body, 0).Err()
+}
+
+// DeleteSubscription removes the subscription with its deliveries.
+func (r *RedisWebhookRepository) DeleteSubscription(sub *models.WebhookSubscription) error {
+	ctx := context.Background()
+
+	deliveries, err := r.Client.LRange(ctx, deliveriesBySubscription(sub.ID), 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		removeDeliveries(ctx, pipe, deliveries)
+		pipe.Del(ctx, subscriptionKey(sub.ID), deliveriesBySubscription(sub.ID))
+		pipe.SRem(ctx, subscriptionsByKey(sub.APIKey), sub.ID)
+		pipe.SRem(ctx, allSubscriptions, sub.ID)
+		return nil
+	})
+	return err
+}
+
+// CreateDelivery records a new delivery unless the subscription already has
+// one for the event, as when a stream entry is read again. It reports
+// whether the delivery was created.
+func (r *RedisWebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) (bool, error) {
+	ctx := context.Background()
+
+	id, err := r.Client.Incr(ctx, "webhook:delivery:seq").Result()
+	if err != nil {
+		return false, err
+	}
+
+	key := deliveryByEvent(delivery.SubscriptionID, delivery.EventID)
+	claimed, err := r.Client.SetNX(ctx, key, id, deliveryRetention).Result()
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		// The event may have been claimed by a run that stopped before
+		// saving its delivery; finish it under the claimed ID.
+		id, err = r.Client.Get(ctx, key).Int64()
+		if err != nil {
+			return false, err
+		}
+		exists, err := r.Client.Exists(ctx, deliveryKey(id)).Result()
+		if err != nil || exists > 0 {
+			return false, err
+		}
+	}
+
+	delivery.ID = id
+	return true, r.save(delivery, true)
+}
+
+func (r *RedisWebhookRepository) SaveDelivery(delivery *models.WebhookDelivery) error {
+	isNew := delivery.ID == 0
+	if isNew {
+		id, err := r.Client.Incr(context.Background(), "webhook:delivery:seq").Result()
+		if err != nil {
+			return err
+		}
+		delivery.ID = id
+	}
+	return r.save(delivery, isNew)
+}
+
+// save stores the delivery for deliveryRetention, logging it under its
+// subscription when new, and schedules it while it is pending or retrying.
+// A new delivery pushes the oldest out of a full log, and they are removed.
+func (r *RedisWebhookRepository) save(delivery *models.WebhookDelivery, isNew bool) error {
+	ctx := context.Background()
+
+	body, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	list := deliveriesBySubscription(delivery.SubscriptionID)
+	var trimmed []string
+	if isNew {
+		trimmed, err = r.Client.LRange(ctx, list, maxDeliveriesPerSubscription-1, -1).Result()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = r.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, deliveryKey(delivery.ID), body, deliveryRetention)
+		if isNew {
+			removeDeliveries(ctx, pipe, trimmed)
+			pipe.LPush(ctx, list, delivery.ID)
+			pipe.LTrim(ctx, list, 0, maxDeliveriesPerSubscription-1)
+		}
+		if delivery.Status == models.DeliveryPending || delivery.Status == models.DeliveryRetrying {
+			pipe.ZAdd(ctx, retrySchedule, redis.Z{Score: float64(delivery.NextAttemptAt), Member: delivery.ID})
+		} else {
+			pipe.ZRem(ctx, retrySchedule, delivery.ID)
+		}
+		return nil
+	})
+	return err
+}
+
+func removeDeliveries(ctx context.Context, pipe redis.Pipeliner, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(ids))
+	members := make([]interface{}, 0, len(ids))
+	for _, raw := range ids {
+		id, _ := strconv.ParseInt(raw, 10, 64)
+		keys = append(keys, deliveryKey(id))
+		members = append(members, raw)
+	}
+	pipe.Del(ctx, keys...)
+	pipe.ZRem(ctx, retrySchedule, members...)
+}
+
+func (r *RedisWebhookRepository) GetDelivery(id int64) (*models.WebhookDelivery, error) {
+	body, err := r.Client.Get(context.Background(), deliveryKey(id)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var delivery models.WebhookDelivery
+	if err := json.Unmarshal(body, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *RedisWebhookRepository) ListDeliveries(subscriptionID int64, limit int) ([]models.WebhookDelivery, error) {
+	ids, err := r.Client.LRange(context.Background(), deliveriesBySubscription(subscriptionID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	return r.loadDeliveries(ids)
+}
+
+func (r *RedisWebhookRepository) DueDeliveries(now int64, limit int) ([]models.WebhookDelivery, error) {
+	ids, err := r.Client.ZRangeByScore(context.Background(), retrySchedule, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now, 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	return r.loadDeliveries(ids)
+}
+
+func (r *RedisWebhookRepository) loadDeliveries(ids []string) ([]models.WebhookDelivery, error) {
+	deliveries := make([]models.WebhookDelivery, 0, len(ids))
+	for _, raw := range ids {
+		id, _ := strconv.ParseInt(raw, 10, 64)
+		delivery, err := r.GetDelivery(id)
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, nil
+}
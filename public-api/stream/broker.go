@@ -0,0 +1,193 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const ListingStream = "listing-events"
+
+var ErrTooManyStreams = errors.New("too many concurrent streams")
+
+// Event is a listing change as sent to SSE clients. ID is the Redis Stream
+// entry ID, which is ordered and used for Last-Event-ID resume.
+// MonthlyPrice is the monthly_price the listing-service adds to the stream
+// entry, zero when it had no exchange rate.
+type Event struct {
+	ID           string
+	Type         string
+	Listing      json.RawMessage
+	MonthlyPrice int
+}
+
+type Subscriber struct {
+	Events chan Event
+	client string
+}
+
+// Broker tails the listing event stream once per process and fans events
+// out to connected SSE clients. The most recent events are kept in a
+// bounded buffer so reconnecting clients can resume without gaps.
+type Broker struct {
+	BufferSize          int
+	MaxStreamsPerClient int
+
+	mu          sync.Mutex
+	buffer      []Event
+	subscribers map[*Subscriber]struct{}
+	perClient   map[string]int
+}
+
+func NewBroker(bufferSize, maxStreamsPerClient int) *Broker {
+	return &Broker{
+		BufferSize:          bufferSize,
+		MaxStreamsPerClient: maxStreamsPerClient,
+		subscribers:         make(map[*Subscriber]struct{}),
+		perClient:           make(map[string]int),
+	}
+}
+
+// Subscribe registers a stream for client and returns the buffered events
+// newer than lastEventID. If lastEventID has already been evicted the whole
+// buffer is replayed.
+func (b *Broker) Subscribe(client, lastEventID string) (*Subscriber, []Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.perClient[client] >= b.MaxStreamsPerClient {
+		return nil, nil, ErrTooManyStreams
+	}
+
+	var replay []Event
+	if lastEventID != "" {
+		for _, e := range b.buffer {
+			if compareIDs(e.ID, lastEventID) > 0 {
+				replay = append(replay, e)
+			}
+		}
+	}
+
+	sub := &Subscriber{Events: make(chan Event, 64), client: client}
+	b.subscribers[sub] = struct{}{}
+	b.perClient[client]++
+	return sub, replay, nil
+}
+
+func (b *Broker) Unsubscribe(sub *Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+func (b *Broker) remove(sub *Subscriber) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.Events)
+
+	b.perClient[sub.client]--
+	if b.perClient[sub.client] <= 0 {
+		delete(b.perClient, sub.client)
+	}
+}
+
+// Publish buffers an event and hands it to every subscriber. A subscriber
+// that cannot keep up is dropped; it reconnects with Last-Event-ID.
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buffer = append(b.buffer, event)
+	if len(b.buffer) > b.BufferSize {
+		b.buffer = b.buffer[len(b.buffer)-b.BufferSize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.Events <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+}
+
+// Run tails the listing event stream from now on until ctx is cancelled.
+// Only listing.* events are public; other aggregates on the stream, such as
+// inquiries, are skipped.
+func (b *Broker) Run(ctx context.Context, rdb *redis.Client) {
+	lastID := "$"
+
+	for ctx.Err() == nil {
+		res, err := rdb.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{ListingStream, lastID},
+			Count:   100,
+			Block:   5 * time.Second,
+		}).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+				log.Println("stream: read:", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		for _, s := range res {
+			for _, msg := range s.Messages {
+				lastID = msg.ID
+				if event := EventFromMessage(msg); strings.HasPrefix(event.Type, "listing.") {
+					b.Publish(event)
+				}
+			}
+		}
+	}
+}
+
+func EventFromMessage(msg redis.XMessage) Event {
+	eventType, _ := msg.Values["event_type"].(string)
+	payload, _ := msg.Values["payload"].(string)
+
+	listing := json.RawMessage(payload)
+	if !json.Valid(listing) {
+		listing = json.RawMessage("null")
+	}
+
+	monthlyPrice, _ := msg.Values["monthly_price"].(string)
+	price, _ := strconv.Atoi(monthlyPrice)
+
+	return Event{ID: msg.ID, Type: eventType, Listing: listing, MonthlyPrice: price}
+}
+
+// compareIDs orders Redis Stream IDs ("<ms>-<seq>").
+func compareIDs(a, b string) int {
+	am, as := splitID(a)
+	bm, bs := splitID(b)
+	switch {
+	case am != bm:
+		if am < bm {
+			return -1
+		}
+		return 1
+	case as != bs:
+		if as < bs {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func splitID(id string) (uint64, uint64) {
+	ms, seq, _ := strings.Cut(id, "-")
+	m, _ := strconv.ParseUint(ms, 10, 64)
+	s, _ := strconv.ParseUint(seq, 10, 64)
+	return m, s
+}
@@ -0,0 +1,75 @@
+package stream
+
+import (
+	"encoding/json"
+	"real-estate-system/public-api/models"
+	"strings"
+)
+
+// Filter mirrors the GetListings query filters of listing-service. Like
+// GetListings, it only matches public listings of its Tenant, the default
+// tenant when empty, and takes prices in whole IDR, per month for rents.
+type Filter struct {
+	Tenant      string
+	ListingType string
+	MinPrice    int
+	MaxPrice    int
+	Area        string
+}
+
+// nonPublicStatuses are the listing statuses only their owner and
+// administrators may see, as in listing-service.
+var nonPublicStatuses = []string{"pending_review", "rejected", "hidden"}
+
+type listingFields struct {
+	TenantID    string `json:"tenant_id"`
+	Status      string `json:"status"`
+	Price       int    `json:"price"`
+	Currency    string `json:"currency"`
+	RentPeriod  string `json:"rent_period"`
+	ListingType string `json:"listing_type"`
+	City        string `json:"city"`
+	District    string `json:"district"`
+}
+
+func (f Filter) Matches(event Event) bool {
+	var l listingFields
+	if err := json.Unmarshal(event.Listing, &l); err != nil {
+		return false
+	}
+
+	if models.TenantOrDefault(l.TenantID) != models.TenantOrDefault(f.Tenant) {
+		return false
+	}
+	for _, status := range nonPublicStatuses {
+		if l.Status == status {
+			return false
+		}
+	}
+	if f.ListingType != "" && l.ListingType != f.ListingType {
+		return false
+	}
+	if f.MinPrice > 0 || f.MaxPrice > 0 {
+		price, ok := comparablePrice(l, event.MonthlyPrice)
+		if !ok || f.MinPrice > 0 && price < f.MinPrice || f.MaxPrice > 0 && price > f.MaxPrice {
+			return false
+		}
+	}
+	if f.Area != "" && !strings.EqualFold(l.City, f.Area) && !strings.EqualFold(l.District, f.Area) {
+		return false
+	}
+	return true
+}
+
+// comparablePrice is the listing's price in whole IDR per month. Without
+// monthlyPrice it is only known for listings priced that way, as all were
+// before currencies.
+func comparablePrice(l listingFields, monthlyPrice int) (int, bool) {
+	if monthlyPrice > 0 {
+		return monthlyPrice, true
+	}
+	if (l.Currency == "" || l.Currency == "IDR") && l.RentPeriod != "year" {
+		return l.Price, true
+	}
+	return 0, false
+}
@@ -0,0 +1,146 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const MessageStream = "message-events"
+
+// InboxEvent is a conversation event for the participants of a thread.
+type InboxEvent struct {
+	ID         string
+	Type       string
+	Data       json.RawMessage
+	Recipients []int
+}
+
+type InboxSubscriber struct {
+	Events chan InboxEvent
+	userID int
+}
+
+// Inbox tails the private message stream and hands each event to the
+// connected streams of the thread's buyer and owner. Unlike Broker it keeps
+// no replay buffer; clients reload thread history after reconnecting.
+type Inbox struct {
+	MaxStreamsPerUser int
+
+	mu          sync.Mutex
+	subscribers map[int]map[*InboxSubscriber]struct{}
+}
+
+func NewInbox(maxStreamsPerUser int) *Inbox {
+	return &Inbox{
+		MaxStreamsPerUser: maxStreamsPerUser,
+		subscribers:       make(map[int]map[*InboxSubscriber]struct{}),
+	}
+}
+
+func (i *Inbox) Subscribe(userID int) (*InboxSubscriber, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(i.subscribers[userID]) >= i.MaxStreamsPerUser {
+		return nil, ErrTooManyStreams
+	}
+
+	sub := &InboxSubscriber{Events: make(chan InboxEvent, 64), userID: userID}
+	if i.subscribers[userID] == nil {
+		i.subscribers[userID] = make(map[*InboxSubscriber]struct{})
+	}
+	i.subscribers[userID][sub] = struct{}{}
+	return sub, nil
+}
+
+func (i *Inbox) Unsubscribe(sub *InboxSubscriber) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(sub)
+}
+
+func (i *Inbox) remove(sub *InboxSubscriber) {
+	subs := i.subscribers[sub.userID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.Events)
+	if len(subs) == 0 {
+		delete(i.subscribers, sub.userID)
+	}
+}
+
+// Publish delivers event to its recipients' streams, dropping any stream
+// that cannot keep up.
+func (i *Inbox) Publish(event InboxEvent) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, userID := range event.Recipients {
+		for sub := range i.subscribers[userID] {
+			select {
+			case sub.Events <- event:
+			default:
+				i.remove(sub)
+			}
+		}
+	}
+}
+
+// Run tails the message stream from now on until ctx is cancelled.
+func (i *Inbox) Run(ctx context.Context, rdb *redis.Client) {
+	lastID := "$"
+
+	for ctx.Err() == nil {
+		res, err := rdb.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{MessageStream, lastID},
+			Count:   100,
+			Block:   5 * time.Second,
+		}).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+				log.Println("inbox: read:", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		for _, s := range res {
+			for _, msg := range s.Messages {
+				lastID = msg.ID
+				i.Publish(InboxEventFromMessage(msg))
+			}
+		}
+	}
+}
+
+// InboxEventFromMessage converts an inquiry, thread, viewing, offer,
+// application, lease or moderation event written by the listing-service
+// outbox relay; its payload names the participants. Fields that are not
+// user IDs, such as an inquiry's listing tenant_id, are skipped.
+func InboxEventFromMessage(msg redis.XMessage) InboxEvent {
+	eventType, _ := msg.Values["event_type"].(string)
+	payload, _ := msg.Values["payload"].(string)
+
+	event := InboxEvent{ID: msg.ID, Type: eventType, Data: json.RawMessage(payload)}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(event.Data, &fields); err != nil {
+		event.Data = json.RawMessage("null")
+		return event
+	}
+	for _, name := range []string{"buyer_id", "owner_id", "agent_id", "seller_id", "applicant_id", "landlord_id", "tenant_id"} {
+		var userID int
+		if json.Unmarshal(fields[name], &userID) == nil && userID > 0 {
+			event.Recipients = append(event.Recipients, userID)
+		}
+	}
+	return event
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"real-estate-system/public-api/stream"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func listingEvent(id, eventType, listing string) stream.Event {
+	return stream.Event{ID: id, Type: eventType, Listing: []byte(listing)}
+}
+
+func TestSubscribe_ReplaysAfterLastEventID(t *testing.T) {
+	broker := stream.NewBroker(3, 2)
+	broker.Publish(listingEvent("1-0", "listing.created", `{"id":1}`))
+	broker.Publish(listingEvent("2-0", "listing.created", `{"id":2}`))
+	broker.Publish(listingEvent("2-1", "listing.updated", `{"id":2}`))
+	broker.Publish(listingEvent("10-0", "listing.created", `{"id":3}`))
+
+	_, replay, err := broker.Subscribe("client", "2-0")
+	assert.NoError(t, err)
+	assert.Len(t, replay, 2)
+	assert.Equal(t, "2-1", replay[0].ID)
+	assert.Equal(t, "10-0", replay[1].ID)
+
+	// 1-0 was evicted from the buffer of 3, so everything left is replayed.
+	_, replay, err = broker.Subscribe("client", "1-0")
+	assert.NoError(t, err)
+	assert.Len(t, replay, 3)
+}
+
+func TestSubscribe_CapsStreamsPerClient(t *testing.T) {
+	broker := stream.NewBroker(10, 1)
+
+	sub, _, err := broker.Subscribe("1.2.3.4", "")
+	assert.NoError(t, err)
+
+	_, _, err = broker.Subscribe("1.2.3.4", "")
+	assert.ErrorIs(t, err, stream.ErrTooManyStreams)
+
+	_, _, err = broker.Subscribe("5.6.7.8", "")
+	assert.NoError(t, err)
+
+	broker.Unsubscribe(sub)
+	_, _, err = broker.Subscribe("1.2.3.4", "")
+	assert.NoError(t, err)
+}
+
+func TestPublish_DropsSlowSubscriber(t *testing.T) {
+	broker := stream.NewBroker(1000, 1)
+	sub, _, _ := broker.Subscribe("client", "")
+
+	for i := 0; i < 100; i++ {
+		broker.Publish(listingEvent("1-0", "listing.created", `{}`))
+	}
+
+	drained := 0
+	for range sub.Events {
+		drained++
+	}
+	assert.Less(t, drained, 100)
+
+	// The dropped stream no longer counts against the client cap.
+	_, _, err := broker.Subscribe("client", "")
+	assert.NoError(t, err)
+}
+
+func TestFilter_Matches(t *testing.T) {
+	event := listingEvent("1-0", "listing.created", `{"price":3500,"listing_type":"rent","city":"Jakarta Selatan","district":"Tebet"}`)
+
+	assert.True(t, stream.Filter{}.Matches(event))
+	assert.True(t, stream.Filter{ListingType: "rent", MaxPrice: 4000, Area: "jakarta selatan"}.Matches(event))
+	assert.True(t, stream.Filter{Area: "TEBET"}.Matches(event))
+	assert.False(t, stream.Filter{ListingType: "sale"}.Matches(event))
+	assert.False(t, stream.Filter{MinPrice: 4000}.Matches(event))
+	assert.False(t, stream.Filter{Area: "Bandung"}.Matches(event))
+}
+
+func TestFilter_SkipsNonPublicListings(t *testing.T) {
+	assert.True(t, stream.Filter{}.Matches(listingEvent("1-0", "listing.updated", `{"status":"active","listing_type":"rent"}`)))
+	for _, status := range []string{"pending_review", "rejected", "hidden"} {
+		event := listingEvent("2-0", "listing.price_dropped", `{"status":"`+status+`","listing_type":"rent"}`)
+		assert.False(t, stream.Filter{}.Matches(event), status)
+	}
+}
+
+func TestFilter_ComparesPricesInIDRPerMonth(t *testing.T) {
+	yearly := stream.EventFromMessage(redis.XMessage{ID: "1-0", Values: map[string]interface{}{
+		"event_type":    "listing.created",
+		"payload":       `{"price":3000,"currency":"USD","rent_period":"year","listing_type":"rent"}`,
+		"monthly_price": "4000000",
+	}})
+	assert.Equal(t, 4000000, yearly.MonthlyPrice)
+	assert.True(t, stream.Filter{MinPrice: 3500000, MaxPrice: 4500000}.Matches(yearly))
+	assert.False(t, stream.Filter{MaxPrice: 3000}.Matches(yearly))
+
+	unconverted := listingEvent("2-0", "listing.created", `{"price":3000,"currency":"SGD","listing_type":"rent"}`)
+	assert.True(t, stream.Filter{}.Matches(unconverted))
+	assert.False(t, stream.Filter{MaxPrice: 4000}.Matches(unconverted))
+}
+
+func TestFilter_OnlyMatchesOwnTenant(t *testing.T) {
+	acme := listingEvent("1-0", "listing.created", `{"tenant_id":"acme","price":3500,"listing_type":"rent"}`)
+	legacy := listingEvent("2-0", "listing.created", `{"price":3500,"listing_type":"rent"}`)
+
+	assert.True(t, stream.Filter{Tenant: "acme"}.Matches(acme))
+	assert.False(t, stream.Filter{}.Matches(acme))
+	assert.False(t, stream.Filter{Tenant: "other"}.Matches(acme))
+	assert.True(t, stream.Filter{Tenant: "default"}.Matches(legacy))
+	assert.False(t, stream.Filter{Tenant: "acme"}.Matches(legacy))
+}
+
+func TestRun_TailsListingEvents(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	broker := stream.NewBroker(10, 1)
+	sub, _, _ := broker.Subscribe("client", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.Run(ctx, rdb)
+
+	// Give Run time to start blocking on "$" before the event is added.
+	time.Sleep(50 * time.Millisecond)
+	rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream.ListingStream,
+		Values: map[string]interface{}{"event_type": "inquiry.created", "payload": `{"id":3,"buyer_id":5}`},
+	})
+	rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream.ListingStream,
+		Values: map[string]interface{}{"event_type": "listing.created", "payload": `{"id":7}`},
+	})
+
+	select {
+	case event := <-sub.Events:
+		assert.Equal(t, "listing.created", event.Type)
+		assert.JSONEq(t, `{"id":7}`, string(event.Listing))
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not received")
+	}
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"real-estate-system/public-api/stream"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInbox_RoutesToParticipants(t *testing.T) {
+	inbox := stream.NewInbox(2)
+	buyer, _ := inbox.Subscribe(5)
+	owner, _ := inbox.Subscribe(2)
+	other, _ := inbox.Subscribe(9)
+
+	inbox.Publish(stream.InboxEventFromMessage(redis.XMessage{
+		ID:     "1-0",
+		Values: map[string]interface{}{"event_type": "message.created", "payload": `{"thread_id":4,"buyer_id":5,"owner_id":2}`},
+	}))
+
+	assert.Len(t, buyer.Events, 1)
+	assert.Len(t, owner.Events, 1)
+	assert.Len(t, other.Events, 0)
+}
+
+func TestInbox_RoutesViewingEventsToAgent(t *testing.T) {
+	event := stream.InboxEventFromMessage(redis.XMessage{
+		ID:     "1-0",
+		Values: map[string]interface{}{"event_type": "viewing.reminder", "payload": `{"id":9,"buyer_id":5,"agent_id":2}`},
+	})
+	assert.ElementsMatch(t, []int{5, 2}, event.Recipients)
+}
+
+func TestInbox_RoutesOfferEventsToSeller(t *testing.T) {
+	event := stream.InboxEventFromMessage(redis.XMessage{
+		ID:     "1-0",
+		Values: map[string]interface{}{"event_type": "offer.countered", "payload": `{"id":4,"buyer_id":5,"seller_id":2}`},
+	})
+	assert.ElementsMatch(t, []int{5, 2}, event.Recipients)
+}
+
+func TestInbox_RoutesApplicationAndLeaseEvents(t *testing.T) {
+	event := stream.InboxEventFromMessage(redis.XMessage{
+		ID:     "1-0",
+		Values: map[string]interface{}{"event_type": "application.status_changed", "payload": `{"id":4,"applicant_id":5,"landlord_id":2}`},
+	})
+	assert.ElementsMatch(t, []int{5, 2}, event.Recipients)
+
+	event = stream.InboxEventFromMessage(redis.XMessage{
+		ID:     "2-0",
+		Values: map[string]interface{}{"event_type": "lease.expiring", "payload": `{"id":3,"landlord_id":2,"tenant_id":5}`},
+	})
+	assert.ElementsMatch(t, []int{2, 5}, event.Recipients)
+}
+
+func TestInbox_NotifiesListerOfModeration(t *testing.T) {
+	event := stream.InboxEventFromMessage(redis.XMessage{
+		ID:     "1-0",
+		Values: map[string]interface{}{"event_type": "moderation.rejected", "payload": `{"id":5,"listing_id":9,"owner_id":3,"reviewed_by":1,"listing":{"id":9,"user_id":3}}`},
+	})
+	assert.Equal(t, []int{3}, event.Recipients)
+}
+
+func TestInbox_RoutesInquiryEvents(t *testing.T) {
+	event := stream.InboxEventFromMessage(redis.XMessage{
+		ID:     "1-0",
+		Values: map[string]interface{}{"event_type": "inquiry.created", "payload": `{"id":3,"tenant_id":"acme","listing_id":7,"owner_id":2,"buyer_id":5,"status":"new","source":"web"}`},
+	})
+	assert.ElementsMatch(t, []int{5, 2}, event.Recipients)
+	assert.JSONEq(t, `{"id":3,"tenant_id":"acme","listing_id":7,"owner_id":2,"buyer_id":5,"status":"new","source":"web"}`, string(event.Data))
+}
+
+func TestInbox_CapsStreamsPerUser(t *testing.T) {
+	inbox := stream.NewInbox(1)
+
+	sub, err := inbox.Subscribe(5)
+	assert.NoError(t, err)
+	_, err = inbox.Subscribe(5)
+	assert.ErrorIs(t, err, stream.ErrTooManyStreams)
+
+	inbox.Unsubscribe(sub)
+	_, err = inbox.Subscribe(5)
+	assert.NoError(t, err)
+}
+
+func TestInbox_RunTailsMessageStream(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	inbox := stream.NewInbox(1)
+	sub, _ := inbox.Subscribe(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go inbox.Run(ctx, rdb)
+
+	time.Sleep(50 * time.Millisecond)
+	rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream.MessageStream,
+		Values: map[string]interface{}{"event_type": "thread.read", "payload": `{"thread_id":4,"buyer_id":5,"owner_id":2,"last_read_id":11}`},
+	})
+
+	select {
+	case event := <-sub.Events:
+		assert.Equal(t, "thread.read", event.Type)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not received")
+	}
+}
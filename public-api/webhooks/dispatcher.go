@@ -0,0 +1,449 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"real-estate-system/public-api/models"
+	"real-estate-system/public-api/repository/interfaces"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	consumerGroup = "webhooks"
+)
+
+var ErrPrivateAddress = errors.New("webhook URLs must point at a public host")
+
+// Streams written by the user-service and listing-service outbox relays.
+var Streams = []string{"listing-events", "user-events"}
+
+// Event is the envelope read from the domain event streams.
+type Event struct {
+	ID          string
+	Type        string
+	AggregateID string
+	OccurredAt  int64
+	Payload     json.RawMessage
+}
+
+// Dispatcher fans events out to matching subscriptions as pending
+// deliveries, which a worker sends and retries with exponential backoff
+// until MaxAttempts, after which the delivery is parked as dead until
+// redelivered by hand. Stream entries left unacknowledged for ClaimIdle by
+// any consumer are claimed and handled again.
+type Dispatcher struct {
+	Repo        interfaces.WebhookRepository
+	Client      *http.Client
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	ClaimIdle   time.Duration
+
+	queued chan struct{}
+}
+
+func NewDispatcher(repo interfaces.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		Repo:        repo,
+		Client:      publicClient(),
+		MaxAttempts: 8,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  6 * time.Hour,
+		ClaimIdle:   5 * time.Minute,
+		queued:      make(chan struct{}, 1),
+	}
+}
+
+// publicClient only connects to public addresses, so a subscription cannot
+// reach the internal network through a host that resolves, or redirects, to
+// a private one.
+func publicClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 5 * time.Second},
+	}
+}
+
+func isPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
+
+// ValidateURL checks that a subscription URL is an absolute https URL whose
+// host is not localhost or a private address. Host names are checked again
+// against the addresses they resolve to on every delivery.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return errors.New("url must be an absolute https URL")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublic(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// Sign returns the signature partners verify: hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the subscription secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Matches reports whether eventType passes the subscription filters.
+// A filter ending in ".*" matches a whole family, e.g. "listing.*".
+func Matches(sub models.WebhookSubscription, eventType string) bool {
+	if len(sub.EventTypes) == 0 {
+		return true
+	}
+	for _, filter := range sub.EventTypes {
+		if filter == eventType || filter == "*" {
+			return true
+		}
+		if strings.HasSuffix(filter, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(filter, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventTenant reads the tenant_id every partner-visible event payload
+// carries.
+func eventTenant(payload json.RawMessage) string {
+	var fields struct {
+		TenantID string `json:"tenant_id"`
+	}
+	json.Unmarshal(payload, &fields)
+	return models.TenantOrDefault(fields.TenantID)
+}
+
+// Backoff returns the wait before the given retry attempt (1-based).
+func (d *Dispatcher) Backoff(attempt int) time.Duration {
+	wait := d.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= d.MaxBackoff {
+			return d.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// HandleEvent records a pending delivery for every matching subscription,
+// once however often the event is read, and wakes the worker to send them.
+func (d *Dispatcher) HandleEvent(event Event) error {
+	subs, err := d.Repo.ListActiveSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":           event.ID,
+		"type":         event.Type,
+		"aggregate_id": event.AggregateID,
+		"occurred_at":  event.OccurredAt,
+		"data":         event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	tenant := eventTenant(event.Payload)
+	now := time.Now().UnixMicro()
+	queued := false
+	for _, sub := range subs {
+		if models.TenantOrDefault(sub.TenantID) != tenant || !Matches(sub, event.Type) {
+			continue
+		}
+
+		delivery := models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(body),
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		created, err := d.Repo.CreateDelivery(&delivery)
+		if err != nil {
+			return err
+		}
+		queued = queued || created
+	}
+
+	if queued {
+		select {
+		case d.queued <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Redeliver sends a delivery again regardless of its state. A dead delivery
+// gets a fresh retry budget.
+func (d *Dispatcher) Redeliver(delivery *models.WebhookDelivery) error {
+	sub, err := d.Repo.GetSubscription(delivery.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	if delivery.Status == models.DeliveryDead {
+		delivery.Attempts = 0
+	}
+	return d.attempt(sub, delivery)
+}
+
+// RetryDue attempts every pending delivery and every retrying one whose
+// backoff has elapsed.
+func (d *Dispatcher) RetryDue() (int, error) {
+	due, err := d.Repo.DueDeliveries(time.Now().UnixMicro(), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		sub, err := d.Repo.GetSubscription(due[i].SubscriptionID)
+		if err != nil {
+			// Subscription was deleted; stop retrying.
+			due[i].Status = models.DeliveryDead
+			due[i].LastError = "subscription not found"
+			if err := d.Repo.SaveDelivery(&due[i]); err != nil {
+				return i, err
+			}
+			continue
+		}
+		if err := d.attempt(sub, &due[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+func (d *Dispatcher) attempt(sub *models.WebhookSubscription, delivery *models.WebhookDelivery) error {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.UpdatedAt = now.UnixMicro()
+
+	status, err := d.send(sub, delivery, now.Unix())
+	delivery.LastStatusCode = status
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.NextAttemptAt = 0
+	case delivery.Attempts >= d.MaxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = 0
+	default:
+		delivery.Status = models.DeliveryRetrying
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(d.Backoff(delivery.Attempts)).UnixMicro()
+	}
+
+	return d.Repo.SaveDelivery(delivery)
+}
+
+func (d *Dispatcher) send(sub *models.WebhookSubscription, delivery *models.WebhookDelivery, timestamp int64) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Run consumes the event streams through a consumer group and sends due
+// deliveries until ctx is cancelled. Stream entries are acknowledged once
+// their deliveries are recorded, so a crash leaves them pending to be read
+// again (at-least-once).
+func (d *Dispatcher) Run(ctx context.Context, rdb *redis.Client, consumer string) {
+	for _, stream := range Streams {
+		err := rdb.XGroupCreateMkStream(ctx, stream, consumerGroup, "0").Err()
+		if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+			log.Println("webhooks: create group:", err)
+		}
+	}
+
+	go d.retryLoop(ctx)
+
+	// Re-read entries left unacknowledged by a previous run once, then
+	// switch to new entries only.
+	ids := make([]string, 0, len(Streams)*2)
+	ids = append(ids, Streams...)
+	for range Streams {
+		ids = append(ids, "0")
+	}
+
+	claimedAt := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(claimedAt) >= d.ClaimIdle {
+			d.Reclaim(ctx, rdb, consumer)
+			claimedAt = time.Now()
+		}
+
+		res, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumerGroup,
+			Consumer: consumer,
+			Streams:  ids,
+			Count:    50,
+			Block:    5 * time.Second,
+		}).Result()
+		for i := len(Streams); i < len(ids); i++ {
+			ids[i] = ">"
+		}
+		if err != nil && !errors.Is(err, redis.Nil) {
+			if ctx.Err() == nil {
+				log.Println("webhooks: read:", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		for _, stream := range res {
+			d.handleMessages(ctx, rdb, stream.Stream, stream.Messages)
+		}
+	}
+}
+
+// Reclaim takes over the entries of every consumer, this one included, left
+// unacknowledged for ClaimIdle, as when a dispatcher stopped or failed to
+// record their deliveries, and handles them. It returns how many it took.
+func (d *Dispatcher) Reclaim(ctx context.Context, rdb *redis.Client, consumer string) int {
+	claimed := 0
+	for _, stream := range Streams {
+		start := "0-0"
+		for {
+			msgs, next, err := rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   stream,
+				Group:    consumerGroup,
+				Consumer: consumer,
+				MinIdle:  d.ClaimIdle,
+				Start:    start,
+				Count:    50,
+			}).Result()
+			if err != nil {
+				if !errors.Is(err, redis.Nil) {
+					log.Println("webhooks: claim:", err)
+				}
+				break
+			}
+			d.handleMessages(ctx, rdb, stream, msgs)
+			claimed += len(msgs)
+			if next == "0-0" {
+				break
+			}
+			start = next
+		}
+	}
+	return claimed
+}
+
+func (d *Dispatcher) handleMessages(ctx context.Context, rdb *redis.Client, stream string, msgs []redis.XMessage) {
+	for _, msg := range msgs {
+		if err := d.HandleEvent(EventFromMessage(msg)); err != nil {
+			log.Println("webhooks: handle event:", err)
+			continue
+		}
+		rdb.XAck(ctx, stream, consumerGroup, msg.ID)
+	}
+}
+
+func (d *Dispatcher) retryLoop(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.queued:
+		}
+		if _, err := d.RetryDue(); err != nil {
+			log.Println("webhooks: retry:", err)
+		}
+	}
+}
+
+// EventFromMessage converts a stream entry written by an outbox relay.
+func EventFromMessage(msg redis.XMessage) Event {
+	str := func(key string) string {
+		v, _ := msg.Values[key].(string)
+		return v
+	}
+	occurredAt, _ := strconv.ParseInt(str("occurred_at"), 10, 64)
+
+	payload := json.RawMessage(str("payload"))
+	if !json.Valid(payload) {
+		payload = json.RawMessage("null")
+	}
+
+	return Event{
+		ID:          str("aggregate_type") + "-" + str("event_id"),
+		Type:        str("event_type"),
+		AggregateID: str("aggregate_id"),
+		OccurredAt:  occurredAt,
+		Payload:     payload,
+	}
+}
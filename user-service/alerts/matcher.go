@@ -0,0 +1,148 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"real-estate-system/user-service/models"
+	repository "real-estate-system/user-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ListingStream = "listing-events"
+	consumerGroup = "saved-searches"
+)
+
+// Listing events that can make a listing newly interesting to a search.
+var matchedEvents = map[string]bool{
+	"listing.created":       true,
+	"listing.price_changed": true,
+}
+
+// Matcher turns listing events into saved search alerts.
+type Matcher struct {
+	Repo           repository.SavedSearchRepository
+	DigestInterval time.Duration
+}
+
+func NewMatcher(repo repository.SavedSearchRepository) *Matcher {
+	return &Matcher{Repo: repo, DigestInterval: 24 * time.Hour}
+}
+
+// HandleEvent records an alert for every saved search matching the listing,
+// comparing prices through the event's monthlyPrice, and returns how many
+// were new. Replayed events are ignored by the (saved_search_id, event_id)
+// unique index.
+func (m *Matcher) HandleEvent(eventID, eventType, payload string, monthlyPrice int) (int, error) {
+	if !matchedEvents[eventType] {
+		return 0, nil
+	}
+
+	var listing models.ListingSnapshot
+	if err := json.Unmarshal([]byte(payload), &listing); err != nil {
+		return 0, err
+	}
+	if !listing.IsPublic() {
+		return 0, nil
+	}
+	if listing.TenantID == "" {
+		listing.TenantID = models.DefaultTenant
+	}
+	listing.MonthlyPrice = monthlyPrice
+
+	searches, err := m.Repo.FindMatching(listing)
+	if err != nil {
+		return 0, err
+	}
+	if len(searches) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().UnixMicro()
+	alerts := make([]models.Alert, len(searches))
+	for i, search := range searches {
+		alerts[i] = models.Alert{
+			TenantID:      listing.TenantID,
+			UserID:        search.UserID,
+			SavedSearchID: search.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			ListingID:     listing.ID,
+			Listing:       payload,
+			Frequency:     search.Frequency,
+			CreatedAt:     now,
+		}
+	}
+
+	return m.Repo.CreateAlerts(alerts)
+}
+
+// Run consumes the listing stream through a consumer group and sends daily
+// digests until ctx is cancelled.
+func (m *Matcher) Run(ctx context.Context, rdb *redis.Client, consumer string) {
+	err := rdb.XGroupCreateMkStream(ctx, ListingStream, consumerGroup, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		log.Println("alerts: create group:", err)
+	}
+
+	go m.digestLoop(ctx)
+
+	// Re-read entries left unacknowledged by a previous run once, then
+	// switch to new entries only.
+	lastID := "0"
+	for ctx.Err() == nil {
+		res, err := rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumerGroup,
+			Consumer: consumer,
+			Streams:  []string{ListingStream, lastID},
+			Count:    100,
+			Block:    5 * time.Second,
+		}).Result()
+		lastID = ">"
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+				log.Println("alerts: read:", err)
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		for _, stream := range res {
+			for _, msg := range stream.Messages {
+				eventID, _ := msg.Values["event_id"].(string)
+				eventType, _ := msg.Values["event_type"].(string)
+				payload, _ := msg.Values["payload"].(string)
+				monthlyPrice, _ := msg.Values["monthly_price"].(string)
+				price, _ := strconv.Atoi(monthlyPrice)
+
+				if _, err := m.HandleEvent("listing-"+eventID, eventType, payload, price); err != nil {
+					log.Println("alerts: handle event:", err)
+					continue
+				}
+				rdb.XAck(ctx, ListingStream, consumerGroup, msg.ID)
+			}
+		}
+	}
+}
+
+func (m *Matcher) digestLoop(ctx context.Context) {
+	ticker := time.NewTicker(m.DigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.Repo.SendDailyDigests(time.Now().UnixMicro()); err != nil {
+				log.Println("alerts: digest:", err)
+			}
+		}
+	}
+}
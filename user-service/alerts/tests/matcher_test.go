@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"errors"
+	"real-estate-system/user-service/alerts"
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const listingPayload = `{"id":9,"price":3500,"listing_type":"rent","city":"Jakarta Selatan","district":"Tebet"}`
+
+func TestHandleEvent_CreatesAlertPerMatch(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	matcher := alerts.NewMatcher(repo)
+
+	snapshot := models.ListingSnapshot{ID: 9, TenantID: "default", Price: 3500, ListingType: "rent", City: "Jakarta Selatan", District: "Tebet"}
+	repo.On("FindMatching", snapshot).Return([]models.SavedSearch{
+		{ID: 1, UserID: 5, Frequency: models.FrequencyInstant},
+		{ID: 2, UserID: 6, Frequency: models.FrequencyDaily},
+	}, nil)
+	repo.On("CreateAlerts", mock.MatchedBy(func(a []models.Alert) bool {
+		return len(a) == 2 &&
+			a[0].UserID == 5 && a[0].SavedSearchID == 1 && a[0].Frequency == "instant" &&
+			a[1].UserID == 6 && a[1].Frequency == "daily" &&
+			a[0].EventID == "listing-42" && a[0].ListingID == 9
+	})).Return(2, nil)
+
+	n, err := matcher.HandleEvent("listing-42", "listing.created", listingPayload, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	repo.AssertExpectations(t)
+}
+
+func TestHandleEvent_OnlyMatchesListingTenant(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	matcher := alerts.NewMatcher(repo)
+
+	repo.On("FindMatching", mock.MatchedBy(func(l models.ListingSnapshot) bool { return l.TenantID == "acme" })).
+		Return([]models.SavedSearch{{ID: 1, UserID: 5, Frequency: models.FrequencyInstant}}, nil)
+	repo.On("CreateAlerts", mock.MatchedBy(func(a []models.Alert) bool {
+		return len(a) == 1 && a[0].TenantID == "acme"
+	})).Return(1, nil)
+
+	_, err := matcher.HandleEvent("listing-46", "listing.created", `{"id":9,"tenant_id":"acme","price":3500,"listing_type":"rent"}`, 0)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestHandleEvent_PriceChangeIsMatched(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	matcher := alerts.NewMatcher(repo)
+
+	repo.On("FindMatching", mock.Anything).Return([]models.SavedSearch{}, nil)
+
+	n, err := matcher.HandleEvent("listing-43", "listing.price_changed", listingPayload, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	repo.AssertCalled(t, "FindMatching", mock.Anything)
+}
+
+func TestHandleEvent_IgnoresOtherEvents(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	matcher := alerts.NewMatcher(repo)
+
+	n, err := matcher.HandleEvent("listing-44", "listing.status_changed", listingPayload, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	repo.AssertNotCalled(t, "FindMatching", mock.Anything)
+}
+
+func TestHandleEvent_IgnoresNonPublicListings(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	matcher := alerts.NewMatcher(repo)
+
+	n, err := matcher.HandleEvent("listing-45", "listing.price_dropped", `{"id":7,"status":"hidden","price":3500,"listing_type":"rent","city":"Jakarta"}`, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	repo.AssertNotCalled(t, "FindMatching", mock.Anything)
+}
+
+func TestHandleEvent_RepoError(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	matcher := alerts.NewMatcher(repo)
+
+	repo.On("FindMatching", mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := matcher.HandleEvent("listing-45", "listing.created", listingPayload, 0)
+	assert.Error(t, err)
+}
+
+func TestHandleEvent_ComparesMonthlyPrice(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	matcher := alerts.NewMatcher(repo)
+
+	repo.On("FindMatching", mock.MatchedBy(func(l models.ListingSnapshot) bool {
+		price, ok := l.ComparablePrice()
+		return ok && price == 4000000 && l.Currency == "USD"
+	})).Return([]models.SavedSearch{}, nil)
+
+	_, err := matcher.HandleEvent("listing-47", "listing.created", `{"id":9,"price":3000,"currency":"USD","rent_period":"year","listing_type":"rent"}`, 4000000)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"encoding/json"
+	"real-estate-system/user-service/models"
+	"time"
+)
+
+const (
+	AggregateUser  = "user"
+	AggregateAlert = "alert"
+
+	UserCreated = "user.created"
+
+	// Saved search alerts, published on the alert aggregate of the user they
+	// are for, so each user's notifications stay ordered.
+	AlertCreated = "alert.created"
+	AlertDigest  = "alert.digest"
+
+	// Reviews, published on the reviewed user's aggregate.
+	ReviewCreated  = "review.created"
+	ReviewReplied  = "review.replied"
+	ReviewHidden   = "review.hidden"
+	ReviewRestored = "review.restored"
+)
+
+// NewOutboxEvent serializes payload into a pending outbox row.
+func NewOutboxEvent(eventType, aggregateType string, aggregateID int64, payload interface{}) (*models.OutboxEvent, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(body),
+		CreatedAt:     time.Now().UnixMicro(),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"real-estate-system/user-service/models"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultStream = "user-events"
+
+	// NotificationStream carries events meant only for one user, such as
+	// saved search alerts. It is kept apart from DefaultStream, which
+	// partners can subscribe to.
+	NotificationStream = "notification-events"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// RedisStreamPublisher appends events to a Redis Stream, Stream unless the
+// aggregate type is routed elsewhere by AggregateStreams. All events of an
+// aggregate go to one stream, which keeps the relay's ID ordering, so
+// consumers see them in the order they were written.
+type RedisStreamPublisher struct {
+	Client           *redis.Client
+	Stream           string
+	AggregateStreams map[string]string
+	MaxLen           int64
+}
+
+func NewRedisStreamPublisher(client *redis.Client, stream string) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		Client: client,
+		Stream: stream,
+		AggregateStreams: map[string]string{
+			AggregateAlert: NotificationStream,
+		},
+		MaxLen: 100000,
+	}
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	stream := p.Stream
+	if routed, ok := p.AggregateStreams[event.AggregateType]; ok {
+		stream = routed
+	}
+
+	return p.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: p.MaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"event_id":       strconv.FormatInt(event.ID, 10),
+			"event_type":     event.EventType,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   strconv.FormatInt(event.AggregateID, 10),
+			"payload":        event.Payload,
+			"occurred_at":    strconv.FormatInt(event.CreatedAt, 10),
+		},
+	}).Err()
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"real-estate-system/user-service/events"
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository/mocks"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type publisherMock struct {
+	mock.Mock
+}
+
+func (m *publisherMock) Publish(ctx context.Context, event models.OutboxEvent) error {
+	args := m.Called(event.ID)
+	return args.Error(0)
+}
+
+func TestPublishPending_Success(t *testing.T) {
+	repo := new(mocks.OutboxRepositoryMock)
+	pub := new(publisherMock)
+	relay := events.NewRelay(repo, pub)
+
+	repo.On("FetchPending", 100).Return([]models.OutboxEvent{
+		{ID: 1, AggregateType: "user", AggregateID: 7},
+		{ID: 2, AggregateType: "user", AggregateID: 8},
+	}, nil)
+	pub.On("Publish", int64(1)).Return(nil)
+	pub.On("Publish", int64(2)).Return(nil)
+	repo.On("MarkPublished", int64(1), mock.Anything).Return(nil)
+	repo.On("MarkPublished", int64(2), mock.Anything).Return(nil)
+
+	n, err := relay.PublishPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	repo.AssertExpectations(t)
+	pub.AssertExpectations(t)
+}
+
+func TestPublishPending_FailureHoldsBackSameAggregate(t *testing.T) {
+	repo := new(mocks.OutboxRepositoryMock)
+	pub := new(publisherMock)
+	relay := events.NewRelay(repo, pub)
+
+	repo.On("FetchPending", 100).Return([]models.OutboxEvent{
+		{ID: 1, AggregateType: "user", AggregateID: 7},
+		{ID: 2, AggregateType: "user", AggregateID: 8},
+		{ID: 3, AggregateType: "user", AggregateID: 7},
+	}, nil)
+	pub.On("Publish", int64(1)).Return(errors.New("redis down"))
+	pub.On("Publish", int64(2)).Return(nil)
+	repo.On("MarkFailed", int64(1), "redis down").Return(nil)
+	repo.On("MarkPublished", int64(2), mock.Anything).Return(nil)
+
+	n, err := relay.PublishPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	pub.AssertNotCalled(t, "Publish", int64(3))
+	repo.AssertExpectations(t)
+}
+
+func TestPublishPending_FetchError(t *testing.T) {
+	repo := new(mocks.OutboxRepositoryMock)
+	relay := events.NewRelay(repo, new(publisherMock))
+
+	repo.On("FetchPending", 100).Return([]models.OutboxEvent{}, errors.New("db error"))
+
+	n, err := relay.PublishPending(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestRedisStreamPublisher_Publish(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	pub := events.NewRedisStreamPublisher(rdb, events.DefaultStream)
+
+	err := pub.Publish(context.Background(), models.OutboxEvent{
+		ID:            5,
+		AggregateType: "user",
+		AggregateID:   7,
+		EventType:     events.UserCreated,
+		Payload:       `{"id":7}`,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := rdb.XRange(context.Background(), events.DefaultStream, "-", "+").Result()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, "user.created", msgs[0].Values["event_type"])
+	assert.Equal(t, "7", msgs[0].Values["aggregate_id"])
+	assert.Equal(t, "5", msgs[0].Values["event_id"])
+}
+
+func TestRedisStreamPublisher_RoutesAlertEvents(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	pub := events.NewRedisStreamPublisher(rdb, events.DefaultStream)
+
+	err := pub.Publish(context.Background(), models.OutboxEvent{
+		ID:            6,
+		AggregateType: events.AggregateAlert,
+		AggregateID:   5,
+		EventType:     events.AlertCreated,
+		Payload:       `{"user_id":5}`,
+	})
+	assert.NoError(t, err)
+
+	msgs, err := rdb.XRange(context.Background(), events.NotificationStream, "-", "+").Result()
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.False(t, mr.Exists(events.DefaultStream))
+}
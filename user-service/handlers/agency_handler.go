@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/user-service/models"
+	repository "real-estate-system/user-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxAgencyName = 200
+
+type AgencyHandler struct {
+	Repo     repository.AgencyRepository
+	Profiles repository.AgentProfileRepository
+	Users    repository.UserRepository
+}
+
+func NewAgencyHandler(repo repository.AgencyRepository, profiles repository.AgentProfileRepository, users repository.UserRepository) *AgencyHandler {
+	return &AgencyHandler{Repo: repo, Profiles: profiles, Users: users}
+}
+
+// actingUser reads the user_id form value of the user making the request.
+func actingUser(c echo.Context) (int64, error) {
+	userID, err := strconv.ParseInt(c.FormValue("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	return userID, nil
+}
+
+// agencyAdmin checks that the acting user is an active admin of the :id
+// agency.
+func (h *AgencyHandler) agencyAdmin(c echo.Context) (int64, int64, error) {
+	agencyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid agency ID")
+	}
+	userID, err := actingUser(c)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	member, err := h.Repo.GetMember(agencyID, userID)
+	if err != nil || member == nil || member.Status != models.MemberActive || member.Role != models.AgencyRoleAdmin {
+		return 0, 0, echo.NewHTTPError(http.StatusForbidden, "Only agency admins can do this")
+	}
+	return agencyID, userID, nil
+}
+
+// CreateAgency creates an agency (name) with the user_id as its admin.
+func (h *AgencyHandler) CreateAgency(c echo.Context) error {
+	userID, err := actingUser(c)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+	if len(name) > maxAgencyName {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is too long")
+	}
+	if user, err := tenantUsers(c, h.Users).GetUser(int(userID)); err != nil || user == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	now := time.Now().UnixMicro()
+	agency := models.Agency{Name: name, CreatedBy: userID, CreatedAt: now, UpdatedAt: now}
+	err = h.Repo.CreateAgency(&agency, userID)
+	if errors.Is(err, models.ErrAlreadyInAgency) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, echo.Map{
+		"result": true,
+		"agency": agency,
+	})
+}
+
+// GetAgency returns the agency with its members and their agent profiles.
+func (h *AgencyHandler) GetAgency(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid agency ID")
+	}
+
+	agency, err := h.Repo.GetAgency(id)
+	if err != nil || agency == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Agency not found")
+	}
+	// Agencies belong to the tenant of the user who created them.
+	if creator, err := tenantUsers(c, h.Users).GetUser(int(agency.CreatedBy)); err != nil || creator == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Agency not found")
+	}
+
+	ids := make([]int64, len(agency.Members))
+	for i, member := range agency.Members {
+		ids[i] = member.UserID
+	}
+	profiles, err := h.Profiles.GetAgentProfiles(ids)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	byUser := make(map[int64]*models.AgentProfile, len(profiles))
+	for i := range profiles {
+		byUser[profiles[i].UserID] = &profiles[i]
+	}
+	for _, member := range agency.Members {
+		if profile := byUser[member.UserID]; profile != nil && member.User != nil {
+			member.User.Agent = profile.Summary(nil)
+		}
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+		"agency": agency,
+	})
+}
+
+// InviteMember lets an agency admin (user_id) invite member_id as an agent,
+// or as an admin with role=admin.
+func (h *AgencyHandler) InviteMember(c echo.Context) error {
+	agencyID, adminID, err := h.agencyAdmin(c)
+	if err != nil {
+		return err
+	}
+	memberID, err := strconv.Atoi(c.FormValue("member_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid member_id")
+	}
+	role := c.FormValue("role")
+	switch role {
+	case "":
+		role = models.AgencyRoleAgent
+	case models.AgencyRoleAgent, models.AgencyRoleAdmin:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "role must be 'agent' or 'admin'")
+	}
+	if user, err := tenantUsers(c, h.Users).GetUser(memberID); err != nil || user == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	member := models.AgencyMember{
+		AgencyID:  agencyID,
+		UserID:    int64(memberID),
+		Role:      role,
+		Status:    models.MemberInvited,
+		InvitedBy: adminID,
+		CreatedAt: time.Now().UnixMicro(),
+	}
+	err = h.Repo.InviteMember(&member)
+	if errors.Is(err, models.ErrAlreadyInvited) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, echo.Map{
+		"result": true,
+		"member": member,
+	})
+}
+
+// RemoveMember removes a member or withdraws their invitation. Agency
+// admins can remove anyone; members can leave (user_id = member_id).
+func (h *AgencyHandler) RemoveMember(c echo.Context) error {
+	agencyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid agency ID")
+	}
+	memberID, err := strconv.ParseInt(c.Param("member_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid member ID")
+	}
+	userID, err := actingUser(c)
+	if err != nil {
+		return err
+	}
+	if userID != memberID {
+		if _, _, err := h.agencyAdmin(c); err != nil {
+			return err
+		}
+	}
+
+	member, err := h.Repo.GetMember(agencyID, memberID)
+	if err != nil || member == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Member not found")
+	}
+	err = h.Repo.RemoveMember(member)
+	if errors.Is(err, models.ErrLastAgencyAdmin) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+	})
+}
+
+// GetMembership returns the agency the user works for, if any, and their
+// open invitations.
+func (h *AgencyHandler) GetMembership(c echo.Context) error {
+	userID, err := existingUser(c, h.Users, "id")
+	if err != nil {
+		return err
+	}
+
+	membership, err := h.Repo.GetMembership(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	invitations, err := h.Repo.GetInvitations(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":      true,
+		"membership":  membership,
+		"invitations": invitations,
+	})
+}
+
+// AcceptInvitation makes the user an active member of the :agency_id
+// agency.
+func (h *AgencyHandler) AcceptInvitation(c echo.Context) error {
+	userID, err := existingUser(c, h.Users, "id")
+	if err != nil {
+		return err
+	}
+	agencyID, err := strconv.ParseInt(c.Param("agency_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid agency ID")
+	}
+
+	member, err := h.Repo.AcceptInvitation(agencyID, userID)
+	switch {
+	case errors.Is(err, models.ErrNotInvited):
+		return echo.NewHTTPError(http.StatusNotFound, "Invitation not found")
+	case errors.Is(err, models.ErrAlreadyInAgency):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+		"member": member,
+	})
+}
+
+// GetManagedAgents lists the users whose listings the user manages as an
+// agency admin.
+func (h *AgencyHandler) GetManagedAgents(c echo.Context) error {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	ids, err := h.Repo.GetManagedAgents(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":    true,
+		"agent_ids": ids,
+	})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/user-service/models"
+	repository "real-estate-system/user-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	roleAdmin = "admin"
+
+	maxLicenseNumber = 50
+	maxProfileItems  = 20
+	maxBio           = 2000
+	maxReviewNote    = 1000
+)
+
+// admin returns the acting user_id if the request is made as an
+// administrator (role=admin).
+func admin(c echo.Context) (int, error) {
+	if c.FormValue("role") != roleAdmin {
+		return 0, echo.NewHTTPError(http.StatusForbidden, "Only administrators can do this")
+	}
+	userID, err := strconv.Atoi(c.FormValue("user_id"))
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user_id")
+	}
+	return userID, nil
+}
+
+type AgentProfileHandler struct {
+	Repo  repository.AgentProfileRepository
+	Users repository.UserRepository
+}
+
+func NewAgentProfileHandler(repo repository.AgentProfileRepository, users repository.UserRepository) *AgentProfileHandler {
+	return &AgentProfileHandler{Repo: repo, Users: users}
+}
+
+func (h *AgentProfileHandler) GetAgentProfile(c echo.Context) error {
+	userID, err := existingUser(c, h.Users, "id")
+	if err != nil {
+		return err
+	}
+
+	profile, err := h.Repo.GetAgentProfile(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if profile == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Agent profile not found")
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":        true,
+		"agent_profile": profile,
+	})
+}
+
+// SaveAgentProfile creates or replaces the user's profile (license_number,
+// comma separated service_areas and languages, bio). New or changed license
+// details, and ones that were rejected, wait for verification again.
+func (h *AgentProfileHandler) SaveAgentProfile(c echo.Context) error {
+	userID, err := existingUser(c, h.Users, "id")
+	if err != nil {
+		return err
+	}
+
+	licenseNumber := strings.TrimSpace(c.FormValue("license_number"))
+	if licenseNumber == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "license_number is required")
+	}
+	if len(licenseNumber) > maxLicenseNumber {
+		return echo.NewHTTPError(http.StatusBadRequest, "license_number is too long")
+	}
+	serviceAreas, err := profileList(c, "service_areas")
+	if err != nil {
+		return err
+	}
+	languages, err := profileList(c, "languages")
+	if err != nil {
+		return err
+	}
+	bio := strings.TrimSpace(c.FormValue("bio"))
+	if len(bio) > maxBio {
+		return echo.NewHTTPError(http.StatusBadRequest, "bio is too long")
+	}
+
+	profile, err := h.Repo.GetAgentProfile(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	now := time.Now().UnixMicro()
+	if profile == nil {
+		profile = &models.AgentProfile{UserID: userID, CreatedAt: now}
+	}
+	if profile.LicenseNumber != licenseNumber || profile.VerificationStatus != models.VerificationVerified {
+		profile.VerificationStatus = models.VerificationPending
+		profile.VerificationNote = ""
+		profile.SubmittedAt = now
+		profile.ReviewedBy = 0
+		profile.ReviewedAt = 0
+	}
+	profile.LicenseNumber = licenseNumber
+	profile.ServiceAreas = serviceAreas
+	profile.Languages = languages
+	profile.Bio = bio
+	profile.UpdatedAt = now
+
+	if err := h.Repo.SaveAgentProfile(profile); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":        true,
+		"agent_profile": profile,
+	})
+}
+
+// profileList splits a comma separated form value, dropping blanks and
+// repeats.
+func profileList(c echo.Context, name string) ([]string, error) {
+	items := []string{}
+	seen := map[string]bool{}
+	for _, item := range strings.Split(c.FormValue(name), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[strings.ToLower(item)] {
+			continue
+		}
+		seen[strings.ToLower(item)] = true
+		items = append(items, item)
+	}
+	if len(items) > maxProfileItems {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, name+" has too many entries")
+	}
+	return items, nil
+}
+
+// GetVerificationQueue lists agent profiles waiting for verification, or
+// those with status (verified, rejected or all), longest waiting first.
+func (h *AgentProfileHandler) GetVerificationQueue(c echo.Context) error {
+	if _, err := admin(c); err != nil {
+		return err
+	}
+
+	status := c.QueryParam("status")
+	switch status {
+	case "":
+		status = models.VerificationPending
+	case "all":
+		status = ""
+	case models.VerificationPending, models.VerificationVerified, models.VerificationRejected:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be 'pending', 'verified', 'rejected' or 'all'")
+	}
+
+	pageNum, _ := strconv.Atoi(c.QueryParam("page_num"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	profiles, err := h.Repo.GetVerificationQueue(status, pageNum, pageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":         true,
+		"agent_profiles": profiles,
+	})
+}
+
+// ApproveAgentProfile verifies the agent's license details.
+func (h *AgentProfileHandler) ApproveAgentProfile(c echo.Context) error {
+	return h.review(c, models.VerificationVerified)
+}
+
+// RejectAgentProfile rejects the agent's license details with a note
+// telling them why.
+func (h *AgentProfileHandler) RejectAgentProfile(c echo.Context) error {
+	return h.review(c, models.VerificationRejected)
+}
+
+func (h *AgentProfileHandler) review(c echo.Context, status string) error {
+	adminID, err := admin(c)
+	if err != nil {
+		return err
+	}
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+	note := strings.TrimSpace(c.FormValue("note"))
+	if len(note) > maxReviewNote {
+		return echo.NewHTTPError(http.StatusBadRequest, "note is too long")
+	}
+	if note == "" && status == models.VerificationRejected {
+		return echo.NewHTTPError(http.StatusBadRequest, "note is required")
+	}
+
+	profile, err := h.Repo.ReviewAgentProfile(userID, status, adminID, note)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Agent profile not found")
+	case errors.Is(err, models.ErrVerificationClosed):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":        true,
+		"agent_profile": profile,
+	})
+}
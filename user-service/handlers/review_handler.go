@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"real-estate-system/user-service/models"
+	repository "real-estate-system/user-service/repository/interfaces"
+	"real-estate-system/user-service/tenant"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	maxReviewText  = 2000
+	maxReviewReply = 2000
+)
+
+type ReviewHandler struct {
+	Repo  repository.ReviewRepository
+	Users repository.UserRepository
+}
+
+func NewReviewHandler(repo repository.ReviewRepository, users repository.UserRepository) *ReviewHandler {
+	return &ReviewHandler{Repo: repo, Users: users}
+}
+
+// CreateReview reviews the :id user as reviewer_id (rating of 1 to 5 stars,
+// text). interaction_type and interaction_id name the closed inquiry,
+// viewing or lease that makes the reviewer eligible; the gateway checks it
+// with the listing service.
+func (h *ReviewHandler) CreateReview(c echo.Context) error {
+	revieweeID, err := existingUser(c, h.Users, "id")
+	if err != nil {
+		return err
+	}
+	reviewerID, err := strconv.ParseInt(c.FormValue("reviewer_id"), 10, 64)
+	if err != nil || reviewerID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid reviewer_id")
+	}
+	if reviewerID == revieweeID {
+		return echo.NewHTTPError(http.StatusBadRequest, "You cannot review yourself")
+	}
+	if user, err := tenantUsers(c, h.Users).GetUser(int(reviewerID)); err != nil || user == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Reviewer not found")
+	}
+
+	rating, err := strconv.Atoi(c.FormValue("rating"))
+	if err != nil || rating < 1 || rating > 5 {
+		return echo.NewHTTPError(http.StatusBadRequest, "rating must be 1 to 5")
+	}
+	text := strings.TrimSpace(c.FormValue("text"))
+	if len(text) > maxReviewText {
+		return echo.NewHTTPError(http.StatusBadRequest, "text is too long")
+	}
+	interactionType := c.FormValue("interaction_type")
+	interactionID, err := strconv.ParseInt(c.FormValue("interaction_id"), 10, 64)
+	if !models.ValidInteractionType(interactionType) || err != nil || interactionID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "A closed inquiry, viewing or lease is required to review")
+	}
+
+	now := time.Now().UnixMicro()
+	review := models.Review{
+		TenantID:        tenant.ID(c),
+		ReviewerID:      reviewerID,
+		RevieweeID:      revieweeID,
+		Rating:          rating,
+		Text:            text,
+		InteractionType: interactionType,
+		InteractionID:   interactionID,
+		Status:          models.ReviewPublished,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = h.Repo.CreateReview(&review)
+	if errors.Is(err, models.ErrReviewExists) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, echo.Map{
+		"result": true,
+		"review": review,
+	})
+}
+
+// GetReviews lists the published reviews of the :id user, newest first,
+// with their rating.
+func (h *ReviewHandler) GetReviews(c echo.Context) error {
+	userID, err := existingUser(c, h.Users, "id")
+	if err != nil {
+		return err
+	}
+	pageNum, pageSize := reviewPage(c)
+
+	filter := models.ReviewFilter{RevieweeID: userID, Status: models.ReviewPublished}
+	reviews, err := h.Repo.GetReviews(filter, pageNum, pageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	rating, err := h.Repo.GetRating(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":  true,
+		"rating":  rating,
+		"reviews": reviews,
+	})
+}
+
+// ReplyToReview sets the :id user's reply to a review of them.
+func (h *ReviewHandler) ReplyToReview(c echo.Context) error {
+	userID, err := existingUser(c, h.Users, "id")
+	if err != nil {
+		return err
+	}
+	reviewID, err := strconv.ParseInt(c.Param("review_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+	reply := strings.TrimSpace(c.FormValue("reply"))
+	if reply == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "reply is required")
+	}
+	if len(reply) > maxReviewReply {
+		return echo.NewHTTPError(http.StatusBadRequest, "reply is too long")
+	}
+
+	review, err := h.Repo.ReplyToReview(reviewID, userID, reply)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, models.ErrNotReviewee):
+		return echo.NewHTTPError(http.StatusNotFound, "Review not found")
+	case errors.Is(err, models.ErrReviewNotVisible):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+		"review": review,
+	})
+}
+
+// GetModerationQueue lists reviews for moderators, newest first, optionally
+// by status (published or hidden).
+func (h *ReviewHandler) GetModerationQueue(c echo.Context) error {
+	if _, err := admin(c); err != nil {
+		return err
+	}
+
+	status := c.QueryParam("status")
+	switch status {
+	case "", models.ReviewPublished, models.ReviewHidden:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be 'published' or 'hidden'")
+	}
+	pageNum, pageSize := reviewPage(c)
+
+	reviews, err := h.Repo.GetReviews(models.ReviewFilter{Status: status}, pageNum, pageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":  true,
+		"reviews": reviews,
+	})
+}
+
+// HideReview takes a review down with a note saying why. It no longer
+// counts towards the rating.
+func (h *ReviewHandler) HideReview(c echo.Context) error {
+	return h.moderate(c, models.ReviewHidden)
+}
+
+// RestoreReview publishes a hidden review again.
+func (h *ReviewHandler) RestoreReview(c echo.Context) error {
+	return h.moderate(c, models.ReviewPublished)
+}
+
+func (h *ReviewHandler) moderate(c echo.Context, status string) error {
+	adminID, err := admin(c)
+	if err != nil {
+		return err
+	}
+	reviewID, err := strconv.ParseInt(c.Param("review_id"), 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid review ID")
+	}
+	note := strings.TrimSpace(c.FormValue("note"))
+	if len(note) > maxReviewNote {
+		return echo.NewHTTPError(http.StatusBadRequest, "note is too long")
+	}
+	if note == "" && status == models.ReviewHidden {
+		return echo.NewHTTPError(http.StatusBadRequest, "note is required")
+	}
+
+	review, err := h.Repo.ModerateReview(reviewID, status, adminID, note)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Review not found")
+	case errors.Is(err, models.ErrReviewModerated):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+		"review": review,
+	})
+}
+
+func reviewPage(c echo.Context) (int, int) {
+	pageNum, _ := strconv.Atoi(c.QueryParam("page_num"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	pageSize, _ := strconv.Atoi(c.QueryParam("page_size"))
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return pageNum, pageSize
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"net/http"
+	"real-estate-system/user-service/models"
+	repository "real-estate-system/user-service/repository/interfaces"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SavedSearchHandler struct {
+	Repo  repository.SavedSearchRepository
+	Users repository.UserRepository
+}
+
+func NewSavedSearchHandler(repo repository.SavedSearchRepository, users repository.UserRepository) *SavedSearchHandler {
+	return &SavedSearchHandler{Repo: repo, Users: users}
+}
+
+func (h *SavedSearchHandler) userID(c echo.Context) (int64, error) {
+	return existingUser(c, h.Users, "id")
+}
+
+// existingUser reads the user ID in the param and checks the user exists.
+func existingUser(c echo.Context, users repository.UserRepository, param string) (int64, error) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
+	}
+
+	user, err := tenantUsers(c, users).GetUser(id)
+	if err != nil || user == nil {
+		return 0, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+	return user.ID, nil
+}
+
+// ownSearch loads the saved search in the URL if it belongs to userID.
+func (h *SavedSearchHandler) ownSearch(c echo.Context, userID int64) (*models.SavedSearch, error) {
+	id, err := strconv.ParseInt(c.Param("search_id"), 10, 64)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid saved search ID")
+	}
+
+	search, err := h.Repo.GetSavedSearch(id)
+	if err != nil || search == nil || search.UserID != userID {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Saved search not found")
+	}
+	return search, nil
+}
+
+// bindSavedSearch applies the form values present in the request to search.
+func bindSavedSearch(c echo.Context, search *models.SavedSearch) error {
+	if name, ok := formValue(c, "name"); ok {
+		search.Name = strings.TrimSpace(name)
+	}
+	if listingType, ok := formValue(c, "listing_type"); ok {
+		if listingType != "" && listingType != "rent" && listingType != "sale" {
+			return echo.NewHTTPError(http.StatusBadRequest, "listing_type must be 'rent' or 'sale'")
+		}
+		search.ListingType = listingType
+	}
+	if area, ok := formValue(c, "area"); ok {
+		search.Area = strings.ToLower(strings.TrimSpace(area))
+	}
+	for name, dst := range map[string]*int{"min_price": &search.MinPrice, "max_price": &search.MaxPrice} {
+		raw, ok := formValue(c, name)
+		if !ok {
+			continue
+		}
+		if raw == "" {
+			*dst = 0
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+		}
+		*dst = v
+	}
+	if frequency, ok := formValue(c, "frequency"); ok {
+		if frequency != models.FrequencyInstant && frequency != models.FrequencyDaily {
+			return echo.NewHTTPError(http.StatusBadRequest, "frequency must be 'instant' or 'daily'")
+		}
+		search.Frequency = frequency
+	}
+
+	if search.MaxPrice > 0 && search.MinPrice > search.MaxPrice {
+		return echo.NewHTTPError(http.StatusBadRequest, "min_price must not exceed max_price")
+	}
+	return nil
+}
+
+func formValue(c echo.Context, name string) (string, bool) {
+	params, err := c.FormParams()
+	if err != nil {
+		return "", false
+	}
+	values, ok := params[name]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+func (h *SavedSearchHandler) CreateSavedSearch(c echo.Context) error {
+	userID, err := h.userID(c)
+	if err != nil {
+		return err
+	}
+
+	search := models.SavedSearch{UserID: userID, Frequency: models.FrequencyInstant}
+	if err := bindSavedSearch(c, &search); err != nil {
+		return err
+	}
+	if search.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+
+	now := time.Now().UnixMicro()
+	search.CreatedAt = now
+	search.UpdatedAt = now
+
+	if err := h.Repo.CreateSavedSearch(&search); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, echo.Map{
+		"result":       true,
+		"saved_search": search,
+	})
+}
+
+func (h *SavedSearchHandler) GetSavedSearches(c echo.Context) error {
+	userID, err := h.userID(c)
+	if err != nil {
+		return err
+	}
+
+	searches, err := h.Repo.GetSavedSearches(userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":         true,
+		"saved_searches": searches,
+	})
+}
+
+func (h *SavedSearchHandler) UpdateSavedSearch(c echo.Context) error {
+	userID, err := h.userID(c)
+	if err != nil {
+		return err
+	}
+
+	search, err := h.ownSearch(c, userID)
+	if err != nil {
+		return err
+	}
+	if err := bindSavedSearch(c, search); err != nil {
+		return err
+	}
+	search.UpdatedAt = time.Now().UnixMicro()
+
+	if err := h.Repo.UpdateSavedSearch(search); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result":       true,
+		"saved_search": search,
+	})
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearch(c echo.Context) error {
+	userID, err := h.userID(c)
+	if err != nil {
+		return err
+	}
+
+	search, err := h.ownSearch(c, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteSavedSearch(search.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+	})
+}
+
+// GetAlerts returns unread alerts unless all=true is given.
+func (h *SavedSearchHandler) GetAlerts(c echo.Context) error {
+	userID, err := h.userID(c)
+	if err != nil {
+		return err
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	alerts, err := h.Repo.GetAlerts(userID, c.QueryParam("all") != "true", limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+		"alerts": alerts,
+	})
+}
+
+// MarkAlertsRead marks the comma separated ids as read, or every unread
+// alert when ids is empty.
+func (h *SavedSearchHandler) MarkAlertsRead(c echo.Context) error {
+	userID, err := h.userID(c)
+	if err != nil {
+		return err
+	}
+
+	var ids []int64
+	for _, raw := range strings.Split(c.FormValue("ids"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid alert ID")
+		}
+		ids = append(ids, id)
+	}
+
+	if err := h.Repo.MarkAlertsRead(userID, ids, time.Now().UnixMicro()); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{
+		"result": true,
+	})
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"net/http"
+	"real-estate-system/user-service/handlers"
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository/mocks"
+	"real-estate-system/user-service/tenant"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newAgencyHandler() (*handlers.AgencyHandler, *mocks.AgencyRepositoryMock, *mocks.AgentProfileRepositoryMock) {
+	repo := new(mocks.AgencyRepositoryMock)
+	profiles := new(mocks.AgentProfileRepositoryMock)
+	users := new(mocks.UserRepositoryMock)
+	users.On("GetUser", 5).Return(&models.User{ID: 5, Name: "Dewi"}, nil)
+	users.On("GetUser", 6).Return(&models.User{ID: 6, Name: "Bayu"}, nil)
+	return handlers.NewAgencyHandler(repo, profiles, users), repo, profiles
+}
+
+func TestInviteMember_AsAgencyAdmin(t *testing.T) {
+	h, repo, _ := newAgencyHandler()
+	repo.On("GetMember", int64(3), int64(5)).Return(&models.AgencyMember{
+		AgencyID: 3, UserID: 5, Role: models.AgencyRoleAdmin, Status: models.MemberActive,
+	}, nil)
+	repo.On("InviteMember", mock.MatchedBy(func(m *models.AgencyMember) bool {
+		return m.AgencyID == 3 && m.UserID == 6 && m.Role == models.AgencyRoleAgent && m.Status == models.MemberInvited && m.InvitedBy == 5
+	})).Return(nil)
+
+	c, rec := newFormContext(http.MethodPost, "/agencies/3/members", "user_id=5&member_id=6", []string{"id"}, []string{"3"})
+
+	assert.NoError(t, h.InviteMember(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestInviteMember_AgentCannotInvite(t *testing.T) {
+	h, repo, _ := newAgencyHandler()
+	repo.On("GetMember", int64(3), int64(5)).Return(&models.AgencyMember{
+		AgencyID: 3, UserID: 5, Role: models.AgencyRoleAgent, Status: models.MemberActive,
+	}, nil)
+
+	c, _ := newFormContext(http.MethodPost, "/agencies/3/members", "user_id=5&member_id=6", []string{"id"}, []string{"3"})
+
+	err := h.InviteMember(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "InviteMember", mock.Anything)
+}
+
+func TestRemoveMember_LastAdminCannotLeave(t *testing.T) {
+	h, repo, _ := newAgencyHandler()
+	admin := &models.AgencyMember{AgencyID: 3, UserID: 5, Role: models.AgencyRoleAdmin, Status: models.MemberActive}
+	repo.On("GetMember", int64(3), int64(5)).Return(admin, nil)
+	repo.On("RemoveMember", admin).Return(models.ErrLastAgencyAdmin)
+
+	c, _ := newFormContext(http.MethodDelete, "/agencies/3/members/5?user_id=5", "", []string{"id", "member_id"}, []string{"3", "5"})
+
+	err := h.RemoveMember(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestAcceptInvitation_AlreadyInAgency(t *testing.T) {
+	h, repo, _ := newAgencyHandler()
+	repo.On("AcceptInvitation", int64(3), int64(6)).Return(nil, models.ErrAlreadyInAgency)
+
+	c, _ := newFormContext(http.MethodPost, "/users/6/agency-invitations/3/accept", "", []string{"id", "agency_id"}, []string{"6", "3"})
+
+	err := h.AcceptInvitation(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestGetAgency_ShowsAgentProfiles(t *testing.T) {
+	h, repo, profiles := newAgencyHandler()
+	repo.On("GetAgency", int64(3)).Return(&models.Agency{ID: 3, Name: "Rumah Kita", CreatedBy: 5, Members: []models.AgencyMember{
+		{AgencyID: 3, UserID: 5, User: &models.User{ID: 5}},
+		{AgencyID: 3, UserID: 6, User: &models.User{ID: 6}},
+	}}, nil)
+	profiles.On("GetAgentProfiles", []int64{5, 6}).Return([]models.AgentProfile{
+		{UserID: 6, LicenseNumber: "AREBI-9", VerificationStatus: models.VerificationPending},
+	}, nil)
+
+	c, rec := newFormContext(http.MethodGet, "/agencies/3", "", []string{"id"}, []string{"3"})
+
+	assert.NoError(t, h.GetAgency(c))
+	assert.Contains(t, rec.Body.String(), `"agent":{"verified":false`)
+	assert.NotContains(t, rec.Body.String(), "AREBI-9")
+}
+
+func TestGetAgency_OtherTenantNotFound(t *testing.T) {
+	repo := new(mocks.AgencyRepositoryMock)
+	users := new(mocks.UserRepositoryMock)
+	h := handlers.NewAgencyHandler(repo, new(mocks.AgentProfileRepositoryMock), users)
+	repo.On("GetAgency", int64(3)).Return(&models.Agency{ID: 3, Name: "Rumah Kita", CreatedBy: 5}, nil)
+	users.On("GetUser", 5).Return(nil, gorm.ErrRecordNotFound)
+
+	c, _ := newFormContext(http.MethodGet, "/agencies/3", "", []string{"id"}, []string{"3"})
+	c.Request().Header.Set(tenant.Header, "acme")
+
+	err := h.GetAgency(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+	assert.Equal(t, "acme", users.TenantID)
+}
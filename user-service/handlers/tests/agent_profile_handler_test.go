@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"net/http"
+	"real-estate-system/user-service/handlers"
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAgentProfileHandler() (*handlers.AgentProfileHandler, *mocks.AgentProfileRepositoryMock) {
+	repo := new(mocks.AgentProfileRepositoryMock)
+	users := new(mocks.UserRepositoryMock)
+	users.On("GetUser", 5).Return(&models.User{ID: 5, Name: "Dewi"}, nil)
+	return handlers.NewAgentProfileHandler(repo, users), repo
+}
+
+func TestSaveAgentProfile_NewLicenseWaitsForVerification(t *testing.T) {
+	h, repo := newAgentProfileHandler()
+	repo.On("GetAgentProfile", int64(5)).Return(&models.AgentProfile{
+		UserID: 5, LicenseNumber: "OLD-1", VerificationStatus: models.VerificationVerified, ReviewedBy: 1,
+	}, nil)
+	repo.On("SaveAgentProfile", mock.MatchedBy(func(p *models.AgentProfile) bool {
+		return p.LicenseNumber == "NEW-2" && p.VerificationStatus == models.VerificationPending && p.ReviewedBy == 0 &&
+			assert.ObjectsAreEqual([]string{"Jakarta Selatan", "Depok"}, p.ServiceAreas) &&
+			assert.ObjectsAreEqual([]string{"id", "en"}, p.Languages)
+	})).Return(nil)
+
+	c, rec := newFormContext(http.MethodPut, "/users/5/agent-profile",
+		"license_number=NEW-2&service_areas=Jakarta+Selatan,+Depok,jakarta+selatan&languages=id,en",
+		[]string{"id"}, []string{"5"})
+
+	assert.NoError(t, h.SaveAgentProfile(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestSaveAgentProfile_SameLicenseStaysVerified(t *testing.T) {
+	h, repo := newAgentProfileHandler()
+	repo.On("GetAgentProfile", int64(5)).Return(&models.AgentProfile{
+		UserID: 5, LicenseNumber: "OLD-1", VerificationStatus: models.VerificationVerified,
+	}, nil)
+	repo.On("SaveAgentProfile", mock.MatchedBy(func(p *models.AgentProfile) bool {
+		return p.VerificationStatus == models.VerificationVerified && p.Bio == "Ten years in Depok"
+	})).Return(nil)
+
+	c, _ := newFormContext(http.MethodPut, "/users/5/agent-profile", "license_number=OLD-1&bio=Ten+years+in+Depok",
+		[]string{"id"}, []string{"5"})
+
+	assert.NoError(t, h.SaveAgentProfile(c))
+	repo.AssertExpectations(t)
+}
+
+func TestRejectAgentProfile_RequiresNote(t *testing.T) {
+	h, repo := newAgentProfileHandler()
+
+	c, _ := newFormContext(http.MethodPost, "/agent-profiles/5/reject", "user_id=1&role=admin",
+		[]string{"user_id"}, []string{"5"})
+
+	err := h.RejectAgentProfile(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "ReviewAgentProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveAgentProfile_AlreadyReviewed(t *testing.T) {
+	h, repo := newAgentProfileHandler()
+	repo.On("ReviewAgentProfile", int64(5), models.VerificationVerified, 1, "").Return(nil, models.ErrVerificationClosed)
+
+	c, _ := newFormContext(http.MethodPost, "/agent-profiles/5/approve", "user_id=1&role=admin",
+		[]string{"user_id"}, []string{"5"})
+
+	err := h.ApproveAgentProfile(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"net/http"
+	"real-estate-system/user-service/handlers"
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository/mocks"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newReviewHandler() (*handlers.ReviewHandler, *mocks.ReviewRepositoryMock) {
+	repo := new(mocks.ReviewRepositoryMock)
+	users := new(mocks.UserRepositoryMock)
+	users.On("GetUser", 5).Return(&models.User{ID: 5, Name: "Dewi"}, nil)
+	users.On("GetUser", 6).Return(&models.User{ID: 6, Name: "Bayu"}, nil)
+	return handlers.NewReviewHandler(repo, users), repo
+}
+
+func TestCreateReview_Success(t *testing.T) {
+	h, repo := newReviewHandler()
+	repo.On("CreateReview", mock.MatchedBy(func(r *models.Review) bool {
+		return r.ReviewerID == 6 && r.RevieweeID == 5 && r.Rating == 4 && r.Text == "Quick to fix the boiler" &&
+			r.InteractionType == models.InteractionLease && r.InteractionID == 9 &&
+			r.Status == models.ReviewPublished && r.TenantID == models.DefaultTenant
+	})).Return(nil)
+
+	c, rec := newFormContext(http.MethodPost, "/users/5/reviews",
+		"reviewer_id=6&rating=4&text=Quick+to+fix+the+boiler&interaction_type=lease&interaction_id=9",
+		[]string{"id"}, []string{"5"})
+
+	assert.NoError(t, h.CreateReview(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateReview_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		form string
+	}{
+		{"rating too low", "reviewer_id=6&rating=0&interaction_type=viewing&interaction_id=2"},
+		{"rating too high", "reviewer_id=6&rating=6&interaction_type=viewing&interaction_id=2"},
+		{"no interaction", "reviewer_id=6&rating=5"},
+		{"unknown interaction", "reviewer_id=6&rating=5&interaction_type=offer&interaction_id=2"},
+		{"self review", "reviewer_id=5&rating=5&interaction_type=viewing&interaction_id=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo := newReviewHandler()
+			c, _ := newFormContext(http.MethodPost, "/users/5/reviews", tt.form, []string{"id"}, []string{"5"})
+
+			err := h.CreateReview(c)
+			assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+			repo.AssertNotCalled(t, "CreateReview", mock.Anything)
+		})
+	}
+}
+
+func TestCreateReview_AlreadyReviewed(t *testing.T) {
+	h, repo := newReviewHandler()
+	repo.On("CreateReview", mock.Anything).Return(models.ErrReviewExists)
+
+	c, _ := newFormContext(http.MethodPost, "/users/5/reviews",
+		"reviewer_id=6&rating=2&interaction_type=inquiry&interaction_id=4", []string{"id"}, []string{"5"})
+
+	err := h.CreateReview(c)
+	assert.Equal(t, http.StatusConflict, err.(*echo.HTTPError).Code)
+}
+
+func TestReplyToReview_OnlyReviewee(t *testing.T) {
+	h, repo := newReviewHandler()
+	repo.On("ReplyToReview", int64(3), int64(6), "Thanks").Return(nil, models.ErrNotReviewee)
+
+	c, _ := newFormContext(http.MethodPost, "/users/6/reviews/3/reply", "reply=Thanks",
+		[]string{"id", "review_id"}, []string{"6", "3"})
+
+	err := h.ReplyToReview(c)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestHideReview_RequiresNote(t *testing.T) {
+	h, repo := newReviewHandler()
+
+	c, _ := newFormContext(http.MethodPost, "/reviews/3/hide", "user_id=1&role=admin",
+		[]string{"review_id"}, []string{"3"})
+
+	err := h.HideReview(c)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+	repo.AssertNotCalled(t, "ModerateReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHideReview_AdminsOnly(t *testing.T) {
+	h, _ := newReviewHandler()
+
+	c, _ := newFormContext(http.MethodPost, "/reviews/3/hide", "user_id=5&note=Spam",
+		[]string{"review_id"}, []string{"3"})
+
+	err := h.HideReview(c)
+	assert.Equal(t, http.StatusForbidden, err.(*echo.HTTPError).Code)
+}
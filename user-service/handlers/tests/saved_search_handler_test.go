@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"real-estate-system/user-service/handlers"
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository/mocks"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSavedSearchHandler() (*handlers.SavedSearchHandler, *mocks.SavedSearchRepositoryMock, *mocks.UserRepositoryMock) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	users := new(mocks.UserRepositoryMock)
+	users.On("GetUser", 5).Return(&models.User{ID: 5, Name: "Dewi"}, nil)
+	return handlers.NewSavedSearchHandler(repo, users), repo, users
+}
+
+func newFormContext(method, target, form string, names, values []string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, target, strings.NewReader(form))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	return c, rec
+}
+
+func TestCreateSavedSearch_Success(t *testing.T) {
+	h, repo, _ := newSavedSearchHandler()
+
+	repo.On("CreateSavedSearch", mock.MatchedBy(func(s *models.SavedSearch) bool {
+		return s.UserID == 5 && s.ListingType == "rent" && s.MaxPrice == 4000 &&
+			s.Area == "jakarta selatan" && s.Frequency == models.FrequencyDaily
+	})).Return(nil)
+
+	c, rec := newFormContext(http.MethodPost, "/users/5/saved-searches",
+		"name=Cheap+rent&listing_type=rent&max_price=4000&area=Jakarta+Selatan&frequency=daily",
+		[]string{"id"}, []string{"5"})
+
+	err := h.CreateSavedSearch(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateSavedSearch_DefaultsToInstant(t *testing.T) {
+	h, repo, _ := newSavedSearchHandler()
+
+	repo.On("CreateSavedSearch", mock.MatchedBy(func(s *models.SavedSearch) bool {
+		return s.Frequency == models.FrequencyInstant
+	})).Return(nil)
+
+	c, _ := newFormContext(http.MethodPost, "/users/5/saved-searches", "name=Anything", []string{"id"}, []string{"5"})
+
+	assert.NoError(t, h.CreateSavedSearch(c))
+	repo.AssertExpectations(t)
+}
+
+func TestCreateSavedSearch_Validation(t *testing.T) {
+	cases := []string{
+		"listing_type=rent",
+		"name=x&listing_type=office",
+		"name=x&min_price=5000&max_price=4000",
+		"name=x&frequency=weekly",
+		"name=x&max_price=cheap",
+	}
+
+	for _, form := range cases {
+		h, _, _ := newSavedSearchHandler()
+		c, _ := newFormContext(http.MethodPost, "/users/5/saved-searches", form, []string{"id"}, []string{"5"})
+
+		err := h.CreateSavedSearch(c)
+		assert.Error(t, err, form)
+		assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code, form)
+	}
+}
+
+func TestCreateSavedSearch_UserNotFound(t *testing.T) {
+	repo := new(mocks.SavedSearchRepositoryMock)
+	users := new(mocks.UserRepositoryMock)
+	users.On("GetUser", 99).Return(nil, errors.New("not found"))
+	h := handlers.NewSavedSearchHandler(repo, users)
+
+	c, _ := newFormContext(http.MethodPost, "/users/99/saved-searches", "name=x", []string{"id"}, []string{"99"})
+
+	err := h.CreateSavedSearch(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateSavedSearch_OtherUsersSearch(t *testing.T) {
+	h, repo, _ := newSavedSearchHandler()
+	repo.On("GetSavedSearch", int64(3)).Return(&models.SavedSearch{ID: 3, UserID: 8}, nil)
+
+	c, _ := newFormContext(http.MethodPut, "/users/5/saved-searches/3", "max_price=1", []string{"id", "search_id"}, []string{"5", "3"})
+
+	err := h.UpdateSavedSearch(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, err.(*echo.HTTPError).Code)
+}
+
+func TestUpdateSavedSearch_Success(t *testing.T) {
+	h, repo, _ := newSavedSearchHandler()
+	repo.On("GetSavedSearch", int64(3)).Return(&models.SavedSearch{ID: 3, UserID: 5, Name: "Old", MaxPrice: 4000}, nil)
+	repo.On("UpdateSavedSearch", mock.MatchedBy(func(s *models.SavedSearch) bool {
+		return s.Name == "Old" && s.MaxPrice == 0
+	})).Return(nil)
+
+	c, rec := newFormContext(http.MethodPut, "/users/5/saved-searches/3", "max_price=", []string{"id", "search_id"}, []string{"5", "3"})
+
+	assert.NoError(t, h.UpdateSavedSearch(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestDeleteSavedSearch_Success(t *testing.T) {
+	h, repo, _ := newSavedSearchHandler()
+	repo.On("GetSavedSearch", int64(3)).Return(&models.SavedSearch{ID: 3, UserID: 5}, nil)
+	repo.On("DeleteSavedSearch", int64(3)).Return(nil)
+
+	c, rec := newFormContext(http.MethodDelete, "/users/5/saved-searches/3", "", []string{"id", "search_id"}, []string{"5", "3"})
+
+	assert.NoError(t, h.DeleteSavedSearch(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetAlerts_UnreadByDefault(t *testing.T) {
+	h, repo, _ := newSavedSearchHandler()
+	repo.On("GetAlerts", int64(5), true, 50).Return([]models.Alert{{ID: 1, ListingID: 9}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/5/alerts", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+
+	assert.NoError(t, h.GetAlerts(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"listing_id":9`)
+	repo.AssertExpectations(t)
+}
+
+func TestMarkAlertsRead_Success(t *testing.T) {
+	h, repo, _ := newSavedSearchHandler()
+	repo.On("MarkAlertsRead", int64(5), []int64{1, 2}, mock.Anything).Return(nil)
+
+	c, rec := newFormContext(http.MethodPost, "/users/5/alerts/read", "ids=1,2", []string{"id"}, []string{"5"})
+
+	assert.NoError(t, h.MarkAlertsRead(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestMarkAlertsRead_InvalidID(t *testing.T) {
+	h, _, _ := newSavedSearchHandler()
+
+	c, _ := newFormContext(http.MethodPost, "/users/5/alerts/read", "ids=1,x", []string{"id"}, []string{"5"})
+
+	err := h.MarkAlertsRead(c)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+}
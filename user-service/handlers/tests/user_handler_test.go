@@ -8,6 +8,7 @@ import (
 	"real-estate-system/user-service/handlers"
 	"real-estate-system/user-service/models"
 	"real-estate-system/user-service/repository/mocks"
+	"real-estate-system/user-service/tenant"
 	"strings"
 	"testing"
 
@@ -18,7 +19,7 @@ import (
 
 func TestCreateUser_Success(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	body := strings.NewReader("name=Alice")
 	req := httptest.NewRequest(http.MethodPost, "/users", body)
@@ -39,7 +40,7 @@ func TestCreateUser_Success(t *testing.T) {
 
 func TestCreateUser_BindError(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	body := strings.NewReader("name=")
 	req := httptest.NewRequest(http.MethodPost, "/users", body)
@@ -55,7 +56,7 @@ func TestCreateUser_BindError(t *testing.T) {
 
 func TestCreateUser_RepoError(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	body := strings.NewReader("name=RepoFail")
 	req := httptest.NewRequest(http.MethodPost, "/users", body)
@@ -76,7 +77,7 @@ func TestCreateUser_RepoError(t *testing.T) {
 
 func TestGetUsers_Success(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/users?page_num=1&page_size=2", nil)
@@ -105,7 +106,7 @@ func TestGetUsers_Success(t *testing.T) {
 
 func TestGetUsers_RepoError(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/users?page_num=1&page_size=2", nil)
@@ -122,7 +123,9 @@ func TestGetUsers_RepoError(t *testing.T) {
 
 func TestGetUser_Success(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	mockProfiles := new(mocks.AgentProfileRepositoryMock)
+	mockReviews := new(mocks.ReviewRepositoryMock)
+	h := handlers.NewUserHandler(mockRepo, mockProfiles, new(mocks.AgencyRepositoryMock), mockReviews)
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
@@ -133,6 +136,8 @@ func TestGetUser_Success(t *testing.T) {
 
 	mockUser := &models.User{ID: 1, Name: "Charlie"}
 	mockRepo.On("GetUser", 1).Return(mockUser, nil)
+	mockProfiles.On("GetAgentProfile", int64(1)).Return(nil, nil)
+	mockReviews.On("GetRating", int64(1)).Return(models.RatingSummary{Average: 4.5, Count: 2}, nil)
 
 	err := h.GetUser(c)
 	assert.NoError(t, err)
@@ -143,13 +148,49 @@ func TestGetUser_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), response["user"].ID)
 	assert.Equal(t, "Charlie", response["user"].Name)
+	assert.Equal(t, &models.RatingSummary{Average: 4.5, Count: 2}, response["user"].Rating)
 
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetUser_VerifiedAgent(t *testing.T) {
+	mockRepo := new(mocks.UserRepositoryMock)
+	mockProfiles := new(mocks.AgentProfileRepositoryMock)
+	mockAgencies := new(mocks.AgencyRepositoryMock)
+	mockReviews := new(mocks.ReviewRepositoryMock)
+	h := handlers.NewUserHandler(mockRepo, mockProfiles, mockAgencies, mockReviews)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	mockRepo.On("GetUser", 1).Return(&models.User{ID: 1, Name: "Charlie"}, nil)
+	mockProfiles.On("GetAgentProfile", int64(1)).Return(&models.AgentProfile{
+		UserID: 1, LicenseNumber: "AREBI-123", VerificationStatus: models.VerificationVerified,
+	}, nil)
+	mockAgencies.On("GetMembership", int64(1)).Return(&models.AgencyMember{
+		AgencyID: 4, UserID: 1, Agency: &models.Agency{ID: 4, Name: "Rumah Kita"},
+	}, nil)
+	mockReviews.On("GetRating", int64(1)).Return(models.RatingSummary{}, nil)
+
+	assert.NoError(t, h.GetUser(c))
+
+	var response struct {
+		User models.User `json:"user"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	if assert.NotNil(t, response.User.Agent) {
+		assert.True(t, response.User.Agent.Verified)
+		assert.Equal(t, "AREBI-123", response.User.Agent.LicenseNumber)
+		assert.Equal(t, "Rumah Kita", response.User.Agent.Agency.Name)
+	}
+}
+
 func TestGetUser_InvalidID(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
@@ -165,7 +206,7 @@ func TestGetUser_InvalidID(t *testing.T) {
 
 func TestGetUser_NotFound(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/users/99", nil)
@@ -182,9 +223,28 @@ func TestGetUser_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetUser_ScopedToRequestTenant(t *testing.T) {
+	mockRepo := new(mocks.UserRepositoryMock)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
+
+	e := echo.New()
+	e.Use(tenant.Middleware())
+	e.GET("/users/:id", h.GetUser)
+
+	mockRepo.On("GetUser", 7).Return((*models.User)(nil), errors.New("record not found"))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set(tenant.Header, "acme")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "acme", mockRepo.TenantID)
+}
+
 func TestGetUsers_InvalidQueryParams(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	// Invalid params default to 1 and 10
 	mockRepo.On("GetUsers", 1, 10).Return([]models.User{}, nil)
@@ -202,7 +262,7 @@ func TestGetUsers_InvalidQueryParams(t *testing.T) {
 
 func TestGetUsers_ZeroPageParams(t *testing.T) {
 	mockRepo := new(mocks.UserRepositoryMock)
-	h := handlers.NewUserHandler(mockRepo)
+	h := handlers.NewUserHandler(mockRepo, new(mocks.AgentProfileRepositoryMock), new(mocks.AgencyRepositoryMock), new(mocks.ReviewRepositoryMock))
 
 	// 0 and negative should default to 1 and 10
 	mockRepo.On("GetUsers", 1, 10).Return([]models.User{}, nil)
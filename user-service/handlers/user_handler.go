@@ -4,17 +4,31 @@ import (
 	"net/http"
 	"real-estate-system/user-service/models"
 	repository "real-estate-system/user-service/repository/interfaces"
+	"real-estate-system/user-service/tenant"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
 )
 
 type UserHandler struct {
-	Repo repository.UserRepository
+	Repo     repository.UserRepository
+	Profiles repository.AgentProfileRepository
+	Agencies repository.AgencyRepository
+	Reviews  repository.ReviewRepository
 }
 
-func NewUserHandler(repo repository.UserRepository) *UserHandler {
-	return &UserHandler{Repo: repo}
+func NewUserHandler(repo repository.UserRepository, profiles repository.AgentProfileRepository, agencies repository.AgencyRepository,
+	reviews repository.ReviewRepository) *UserHandler {
+	return &UserHandler{Repo: repo, Profiles: profiles, Agencies: agencies, Reviews: reviews}
+}
+
+// tenantUsers narrows repo to the users of the request's tenant.
+func tenantUsers(c echo.Context, repo repository.UserRepository) repository.UserRepository {
+	return repo.ForTenant(tenant.ID(c))
+}
+
+func (h *UserHandler) users(c echo.Context) repository.UserRepository {
+	return tenantUsers(c, h.Repo)
 }
 
 func (h *UserHandler) GetUsers(c echo.Context) error {
@@ -27,7 +41,7 @@ func (h *UserHandler) GetUsers(c echo.Context) error {
 		pageSize = 10
 	}
 
-	users, err := h.Repo.GetUsers(pageNum, pageSize)
+	users, err := h.users(c).GetUsers(pageNum, pageSize)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -44,10 +58,18 @@ func (h *UserHandler) GetUser(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user ID")
 	}
 
-	user, err := h.Repo.GetUser(id)
+	user, err := h.users(c).GetUser(id)
 	if err != nil || user == nil {
 		return echo.NewHTTPError(http.StatusNotFound, "User not found")
 	}
+	if err := h.withAgent(user); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	rating, err := h.Reviews.GetRating(user.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	user.Rating = &rating
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"result": true,
@@ -65,7 +87,7 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		Name: name,
 	}
 
-	err := h.Repo.CreateUser(&user)
+	err := h.users(c).CreateUser(&user)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
 	}
@@ -75,3 +97,22 @@ func (h *UserHandler) CreateUser(c echo.Context) error {
 		"user":   user,
 	})
 }
+
+// withAgent adds the agent profile of agents, with their agency, to user.
+func (h *UserHandler) withAgent(user *models.User) error {
+	profile, err := h.Profiles.GetAgentProfile(user.ID)
+	if err != nil || profile == nil {
+		return err
+	}
+	membership, err := h.Agencies.GetMembership(user.ID)
+	if err != nil {
+		return err
+	}
+
+	var agency *models.Agency
+	if membership != nil {
+		agency = membership.Agency
+	}
+	user.Agent = profile.Summary(agency)
+	return nil
+}
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"real-estate-system/user-service/alerts"
+	"real-estate-system/user-service/events"
 	"real-estate-system/user-service/handlers"
 	"real-estate-system/user-service/models"
 	"real-estate-system/user-service/repository"
 	"real-estate-system/user-service/seeders"
+	"real-estate-system/user-service/tenant"
 
 	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -22,24 +27,77 @@ func main() {
 	}
 
 	// Auto-migrate table
-	if err := db.AutoMigrate(&models.User{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.OutboxEvent{}, &models.SavedSearch{}, &models.Alert{},
+		&models.Agency{}, &models.AgencyMember{}, &models.AgentProfile{}, &models.Review{}); err != nil {
 		log.Fatalf("failed to migrate: %v", err)
 	}
 
 	// Seed
 	seeders.SeedUsers(db)
 
+	// Publish outbox events to Redis Streams
+	rdb := redis.NewClient(&redis.Options{
+		Addr: redisAddr(),
+	})
+	relay := events.NewRelay(repository.NewGormOutboxRepository(db), events.NewRedisStreamPublisher(rdb, events.DefaultStream))
+	go relay.Run(context.Background())
+
 	e := echo.New()
+	e.Use(tenant.Middleware())
 
 	userRepo := repository.NewGormUserRepository(db)
 
-	h := handlers.NewUserHandler(userRepo)
+	profileRepo := repository.NewGormAgentProfileRepository(db)
+	agencyRepo := repository.NewGormAgencyRepository(db)
+	reviewRepo := repository.NewGormReviewRepository(db)
+
+	h := handlers.NewUserHandler(userRepo, profileRepo, agencyRepo, reviewRepo)
+	aph := handlers.NewAgentProfileHandler(profileRepo, userRepo)
+	ah := handlers.NewAgencyHandler(agencyRepo, profileRepo, userRepo)
+	rh := handlers.NewReviewHandler(reviewRepo, userRepo)
+
+	savedSearchRepo := repository.NewGormSavedSearchRepository(db)
+	ssh := handlers.NewSavedSearchHandler(savedSearchRepo, userRepo)
+
+	// Match listing events against saved searches
+	go alerts.NewMatcher(savedSearchRepo).Run(context.Background(), rdb, hostname())
 
 	// Routes
 	e.GET("/users", h.GetUsers)
 	e.GET("/users/:id", h.GetUser)
 	e.POST("/users", h.CreateUser)
 
+	e.POST("/users/:id/saved-searches", ssh.CreateSavedSearch)
+	e.GET("/users/:id/saved-searches", ssh.GetSavedSearches)
+	e.PUT("/users/:id/saved-searches/:search_id", ssh.UpdateSavedSearch)
+	e.DELETE("/users/:id/saved-searches/:search_id", ssh.DeleteSavedSearch)
+	e.GET("/users/:id/alerts", ssh.GetAlerts)
+	e.POST("/users/:id/alerts/read", ssh.MarkAlertsRead)
+
+	// Agent profiles and license verification
+	e.GET("/users/:id/agent-profile", aph.GetAgentProfile)
+	e.PUT("/users/:id/agent-profile", aph.SaveAgentProfile)
+	e.GET("/agent-profiles", aph.GetVerificationQueue)
+	e.POST("/agent-profiles/:user_id/approve", aph.ApproveAgentProfile)
+	e.POST("/agent-profiles/:user_id/reject", aph.RejectAgentProfile)
+
+	// Agencies and their members
+	e.POST("/agencies", ah.CreateAgency)
+	e.GET("/agencies/:id", ah.GetAgency)
+	e.POST("/agencies/:id/members", ah.InviteMember)
+	e.DELETE("/agencies/:id/members/:member_id", ah.RemoveMember)
+	e.GET("/users/:id/agency", ah.GetMembership)
+	e.POST("/users/:id/agency-invitations/:agency_id/accept", ah.AcceptInvitation)
+	e.GET("/users/:id/managed-agents", ah.GetManagedAgents)
+
+	// Reviews of agents and landlords
+	e.POST("/users/:id/reviews", rh.CreateReview)
+	e.GET("/users/:id/reviews", rh.GetReviews)
+	e.POST("/users/:id/reviews/:review_id/reply", rh.ReplyToReview)
+	e.GET("/reviews", rh.GetModerationQueue)
+	e.POST("/reviews/:review_id/hide", rh.HideReview)
+	e.POST("/reviews/:review_id/restore", rh.RestoreReview)
+
 	fmt.Println("User service running on :6001")
 	e.Logger.Fatal(e.Start(":6001"))
 }
@@ -56,3 +114,23 @@ func buildDSN() string {
 		os.Getenv("DB_TIMEZONE"),
 	)
 }
+
+func redisAddr() string {
+	host := os.Getenv("REDIS_HOST")
+	port := os.Getenv("REDIS_PORT")
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" {
+		port = "6379"
+	}
+	return host + ":" + port
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "user-service"
+	}
+	return name
+}
@@ -0,0 +1,42 @@
+package models
+
+import "errors"
+
+const (
+	AgencyRoleAdmin = "admin"
+	AgencyRoleAgent = "agent"
+
+	MemberInvited = "invited"
+	MemberActive  = "active"
+)
+
+var (
+	ErrAlreadyInAgency = errors.New("user already belongs to an agency")
+	ErrAlreadyInvited  = errors.New("user is already a member of or invited to this agency")
+	ErrNotInvited      = errors.New("no open invitation to this agency")
+	ErrLastAgencyAdmin = errors.New("an agency with agents needs another admin before its last admin leaves")
+)
+
+type Agency struct {
+	ID        int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string         `json:"name"`
+	CreatedBy int64          `json:"created_by"`
+	CreatedAt int64          `json:"created_at"`
+	UpdatedAt int64          `json:"updated_at"`
+	Members   []AgencyMember `gorm:"foreignKey:AgencyID" json:"members,omitempty"`
+}
+
+// AgencyMember is an invitation until the user accepts it. A user is an
+// active member of at most one agency.
+type AgencyMember struct {
+	ID        int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgencyID  int64   `gorm:"uniqueIndex:idx_agency_member" json:"agency_id"`
+	UserID    int64   `gorm:"uniqueIndex:idx_agency_member;index" json:"user_id"`
+	Role      string  `json:"role"`   // admin or agent
+	Status    string  `json:"status"` // invited or active
+	InvitedBy int64   `json:"invited_by"`
+	CreatedAt int64   `json:"created_at"`
+	JoinedAt  int64   `json:"joined_at"`
+	Agency    *Agency `gorm:"foreignKey:AgencyID" json:"agency,omitempty"`
+	User      *User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
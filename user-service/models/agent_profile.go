@@ -0,0 +1,58 @@
+package models
+
+import "errors"
+
+const (
+	VerificationPending  = "pending"
+	VerificationVerified = "verified"
+	VerificationRejected = "rejected"
+)
+
+var ErrVerificationClosed = errors.New("license details are not waiting for verification")
+
+// AgentProfile holds an agent's license details. Changing the license number
+// sends it back for verification.
+type AgentProfile struct {
+	UserID             int64    `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	LicenseNumber      string   `json:"license_number"`
+	ServiceAreas       []string `gorm:"type:jsonb;serializer:json" json:"service_areas"`
+	Languages          []string `gorm:"type:jsonb;serializer:json" json:"languages"`
+	Bio                string   `gorm:"type:text" json:"bio"`
+	VerificationStatus string   `gorm:"index" json:"verification_status"`
+	VerificationNote   string   `json:"verification_note,omitempty"`
+	SubmittedAt        int64    `json:"submitted_at"`
+	ReviewedBy         int      `json:"reviewed_by,omitempty"`
+	ReviewedAt         int64    `json:"reviewed_at,omitempty"`
+	CreatedAt          int64    `json:"created_at"`
+	UpdatedAt          int64    `json:"updated_at"`
+}
+
+func (p *AgentProfile) Verified() bool {
+	return p.VerificationStatus == VerificationVerified
+}
+
+// AgentSummary is what everyone sees of an agent on their user.
+type AgentSummary struct {
+	Verified      bool     `json:"verified"`
+	LicenseNumber string   `json:"license_number,omitempty"` // once verified
+	ServiceAreas  []string `json:"service_areas"`
+	Languages     []string `json:"languages"`
+	Bio           string   `json:"bio"`
+	Agency        *Agency  `json:"agency,omitempty"`
+}
+
+// Summary is the profile as shown on the user, with the agency they work
+// for if any.
+func (p *AgentProfile) Summary(agency *Agency) *AgentSummary {
+	summary := &AgentSummary{
+		Verified:     p.Verified(),
+		ServiceAreas: p.ServiceAreas,
+		Languages:    p.Languages,
+		Bio:          p.Bio,
+		Agency:       agency,
+	}
+	if summary.Verified {
+		summary.LicenseNumber = p.LicenseNumber
+	}
+	return summary
+}
@@ -0,0 +1,15 @@
+package models
+
+// OutboxEvent is a domain event written in the same transaction as the
+// mutation that produced it. The relay publishes pending rows in ID order.
+type OutboxEvent struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	AggregateType string `gorm:"index:idx_outbox_aggregate" json:"aggregate_type"`
+	AggregateID   int64  `gorm:"index:idx_outbox_aggregate" json:"aggregate_id"`
+	EventType     string `json:"event_type"`
+	Payload       string `gorm:"type:jsonb" json:"payload"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error"`
+	CreatedAt     int64  `json:"created_at"`
+	PublishedAt   int64  `gorm:"index" json:"published_at"` // 0 while pending
+}
@@ -0,0 +1,63 @@
+package models
+
+import "errors"
+
+const (
+	ReviewPublished = "published"
+	ReviewHidden    = "hidden"
+
+	// Interactions that make a buyer or tenant eligible to review the agent
+	// or landlord they dealt with.
+	InteractionInquiry = "inquiry"
+	InteractionViewing = "viewing"
+	InteractionLease   = "lease"
+)
+
+var (
+	ErrReviewExists     = errors.New("you have already reviewed this user")
+	ErrReviewModerated  = errors.New("the review already has this status")
+	ErrNotReviewee      = errors.New("only the reviewed user can reply")
+	ErrReviewNotVisible = errors.New("hidden reviews cannot be replied to")
+)
+
+// Review is a buyer's or tenant's rating of an agent or landlord, backed by
+// the interaction that made them eligible. A reviewer reviews a user once.
+// Hidden reviews are kept for moderators but are not shown or counted.
+type Review struct {
+	ID              int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID        string `gorm:"not null;default:default" json:"tenant_id"`
+	ReviewerID      int64  `gorm:"uniqueIndex:idx_review_pair" json:"reviewer_id"`
+	RevieweeID      int64  `gorm:"uniqueIndex:idx_review_pair;index:idx_review_reviewee_status" json:"reviewee_id"`
+	Rating          int    `json:"rating"` // 1 to 5 stars
+	Text            string `gorm:"type:text" json:"text"`
+	InteractionType string `json:"interaction_type"`
+	InteractionID   int64  `json:"interaction_id"`
+	Status          string `gorm:"index:idx_review_reviewee_status" json:"status"`
+	Reply           string `gorm:"type:text" json:"reply,omitempty"`
+	RepliedAt       int64  `json:"replied_at,omitempty"`
+	ModerationNote  string `json:"moderation_note,omitempty"`
+	ModeratedBy     int    `json:"moderated_by,omitempty"`
+	ModeratedAt     int64  `json:"moderated_at,omitempty"`
+	CreatedAt       int64  `json:"created_at"`
+	UpdatedAt       int64  `json:"updated_at"`
+}
+
+type ReviewFilter struct {
+	RevieweeID int64
+	Status     string // all statuses when empty
+}
+
+// RatingSummary is the mean rating and count of a user's published
+// reviews. Average is 0 without reviews.
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+func ValidInteractionType(kind string) bool {
+	switch kind {
+	case InteractionInquiry, InteractionViewing, InteractionLease:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package models
+
+const (
+	FrequencyInstant = "instant"
+	FrequencyDaily   = "daily"
+)
+
+// SavedSearch stores empty strings and zero prices for "any", so matching
+// can use IN (value, '') lookups on the (listing_type, area) index. Prices
+// are in whole IDR, per month for rents.
+type SavedSearch struct {
+	ID          int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      int64  `gorm:"index" json:"user_id"`
+	Name        string `json:"name"`
+	ListingType string `gorm:"index:idx_saved_search_match" json:"listing_type"`
+	Area        string `gorm:"index:idx_saved_search_match" json:"area"` // lower-cased city or district
+	MinPrice    int    `json:"min_price"`
+	MaxPrice    int    `json:"max_price"`
+	Frequency   string `json:"frequency"` // instant or daily
+	CreatedAt   int64  `json:"created_at"`
+	UpdatedAt   int64  `json:"updated_at"`
+}
+
+// Alert records that a listing event matched a saved search.
+type Alert struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID      string `gorm:"not null;default:default" json:"tenant_id"`
+	UserID        int64  `gorm:"index" json:"user_id"`
+	SavedSearchID int64  `gorm:"uniqueIndex:idx_alert_event" json:"saved_search_id"`
+	EventID       string `gorm:"uniqueIndex:idx_alert_event" json:"event_id"`
+	EventType     string `json:"event_type"`
+	ListingID     int    `json:"listing_id"`
+	Listing       string `gorm:"type:jsonb" json:"listing"`
+	Frequency     string `json:"frequency"`
+	ReadAt        int64  `json:"read_at"`
+	NotifiedAt    int64  `json:"notified_at"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+// ListingSnapshot is the part of a listing event used for matching. Events
+// from before tenants existed have no TenantID and belong to the default
+// tenant. Only public listings, by Status, are matched. MonthlyPrice is the monthly_price the listing-service adds to the
+// stream entry, zero when it had no exchange rate.
+type ListingSnapshot struct {
+	ID           int    `json:"id"`
+	TenantID     string `json:"tenant_id"`
+	Status       string `json:"status"`
+	Price        int    `json:"price"`
+	Currency     string `json:"currency"`
+	RentPeriod   string `json:"rent_period"`
+	ListingType  string `json:"listing_type"`
+	City         string `json:"city"`
+	District     string `json:"district"`
+	MonthlyPrice int    `json:"-"`
+}
+
+// nonPublicStatuses are the listing statuses only their owner and
+// administrators may see, as in listing-service.
+var nonPublicStatuses = []string{"pending_review", "rejected", "hidden"}
+
+// IsPublic reports whether anyone may see the listing. Events from before
+// moderation have no Status and are public.
+func (l ListingSnapshot) IsPublic() bool {
+	for _, status := range nonPublicStatuses {
+		if l.Status == status {
+			return false
+		}
+	}
+	return true
+}
+
+// ComparablePrice is the listing's price in the unit of saved search
+// bounds: whole IDR, per month for rents. Without MonthlyPrice it is only
+// known for listings priced that way, as all were before currencies.
+func (l ListingSnapshot) ComparablePrice() (int, bool) {
+	if l.MonthlyPrice > 0 {
+		return l.MonthlyPrice, true
+	}
+	if (l.Currency == "" || l.Currency == "IDR") && l.RentPeriod != "year" {
+		return l.Price, true
+	}
+	return 0, false
+}
@@ -1,8 +1,12 @@
 package models
 
+// User belongs to one tenant and is only visible to it.
 type User struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name" form:"name"`
-	CreatedAt int64  `json:"created_at"`
-	UpdatedAt int64  `json:"updated_at"`
+	ID        int64          `json:"id"`
+	TenantID  string         `gorm:"index;not null;default:default" json:"tenant_id"`
+	Name      string         `json:"name" form:"name"`
+	CreatedAt int64          `json:"created_at"`
+	UpdatedAt int64          `json:"updated_at"`
+	Agent     *AgentSummary  `gorm:"-" json:"agent,omitempty"`
+	Rating    *RatingSummary `gorm:"-" json:"rating,omitempty"`
 }
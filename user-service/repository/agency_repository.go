@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/user-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormAgencyRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormAgencyRepository(db *gorm.DB) *GormAgencyRepository {
+	return &GormAgencyRepository{DB: db}
+}
+
+// CreateAgency creates the agency with adminID as its first, active admin.
+func (r *GormAgencyRepository) CreateAgency(agency *models.Agency, adminID int64) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, adminID); err != nil {
+			return err
+		}
+		if err := ensureNotInAgency(tx, adminID); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Members").Create(agency).Error; err != nil {
+			return err
+		}
+		admin := models.AgencyMember{
+			AgencyID:  agency.ID,
+			UserID:    adminID,
+			Role:      models.AgencyRoleAdmin,
+			Status:    models.MemberActive,
+			InvitedBy: adminID,
+			CreatedAt: agency.CreatedAt,
+			JoinedAt:  agency.CreatedAt,
+		}
+		if err := tx.Create(&admin).Error; err != nil {
+			return err
+		}
+		agency.Members = []models.AgencyMember{admin}
+		return nil
+	})
+}
+
+// GetAgency loads the agency with its active members and their users.
+func (r *GormAgencyRepository) GetAgency(id int64) (*models.Agency, error) {
+	var agency models.Agency
+	err := r.DB.Preload("Members", "status = ?", models.MemberActive, func(db *gorm.DB) *gorm.DB {
+		return db.Order("joined_at, id")
+	}).Preload("Members.User").First(&agency, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &agency, nil
+}
+
+func (r *GormAgencyRepository) GetMember(agencyID, userID int64) (*models.AgencyMember, error) {
+	var member models.AgencyMember
+	err := r.DB.Where("agency_id = ? AND user_id = ?", agencyID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// GetMembership returns the user's active membership with its agency, or
+// nil if the user is in no agency.
+func (r *GormAgencyRepository) GetMembership(userID int64) (*models.AgencyMember, error) {
+	var member models.AgencyMember
+	err := r.DB.Preload("Agency").Where("user_id = ? AND status = ?", userID, models.MemberActive).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *GormAgencyRepository) GetInvitations(userID int64) ([]models.AgencyMember, error) {
+	invitations := []models.AgencyMember{}
+	err := r.DB.Preload("Agency").Where("user_id = ? AND status = ?", userID, models.MemberInvited).
+		Order("created_at desc").Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *GormAgencyRepository) InviteMember(member *models.AgencyMember) error {
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(member)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrAlreadyInvited
+	}
+	return nil
+}
+
+// AcceptInvitation makes the user an active member of the agency, unless
+// they already belong to one.
+func (r *GormAgencyRepository) AcceptInvitation(agencyID, userID int64) (*models.AgencyMember, error) {
+	var member models.AgencyMember
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+		err := tx.Where("agency_id = ? AND user_id = ? AND status = ?", agencyID, userID, models.MemberInvited).
+			First(&member).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrNotInvited
+		}
+		if err != nil {
+			return err
+		}
+		if err := ensureNotInAgency(tx, userID); err != nil {
+			return err
+		}
+
+		member.Status = models.MemberActive
+		member.JoinedAt = time.Now().UnixMicro()
+		return tx.Model(&member).Updates(map[string]interface{}{
+			"status":    member.Status,
+			"joined_at": member.JoinedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// RemoveMember removes a member or withdraws an invitation. The last admin
+// can only leave an agency without other members.
+func (r *GormAgencyRepository) RemoveMember(member *models.AgencyMember) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var agency models.Agency
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&agency, member.AgencyID).Error; err != nil {
+			return err
+		}
+
+		if member.Role == models.AgencyRoleAdmin && member.Status == models.MemberActive {
+			var admins, members int64
+			active := tx.Model(&models.AgencyMember{}).Where("agency_id = ? AND status = ?", member.AgencyID, models.MemberActive)
+			if err := active.Session(&gorm.Session{}).Where("role = ?", models.AgencyRoleAdmin).Count(&admins).Error; err != nil {
+				return err
+			}
+			if err := active.Session(&gorm.Session{}).Count(&members).Error; err != nil {
+				return err
+			}
+			if admins == 1 && members > 1 {
+				return models.ErrLastAgencyAdmin
+			}
+		}
+
+		return tx.Delete(member).Error
+	})
+}
+
+// GetManagedAgents returns the active members of the agency adminID is an
+// active admin of, adminID included, or none.
+func (r *GormAgencyRepository) GetManagedAgents(adminID int64) ([]int64, error) {
+	ids := []int64{}
+	agency := r.DB.Model(&models.AgencyMember{}).Select("agency_id").
+		Where("user_id = ? AND role = ? AND status = ?", adminID, models.AgencyRoleAdmin, models.MemberActive)
+	err := r.DB.Model(&models.AgencyMember{}).
+		Where("agency_id IN (?) AND status = ?", agency, models.MemberActive).
+		Order("user_id").Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// lockUser serializes membership changes of a user.
+func lockUser(tx *gorm.DB, userID int64) error {
+	var user models.User
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error
+}
+
+func ensureNotInAgency(tx *gorm.DB, userID int64) error {
+	var count int64
+	err := tx.Model(&models.AgencyMember{}).Where("user_id = ? AND status = ?", userID, models.MemberActive).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrAlreadyInAgency
+	}
+	return nil
+}
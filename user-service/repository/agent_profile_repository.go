@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"real-estate-system/user-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormAgentProfileRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormAgentProfileRepository(db *gorm.DB) *GormAgentProfileRepository {
+	return &GormAgentProfileRepository{DB: db}
+}
+
+// GetAgentProfile returns the user's agent profile, or nil if they have none.
+func (r *GormAgentProfileRepository) GetAgentProfile(userID int64) (*models.AgentProfile, error) {
+	var profile models.AgentProfile
+	err := r.DB.Where("user_id = ?", userID).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *GormAgentProfileRepository) GetAgentProfiles(userIDs []int64) ([]models.AgentProfile, error) {
+	profiles := []models.AgentProfile{}
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+	err := r.DB.Where("user_id IN ?", userIDs).Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *GormAgentProfileRepository) SaveAgentProfile(profile *models.AgentProfile) error {
+	return r.DB.Save(profile).Error
+}
+
+// GetVerificationQueue lists profiles by status, or all of them for an empty
+// status, longest waiting first.
+func (r *GormAgentProfileRepository) GetVerificationQueue(status string, page, size int) ([]models.AgentProfile, error) {
+	profiles := []models.AgentProfile{}
+	db := r.DB
+	if status != "" {
+		db = db.Where("verification_status = ?", status)
+	}
+	err := db.Order("submitted_at, user_id").Offset((page - 1) * size).Limit(size).Find(&profiles).Error
+	return profiles, err
+}
+
+// ReviewAgentProfile verifies or rejects license details waiting for
+// verification.
+func (r *GormAgentProfileRepository) ReviewAgentProfile(userID int64, status string, adminID int, note string) (*models.AgentProfile, error) {
+	var profile models.AgentProfile
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&profile).Error
+		if err != nil {
+			return err
+		}
+		if profile.VerificationStatus != models.VerificationPending {
+			return models.ErrVerificationClosed
+		}
+
+		now := time.Now().UnixMicro()
+		profile.VerificationStatus = status
+		profile.VerificationNote = note
+		profile.ReviewedBy = adminID
+		profile.ReviewedAt = now
+		profile.UpdatedAt = now
+		return tx.Model(&profile).Updates(map[string]interface{}{
+			"verification_status": profile.VerificationStatus,
+			"verification_note":   profile.VerificationNote,
+			"reviewed_by":         profile.ReviewedBy,
+			"reviewed_at":         profile.ReviewedAt,
+			"updated_at":          profile.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
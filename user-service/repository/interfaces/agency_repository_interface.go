@@ -0,0 +1,15 @@
+package repository
+
+import "real-estate-system/user-service/models"
+
+type AgencyRepository interface {
+	CreateAgency(agency *models.Agency, adminID int64) error
+	GetAgency(id int64) (*models.Agency, error)
+	GetMember(agencyID, userID int64) (*models.AgencyMember, error)
+	GetMembership(userID int64) (*models.AgencyMember, error)
+	GetInvitations(userID int64) ([]models.AgencyMember, error)
+	InviteMember(member *models.AgencyMember) error
+	AcceptInvitation(agencyID, userID int64) (*models.AgencyMember, error)
+	RemoveMember(member *models.AgencyMember) error
+	GetManagedAgents(adminID int64) ([]int64, error)
+}
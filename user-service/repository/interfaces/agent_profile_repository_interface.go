@@ -0,0 +1,11 @@
+package repository
+
+import "real-estate-system/user-service/models"
+
+type AgentProfileRepository interface {
+	GetAgentProfile(userID int64) (*models.AgentProfile, error)
+	GetAgentProfiles(userIDs []int64) ([]models.AgentProfile, error)
+	SaveAgentProfile(profile *models.AgentProfile) error
+	GetVerificationQueue(status string, page, size int) ([]models.AgentProfile, error)
+	ReviewAgentProfile(userID int64, status string, adminID int, note string) (*models.AgentProfile, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "real-estate-system/user-service/models"
+
+type ReviewRepository interface {
+	CreateReview(review *models.Review) error
+	GetReview(id int64) (*models.Review, error)
+	GetReviews(filter models.ReviewFilter, page, size int) ([]models.Review, error)
+	GetRating(userID int64) (models.RatingSummary, error)
+	ReplyToReview(id, revieweeID int64, reply string) (*models.Review, error)
+	ModerateReview(id int64, status string, adminID int, note string) (*models.Review, error)
+}
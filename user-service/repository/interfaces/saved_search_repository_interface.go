@@ -0,0 +1,17 @@
+package repository
+
+import "real-estate-system/user-service/models"
+
+type SavedSearchRepository interface {
+	CreateSavedSearch(search *models.SavedSearch) error
+	GetSavedSearch(id int64) (*models.SavedSearch, error)
+	GetSavedSearches(userID int64) ([]models.SavedSearch, error)
+	UpdateSavedSearch(search *models.SavedSearch) error
+	DeleteSavedSearch(id int64) error
+	FindMatching(listing models.ListingSnapshot) ([]models.SavedSearch, error)
+
+	CreateAlerts(alerts []models.Alert) (int, error)
+	GetAlerts(userID int64, unreadOnly bool, limit int) ([]models.Alert, error)
+	MarkAlertsRead(userID int64, ids []int64, readAt int64) error
+	SendDailyDigests(now int64) (int, error)
+}
@@ -2,7 +2,10 @@ package repository
 
 import "real-estate-system/user-service/models"
 
+// UserRepository only sees the users of one tenant; ForTenant returns the
+// repository of another.
 type UserRepository interface {
+	ForTenant(tenantID string) UserRepository
 	CreateUser(user *models.User) error
 	GetUsers(page, size int) ([]models.User, error)
 	GetUser(id int) (*models.User, error)
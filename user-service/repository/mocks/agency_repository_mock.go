@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"real-estate-system/user-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AgencyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AgencyRepositoryMock) CreateAgency(agency *models.Agency, adminID int64) error {
+	args := m.Called(agency, adminID)
+	return args.Error(0)
+}
+
+func (m *AgencyRepositoryMock) GetAgency(id int64) (*models.Agency, error) {
+	args := m.Called(id)
+	var agency *models.Agency
+	if args.Get(0) != nil {
+		agency = args.Get(0).(*models.Agency)
+	}
+	return agency, args.Error(1)
+}
+
+func (m *AgencyRepositoryMock) GetMember(agencyID, userID int64) (*models.AgencyMember, error) {
+	args := m.Called(agencyID, userID)
+	var member *models.AgencyMember
+	if args.Get(0) != nil {
+		member = args.Get(0).(*models.AgencyMember)
+	}
+	return member, args.Error(1)
+}
+
+func (m *AgencyRepositoryMock) GetMembership(userID int64) (*models.AgencyMember, error) {
+	args := m.Called(userID)
+	var member *models.AgencyMember
+	if args.Get(0) != nil {
+		member = args.Get(0).(*models.AgencyMember)
+	}
+	return member, args.Error(1)
+}
+
+func (m *AgencyRepositoryMock) GetInvitations(userID int64) ([]models.AgencyMember, error) {
+	args := m.Called(userID)
+	var invitations []models.AgencyMember
+	if args.Get(0) != nil {
+		invitations = args.Get(0).([]models.AgencyMember)
+	}
+	return invitations, args.Error(1)
+}
+
+func (m *AgencyRepositoryMock) InviteMember(member *models.AgencyMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *AgencyRepositoryMock) AcceptInvitation(agencyID, userID int64) (*models.AgencyMember, error) {
+	args := m.Called(agencyID, userID)
+	var member *models.AgencyMember
+	if args.Get(0) != nil {
+		member = args.Get(0).(*models.AgencyMember)
+	}
+	return member, args.Error(1)
+}
+
+func (m *AgencyRepositoryMock) RemoveMember(member *models.AgencyMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *AgencyRepositoryMock) GetManagedAgents(adminID int64) ([]int64, error) {
+	args := m.Called(adminID)
+	var ids []int64
+	if args.Get(0) != nil {
+		ids = args.Get(0).([]int64)
+	}
+	return ids, args.Error(1)
+}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"real-estate-system/user-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AgentProfileRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *AgentProfileRepositoryMock) GetAgentProfile(userID int64) (*models.AgentProfile, error) {
+	args := m.Called(userID)
+	var profile *models.AgentProfile
+	if args.Get(0) != nil {
+		profile = args.Get(0).(*models.AgentProfile)
+	}
+	return profile, args.Error(1)
+}
+
+func (m *AgentProfileRepositoryMock) GetAgentProfiles(userIDs []int64) ([]models.AgentProfile, error) {
+	args := m.Called(userIDs)
+	var profiles []models.AgentProfile
+	if args.Get(0) != nil {
+		profiles = args.Get(0).([]models.AgentProfile)
+	}
+	return profiles, args.Error(1)
+}
+
+func (m *AgentProfileRepositoryMock) SaveAgentProfile(profile *models.AgentProfile) error {
+	args := m.Called(profile)
+	return args.Error(0)
+}
+
+func (m *AgentProfileRepositoryMock) GetVerificationQueue(status string, page, size int) ([]models.AgentProfile, error) {
+	args := m.Called(status, page, size)
+	var profiles []models.AgentProfile
+	if args.Get(0) != nil {
+		profiles = args.Get(0).([]models.AgentProfile)
+	}
+	return profiles, args.Error(1)
+}
+
+func (m *AgentProfileRepositoryMock) ReviewAgentProfile(userID int64, status string, adminID int, note string) (*models.AgentProfile, error) {
+	args := m.Called(userID, status, adminID, note)
+	var profile *models.AgentProfile
+	if args.Get(0) != nil {
+		profile = args.Get(0).(*models.AgentProfile)
+	}
+	return profile, args.Error(1)
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"real-estate-system/user-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReviewRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReviewRepositoryMock) CreateReview(review *models.Review) error {
+	args := m.Called(review)
+	return args.Error(0)
+}
+
+func (m *ReviewRepositoryMock) GetReview(id int64) (*models.Review, error) {
+	args := m.Called(id)
+	var review *models.Review
+	if args.Get(0) != nil {
+		review = args.Get(0).(*models.Review)
+	}
+	return review, args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) GetReviews(filter models.ReviewFilter, page, size int) ([]models.Review, error) {
+	args := m.Called(filter, page, size)
+	var reviews []models.Review
+	if args.Get(0) != nil {
+		reviews = args.Get(0).([]models.Review)
+	}
+	return reviews, args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) GetRating(userID int64) (models.RatingSummary, error) {
+	args := m.Called(userID)
+	return args.Get(0).(models.RatingSummary), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) ReplyToReview(id, revieweeID int64, reply string) (*models.Review, error) {
+	args := m.Called(id, revieweeID, reply)
+	var review *models.Review
+	if args.Get(0) != nil {
+		review = args.Get(0).(*models.Review)
+	}
+	return review, args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) ModerateReview(id int64, status string, adminID int, note string) (*models.Review, error) {
+	args := m.Called(id, status, adminID, note)
+	var review *models.Review
+	if args.Get(0) != nil {
+		review = args.Get(0).(*models.Review)
+	}
+	return review, args.Error(1)
+}
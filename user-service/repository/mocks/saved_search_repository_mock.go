@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"real-estate-system/user-service/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SavedSearchRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SavedSearchRepositoryMock) CreateSavedSearch(search *models.SavedSearch) error {
+	args := m.Called(search)
+	return args.Error(0)
+}
+
+func (m *SavedSearchRepositoryMock) GetSavedSearch(id int64) (*models.SavedSearch, error) {
+	args := m.Called(id)
+	var search *models.SavedSearch
+	if args.Get(0) != nil {
+		search = args.Get(0).(*models.SavedSearch)
+	}
+	return search, args.Error(1)
+}
+
+func (m *SavedSearchRepositoryMock) GetSavedSearches(userID int64) ([]models.SavedSearch, error) {
+	args := m.Called(userID)
+	var searches []models.SavedSearch
+	if args.Get(0) != nil {
+		searches = args.Get(0).([]models.SavedSearch)
+	}
+	return searches, args.Error(1)
+}
+
+func (m *SavedSearchRepositoryMock) UpdateSavedSearch(search *models.SavedSearch) error {
+	args := m.Called(search)
+	return args.Error(0)
+}
+
+func (m *SavedSearchRepositoryMock) DeleteSavedSearch(id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *SavedSearchRepositoryMock) FindMatching(listing models.ListingSnapshot) ([]models.SavedSearch, error) {
+	args := m.Called(listing)
+	var searches []models.SavedSearch
+	if args.Get(0) != nil {
+		searches = args.Get(0).([]models.SavedSearch)
+	}
+	return searches, args.Error(1)
+}
+
+func (m *SavedSearchRepositoryMock) CreateAlerts(alerts []models.Alert) (int, error) {
+	args := m.Called(alerts)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *SavedSearchRepositoryMock) GetAlerts(userID int64, unreadOnly bool, limit int) ([]models.Alert, error) {
+	args := m.Called(userID, unreadOnly, limit)
+	var alerts []models.Alert
+	if args.Get(0) != nil {
+		alerts = args.Get(0).([]models.Alert)
+	}
+	return alerts, args.Error(1)
+}
+
+func (m *SavedSearchRepositoryMock) MarkAlertsRead(userID int64, ids []int64, readAt int64) error {
+	args := m.Called(userID, ids, readAt)
+	return args.Error(0)
+}
+
+func (m *SavedSearchRepositoryMock) SendDailyDigests(now int64) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
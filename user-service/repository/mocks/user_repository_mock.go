@@ -2,12 +2,21 @@ package mocks
 
 import (
 	"real-estate-system/user-service/models"
+	repository "real-estate-system/user-service/repository/interfaces"
 
 	"github.com/stretchr/testify/mock"
 )
 
+// UserRepositoryMock records the tenant last passed to ForTenant and serves
+// every tenant itself.
 type UserRepositoryMock struct {
 	mock.Mock
+	TenantID string
+}
+
+func (m *UserRepositoryMock) ForTenant(tenantID string) repository.UserRepository {
+	m.TenantID = tenantID
+	return m
 }
 
 func (m *UserRepositoryMock) CreateUser(user *models.User) error {
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"real-estate-system/user-service/events"
+	"real-estate-system/user-service/models"
+
+	"gorm.io/gorm"
+)
+
+type GormOutboxRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormOutboxRepository(db *gorm.DB) *GormOutboxRepository {
+	return &GormOutboxRepository{DB: db}
+}
+
+func (r *GormOutboxRepository) FetchPending(limit int) ([]models.OutboxEvent, error) {
+	var pending []models.OutboxEvent
+	err := r.DB.Where("published_at = ?", 0).Order("id asc").Limit(limit).Find(&pending).Error
+	return pending, err
+}
+
+func (r *GormOutboxRepository) MarkPublished(id int64, publishedAt int64) error {
+	return r.DB.Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Update("published_at", publishedAt).Error
+}
+
+func (r *GormOutboxRepository) MarkFailed(id int64, reason string) error {
+	return r.DB.Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}).Error
+}
+
+// writeOutbox records an event on tx so it commits or rolls back together
+// with the mutation it describes.
+func writeOutbox(tx *gorm.DB, eventType string, aggregateID int64, payload interface{}) error {
+	return writeOutboxFor(tx, eventType, events.AggregateUser, aggregateID, payload)
+}
+
+func writeOutboxFor(tx *gorm.DB, eventType, aggregateType string, aggregateID int64, payload interface{}) error {
+	event, err := events.NewOutboxEvent(eventType, aggregateType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
+}
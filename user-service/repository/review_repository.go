@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"real-estate-system/user-service/events"
+	"real-estate-system/user-service/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormReviewRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormReviewRepository(db *gorm.DB) *GormReviewRepository {
+	return &GormReviewRepository{DB: db}
+}
+
+// CreateReview publishes the review, unless the reviewer already reviewed
+// the user.
+func (r *GormReviewRepository) CreateReview(review *models.Review) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrReviewExists
+		}
+		return writeOutbox(tx, events.ReviewCreated, review.RevieweeID, review)
+	})
+}
+
+// GetReview returns the review, or nil if there is none.
+func (r *GormReviewRepository) GetReview(id int64) (*models.Review, error) {
+	var review models.Review
+	err := r.DB.First(&review, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *GormReviewRepository) GetReviews(filter models.ReviewFilter, page, size int) ([]models.Review, error) {
+	reviews := []models.Review{}
+	db := r.DB
+	if filter.RevieweeID > 0 {
+		db = db.Where("reviewee_id = ?", filter.RevieweeID)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	err := db.Order("created_at desc, id desc").Offset((page - 1) * size).Limit(size).Find(&reviews).Error
+	return reviews, err
+}
+
+// GetRating sums up the user's published reviews, with the average rounded
+// to one decimal.
+func (r *GormReviewRepository) GetRating(userID int64) (models.RatingSummary, error) {
+	var row struct {
+		Average float64
+		Count   int
+	}
+	err := r.DB.Model(&models.Review{}).Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("reviewee_id = ? AND status = ?", userID, models.ReviewPublished).Scan(&row).Error
+	if err != nil {
+		return models.RatingSummary{}, err
+	}
+	return models.RatingSummary{Average: math.Round(row.Average*10) / 10, Count: row.Count}, nil
+}
+
+// ReplyToReview sets or replaces the reviewed user's public reply.
+func (r *GormReviewRepository) ReplyToReview(id, revieweeID int64, reply string) (*models.Review, error) {
+	var review models.Review
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, id).Error; err != nil {
+			return err
+		}
+		if review.RevieweeID != revieweeID {
+			return models.ErrNotReviewee
+		}
+		if review.Status != models.ReviewPublished {
+			return models.ErrReviewNotVisible
+		}
+
+		now := time.Now().UnixMicro()
+		review.Reply = reply
+		review.RepliedAt = now
+		review.UpdatedAt = now
+		err := tx.Model(&review).Updates(map[string]interface{}{
+			"reply":      review.Reply,
+			"replied_at": review.RepliedAt,
+			"updated_at": review.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return writeOutbox(tx, events.ReviewReplied, review.RevieweeID, &review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ModerateReview hides a review or publishes a hidden one again.
+func (r *GormReviewRepository) ModerateReview(id int64, status string, adminID int, note string) (*models.Review, error) {
+	var review models.Review
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&review, id).Error; err != nil {
+			return err
+		}
+		if review.Status == status {
+			return models.ErrReviewModerated
+		}
+
+		now := time.Now().UnixMicro()
+		review.Status = status
+		review.ModerationNote = note
+		review.ModeratedBy = adminID
+		review.ModeratedAt = now
+		review.UpdatedAt = now
+		err := tx.Model(&review).Updates(map[string]interface{}{
+			"status":          review.Status,
+			"moderation_note": review.ModerationNote,
+			"moderated_by":    review.ModeratedBy,
+			"moderated_at":    review.ModeratedAt,
+			"updated_at":      review.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		eventType := events.ReviewRestored
+		if status == models.ReviewHidden {
+			eventType = events.ReviewHidden
+		}
+		return writeOutbox(tx, eventType, review.RevieweeID, &review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"real-estate-system/user-service/events"
+	"real-estate-system/user-service/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormSavedSearchRepository struct {
+	DB *gorm.DB
+}
+
+func NewGormSavedSearchRepository(db *gorm.DB) *GormSavedSearchRepository {
+	return &GormSavedSearchRepository{DB: db}
+}
+
+func (r *GormSavedSearchRepository) CreateSavedSearch(search *models.SavedSearch) error {
+	return r.DB.Create(search).Error
+}
+
+func (r *GormSavedSearchRepository) GetSavedSearch(id int64) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	if err := r.DB.First(&search, id).Error; err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+func (r *GormSavedSearchRepository) GetSavedSearches(userID int64) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := r.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&searches).Error
+	return searches, err
+}
+
+func (r *GormSavedSearchRepository) UpdateSavedSearch(search *models.SavedSearch) error {
+	return r.DB.Save(search).Error
+}
+
+func (r *GormSavedSearchRepository) DeleteSavedSearch(id int64) error {
+	return r.DB.Delete(&models.SavedSearch{}, id).Error
+}
+
+// FindMatching looks up candidate searches through the (listing_type, area)
+// index, so the cost depends on how many searches share the listing's type
+// and area rather than on the total number of saved searches. Only searches
+// of users in the listing's tenant match, and only those without a price
+// range when the listing's price cannot be compared.
+func (r *GormSavedSearchRepository) FindMatching(listing models.ListingSnapshot) ([]models.SavedSearch, error) {
+	tenantUsers := r.DB.Model(&models.User{}).Select("id").Where("tenant_id = ?", listing.TenantID)
+
+	query := r.DB.
+		Where("listing_type IN ?", []string{listing.ListingType, ""}).
+		Where("area IN ?", []string{strings.ToLower(listing.City), strings.ToLower(listing.District), ""})
+	if price, ok := listing.ComparablePrice(); ok {
+		query = query.Where("min_price <= ?", price).Where("max_price = 0 OR max_price >= ?", price)
+	} else {
+		query = query.Where("min_price = ? AND max_price = ?", 0, 0)
+	}
+
+	var searches []models.SavedSearch
+	err := query.Where("user_id IN (?)", tenantUsers).Find(&searches).Error
+	return searches, err
+}
+
+// CreateAlerts inserts alerts, skipping ones already recorded for the same
+// search and event, and queues instant notifications in the outbox. It
+// returns the number of new alerts.
+func (r *GormSavedSearchRepository) CreateAlerts(alerts []models.Alert) (int, error) {
+	created := 0
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range alerts {
+			alert := &alerts[i]
+			if alert.Frequency == models.FrequencyInstant {
+				alert.NotifiedAt = alert.CreatedAt
+			}
+
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+			created++
+
+			if alert.Frequency == models.FrequencyInstant {
+				if err := writeOutboxFor(tx, events.AlertCreated, events.AggregateAlert, alert.UserID, alert); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return created, err
+}
+
+func (r *GormSavedSearchRepository) GetAlerts(userID int64, unreadOnly bool, limit int) ([]models.Alert, error) {
+	var alerts []models.Alert
+	query := r.DB.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at = ?", 0)
+	}
+	err := query.Order("created_at desc").Limit(limit).Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *GormSavedSearchRepository) MarkAlertsRead(userID int64, ids []int64, readAt int64) error {
+	query := r.DB.Model(&models.Alert{}).Where("user_id = ? AND read_at = ?", userID, 0)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	return query.Update("read_at", readAt).Error
+}
+
+// SendDailyDigests queues one digest event per user for daily alerts that
+// have not been notified yet and returns the number of digests.
+func (r *GormSavedSearchRepository) SendDailyDigests(now int64) (int, error) {
+	sent := 0
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var pending []models.Alert
+		err := tx.Where("frequency = ? AND notified_at = ?", models.FrequencyDaily, 0).
+			Order("user_id, id").Find(&pending).Error
+		if err != nil {
+			return err
+		}
+
+		byUser := make(map[int64][]models.Alert)
+		var users []int64
+		for _, alert := range pending {
+			if _, ok := byUser[alert.UserID]; !ok {
+				users = append(users, alert.UserID)
+			}
+			byUser[alert.UserID] = append(byUser[alert.UserID], alert)
+		}
+
+		for _, userID := range users {
+			userAlerts := byUser[userID]
+			ids := make([]int64, len(userAlerts))
+			for i, alert := range userAlerts {
+				ids[i] = alert.ID
+			}
+
+			digest := map[string]interface{}{
+				"tenant_id": userAlerts[0].TenantID,
+				"user_id":   userID,
+				"count":     len(userAlerts),
+				"alerts":    userAlerts,
+				"sent_at":   now,
+			}
+			if err := writeOutboxFor(tx, events.AlertDigest, events.AggregateAlert, userID, digest); err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Alert{}).Where("id IN ?", ids).Update("notified_at", now).Error; err != nil {
+				return err
+			}
+			sent++
+		}
+		return nil
+	})
+	return sent, err
+}
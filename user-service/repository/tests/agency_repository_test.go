@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAgency_AdminAlreadyInAgency(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormAgencyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1 ORDER BY "users"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(int64(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "agency_members" WHERE user_id = $1 AND status = $2`)).
+		WithArgs(int64(5), "active").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err := repo.CreateAgency(&models.Agency{Name: "Rumah Kita"}, 5)
+	assert.ErrorIs(t, err, models.ErrAlreadyInAgency)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAgency_CreatorBecomesAdmin(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormAgencyRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "agency_members"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "agencies" ("name","created_by","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
+		WithArgs("Rumah Kita", int64(5), int64(999), int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "agency_members" ("agency_id","user_id","role","status","invited_by","created_at","joined_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs(int64(3), int64(5), "admin", "active", int64(5), int64(999), int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	agency := &models.Agency{Name: "Rumah Kita", CreatedBy: 5, CreatedAt: 999, UpdatedAt: 999}
+	err := repo.CreateAgency(agency, 5)
+	assert.NoError(t, err)
+	assert.Len(t, agency.Members, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetManagedAgents(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormAgencyRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "user_id" FROM "agency_members" WHERE agency_id IN (SELECT "agency_id" FROM "agency_members" WHERE user_id = $1 AND role = $2 AND status = $3) AND status = $4 ORDER BY user_id`)).
+		WithArgs(int64(5), "admin", "active", "active").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5).AddRow(6))
+
+	ids, err := repo.GetManagedAgents(5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{5, 6}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewAgentProfile_NotPending(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormAgentProfileRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "agent_profiles" WHERE user_id = $1 ORDER BY "agent_profiles"."user_id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(int64(6), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "verification_status"}).AddRow(6, "verified"))
+	mock.ExpectRollback()
+
+	_, err := repo.ReviewAgentProfile(6, models.VerificationRejected, 1, "Expired")
+	assert.ErrorIs(t, err, models.ErrVerificationClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReview_AlreadyReviewed(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReviewRepository(db)
+
+	mock.ExpectBegin()
+	// The reviewer already reviewed this user: ON CONFLICT DO NOTHING returns no row.
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reviews"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	err := repo.CreateReview(&models.Review{ReviewerID: 6, RevieweeID: 5, Rating: 4, Status: models.ReviewPublished})
+	assert.ErrorIs(t, err, models.ErrReviewExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateReview_EmitsEvent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReviewRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "reviews"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("user", int64(5), "review.created", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	review := &models.Review{ReviewerID: 6, RevieweeID: 5, Rating: 4, Status: models.ReviewPublished}
+	assert.NoError(t, repo.CreateReview(review))
+	assert.Equal(t, int64(3), review.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRating_CountsPublishedReviews(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReviewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count FROM "reviews" WHERE reviewee_id = $1 AND status = $2`)).
+		WithArgs(int64(5), "published").
+		WillReturnRows(sqlmock.NewRows([]string{"average", "count"}).AddRow(4.333333, 3))
+
+	rating, err := repo.GetRating(5)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RatingSummary{Average: 4.3, Count: 3}, rating)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplyToReview_NotReviewee(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReviewRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "reviews" WHERE "reviews"."id" = $1 ORDER BY "reviews"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs(int64(3), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reviewer_id", "reviewee_id", "status"}).AddRow(3, 6, 5, "published"))
+	mock.ExpectRollback()
+
+	_, err := repo.ReplyToReview(3, 6, "Thanks")
+	assert.ErrorIs(t, err, models.ErrNotReviewee)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerateReview_HideEmitsEvent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormReviewRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reviewer_id", "reviewee_id", "status"}).AddRow(3, 6, 5, "published"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reviews" SET "moderated_at"=$1,"moderated_by"=$2,"moderation_note"=$3,"status"=$4,"updated_at"=$5 WHERE "id" = $6`)).
+		WithArgs(sqlmock.AnyArg(), 1, "Personal details", "hidden", sqlmock.AnyArg(), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("user", int64(5), "review.hidden", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	review, err := repo.ModerateReview(3, models.ReviewHidden, 1, "Personal details")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReviewHidden, review.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"real-estate-system/user-service/models"
+	"real-estate-system/user-service/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMatchingSavedSearches(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormSavedSearchRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "name", "listing_type", "area", "min_price", "max_price", "frequency"}).
+		AddRow(1, 5, "Cheap rent", "rent", "jakarta selatan", 0, 4000, "instant")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "saved_searches" WHERE listing_type IN ($1,$2) AND area IN ($3,$4,$5) AND min_price <= $6 AND (max_price = 0 OR max_price >= $7) AND user_id IN (SELECT "id" FROM "users" WHERE tenant_id = $8)`)).
+		WithArgs("rent", "", "jakarta selatan", "tebet", "", 3500, 3500, "acme").
+		WillReturnRows(rows)
+
+	searches, err := repo.FindMatching(models.ListingSnapshot{ID: 9, TenantID: "acme", Price: 3500, ListingType: "rent", City: "Jakarta Selatan", District: "Tebet"})
+	assert.NoError(t, err)
+	assert.Len(t, searches, 1)
+	assert.Equal(t, int64(5), searches[0].UserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindMatching_UnconvertedPriceOnlyMatchesAnyPrice(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormSavedSearchRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "saved_searches" WHERE listing_type IN ($1,$2) AND area IN ($3,$4,$5) AND (min_price = $6 AND max_price = $7) AND user_id IN (SELECT "id" FROM "users" WHERE tenant_id = $8)`)).
+		WithArgs("rent", "", "singapore", "", "", 0, 0, "acme").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.FindMatching(models.ListingSnapshot{ID: 9, TenantID: "acme", Price: 3500, Currency: "SGD", ListingType: "rent", City: "Singapore"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateAlerts_InstantWritesOutbox(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormSavedSearchRepository(db)
+
+	alerts := []models.Alert{
+		{UserID: 5, SavedSearchID: 1, EventID: "listing-1", EventType: "listing.created", ListingID: 9, Listing: "{}", Frequency: "instant", CreatedAt: 100},
+		{UserID: 6, SavedSearchID: 2, EventID: "listing-1", EventType: "listing.created", ListingID: 9, Listing: "{}", Frequency: "daily", CreatedAt: 100},
+		{UserID: 7, SavedSearchID: 3, EventID: "listing-1", EventType: "listing.created", ListingID: 9, Listing: "{}", Frequency: "instant", CreatedAt: 100},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "alerts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("alert", int64(5), "alert.created", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "alerts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	// Already recorded for this event: ON CONFLICT DO NOTHING returns no row.
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "alerts"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	created, err := repo.CreateAlerts(alerts)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+	assert.Equal(t, int64(100), alerts[0].NotifiedAt)
+	assert.Equal(t, int64(0), alerts[1].NotifiedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUnreadAlerts(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormSavedSearchRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "alerts" WHERE user_id = $1 AND read_at = $2 ORDER BY created_at desc LIMIT $3`)).
+		WithArgs(int64(5), 0, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id"}).AddRow(1, 5))
+
+	alerts, err := repo.GetAlerts(5, true, 50)
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkAlertsRead(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormSavedSearchRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "alerts" SET "read_at"=$1 WHERE (user_id = $2 AND read_at = $3) AND id IN ($4,$5)`)).
+		WithArgs(int64(999), int64(5), 0, int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.MarkAlertsRead(5, []int64{1, 2}, 999)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSendDailyDigests_OnePerUser(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormSavedSearchRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "frequency"}).
+		AddRow(1, 5, "daily").
+		AddRow(2, 5, "daily").
+		AddRow(3, 6, "daily")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "alerts" WHERE frequency = $1 AND notified_at = $2 ORDER BY user_id, id`)).
+		WithArgs("daily", 0).
+		WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("alert", int64(5), "alert.digest", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "alerts" SET "notified_at"=$1 WHERE id IN ($2,$3)`)).
+		WithArgs(int64(999), int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("alert", int64(6), "alert.digest", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "alerts" SET "notified_at"=$1 WHERE id IN ($2)`)).
+		WithArgs(int64(999), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	sent, err := repo.SendDailyDigests(999)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -39,8 +39,11 @@ func TestCreateUser(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("name","created_at","updated_at") VALUES ($1,$2,$3) RETURNING "id"`)).
-		WithArgs(user.Name, user.CreatedAt, user.UpdatedAt).
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("tenant_id","name","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
+		WithArgs("default", user.Name, user.CreatedAt, user.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WithArgs("user", int64(1), "user.created", sqlmock.AnyArg(), 0, "", sqlmock.AnyArg(), 0).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
 
@@ -56,8 +59,8 @@ func TestGetUser(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
 		AddRow(1, "Charlie", 123456, 123456)
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1 ORDER BY "users"."id" LIMIT $2`)).
-		WithArgs(1, 1).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE tenant_id = $1 AND "users"."id" = $2 ORDER BY "users"."id" LIMIT $3`)).
+		WithArgs("default", 1, 1).
 		WillReturnRows(rows)
 
 	user, err := repo.GetUser(1)
@@ -76,8 +79,8 @@ func TestGetUsers(t *testing.T) {
 		AddRow(2, "User2", 123456, 123456)
 
 	// GORM may omit OFFSET if it's 0, so we test only the LIMIT
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" ORDER BY created_at desc LIMIT $1`)).
-		WithArgs(10).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE tenant_id = $1 ORDER BY created_at desc LIMIT $2`)).
+		WithArgs("default", 10).
 		WillReturnRows(rows)
 
 	users, err := repo.GetUsers(1, 10)
@@ -93,8 +96,8 @@ func TestGetUser_NotFound(t *testing.T) {
 	repo := repository.NewGormUserRepository(db)
 
 	// Simulate user not found
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1 ORDER BY "users"."id" LIMIT $2`)).
-		WithArgs(999, 1).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE tenant_id = $1 AND "users"."id" = $2 ORDER BY "users"."id" LIMIT $3`)).
+		WithArgs("default", 999, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	user, err := repo.GetUser(999)
@@ -103,3 +106,35 @@ func TestGetUser_NotFound(t *testing.T) {
 	assert.Equal(t, gorm.ErrRecordNotFound, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetUser_OtherTenantNotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormUserRepository(db).ForTenant("acme")
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE tenant_id = $1 AND "users"."id" = $2 ORDER BY "users"."id" LIMIT $3`)).
+		WithArgs("acme", 1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	user, err := repo.GetUser(1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, user)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateUser_BelongsToRepositoryTenant(t *testing.T) {
+	db, mock := setupMockDB(t)
+	repo := repository.NewGormUserRepository(db).ForTenant("acme")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users"`)).
+		WithArgs("acme", "Alice", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	user := &models.User{TenantID: "default", Name: "Alice"}
+	assert.NoError(t, repo.CreateUser(user))
+	assert.Equal(t, "acme", user.TenantID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
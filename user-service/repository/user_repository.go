@@ -1,33 +1,53 @@
 package repository
 
 import (
+	"real-estate-system/user-service/events"
 	"real-estate-system/user-service/models"
+	repository "real-estate-system/user-service/repository/interfaces"
 
 	"gorm.io/gorm"
 )
 
+// GormUserRepository reads and writes the users of one tenant, the default
+// one unless it comes from ForTenant.
 type GormUserRepository struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	TenantID string
 }
 
 func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
-	return &GormUserRepository{DB: db}
+	return &GormUserRepository{DB: db, TenantID: models.DefaultTenant}
+}
+
+func (r *GormUserRepository) ForTenant(tenantID string) repository.UserRepository {
+	return &GormUserRepository{DB: r.DB, TenantID: tenantID}
+}
+
+// scoped limits db to the tenant's users.
+func (r *GormUserRepository) scoped(db *gorm.DB) *gorm.DB {
+	return db.Where("tenant_id = ?", r.TenantID)
 }
 
 func (r *GormUserRepository) CreateUser(user *models.User) error {
-	return r.DB.Create(user).Error
+	user.TenantID = r.TenantID
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events.UserCreated, user.ID, user)
+	})
 }
 
 func (r *GormUserRepository) GetUsers(page, size int) ([]models.User, error) {
 	var users []models.User
 	offset := (page - 1) * size
-	result := r.DB.Order("created_at desc").Offset(offset).Limit(size).Find(&users)
+	result := r.scoped(r.DB).Order("created_at desc").Offset(offset).Limit(size).Find(&users)
 	return users, result.Error
 }
 
 func (r *GormUserRepository) GetUser(id int) (*models.User, error) {
 	var user models.User
-	result := r.DB.First(&user, id)
+	result := r.scoped(r.DB).First(&user, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}